@@ -0,0 +1,7 @@
+-- Speeds up amenity filtering in the faceted room search.
+-- The @> (all-of) and && (any-of) array operators are both served by a GIN index.
+CREATE INDEX IF NOT EXISTS idx_rooms_amenities ON rooms USING GIN (amenities);
+
+-- Supports the price, floor and room type filters and the default sort.
+CREATE INDEX IF NOT EXISTS idx_rooms_room_type ON rooms (room_type);
+CREATE INDEX IF NOT EXISTS idx_rooms_price_per_night ON rooms (price_per_night);
@@ -0,0 +1,19 @@
+-- Photos attached either to a single room or to every room of a room type.
+CREATE TABLE IF NOT EXISTS room_media (
+    id            SERIAL PRIMARY KEY,
+    room_id       INT REFERENCES rooms (id) ON DELETE CASCADE,
+    room_type     VARCHAR(50),
+    storage_key   TEXT NOT NULL,
+    thumbnail_key TEXT NOT NULL,
+    content_type  VARCHAR(50) NOT NULL,
+    size_bytes    BIGINT NOT NULL,
+    caption       TEXT NOT NULL DEFAULT '',
+    position      INT NOT NULL DEFAULT 0,
+    created_at    TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMP NOT NULL DEFAULT NOW(),
+    -- Every photo belongs to exactly one owner
+    CONSTRAINT room_media_single_owner CHECK ((room_id IS NULL) <> (room_type IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_room_media_room ON room_media (room_id, position) WHERE room_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_room_media_room_type ON room_media (room_type, position) WHERE room_type IS NOT NULL;
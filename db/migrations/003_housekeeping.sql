@@ -0,0 +1,22 @@
+-- Housekeeping state of each room. Existing rooms start as inspected (ready to sell).
+ALTER TABLE rooms
+    ADD COLUMN IF NOT EXISTS housekeeping_status VARCHAR(20) NOT NULL DEFAULT 'inspected',
+    ADD COLUMN IF NOT EXISTS housekeeping_updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    ADD COLUMN IF NOT EXISTS housekeeping_updated_by INT REFERENCES users (id);
+
+ALTER TABLE rooms DROP CONSTRAINT IF EXISTS rooms_housekeeping_status_check;
+ALTER TABLE rooms ADD CONSTRAINT rooms_housekeeping_status_check
+    CHECK (housekeeping_status IN ('dirty', 'cleaning', 'clean', 'inspected', 'out_of_order'));
+
+-- Audit trail of every housekeeping transition.
+CREATE TABLE IF NOT EXISTS housekeeping_events (
+    id          SERIAL PRIMARY KEY,
+    room_id     INT NOT NULL REFERENCES rooms (id) ON DELETE CASCADE,
+    from_status VARCHAR(20) NOT NULL,
+    to_status   VARCHAR(20) NOT NULL,
+    changed_by  INT REFERENCES users (id),
+    note        TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_housekeeping_events_room ON housekeeping_events (room_id, created_at DESC);
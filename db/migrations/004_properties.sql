@@ -0,0 +1,40 @@
+-- Hotels operated by the group. Rooms, bookings and maintenance belong to exactly one property.
+CREATE TABLE IF NOT EXISTS properties (
+    id         SERIAL PRIMARY KEY,
+    code       VARCHAR(20) NOT NULL UNIQUE,
+    name       VARCHAR(100) NOT NULL,
+    address    TEXT NOT NULL DEFAULT '',
+    timezone   VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Existing single-property data is moved to a default property.
+INSERT INTO properties (code, name) VALUES ('MAIN', 'Main property') ON CONFLICT (code) DO NOTHING;
+
+-- Rooms: room numbers are now unique per property instead of globally.
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS property_id INT REFERENCES properties (id);
+UPDATE rooms SET property_id = (SELECT id FROM properties WHERE code = 'MAIN') WHERE property_id IS NULL;
+ALTER TABLE rooms ALTER COLUMN property_id SET NOT NULL;
+ALTER TABLE rooms DROP CONSTRAINT IF EXISTS rooms_room_number_key;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_rooms_property_room_number ON rooms (property_id, room_number);
+
+-- Bookings and maintenance copy the property of their room so they can be filtered without a join.
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS property_id INT REFERENCES properties (id);
+UPDATE bookings b SET property_id = r.property_id FROM rooms r WHERE r.id = b.room_id AND b.property_id IS NULL;
+ALTER TABLE bookings ALTER COLUMN property_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_bookings_property ON bookings (property_id);
+
+ALTER TABLE room_maintenance ADD COLUMN IF NOT EXISTS property_id INT REFERENCES properties (id);
+UPDATE room_maintenance m SET property_id = r.property_id FROM rooms r WHERE r.id = m.room_id AND m.property_id IS NULL;
+ALTER TABLE room_maintenance ALTER COLUMN property_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_room_maintenance_property ON room_maintenance (property_id);
+
+-- Staff assignments: a staff user sees only the properties they are assigned to.
+CREATE TABLE IF NOT EXISTS user_properties (
+    user_id     INT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    property_id INT NOT NULL REFERENCES properties (id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, property_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_properties_property ON user_properties (property_id);
@@ -0,0 +1,31 @@
+-- Prevent double-booking: a room cannot have two active bookings whose nights overlap.
+-- Nights are the half-open date range [check_in, check_out), so a check-out and a check-in
+-- on the same day do not conflict. Cancelled and no-show bookings free their nights.
+-- Existing overlapping bookings must be resolved before this migration can run.
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+-- Index expressions must be immutable: a timestamptz is converted to a date in UTC explicitly,
+-- while a plain timestamp or date can be cast directly.
+DO $$
+DECLARE
+    col_type text;
+    night_expr text;
+BEGIN
+    SELECT data_type INTO col_type
+    FROM information_schema.columns
+    WHERE table_name = 'bookings' AND column_name = 'check_in_date';
+
+    IF col_type = 'timestamp with time zone' THEN
+        night_expr := $e$daterange((check_in_date AT TIME ZONE 'UTC')::date, (check_out_date AT TIME ZONE 'UTC')::date, '[)')$e$;
+    ELSE
+        night_expr := $e$daterange(check_in_date::date, check_out_date::date, '[)')$e$;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'bookings_no_overlap') THEN
+        EXECUTE format(
+            'ALTER TABLE bookings ADD CONSTRAINT bookings_no_overlap EXCLUDE USING gist (room_id WITH =, %s WITH &&) WHERE (status NOT IN (''cancelled'', ''no_show''))',
+            night_expr
+        );
+    END IF;
+END
+$$;
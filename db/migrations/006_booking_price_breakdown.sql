@@ -0,0 +1,2 @@
+-- Itemised price computed by the server when the booking is created, kept for auditing.
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS price_breakdown JSONB;
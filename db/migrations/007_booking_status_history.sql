@@ -0,0 +1,18 @@
+-- Booking lifecycle: pending -> confirmed -> checked_in -> checked_out, with cancelled and no_show as exits.
+-- NOT VALID keeps legacy rows untouched while every new or updated row must use a known status.
+ALTER TABLE bookings DROP CONSTRAINT IF EXISTS bookings_status_check;
+ALTER TABLE bookings ADD CONSTRAINT bookings_status_check
+    CHECK (status IN ('pending', 'confirmed', 'checked_in', 'checked_out', 'cancelled', 'no_show')) NOT VALID;
+
+-- Audit trail of every status change, including the creation of the booking (from_status NULL).
+CREATE TABLE IF NOT EXISTS booking_status_history (
+    id          SERIAL PRIMARY KEY,
+    booking_id  INT NOT NULL REFERENCES bookings (id) ON DELETE CASCADE,
+    from_status VARCHAR(20),
+    to_status   VARCHAR(20) NOT NULL,
+    changed_by  INT REFERENCES users (id) ON DELETE SET NULL,
+    reason      TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_status_history_booking ON booking_status_history (booking_id, created_at);
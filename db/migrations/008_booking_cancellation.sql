@@ -0,0 +1,2 @@
+-- Penalty and refund applied when a booking is cancelled (NULL for bookings that were never cancelled).
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS cancellation JSONB;
@@ -0,0 +1,18 @@
+-- Bookings carry a version that is bumped by every modification.
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+
+-- Versioned change log: the stay before and after each modification and what it did to the balance.
+CREATE TABLE IF NOT EXISTS booking_changes (
+    id               SERIAL PRIMARY KEY,
+    booking_id       INT NOT NULL REFERENCES bookings (id) ON DELETE CASCADE,
+    version          INT NOT NULL,
+    changed_by       INT REFERENCES users (id) ON DELETE SET NULL,
+    reason           TEXT NOT NULL DEFAULT '',
+    before           JSONB NOT NULL,
+    after            JSONB NOT NULL,
+    price_difference NUMERIC(10, 2) NOT NULL,
+    balance_due      NUMERIC(10, 2) NOT NULL DEFAULT 0,
+    refund_due       NUMERIC(10, 2) NOT NULL DEFAULT 0,
+    created_at       TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (booking_id, version)
+);
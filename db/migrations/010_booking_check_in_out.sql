@@ -0,0 +1,3 @@
+-- Front desk timestamps recorded by check-in and check-out.
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS checked_in_at TIMESTAMP;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS checked_out_at TIMESTAMP;
@@ -0,0 +1,4 @@
+-- Unpaid pending bookings hold their room until hold_expires_at; the expiry worker cancels them afterwards.
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS hold_expires_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_bookings_hold_expiry ON bookings (hold_expires_at) WHERE status = 'pending';
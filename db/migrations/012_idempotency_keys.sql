@@ -0,0 +1,14 @@
+-- Responses to requests sent with an Idempotency-Key header, scoped to the caller.
+-- status_code and response_body stay NULL while the original request is still running.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    caller        TEXT NOT NULL,
+    key           TEXT NOT NULL,
+    request_hash  TEXT NOT NULL,
+    status_code   INT,
+    response_body BYTEA,
+    created_at    TIMESTAMP NOT NULL DEFAULT NOW(),
+    completed_at  TIMESTAMP,
+    PRIMARY KEY (caller, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys (created_at);
@@ -0,0 +1,16 @@
+-- Group reservations: several room bookings under one confirmation code and one lead guest.
+CREATE TABLE IF NOT EXISTS reservations (
+    id                SERIAL PRIMARY KEY,
+    confirmation_code TEXT NOT NULL UNIQUE,
+    property_id       INT NOT NULL REFERENCES properties (id),
+    lead_user_id      INT NOT NULL REFERENCES users (id),
+    special_requests  TEXT NOT NULL DEFAULT '',
+    created_at        TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS reservation_id INT REFERENCES reservations (id);
+CREATE INDEX IF NOT EXISTS idx_bookings_reservation ON bookings (reservation_id) WHERE reservation_id IS NOT NULL;
+
+-- A payment made for a whole reservation is split into one payment per booking, grouped by reservation_id.
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS reservation_id INT REFERENCES reservations (id);
@@ -0,0 +1,23 @@
+-- Guests waiting for a room type of a property to be freed for their dates.
+CREATE TABLE IF NOT EXISTS waitlist_entries (
+    id               SERIAL PRIMARY KEY,
+    property_id      INT NOT NULL REFERENCES properties (id),
+    user_id          INT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    room_type        TEXT NOT NULL,
+    check_in_date    DATE NOT NULL,
+    check_out_date   DATE NOT NULL,
+    adults           INT NOT NULL,
+    children         INT NOT NULL DEFAULT 0,
+    status           TEXT NOT NULL DEFAULT 'waiting'
+        CHECK (status IN ('waiting', 'offered', 'booked', 'expired', 'cancelled')),
+    offered_room_id  INT REFERENCES rooms (id) ON DELETE SET NULL,
+    offer_expires_at TIMESTAMP,
+    booking_id       INT REFERENCES bookings (id) ON DELETE SET NULL,
+    created_at       TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (check_out_date > check_in_date)
+);
+
+-- Matching walks waiting entries oldest first; expiry looks at open offers.
+CREATE INDEX IF NOT EXISTS idx_waitlist_waiting ON waitlist_entries (created_at) WHERE status = 'waiting';
+CREATE INDEX IF NOT EXISTS idx_waitlist_offers ON waitlist_entries (offer_expires_at) WHERE status = 'offered';
@@ -0,0 +1,17 @@
+-- Guests staying under a booking, as required for guest registration with the authorities.
+-- Document numbers are encrypted by the application (AES-256-GCM) before they are stored.
+CREATE TABLE IF NOT EXISTS booking_guests (
+    id              SERIAL PRIMARY KEY,
+    booking_id      INT NOT NULL REFERENCES bookings (id) ON DELETE CASCADE,
+    first_name      TEXT NOT NULL,
+    last_name       TEXT NOT NULL,
+    nationality     CHAR(2) NOT NULL,
+    date_of_birth   DATE NOT NULL,
+    is_child        BOOLEAN NOT NULL DEFAULT FALSE,
+    document_type   TEXT NOT NULL DEFAULT ''
+        CHECK (document_type IN ('', 'passport', 'national_id', 'driving_licence')),
+    document_cipher BYTEA,
+    created_at      TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_guests_booking ON booking_guests (booking_id);
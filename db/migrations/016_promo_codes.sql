@@ -0,0 +1,37 @@
+-- Marketing promo codes and the bookings that redeemed them.
+-- Date windows are inclusive; NULL means unbounded and NULL limits mean unlimited.
+CREATE TABLE IF NOT EXISTS promo_codes (
+    id                SERIAL PRIMARY KEY,
+    code              TEXT NOT NULL UNIQUE,
+    description       TEXT NOT NULL DEFAULT '',
+    discount_type     TEXT NOT NULL CHECK (discount_type IN ('percentage', 'fixed')),
+    discount_value    NUMERIC(10, 2) NOT NULL CHECK (discount_value > 0),
+    stay_from         DATE,
+    stay_to           DATE,
+    book_from         TIMESTAMP,
+    book_to           TIMESTAMP,
+    min_nights        INT NOT NULL DEFAULT 1,
+    room_types        TEXT[] NOT NULL DEFAULT '{}',
+    max_uses          INT,
+    max_uses_per_user INT,
+    times_used        INT NOT NULL DEFAULT 0,
+    stackable         BOOLEAN NOT NULL DEFAULT FALSE,
+    is_active         BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at        TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (discount_type <> 'percentage' OR discount_value <= 100)
+);
+
+CREATE TABLE IF NOT EXISTS promo_code_redemptions (
+    id              SERIAL PRIMARY KEY,
+    promo_code_id   INT NOT NULL REFERENCES promo_codes (id),
+    booking_id      INT NOT NULL REFERENCES bookings (id) ON DELETE CASCADE,
+    user_id         INT NOT NULL REFERENCES users (id),
+    discount_amount NUMERIC(10, 2) NOT NULL,
+    created_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (promo_code_id, booking_id)
+);
+
+-- Per-user limits count a guest's redemptions of a code.
+CREATE INDEX IF NOT EXISTS idx_promo_redemptions_user ON promo_code_redemptions (promo_code_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_promo_redemptions_booking ON promo_code_redemptions (booking_id);
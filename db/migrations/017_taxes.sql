@@ -0,0 +1,31 @@
+-- Taxes and fees charged on stays, such as VAT, city tax and service fees.
+-- A rate without a property applies to every property; validity dates are inclusive and open when NULL.
+CREATE TABLE IF NOT EXISTS tax_rates (
+    id              SERIAL PRIMARY KEY,
+    property_id     INT REFERENCES properties (id),
+    code            TEXT NOT NULL,
+    name            TEXT NOT NULL,
+    basis           TEXT NOT NULL CHECK (basis IN ('percentage', 'per_person', 'per_room')),
+    period          TEXT NOT NULL DEFAULT 'night' CHECK (period IN ('night', 'stay')),
+    rate            NUMERIC(10, 2) NOT NULL CHECK (rate > 0),
+    inclusive       BOOLEAN NOT NULL DEFAULT FALSE,
+    children_exempt BOOLEAN NOT NULL DEFAULT FALSE,
+    valid_from      DATE,
+    valid_to        DATE,
+    created_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (valid_to IS NULL OR valid_from IS NULL OR valid_to >= valid_from)
+);
+
+-- Tax lines of each booking, kept apart from the room charge so tax can be reported.
+-- Inclusive lines are already part of the room charge; exclusive ones are added to the total.
+CREATE TABLE IF NOT EXISTS booking_taxes (
+    id          SERIAL PRIMARY KEY,
+    booking_id  INT NOT NULL REFERENCES bookings (id) ON DELETE CASCADE,
+    tax_rate_id INT REFERENCES tax_rates (id) ON DELETE SET NULL,
+    code        TEXT NOT NULL,
+    description TEXT NOT NULL,
+    inclusive   BOOLEAN NOT NULL,
+    amount      NUMERIC(10, 2) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_taxes_booking ON booking_taxes (booking_id);
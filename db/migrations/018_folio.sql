@@ -0,0 +1,19 @@
+-- Incidental charges and credits posted to a booking's folio during the stay.
+-- Amounts are positive; credits reduce the balance. Voided entries stay on the folio with a reason.
+CREATE TABLE IF NOT EXISTS folio_entries (
+    id          SERIAL PRIMARY KEY,
+    booking_id  INT NOT NULL REFERENCES bookings (id) ON DELETE CASCADE,
+    kind        TEXT NOT NULL CHECK (kind IN ('charge', 'credit')),
+    category    TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    amount      NUMERIC(10, 2) NOT NULL CHECK (amount > 0),
+    tax_amount  NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (tax_amount >= 0),
+    posted_by   INT REFERENCES users (id),
+    posted_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+    voided_by   INT REFERENCES users (id),
+    voided_at   TIMESTAMP,
+    void_reason TEXT,
+    CHECK (voided_at IS NULL OR void_reason <> '')
+);
+
+CREATE INDEX IF NOT EXISTS idx_folio_entries_booking ON folio_entries (booking_id, posted_at);
@@ -0,0 +1,54 @@
+-- Invoices and credit notes issued for bookings. Numbers are gap-free per property and kind:
+-- invoice_sequences is incremented in the issuing transaction, so a rolled back issue frees its number.
+CREATE TABLE IF NOT EXISTS invoice_sequences (
+    property_id INT NOT NULL REFERENCES properties (id),
+    kind        TEXT NOT NULL CHECK (kind IN ('invoice', 'credit_note')),
+    last_number INT NOT NULL,
+    PRIMARY KEY (property_id, kind)
+);
+
+-- Issued documents are a snapshot of the booking's charges, taxes and payments at issue time.
+-- A credit note reverses the invoice it corrects in full.
+CREATE TABLE IF NOT EXISTS invoices (
+    id                  SERIAL PRIMARY KEY,
+    property_id         INT NOT NULL REFERENCES properties (id),
+    booking_id          INT NOT NULL REFERENCES bookings (id),
+    kind                TEXT NOT NULL CHECK (kind IN ('invoice', 'credit_note')),
+    number              INT NOT NULL,
+    invoice_number      TEXT NOT NULL UNIQUE,
+    corrects_invoice_id INT UNIQUE REFERENCES invoices (id),
+    reason              TEXT NOT NULL DEFAULT '',
+    property_name       TEXT NOT NULL,
+    property_address    TEXT NOT NULL DEFAULT '',
+    bill_to_name        TEXT NOT NULL,
+    bill_to_address     TEXT NOT NULL DEFAULT '',
+    bill_to_tax_id      TEXT NOT NULL DEFAULT '',
+    stay_from           DATE NOT NULL,
+    stay_to             DATE NOT NULL,
+    lines               JSONB NOT NULL,
+    taxes               JSONB NOT NULL,
+    subtotal            NUMERIC(10, 2) NOT NULL,
+    tax_total           NUMERIC(10, 2) NOT NULL,
+    included_tax        NUMERIC(10, 2) NOT NULL,
+    total               NUMERIC(10, 2) NOT NULL,
+    amount_paid         NUMERIC(10, 2) NOT NULL,
+    balance_due         NUMERIC(10, 2) NOT NULL,
+    issued_by           INT REFERENCES users (id),
+    issued_at           TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (property_id, kind, number),
+    CHECK ((kind = 'credit_note') = (corrects_invoice_id IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_invoices_booking ON invoices (booking_id, id);
+
+-- Issued invoices are immutable; corrections are made by issuing a credit note
+CREATE OR REPLACE FUNCTION reject_invoice_change() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'issued invoices cannot be changed or deleted; issue a credit note instead';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS invoices_immutable ON invoices;
+CREATE TRIGGER invoices_immutable
+    BEFORE UPDATE OR DELETE ON invoices
+    FOR EACH ROW EXECUTE FUNCTION reject_invoice_change();
@@ -0,0 +1,38 @@
+-- iCal feeds of external booking platforms attached to a room, with the state of their last sync.
+-- etag and last_modified hold the validators of the last download for conditional requests.
+CREATE TABLE IF NOT EXISTS external_calendars (
+    id              SERIAL PRIMARY KEY,
+    property_id     INT NOT NULL REFERENCES properties (id),
+    room_id         INT NOT NULL REFERENCES rooms (id) ON DELETE CASCADE,
+    name            TEXT NOT NULL,
+    url             TEXT NOT NULL,
+    active          BOOLEAN NOT NULL DEFAULT TRUE,
+    etag            TEXT,
+    last_modified   TEXT,
+    last_synced_at  TIMESTAMP,
+    last_success_at TIMESTAMP,
+    last_error      TEXT,
+    created_by      INT REFERENCES users (id),
+    created_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (room_id, url)
+);
+
+CREATE INDEX IF NOT EXISTS idx_external_calendars_due ON external_calendars (last_synced_at NULLS FIRST) WHERE active;
+
+-- Nights taken by reservations imported from an external calendar, keyed by the event UID.
+-- Nights are the half-open date range [start_date, end_date), like bookings.
+CREATE TABLE IF NOT EXISTS external_blocks (
+    id          SERIAL PRIMARY KEY,
+    calendar_id INT NOT NULL REFERENCES external_calendars (id) ON DELETE CASCADE,
+    property_id INT NOT NULL REFERENCES properties (id),
+    room_id     INT NOT NULL REFERENCES rooms (id) ON DELETE CASCADE,
+    uid         TEXT NOT NULL,
+    summary     TEXT NOT NULL DEFAULT '',
+    start_date  DATE NOT NULL,
+    end_date    DATE NOT NULL CHECK (end_date > start_date),
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (calendar_id, uid)
+);
+
+CREATE INDEX IF NOT EXISTS idx_external_blocks_room_nights ON external_blocks USING gist (room_id, daterange(start_date, end_date, '[)'));
@@ -0,0 +1,19 @@
+-- Domain events waiting to be delivered to guests, written in the same transaction as the change
+-- they describe. The dispatcher claims due pending events, records each channel that delivered
+-- the event and retries the rest with backoff; events out of attempts are kept as dead letters.
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id                 BIGSERIAL PRIMARY KEY,
+    event_type         VARCHAR(50) NOT NULL,
+    booking_id         INT NOT NULL REFERENCES bookings (id) ON DELETE CASCADE,
+    payload            JSONB NOT NULL DEFAULT '{}',
+    status             VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'dead')),
+    attempts           INT NOT NULL DEFAULT 0,
+    next_attempt_at    TIMESTAMP NOT NULL DEFAULT NOW(),
+    delivered_channels TEXT[] NOT NULL DEFAULT '{}',
+    last_error         TEXT,
+    created_at         TIMESTAMP NOT NULL DEFAULT NOW(),
+    sent_at            TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_due ON outbox_events (next_attempt_at, id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_outbox_events_status ON outbox_events (status, id);
@@ -0,0 +1,3 @@
+-- Redemptions of bookings that were cancelled, expired or became no-shows are released: they stop
+-- counting towards the usage limits of their code but are kept for reporting.
+ALTER TABLE promo_code_redemptions ADD COLUMN IF NOT EXISTS released_at TIMESTAMP;
@@ -56,3 +56,19 @@ func Close() {
 		_ = Client.Close()
 	}
 }
+
+// DeletePattern removes every key matching a glob pattern (e.g. "property:*:available_rooms").
+// Keys are found with SCAN so large keyspaces do not block the server.
+// It does nothing when Redis is not configured.
+func DeletePattern(ctx context.Context, pattern string) error {
+	if Client == nil {
+		return nil
+	}
+	iter := Client.Scan(ctx, 0, pattern, 100).Iterator()
+	for iter.Next(ctx) {
+		if err := Client.Del(ctx, iter.Val()).Err(); err != nil {
+			return err
+		}
+	}
+	return iter.Err()
+}
@@ -0,0 +1,294 @@
+// Package calsync downloads and parses the iCalendar (RFC 5545) feeds that external booking
+// platforms publish for the rooms they sell, so their reservations can block our inventory.
+package calsync
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"industry-api/internal/models"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMaxBytes is the largest feed a Client downloads when MaxBytes is not set.
+const DefaultMaxBytes = 5 << 20
+
+// Client downloads external calendar feeds.
+type Client struct {
+	HTTP     *http.Client // HTTP client used for downloads (http.DefaultClient when nil)
+	MaxBytes int64        // Largest accepted feed in bytes (DefaultMaxBytes when 0)
+}
+
+// Feed is the result of downloading a calendar feed.
+type Feed struct {
+	NotModified  bool                   // The feed has not changed since the validators sent
+	Events       []models.CalendarEvent // Events of the feed (nil when NotModified)
+	ETag         string                 // ETag to send on the next download
+	LastModified string                 // Last-Modified to send on the next download
+}
+
+// Fetch downloads the feed at url. The etag and lastModified of the previous download (empty
+// when unknown) are sent as conditional request headers, and an unchanged feed is reported as
+// NotModified. webcal:// URLs are fetched over https.
+// Returns an error for a failed request, an unexpected status, an oversized feed or a document
+// that is not a calendar.
+func (c *Client) Fetch(ctx context.Context, url, etag, lastModified string) (*Feed, error) {
+	if rest, ok := strings.CutPrefix(url, "webcal://"); ok {
+		url = "https://" + rest
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid calendar URL: %w", err)
+	}
+	req.Header.Set("Accept", "text/calendar, */*;q=0.5")
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download calendar: %w", err)
+	}
+	defer resp.Body.Close()
+
+	feed := &Feed{ETag: resp.Header.Get("ETag"), LastModified: resp.Header.Get("Last-Modified")}
+	if resp.StatusCode == http.StatusNotModified {
+		feed.NotModified = true
+		if feed.ETag == "" {
+			feed.ETag = etag
+		}
+		if feed.LastModified == "" {
+			feed.LastModified = lastModified
+		}
+		return feed, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("calendar download failed with status %s", resp.Status)
+	}
+
+	limit := c.MaxBytes
+	if limit <= 0 {
+		limit = DefaultMaxBytes
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download calendar: %w", err)
+	}
+	if int64(len(body)) > limit {
+		return nil, fmt.Errorf("calendar is larger than %d bytes", limit)
+	}
+	if feed.Events, err = Parse(bytes.NewReader(body)); err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
+
+// Parse reads the events of an iCalendar document. Dates without a time are all-day events;
+// times without a zone are read as UTC, and times with an unknown TZID as well. An event
+// without an end lasts one day when it is all-day and no time otherwise. Recurrence rules are
+// not expanded, so only the first occurrence of a recurring event is returned.
+// Returns an error if the document is not a calendar or an event has an unreadable date.
+func Parse(r io.Reader) ([]models.CalendarEvent, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 || !strings.EqualFold(lines[0], "BEGIN:VCALENDAR") {
+		return nil, errors.New("document is not an iCalendar feed")
+	}
+
+	events := []models.CalendarEvent{}
+	var event *models.CalendarEvent
+	var duration *time.Duration
+	hasEnd := false
+	nested := 0 // Depth of components inside the current event, such as alarms
+	for n, line := range lines {
+		name, params, value := splitContentLine(line)
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT") && event == nil:
+			event, duration, hasEnd, nested = &models.CalendarEvent{}, nil, false, 0
+		case event == nil:
+		case name == "BEGIN":
+			nested++
+		case name == "END" && nested > 0:
+			nested--
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if !hasEnd {
+				switch {
+				case duration != nil:
+					event.End = event.Start.Add(*duration)
+				case event.AllDay:
+					event.End = event.Start.AddDate(0, 0, 1)
+				default:
+					event.End = event.Start
+				}
+			}
+			if event.UID == "" {
+				event.UID = fmt.Sprintf("%s/%s", event.Start.Format(time.RFC3339), event.End.Format(time.RFC3339))
+			}
+			if !event.Start.IsZero() {
+				events = append(events, *event)
+			}
+			event = nil
+		case nested > 0:
+		case name == "UID":
+			event.UID = value
+		case name == "SUMMARY":
+			event.Summary = unescapeText(value)
+		case name == "DESCRIPTION":
+			event.Description = unescapeText(value)
+		case name == "STATUS":
+			event.Status = strings.ToUpper(value)
+		case name == "SEQUENCE":
+			event.Sequence, _ = strconv.Atoi(value)
+		case name == "LAST-MODIFIED":
+			event.LastModified, _, _ = parseTime(value, params)
+		case name == "DTSTART":
+			if event.Start, event.AllDay, err = parseTime(value, params); err != nil {
+				return nil, fmt.Errorf("line %d: invalid DTSTART: %w", n+1, err)
+			}
+		case name == "DTEND":
+			if event.End, _, err = parseTime(value, params); err != nil {
+				return nil, fmt.Errorf("line %d: invalid DTEND: %w", n+1, err)
+			}
+			hasEnd = true
+		case name == "DURATION":
+			d, err := parseDuration(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid DURATION: %w", n+1, err)
+			}
+			duration = &d
+		}
+	}
+	return events, nil
+}
+
+// unfold reads the content lines of a document, joining folded continuation lines.
+// Both CRLF and bare LF line breaks are accepted.
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), DefaultMaxBytes)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if len(lines) == 0 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %w", err)
+	}
+	return lines, nil
+}
+
+// splitContentLine splits a content line into its upper-cased property name, its parameters
+// keyed by upper-cased name and its value. Colons inside quoted parameter values are skipped.
+func splitContentLine(line string) (string, map[string]string, string) {
+	quoted := false
+	colon := -1
+	for i := 0; i < len(line) && colon < 0; i++ {
+		switch line[i] {
+		case '"':
+			quoted = !quoted
+		case ':':
+			if !quoted {
+				colon = i
+			}
+		}
+	}
+	if colon < 0 {
+		return strings.ToUpper(line), nil, ""
+	}
+	parts := strings.Split(line[:colon], ";")
+	params := map[string]string{}
+	for _, param := range parts[1:] {
+		if key, value, ok := strings.Cut(param, "="); ok {
+			params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, line[colon+1:]
+}
+
+// parseTime reads a DATE or DATE-TIME value and reports whether it was a date.
+func parseTime(value string, params map[string]string) (time.Time, bool, error) {
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err := time.Parse("20060102", value)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+	loc := time.UTC
+	if tzid := params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
+
+// parseDuration reads a DURATION value such as P1D, PT3H30M or P1W.
+func parseDuration(value string) (time.Duration, error) {
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(value, "-"):
+		sign, value = -1, value[1:]
+	case strings.HasPrefix(value, "+"):
+		value = value[1:]
+	}
+	rest, ok := strings.CutPrefix(value, "P")
+	if !ok || rest == "" {
+		return 0, fmt.Errorf("%q is not a duration", value)
+	}
+	units := map[byte]time.Duration{'W': 7 * 24 * time.Hour, 'D': 24 * time.Hour}
+	var total time.Duration
+	number := ""
+	for i := 0; i < len(rest); i++ {
+		ch := rest[i]
+		switch {
+		case ch >= '0' && ch <= '9':
+			number += string(ch)
+		case ch == 'T':
+			units = map[byte]time.Duration{'H': time.Hour, 'M': time.Minute, 'S': time.Second}
+		default:
+			unit, known := units[ch]
+			n, err := strconv.Atoi(number)
+			if !known || err != nil {
+				return 0, fmt.Errorf("%q is not a duration", value)
+			}
+			total += time.Duration(n) * unit
+			number = ""
+		}
+	}
+	if number != "" {
+		return 0, fmt.Errorf("%q is not a duration", value)
+	}
+	return sign * total, nil
+}
+
+// unescapeText reverses the escaping of a TEXT value.
+func unescapeText(s string) string {
+	return strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n").Replace(s)
+}
@@ -0,0 +1,108 @@
+package calsync
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testFeed = "\ufeffBEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//Example Platform//EN\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:abc-1@example.com\r\n" +
+	"DTSTART;VALUE=DATE:20300503\r\n" +
+	"DTEND;VALUE=DATE:20300506\r\n" +
+	"SUMMARY:Reserved\\, guest\r\n" +
+	"  from Example\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"TRIGGER:-PT15M\r\n" +
+	"DESCRIPTION:Alarm\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:abc-2@example.com\r\n" +
+	"DTSTART;TZID=\"Europe/Berlin\":20300510T150000\r\n" +
+	"DURATION:P2DT20H\r\n" +
+	"STATUS:cancelled\r\n" +
+	"END:VEVENT\n" +
+	"BEGIN:VEVENT\n" +
+	"UID:abc-3@example.com\n" +
+	"DTSTART:20300520\n" +
+	"END:VEVENT\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParse(t *testing.T) {
+	events, err := Parse(strings.NewReader(testFeed))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %+v", events)
+	}
+
+	first := events[0]
+	if first.UID != "abc-1@example.com" || !first.AllDay || first.Summary != "Reserved, guest from Example" || first.Description != "" ||
+		!first.Start.Equal(time.Date(2030, 5, 3, 0, 0, 0, 0, time.UTC)) || !first.End.Equal(time.Date(2030, 5, 6, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected first event: %+v", first)
+	}
+	second := events[1]
+	if second.AllDay || second.Status != "CANCELLED" || second.Start.UTC() != time.Date(2030, 5, 10, 13, 0, 0, 0, time.UTC) ||
+		second.End.UTC() != time.Date(2030, 5, 13, 9, 0, 0, 0, time.UTC) {
+		t.Fatalf("unexpected second event: %+v", second)
+	}
+	if third := events[2]; !third.AllDay || !third.End.Equal(time.Date(2030, 5, 21, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected an all-day event without an end to last a day, got %+v", third)
+	}
+
+	if _, err := Parse(strings.NewReader("<html></html>")); err == nil {
+		t.Fatalf("expected a document that is not a calendar to be rejected")
+	}
+	if _, err := Parse(strings.NewReader("BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART:tomorrow\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n")); err == nil {
+		t.Fatalf("expected an unreadable date to be rejected")
+	}
+}
+
+func TestFetch_ConditionalRequests(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/feed.ics":
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("Content-Type", "text/calendar")
+			w.Write([]byte(testFeed))
+		case "/big.ics":
+			w.Write([]byte("BEGIN:VCALENDAR\r\n" + strings.Repeat("X-PADDING:0123456789\r\n", 100) + "END:VCALENDAR\r\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	client := &Client{MaxBytes: 1024}
+
+	feed, err := client.Fetch(context.Background(), server.URL+"/feed.ics", "", "")
+	if err != nil || feed.NotModified || feed.ETag != `"v1"` || len(feed.Events) != 3 {
+		t.Fatalf("unexpected first download: %+v err=%v", feed, err)
+	}
+	feed, err = client.Fetch(context.Background(), server.URL+"/feed.ics", `"v1"`, "")
+	if err != nil || !feed.NotModified || feed.ETag != `"v1"` || feed.Events != nil {
+		t.Fatalf("expected an unchanged feed, got %+v err=%v", feed, err)
+	}
+	if _, err := client.Fetch(context.Background(), server.URL+"/missing.ics", "", ""); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("expected the status in the error, got %v", err)
+	}
+	if _, err := client.Fetch(context.Background(), server.URL+"/big.ics", "", ""); err == nil || !strings.Contains(err.Error(), "larger than") {
+		t.Fatalf("expected an oversized feed to be rejected, got %v", err)
+	}
+	if requests != 4 {
+		t.Fatalf("expected 4 requests, got %d", requests)
+	}
+}
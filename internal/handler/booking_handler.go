@@ -3,10 +3,15 @@
 package handler
 
 import (
+	"errors"
+	"industry-api/internal/middleware"
 	"industry-api/internal/models"
 	"industry-api/internal/response"
 	"industry-api/internal/service"
+	"io"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -24,6 +29,7 @@ func NewBookingHandler(svc *service.BookingService) *BookingHandler {
 
 // AddBooking handles HTTP POST requests to create a new booking.
 // It validates the incoming request, converts it to a Booking model, and calls the service layer.
+// The booking is made for the caller; staff may make it for another user with user_id.
 // Returns a 201 Created status with the created booking or an error response.
 func (h *BookingHandler) AddBooking(c *gin.Context) {
 	// Parse and validate the JSON request body
@@ -33,33 +39,395 @@ func (h *BookingHandler) AddBooking(c *gin.Context) {
 		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, err.Error())
 		return
 	}
+	userID, ok := bookingUserID(c, req.UserID)
+	if !ok {
+		return
+	}
 	// Additional validation for required fields
-	if req.UserID == 0 || req.RoomID == 0 || req.CheckInDate.IsZero() || req.CheckOutDate.IsZero() || req.Adults == 0 || req.TotalAmount == 0 {
+	if req.RoomID == 0 || req.CheckInDate.IsZero() || req.CheckOutDate.IsZero() || req.Adults == 0 {
 		response.JSON(c, http.StatusBadRequest, false, "all fields are required", nil, "missing required fields")
+		return
 	}
 
 	// Convert request to domain model
 	booking := &models.Booking{
-		UserID:          req.UserID,
+		UserID:          userID,
 		RoomID:          req.RoomID,
 		CheckInDate:     req.CheckInDate,
 		CheckOutDate:    req.CheckOutDate,
 		Adults:          req.Adults,
 		Children:        req.Children,
 		SpecialRequests: req.SpecialRequests,
-		Status:          req.Status,
-		PaymentStatus:   req.PaymentStatus,
 		TotalAmount:     req.TotalAmount,
+		PromoCodes:      req.PromoCodes,
 	}
 
 	// Call service to create the booking
 	createdBooking, err := h.svc.AddBooking(c, booking)
 	if err != nil {
-		// Return 500 Internal Server Error if service call fails
-		response.JSON(c, http.StatusInternalServerError, false, "failed to add booking", nil, err.Error())
+		// Return 409 Conflict with the dates already taken if the room is booked
+		var conflict *service.BookingConflictError
+		if errors.As(err, &conflict) {
+			response.JSON(c, http.StatusConflict, false, "room is already booked for the selected dates", conflict.Conflict, err.Error())
+			return
+		}
+		respondError(c, "failed to add booking", err)
 		return
 	}
 	// Return 201 Created with the newly created booking
 	response.JSON(c, http.StatusCreated, true, "booking added successfully", createdBooking, "")
 
 }
+
+// ConfirmBooking handles HTTP POST requests that confirm a pending booking.
+func (h *BookingHandler) ConfirmBooking(c *gin.Context) {
+	h.changeStatus(c, models.BookingStatusConfirmed, "booking confirmed successfully")
+}
+
+// CheckInBooking handles HTTP POST requests that check the guest of a confirmed booking in.
+// The optional body may assign another room or allow an early check-in.
+// Returns 409 Conflict if the booking is not confirmed, the date does not fit or the room is not ready.
+func (h *BookingHandler) CheckInBooking(c *gin.Context) {
+	var req models.CheckInRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, err.Error())
+		return
+	}
+	booking, ok := h.accessibleBooking(c)
+	if !ok {
+		return
+	}
+
+	checkedIn, err := h.svc.CheckIn(c, booking.ID, &req, currentUserID(c), middleware.PropertyIDs(c))
+	if err != nil {
+		var conflict *service.BookingConflictError
+		if errors.As(err, &conflict) {
+			response.JSON(c, http.StatusConflict, false, "room is already booked for the selected dates", conflict.Conflict, err.Error())
+			return
+		}
+		respondError(c, "failed to check in booking", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "booking checked in successfully", checkedIn, "")
+}
+
+// CheckOutBooking handles HTTP POST requests that check the guest of a booking out and flag
+// the room for housekeeping. Returns 409 Conflict if a balance is outstanding and not overridden.
+func (h *BookingHandler) CheckOutBooking(c *gin.Context) {
+	var req models.CheckOutRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, err.Error())
+		return
+	}
+	booking, ok := h.accessibleBooking(c)
+	if !ok {
+		return
+	}
+
+	checkedOut, err := h.svc.CheckOut(c, booking.ID, &req, currentUserID(c))
+	if err != nil {
+		respondError(c, "failed to check out booking", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "booking checked out successfully", checkedOut, "")
+}
+
+// CancelBooking handles HTTP POST requests that cancel a pending or confirmed booking.
+// The response carries the penalty and refund of the cancellation policy; with ?preview=true
+// the quote is returned without cancelling. Guests may only cancel their own bookings.
+func (h *BookingHandler) CancelBooking(c *gin.Context) {
+	preview, err := strconv.ParseBool(c.DefaultQuery("preview", "false"))
+	if err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, "preview must be true or false")
+		return
+	}
+	var req models.BookingTransitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, err.Error())
+		return
+	}
+	booking, ok := h.accessibleBooking(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.CancelBooking(c, booking.ID, currentUserID(c), req.Reason, preview)
+	if err != nil {
+		respondError(c, "failed to cancel booking", err)
+		return
+	}
+	if preview {
+		response.JSON(c, http.StatusOK, true, "cancellation preview computed successfully", result, "")
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "booking cancelled successfully", result, "")
+}
+
+// MarkNoShow handles HTTP POST requests that record a confirmed booking's guest as a no-show.
+func (h *BookingHandler) MarkNoShow(c *gin.Context) {
+	h.changeStatus(c, models.BookingStatusNoShow, "booking marked as no-show successfully")
+}
+
+// ModifyBooking handles HTTP PATCH requests that change the room, dates or guests of a booking.
+// The response carries the repriced booking and the change log entry with the balance owed or
+// refund due. Returns 409 Conflict if the booking can no longer be changed or the room is taken.
+func (h *BookingHandler) ModifyBooking(c *gin.Context) {
+	var req models.BookingUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, err.Error())
+		return
+	}
+	booking, ok := h.accessibleBooking(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.ModifyBooking(c, booking.ID, &req, currentUserID(c), middleware.PropertyIDs(c))
+	if err != nil {
+		var conflict *service.BookingConflictError
+		if errors.As(err, &conflict) {
+			response.JSON(c, http.StatusConflict, false, "room is already booked for the selected dates", conflict.Conflict, err.Error())
+			return
+		}
+		respondError(c, "failed to modify booking", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "booking modified successfully", result, "")
+}
+
+// GetBookingChanges handles HTTP GET requests for the versioned change log of a booking.
+func (h *BookingHandler) GetBookingChanges(c *gin.Context) {
+	booking, ok := h.accessibleBooking(c)
+	if !ok {
+		return
+	}
+	changes, err := h.svc.Changes(c, booking.ID)
+	if err != nil {
+		respondError(c, "failed to get booking changes", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "booking changes fetched successfully", changes, "")
+}
+
+// GetBookingHistory handles HTTP GET requests for the status history of a booking.
+func (h *BookingHandler) GetBookingHistory(c *gin.Context) {
+	booking, ok := h.accessibleBooking(c)
+	if !ok {
+		return
+	}
+	history, err := h.svc.History(c, booking.ID)
+	if err != nil {
+		respondError(c, "failed to get booking history", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "booking history fetched successfully", history, "")
+}
+
+// changeStatus applies a lifecycle transition to the booking in the id path parameter.
+// The optional JSON body carries a reason, and the authenticated user is recorded as the actor.
+// Returns 409 Conflict if the transition is not allowed from the booking's current status.
+func (h *BookingHandler) changeStatus(c *gin.Context, to, message string) {
+	// An empty body is allowed; the reason is optional
+	var req models.BookingTransitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, err.Error())
+		return
+	}
+	booking, ok := h.accessibleBooking(c)
+	if !ok {
+		return
+	}
+
+	updated, err := h.svc.ChangeStatus(c, booking.ID, to, currentUserID(c), req.Reason)
+	if err != nil {
+		respondError(c, "failed to update booking status", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, message, updated, "")
+}
+
+// currentUserID returns the authenticated caller's user ID, or nil for anonymous requests.
+func currentUserID(c *gin.Context) *int {
+	if userID, _, ok := middleware.CurrentUser(c); ok {
+		return &userID
+	}
+	return nil
+}
+
+// bookingUserID returns the user a booking is made for: the caller, or for staff the requested
+// user if one is given. It writes a 403 response and returns false when any other caller asks to
+// book for someone else.
+func bookingUserID(c *gin.Context, requested int) (int, bool) {
+	userID, role, _ := middleware.CurrentUser(c)
+	if requested == 0 || requested == userID {
+		return userID, true
+	}
+	if role != models.RoleAdmin && role != models.RoleStaff {
+		response.JSON(c, http.StatusForbidden, false, "forbidden", nil, "only staff may book for another user")
+		return 0, false
+	}
+	return requested, true
+}
+
+// accessibleBooking loads the booking in the id path parameter and checks the caller may act on it.
+func (h *BookingHandler) accessibleBooking(c *gin.Context) (*models.Booking, bool) {
+	return loadAccessibleBooking(c, h.svc)
+}
+
+// loadAccessibleBooking loads the booking in the id path parameter and checks the caller may act
+// on it: guests only reach their own bookings and staff only the bookings of their properties.
+// It writes the error response and returns false otherwise.
+func loadAccessibleBooking(c *gin.Context, svc *service.BookingService) (*models.Booking, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid ID", nil, "ID must be a valid integer")
+		return nil, false
+	}
+	booking, err := svc.GetBooking(c, id)
+	if err != nil {
+		respondError(c, "failed to get booking", err)
+		return nil, false
+	}
+	if !canAccessBooking(c, booking) {
+		response.JSON(c, http.StatusNotFound, false, "failed to get booking", nil, "booking not found")
+		return nil, false
+	}
+	return booking, true
+}
+
+// canAccessBooking reports whether the caller may see a booking: guests only their own, staff
+// those of the properties they are assigned to.
+func canAccessBooking(c *gin.Context, booking *models.Booking) bool {
+	userID, role, _ := middleware.CurrentUser(c)
+	return (role != models.RoleGuest || booking.UserID == userID) && canAccessProperty(c, booking.PropertyID)
+}
+
+// BookingListQuery represents the query parameters of the booking list.
+// Dates use the YYYY-MM-DD format and ranges are inclusive.
+type BookingListQuery struct {
+	UserID        *int     `form:"user_id"`                                 // Guest whose bookings to list
+	RoomID        *int     `form:"room_id"`                                 // Room whose bookings to list
+	Status        []string `form:"status"`                                  // Booking statuses (repeated or comma-separated)
+	PaymentStatus string   `form:"payment_status"`                          // Payment status
+	CheckInFrom   string   `form:"check_in_from"`                           // Earliest arrival date
+	CheckInTo     string   `form:"check_in_to"`                             // Latest arrival date
+	CheckOutFrom  string   `form:"check_out_from"`                          // Earliest departure date
+	CheckOutTo    string   `form:"check_out_to"`                            // Latest departure date
+	CreatedFrom   string   `form:"created_from"`                            // Earliest creation date
+	CreatedTo     string   `form:"created_to"`                              // Latest creation date
+	Sort          string   `form:"sort"`                                    // Sort field
+	Order         string   `form:"order"`                                   // Sort direction (asc or desc)
+	Page          int      `form:"page" binding:"omitempty,min=1"`          // Page number
+	Limit         int      `form:"limit" binding:"omitempty,min=1,max=100"` // Bookings per page
+}
+
+// GetBooking handles HTTP GET requests for a single booking.
+// Guests only see their own bookings and staff only the bookings of their properties.
+func (h *BookingHandler) GetBooking(c *gin.Context) {
+	booking, ok := h.accessibleBooking(c)
+	if !ok {
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "booking fetched successfully", booking, "")
+}
+
+// ListBookings handles HTTP GET requests for the filtered, sorted and paginated booking list.
+// Guests always get their own bookings, whatever user_id they pass.
+func (h *BookingHandler) ListBookings(c *gin.Context) {
+	var req BookingListQuery
+	// Parse and validate query parameters
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, err.Error())
+		return
+	}
+
+	filter := models.BookingFilter{
+		PropertyIDs:   middleware.PropertyIDs(c),
+		UserID:        req.UserID,
+		RoomID:        req.RoomID,
+		Statuses:      splitList(req.Status),
+		PaymentStatus: req.PaymentStatus,
+		Sort:          req.Sort,
+		Order:         req.Order,
+		Page:          req.Page,
+		Limit:         req.Limit,
+	}
+	dates := []struct {
+		name  string
+		value string
+		dst   **time.Time
+	}{
+		{"check_in_from", req.CheckInFrom, &filter.CheckInFrom},
+		{"check_in_to", req.CheckInTo, &filter.CheckInTo},
+		{"check_out_from", req.CheckOutFrom, &filter.CheckOutFrom},
+		{"check_out_to", req.CheckOutTo, &filter.CheckOutTo},
+		{"created_from", req.CreatedFrom, &filter.CreatedFrom},
+		{"created_to", req.CreatedTo, &filter.CreatedTo},
+	}
+	for _, d := range dates {
+		if d.value == "" {
+			continue
+		}
+		parsed, err := time.Parse("2006-01-02", d.value)
+		if err != nil {
+			response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, d.name+" must be in YYYY-MM-DD format")
+			return
+		}
+		*d.dst = &parsed
+	}
+	// Guests only ever see their own bookings
+	if userID, role, ok := middleware.CurrentUser(c); ok && role == models.RoleGuest {
+		filter.UserID = &userID
+	}
+
+	result, err := h.svc.ListBookings(c, filter)
+	if err != nil {
+		respondError(c, "failed to list bookings", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "bookings fetched successfully", result, "")
+}
+
+// GetArrivals handles HTTP GET requests for the bookings arriving on ?date= (today by default).
+func (h *BookingHandler) GetArrivals(c *gin.Context) {
+	date, ok := frontDeskDate(c)
+	if !ok {
+		return
+	}
+	bookings, err := h.svc.Arrivals(c, date, middleware.PropertyIDs(c))
+	if err != nil {
+		respondError(c, "failed to get arrivals", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "arrivals fetched successfully", bookings, "")
+}
+
+// GetDepartures handles HTTP GET requests for the bookings departing on ?date= (today by default).
+func (h *BookingHandler) GetDepartures(c *gin.Context) {
+	date, ok := frontDeskDate(c)
+	if !ok {
+		return
+	}
+	bookings, err := h.svc.Departures(c, date, middleware.PropertyIDs(c))
+	if err != nil {
+		respondError(c, "failed to get departures", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "departures fetched successfully", bookings, "")
+}
+
+// frontDeskDate parses the date query parameter of the arrivals and departures lists,
+// defaulting to today. It writes a 400 response and returns false if the date is invalid.
+func frontDeskDate(c *gin.Context) (time.Time, bool) {
+	value := c.Query("date")
+	if value == "" {
+		now := time.Now()
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), true
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, "date must be in YYYY-MM-DD format")
+		return time.Time{}, false
+	}
+	return date, true
+}
@@ -4,63 +4,196 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"testing"
 	"time"
 
+	"industry-api/internal/middleware"
 	"industry-api/internal/models"
+	"industry-api/internal/repository"
 	"industry-api/internal/service"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 )
 
 type mockBookingSvcRepo struct {
-	add func(ctx context.Context, b *models.Booking) (*models.Booking, error)
+	add     func(ctx context.Context, b *models.Booking) (*models.Booking, error)
+	booking *models.Booking
+	filter  models.BookingFilter
 }
 
 func (m *mockBookingSvcRepo) AddBooking(ctx context.Context, b *models.Booking) (*models.Booking, error) {
 	return m.add(ctx, b)
 }
+func (m *mockBookingSvcRepo) GetRoomByID(ctx context.Context, roomID int) (*models.Room, error) {
+	return &models.Room{ID: roomID, RoomNumber: "101", Price: 100, Capacity: 4, HousekeepingStatus: models.HousekeepingInspected}, nil
+}
+
+func (m *mockBookingSvcRepo) GetBookingByID(ctx context.Context, id int) (*models.Booking, error) {
+	if m.booking == nil || m.booking.ID != id {
+		return nil, fmt.Errorf("booking %w", repository.ErrNotFound)
+	}
+	copied := *m.booking
+	return &copied, nil
+}
+func (m *mockBookingSvcRepo) SetBookingStatus(ctx context.Context, id int, from, to string, changedBy *int, reason string) (*models.Booking, error) {
+	m.booking.Status = to
+	copied := *m.booking
+	return &copied, nil
+}
+func (m *mockBookingSvcRepo) GetPaidAmount(ctx context.Context, bookingID int) (int64, error) {
+	return 0, nil
+}
+func (m *mockBookingSvcRepo) CancelBooking(ctx context.Context, id int, from string, quote *models.CancellationQuote, changedBy *int, reason string) (*models.Booking, error) {
+	m.booking.Status = models.BookingStatusCancelled
+	m.booking.Cancellation = quote
+	copied := *m.booking
+	return &copied, nil
+}
+func (m *mockBookingSvcRepo) ModifyBooking(ctx context.Context, b *models.Booking, change *models.BookingChange) (*models.Booking, error) {
+	b.Version = change.Version
+	*m.booking = *b
+	return b, nil
+}
+func (m *mockBookingSvcRepo) ListBookingChanges(ctx context.Context, id int) ([]models.BookingChange, error) {
+	return []models.BookingChange{}, nil
+}
+func (m *mockBookingSvcRepo) ListBookings(ctx context.Context, filter models.BookingFilter) (*models.BookingListResponse, error) {
+	m.filter = filter
+	return &models.BookingListResponse{Page: filter.Page, Limit: filter.Limit, Bookings: []models.Booking{}}, nil
+}
+func (m *mockBookingSvcRepo) CheckInBooking(ctx context.Context, b *models.Booking, roomID int, changedBy *int, reason string) (*models.Booking, error) {
+	m.booking.Status = models.BookingStatusCheckedIn
+	m.booking.RoomID = roomID
+	copied := *m.booking
+	return &copied, nil
+}
+func (m *mockBookingSvcRepo) CheckOutBooking(ctx context.Context, b *models.Booking, changedBy *int, reason string) (*models.Booking, error) {
+	m.booking.Status = models.BookingStatusCheckedOut
+	copied := *m.booking
+	return &copied, nil
+}
+func (m *mockBookingSvcRepo) ExpireHolds(ctx context.Context, now time.Time, limit int) ([]int, error) {
+	return nil, nil
+}
+func (m *mockBookingSvcRepo) ListNoShowCandidates(ctx context.Context, now time.Time, cutoff time.Duration, limit int) ([]models.Booking, error) {
+	return nil, nil
+}
+func (m *mockBookingSvcRepo) MarkNoShow(ctx context.Context, id int, quote *models.CancellationQuote, changedBy *int, reason string) (*models.Booking, error) {
+	m.booking.Status = models.BookingStatusNoShow
+	m.booking.Cancellation = quote
+	copied := *m.booking
+	return &copied, nil
+}
+func (m *mockBookingSvcRepo) GetPromoCodes(ctx context.Context, codes []string) ([]models.PromoCode, error) {
+	return nil, nil
+}
+func (m *mockBookingSvcRepo) ListBookingPromoCodes(ctx context.Context, bookingID int) ([]models.PromoCode, error) {
+	return nil, nil
+}
+func (m *mockBookingSvcRepo) GetFolioTotal(ctx context.Context, bookingID int) (float64, error) {
+	return 0, nil
+}
+func (m *mockBookingSvcRepo) GetApplicableTaxRates(ctx context.Context, propertyID int, checkIn, checkOut time.Time) ([]models.TaxRate, error) {
+	return nil, nil
+}
+func (m *mockBookingSvcRepo) ListBookingStatusHistory(ctx context.Context, id int) ([]models.BookingStatusChange, error) {
+	return []models.BookingStatusChange{}, nil
+}
+
+// staticPropertyResolver assigns staff to fixed properties.
+type staticPropertyResolver map[int][]int
+
+func (r staticPropertyResolver) UserPropertyIDs(ctx context.Context, userID int) ([]int, error) {
+	return r[userID], nil
+}
+
+// addBookingRequest posts a booking request through Authenticate as the given caller.
+func addBookingRequest(h *BookingHandler, callerID int, role string, body models.BookingRequest) *httptest.ResponseRecorder {
+	os.Setenv("JWT_SECRET", "booking-handler-test-secret")
+	r := gin.New()
+	r.POST("/api/v1/bookings/add", middleware.Authenticate(), h.AddBooking)
+	b, _ := json.Marshal(body)
+	req, _ := http.NewRequest("POST", "/api/v1/bookings/add", bytes.NewBuffer(b))
+	req.Header.Set("Content-Type", "application/json")
+	claims := jwt.MapClaims{"user_id": fmt.Sprint(callerID), "role": role, "exp": time.Now().Add(time.Hour).Unix()}
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(os.Getenv("JWT_SECRET")))
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
 
 func TestAddBookingHandler_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mr := &mockBookingSvcRepo{
 		add: func(ctx context.Context, b *models.Booking) (*models.Booking, error) { b.ID = 1; return b, nil },
 	}
-	h := NewBookingHandler(service.NewBookingService(mr))
+	h := NewBookingHandler(service.NewBookingService(mr, service.DefaultBookingConfig))
 
 	reqBody := models.BookingRequest{
-		UserID:        1,
-		RoomID:        1,
-		CheckInDate:   time.Now(),
-		CheckOutDate:  time.Now().Add(24 * time.Hour),
-		Adults:        2,
-		Children:      1,
-		TotalAmount:   200,
-		Status:        "pending",
-		PaymentStatus: "pending",
+		RoomID:       1,
+		CheckInDate:  time.Now(),
+		CheckOutDate: time.Now().Add(24 * time.Hour),
+		Adults:       2,
+		Children:     1,
 	}
-	b, _ := json.Marshal(reqBody)
 
-	w := httptest.NewRecorder()
-	c, _ := gin.CreateTestContext(w)
-	c.Request, _ = http.NewRequest("POST", "/api/v1/bookings/add", bytes.NewBuffer(b))
-	c.Request.Header.Set("Content-Type", "application/json")
-
-	h.AddBooking(c)
+	w := addBookingRequest(h, 1, models.RoleGuest, reqBody)
 
 	if w.Code != http.StatusCreated {
 		t.Fatalf("expected 201, got %d body=%s", w.Code, w.Body.String())
 	}
+	// one night at 100 plus one child beyond the two included guests
+	var resp struct {
+		Data models.Booking `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("json unmarshal: %v", err)
+	}
+	if resp.Data.TotalAmount != 110 || resp.Data.PriceBreakdown == nil || len(resp.Data.PriceBreakdown.Lines) != 2 {
+		t.Fatalf("expected server-computed price, got %+v", resp.Data)
+	}
+}
+
+func TestAddBookingHandler_BooksForCaller(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var stored *models.Booking
+	mr := &mockBookingSvcRepo{
+		add: func(ctx context.Context, b *models.Booking) (*models.Booking, error) { stored = b; return b, nil },
+	}
+	h := NewBookingHandler(service.NewBookingService(mr, service.DefaultBookingConfig))
+	in := time.Now().AddDate(0, 0, 10)
+	book := func(callerID int, role string, userID int) int {
+		body := models.BookingRequest{UserID: userID, RoomID: 1, CheckInDate: in, CheckOutDate: in.AddDate(0, 0, 1), Adults: 1, Children: 1}
+		return addBookingRequest(h, callerID, role, body).Code
+	}
+
+	if code := book(4, models.RoleGuest, 0); code != http.StatusCreated || stored.UserID != 4 {
+		t.Fatalf("expected a booking for the caller, got %d user=%d", code, stored.UserID)
+	}
+	stored = nil
+	if code := book(4, models.RoleGuest, 9); code != http.StatusForbidden || stored != nil {
+		t.Fatalf("expected 403 for a guest booking for someone else, got %d", code)
+	}
+	if code := book(5, models.RoleStaff, 9); code != http.StatusCreated || stored.UserID != 9 {
+		t.Fatalf("expected staff to book for the guest, got %d", code)
+	}
 }
 
 func TestAddBookingHandler_ValidationError(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mr := &mockBookingSvcRepo{
-		add: func(ctx context.Context, b *models.Booking) (*models.Booking, error) { return b, nil },
+		add: func(ctx context.Context, b *models.Booking) (*models.Booking, error) {
+			t.Fatal("a booking with missing fields must not be stored")
+			return b, nil
+		},
 	}
-	h := NewBookingHandler(service.NewBookingService(mr))
+	h := NewBookingHandler(service.NewBookingService(mr, service.DefaultBookingConfig))
 
 	// missing required fields
 	reqBody := models.BookingRequest{UserID: 0, RoomID: 0}
@@ -76,4 +209,153 @@ func TestAddBookingHandler_ValidationError(t *testing.T) {
 	if w.Code != http.StatusBadRequest {
 		t.Fatalf("expected 400, got %d", w.Code)
 	}
+	// only the handler's own response is written
+	var resp map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("expected a single validation response, got %s", w.Body.String())
+	}
+}
+
+func TestAddBookingHandler_ConflictReturns409(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	in := time.Now().AddDate(0, 0, 10)
+	mr := &mockBookingSvcRepo{
+		add: func(ctx context.Context, b *models.Booking) (*models.Booking, error) {
+			return nil, &service.BookingConflictError{Conflict: models.BookingConflict{BookingID: 7, RoomID: b.RoomID, CheckInDate: in, CheckOutDate: in.AddDate(0, 0, 2)}}
+		},
+	}
+	h := NewBookingHandler(service.NewBookingService(mr, service.DefaultBookingConfig))
+
+	reqBody := models.BookingRequest{RoomID: 1, CheckInDate: in, CheckOutDate: in.AddDate(0, 0, 1), Adults: 2, Children: 1}
+
+	w := addBookingRequest(h, 1, models.RoleGuest, reqBody)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d body=%s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data models.BookingConflict `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("json unmarshal: %v", err)
+	}
+	if resp.Data.BookingID != 7 || resp.Data.CheckInDate.IsZero() || resp.Data.CheckOutDate.IsZero() {
+		t.Fatalf("expected conflicting dates in response, got %+v", resp.Data)
+	}
+}
+
+func TestBookingTransitions_GuestsCancelOnlyTheirOwn(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	os.Setenv("JWT_SECRET", "booking-handler-test-secret")
+	mr := &mockBookingSvcRepo{booking: &models.Booking{ID: 4, UserID: 5, PropertyID: 1, Status: models.BookingStatusPending}}
+	h := NewBookingHandler(service.NewBookingService(mr, service.DefaultBookingConfig))
+
+	r := gin.New()
+	r.POST("/bookings/:id/cancel", middleware.Authenticate(), middleware.PropertyScope(nil), h.CancelBooking)
+	r.POST("/bookings/:id/confirm", middleware.Authenticate(), middleware.PropertyScope(nil), h.ConfirmBooking)
+	send := func(path string, userID int, body string) *httptest.ResponseRecorder {
+		claims := jwt.MapClaims{"user_id": fmt.Sprint(userID), "role": models.RoleGuest, "exp": time.Now().Add(time.Hour).Unix()}
+		token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(os.Getenv("JWT_SECRET")))
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", path, bytes.NewBufferString(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := send("/bookings/4/cancel", 9, ""); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for another guest's booking, got %d body=%s", w.Code, w.Body.String())
+	}
+	if w := send("/bookings/4/cancel?preview=true", 5, ""); w.Code != http.StatusOK || mr.booking.Status != models.BookingStatusPending {
+		t.Fatalf("expected a preview that leaves the booking pending, got %d status=%s", w.Code, mr.booking.Status)
+	}
+	if w := send("/bookings/4/cancel", 5, `{"reason":"change of plans"}`); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	if mr.booking.Status != models.BookingStatusCancelled {
+		t.Fatalf("expected booking to be cancelled, got %s", mr.booking.Status)
+	}
+	// cancelled is final
+	if w := send("/bookings/4/confirm", 5, ""); w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 confirming a cancelled booking, got %d body=%s", w.Code, w.Body.String())
+	}
+}
+
+func TestModifyBookingHandler_Reprices(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	in := time.Now().AddDate(0, 1, 0)
+	mr := &mockBookingSvcRepo{booking: &models.Booking{ID: 6, UserID: 5, RoomID: 1, PropertyID: 1, Status: models.BookingStatusPending, CheckInDate: in, CheckOutDate: in.AddDate(0, 0, 1), Adults: 1, TotalAmount: 100, Version: 1}}
+	h := NewBookingHandler(service.NewBookingService(mr, service.DefaultBookingConfig))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: "6"}}
+	c.Request, _ = http.NewRequest("PATCH", "/api/v1/bookings/6", bytes.NewBufferString(`{"adults":3}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	h.ModifyBooking(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	// one night at 100 plus one extra adult at 25
+	if mr.booking.TotalAmount != 125 || mr.booking.Version != 2 {
+		t.Fatalf("expected repriced booking at version 2, got %+v", mr.booking)
+	}
+}
+
+func TestListBookingsHandler_GuestsSeeOnlyTheirOwn(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	os.Setenv("JWT_SECRET", "booking-handler-test-secret")
+	mr := &mockBookingSvcRepo{}
+	h := NewBookingHandler(service.NewBookingService(mr, service.DefaultBookingConfig))
+
+	r := gin.New()
+	r.GET("/bookings", middleware.Authenticate(), middleware.PropertyScope(staticPropertyResolver{5: {1}}), h.ListBookings)
+	send := func(path, role string) *httptest.ResponseRecorder {
+		claims := jwt.MapClaims{"user_id": "5", "role": role, "exp": time.Now().Add(time.Hour).Unix()}
+		token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(os.Getenv("JWT_SECRET")))
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send("/bookings?user_id=9&status=confirmed,checked_in&check_in_from=2030-01-01&sort=check_in_date", models.RoleGuest)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	if mr.filter.UserID == nil || *mr.filter.UserID != 5 || len(mr.filter.Statuses) != 2 || mr.filter.CheckInFrom == nil {
+		t.Fatalf("expected the guest's own bookings with the requested filters, got %+v", mr.filter)
+	}
+
+	// staff may list any guest's bookings within their properties
+	if w := send("/bookings?user_id=9", models.RoleStaff); w.Code != http.StatusOK || mr.filter.UserID == nil || *mr.filter.UserID != 9 || len(mr.filter.PropertyIDs) != 1 {
+		t.Fatalf("expected staff to filter by user 9 in property 1, got %d filter=%+v", w.Code, mr.filter)
+	}
+	if w := send("/bookings?created_to=yesterday", models.RoleStaff); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an invalid date, got %d", w.Code)
+	}
+}
+
+func TestCheckOutBookingHandler_OutstandingBalance(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mr := &mockBookingSvcRepo{booking: &models.Booking{ID: 8, PropertyID: 1, RoomID: 1, Status: models.BookingStatusCheckedIn, TotalAmount: 100}}
+	h := NewBookingHandler(service.NewBookingService(mr, service.DefaultBookingConfig))
+
+	send := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "id", Value: "8"}}
+		c.Request, _ = http.NewRequest("POST", "/api/v1/bookings/8/check-out", bytes.NewBufferString(body))
+		h.CheckOutBooking(c)
+		return w
+	}
+
+	if w := send(""); w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 with nothing paid, got %d body=%s", w.Code, w.Body.String())
+	}
+	if w := send(`{"override_balance":true}`); w.Code != http.StatusOK || mr.booking.Status != models.BookingStatusCheckedOut {
+		t.Fatalf("expected check-out with override, got %d status=%s", w.Code, mr.booking.Status)
+	}
 }
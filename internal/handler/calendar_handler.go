@@ -0,0 +1,105 @@
+// Package handler contains HTTP request handlers for all API endpoints.
+// Handlers receive HTTP requests, validate input, call services, and send responses.
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"industry-api/internal/middleware"
+	"industry-api/internal/models"
+	"industry-api/internal/response"
+	"industry-api/internal/service"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CalendarHandler handles HTTP requests related to iCalendar feeds.
+type CalendarHandler struct {
+	svc *service.CalendarService // Service layer for business logic
+}
+
+// NewCalendarHandler creates and returns a new instance of CalendarHandler.
+// It accepts a CalendarService dependency for handling calendar feeds.
+func NewCalendarHandler(svc *service.CalendarService) *CalendarHandler {
+	return &CalendarHandler{svc: svc}
+}
+
+// CalendarLinkQuery represents the query parameters selecting a calendar feed.
+// At most one is given; without any the caller's own staff feed is returned.
+type CalendarLinkQuery struct {
+	RoomID     *int `form:"room_id"`     // Room whose feed to link
+	PropertyID *int `form:"property_id"` // Property whose feed to link
+	UserID     *int `form:"user_id"`     // Staff member whose feed to link (admins only for others)
+}
+
+// GetFeedLink handles HTTP GET requests for the signed subscription link of a room, property or
+// staff feed. Rooms and properties outside the caller's scope are reported as not found.
+func (h *CalendarHandler) GetFeedLink(c *gin.Context) {
+	var req CalendarLinkQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, err.Error())
+		return
+	}
+
+	userID, role, _ := middleware.CurrentUser(c)
+	scope, id, given := models.CalendarFeedStaff, userID, 0
+	if req.RoomID != nil {
+		scope, id, given = models.CalendarFeedRoom, *req.RoomID, given+1
+	}
+	if req.PropertyID != nil {
+		scope, id, given = models.CalendarFeedProperty, *req.PropertyID, given+1
+	}
+	if req.UserID != nil {
+		scope, id, given = models.CalendarFeedStaff, *req.UserID, given+1
+	}
+	if given > 1 {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, "only one of room_id, property_id and user_id may be given")
+		return
+	}
+	if scope == models.CalendarFeedStaff && id != userID && role != models.RoleAdmin {
+		response.JSON(c, http.StatusForbidden, false, "failed to get calendar feed link", nil, "only admins can link the feeds of other users")
+		return
+	}
+
+	link, err := h.svc.FeedLink(c, scope, id, middleware.PropertyIDs(c))
+	if err != nil {
+		respondError(c, "failed to get calendar feed link", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "calendar feed link fetched successfully", link, "")
+}
+
+// GetFeed handles HTTP GET requests for a calendar feed. It is public: the signed token in the
+// path authorises it. Responses carry an ETag, and a request whose If-None-Match matches the
+// current feed gets 304 Not Modified without a body.
+func (h *CalendarHandler) GetFeed(c *gin.Context) {
+	body, err := h.svc.Feed(c, strings.TrimSuffix(c.Param("token"), ".ics"))
+	if err != nil {
+		respondError(c, "failed to get calendar feed", err)
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, max-age=300")
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", body)
+}
+
+// etagMatches reports whether an If-None-Match header lists etag. Weak validators match their
+// strong counterpart, as the header is compared with the weak comparison of RFC 9110.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
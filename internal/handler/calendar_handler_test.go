@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"industry-api/internal/models"
+	"industry-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type mockCalendarSvcRepo struct {
+	entries []models.CalendarEntry
+}
+
+func (m *mockCalendarSvcRepo) ListCalendarEntries(ctx context.Context, filter models.CalendarFilter) ([]models.CalendarEntry, error) {
+	return m.entries, nil
+}
+func (m *mockCalendarSvcRepo) GetFeedUser(ctx context.Context, userID int) (string, []int, error) {
+	return models.RoleStaff, []int{1}, nil
+}
+func (m *mockCalendarSvcRepo) GetRoomPropertyID(ctx context.Context, roomID int) (int, error) {
+	return 1, nil
+}
+
+func TestGetFeed_ConditionalGet(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	start := time.Now().AddDate(0, 0, 3)
+	repo := &mockCalendarSvcRepo{entries: []models.CalendarEntry{{Kind: models.CalendarEntryBooking, ID: 1, RoomNumber: "101", Start: start, End: start.AddDate(0, 0, 2),
+		Status: models.BookingStatusConfirmed, Adults: 1, UpdatedAt: start.AddDate(0, 0, -10)}}}
+	svc := service.NewCalendarService(repo, service.CalendarConfig{Secret: []byte("secret"), FeedURL: "/calendar/feeds"})
+	link, err := svc.FeedLink(context.Background(), models.CalendarFeedRoom, 4, nil)
+	if err != nil {
+		t.Fatalf("FeedLink failed: %v", err)
+	}
+
+	r := gin.New()
+	r.GET("/calendar/feeds/:token", NewCalendarHandler(svc).GetFeed)
+	get := func(url, ifNoneMatch string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", url, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	first := get(link.URL, "")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || !strings.HasPrefix(first.Header().Get("Content-Type"), "text/calendar") || etag == "" || !strings.Contains(first.Body.String(), "UID:booking-1@industry-api") {
+		t.Fatalf("expected the feed, got %d %s %s", first.Code, first.Header().Get("Content-Type"), first.Body.String())
+	}
+	if w := get(link.URL, `"stale", W/`+etag); w.Code != http.StatusNotModified || w.Body.Len() != 0 || w.Header().Get("ETag") != etag {
+		t.Fatalf("expected 304 for a matching ETag, got %d", w.Code)
+	}
+
+	repo.entries[0].Sequence, repo.entries[0].Status = 1, models.BookingStatusPending
+	if w := get(link.URL, etag); w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+		t.Fatalf("expected a changed feed to be sent with a new ETag, got %d", w.Code)
+	}
+	if w := get("/calendar/feeds/room.5.abc.ics", ""); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected an invalid token to be rejected, got %d", w.Code)
+	}
+}
@@ -0,0 +1,27 @@
+// Package handler contains HTTP request handlers for all API endpoints.
+// Handlers receive HTTP requests, validate input, call services, and send responses.
+package handler
+
+import (
+	"errors"
+	"industry-api/internal/response"
+	"industry-api/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondError maps service errors to HTTP status codes:
+// validation errors become 400, missing records 404, state conflicts 409 and anything else 500.
+func respondError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, service.ErrValidation):
+		response.JSON(c, http.StatusBadRequest, false, message, nil, err.Error())
+	case errors.Is(err, service.ErrNotFound):
+		response.JSON(c, http.StatusNotFound, false, message, nil, err.Error())
+	case errors.Is(err, service.ErrConflict):
+		response.JSON(c, http.StatusConflict, false, message, nil, err.Error())
+	default:
+		response.JSON(c, http.StatusInternalServerError, false, message, nil, err.Error())
+	}
+}
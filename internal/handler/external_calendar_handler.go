@@ -0,0 +1,116 @@
+// Package handler contains HTTP request handlers for all API endpoints.
+// Handlers receive HTTP requests, validate input, call services, and send responses.
+package handler
+
+import (
+	"industry-api/internal/middleware"
+	"industry-api/internal/models"
+	"industry-api/internal/response"
+	"industry-api/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExternalCalendarHandler handles HTTP requests related to external calendars imported from
+// other booking platforms.
+type ExternalCalendarHandler struct {
+	svc *service.ExternalCalendarService // Service layer for business logic
+}
+
+// NewExternalCalendarHandler creates and returns a new instance of ExternalCalendarHandler.
+// It accepts an ExternalCalendarService dependency for handling external calendars.
+func NewExternalCalendarHandler(svc *service.ExternalCalendarService) *ExternalCalendarHandler {
+	return &ExternalCalendarHandler{svc: svc}
+}
+
+// AddCalendar handles HTTP POST requests that attach an external iCal feed to a room.
+// The feed is synced right away; returns 201 Created with the calendar and its sync state.
+func (h *ExternalCalendarHandler) AddCalendar(c *gin.Context) {
+	var req models.ExternalCalendarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, err.Error())
+		return
+	}
+	calendar, err := h.svc.AddCalendar(c, &models.ExternalCalendar{RoomID: req.RoomID, Name: req.Name, URL: req.URL, CreatedBy: currentUserID(c)})
+	if err != nil {
+		respondError(c, "failed to add external calendar", err)
+		return
+	}
+	response.JSON(c, http.StatusCreated, true, "external calendar added successfully", calendar, "")
+}
+
+// ListCalendars handles HTTP GET requests for the external calendars of the caller's properties
+// with the time and error of their last sync.
+func (h *ExternalCalendarHandler) ListCalendars(c *gin.Context) {
+	calendars, err := h.svc.ListCalendars(c, middleware.PropertyIDs(c))
+	if err != nil {
+		respondError(c, "failed to list external calendars", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "external calendars fetched successfully", calendars, "")
+}
+
+// ListBlocks handles HTTP GET requests for the nights an external calendar currently blocks.
+func (h *ExternalCalendarHandler) ListBlocks(c *gin.Context) {
+	calendar, ok := h.accessibleCalendar(c)
+	if !ok {
+		return
+	}
+	blocks, err := h.svc.ListBlocks(c, calendar.ID)
+	if err != nil {
+		respondError(c, "failed to list external blocks", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "external blocks fetched successfully", blocks, "")
+}
+
+// SyncCalendar handles HTTP POST requests that sync an external calendar immediately.
+// Returns the calendar with its new sync state; a failed download shows in last_error.
+func (h *ExternalCalendarHandler) SyncCalendar(c *gin.Context) {
+	calendar, ok := h.accessibleCalendar(c)
+	if !ok {
+		return
+	}
+	synced, err := h.svc.SyncCalendar(c, calendar.ID)
+	if err != nil {
+		respondError(c, "failed to sync external calendar", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "external calendar synced", synced, "")
+}
+
+// DeleteCalendar handles HTTP DELETE requests that detach an external calendar.
+// Its blocks are removed, so their nights can be booked again.
+func (h *ExternalCalendarHandler) DeleteCalendar(c *gin.Context) {
+	calendar, ok := h.accessibleCalendar(c)
+	if !ok {
+		return
+	}
+	if err := h.svc.DeleteCalendar(c, calendar.ID); err != nil {
+		respondError(c, "failed to delete external calendar", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "external calendar deleted successfully", nil, "")
+}
+
+// accessibleCalendar loads the external calendar in the id path parameter and checks it belongs
+// to one of the caller's properties. It writes the error response and returns false otherwise.
+func (h *ExternalCalendarHandler) accessibleCalendar(c *gin.Context) (*models.ExternalCalendar, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid ID", nil, "ID must be a valid integer")
+		return nil, false
+	}
+	calendar, err := h.svc.GetCalendar(c, id)
+	if err != nil {
+		respondError(c, "failed to get external calendar", err)
+		return nil, false
+	}
+	if !canAccessProperty(c, calendar.PropertyID) {
+		response.JSON(c, http.StatusNotFound, false, "failed to get external calendar", nil, "external calendar not found")
+		return nil, false
+	}
+	return calendar, true
+}
@@ -0,0 +1,93 @@
+// Package handler contains HTTP request handlers for all API endpoints.
+// Handlers receive HTTP requests, validate input, call services, and send responses.
+package handler
+
+import (
+	"industry-api/internal/models"
+	"industry-api/internal/response"
+	"industry-api/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FolioHandler handles HTTP requests related to booking folios.
+type FolioHandler struct {
+	svc      *service.FolioService   // Service layer for business logic
+	bookings *service.BookingService // Loads the bookings folios belong to
+}
+
+// NewFolioHandler creates and returns a new instance of FolioHandler.
+// It accepts the FolioService handling folios and the BookingService owning the bookings.
+func NewFolioHandler(svc *service.FolioService, bookings *service.BookingService) *FolioHandler {
+	return &FolioHandler{svc: svc, bookings: bookings}
+}
+
+// GetFolio handles HTTP GET requests for the folio of a booking with its running balance.
+// Guests only see the folios of their own bookings.
+func (h *FolioHandler) GetFolio(c *gin.Context) {
+	booking, ok := loadAccessibleBooking(c, h.bookings)
+	if !ok {
+		return
+	}
+	folio, err := h.svc.GetFolio(c, booking)
+	if err != nil {
+		respondError(c, "failed to get folio", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "folio fetched successfully", folio, "")
+}
+
+// PostEntry handles HTTP POST requests that post a charge or credit to a booking's folio.
+// Returns 201 Created with the entry, or 409 Conflict if the booking is not checked in.
+func (h *FolioHandler) PostEntry(c *gin.Context) {
+	var req models.FolioEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, err.Error())
+		return
+	}
+	booking, ok := loadAccessibleBooking(c, h.bookings)
+	if !ok {
+		return
+	}
+
+	entry, err := h.svc.PostEntry(c, booking, &models.FolioEntry{
+		Kind:        req.Kind,
+		Category:    req.Category,
+		Description: req.Description,
+		Amount:      req.Amount,
+		TaxAmount:   req.TaxAmount,
+	}, currentUserID(c))
+	if err != nil {
+		respondError(c, "failed to post folio entry", err)
+		return
+	}
+	response.JSON(c, http.StatusCreated, true, "folio entry posted successfully", entry, "")
+}
+
+// VoidEntry handles HTTP POST requests that void a folio entry with a reason.
+// Returns 409 Conflict if the entry was already voided.
+func (h *FolioHandler) VoidEntry(c *gin.Context) {
+	entryID, err := strconv.Atoi(c.Param("entryId"))
+	if err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid ID", nil, "entry ID must be a valid integer")
+		return
+	}
+	var req models.FolioVoidRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, err.Error())
+		return
+	}
+	booking, ok := loadAccessibleBooking(c, h.bookings)
+	if !ok {
+		return
+	}
+
+	entry, err := h.svc.VoidEntry(c, booking, entryID, currentUserID(c), req.Reason)
+	if err != nil {
+		respondError(c, "failed to void folio entry", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "folio entry voided successfully", entry, "")
+}
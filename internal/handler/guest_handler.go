@@ -0,0 +1,109 @@
+// Package handler contains HTTP request handlers for all API endpoints.
+// Handlers receive HTTP requests, validate input, call services, and send responses.
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"industry-api/internal/middleware"
+	"industry-api/internal/models"
+	"industry-api/internal/response"
+	"industry-api/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GuestHandler handles HTTP requests related to booking guest manifests.
+type GuestHandler struct {
+	svc      *service.GuestService   // Service layer for business logic
+	bookings *service.BookingService // Loads the bookings manifests belong to
+}
+
+// NewGuestHandler creates and returns a new instance of GuestHandler.
+// It accepts the GuestService handling manifests and the BookingService owning the bookings.
+func NewGuestHandler(svc *service.GuestService, bookings *service.BookingService) *GuestHandler {
+	return &GuestHandler{svc: svc, bookings: bookings}
+}
+
+// ListGuests handles HTTP GET requests for the guest manifest of a booking.
+// Document numbers are masked; guests only see the manifests of their own bookings.
+func (h *GuestHandler) ListGuests(c *gin.Context) {
+	booking, ok := loadAccessibleBooking(c, h.bookings)
+	if !ok {
+		return
+	}
+	guests, err := h.svc.ListGuests(c, booking.ID)
+	if err != nil {
+		respondError(c, "failed to get booking guests", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "booking guests fetched successfully", guests, "")
+}
+
+// ReplaceGuests handles HTTP PUT requests that replace the guest manifest of a booking.
+// Returns 400 Bad Request if the guests do not match the booking's adults and children.
+func (h *GuestHandler) ReplaceGuests(c *gin.Context) {
+	var req models.BookingGuestsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, err.Error())
+		return
+	}
+	booking, ok := loadAccessibleBooking(c, h.bookings)
+	if !ok {
+		return
+	}
+
+	guests, err := h.svc.ReplaceGuests(c, booking, req.Guests)
+	if err != nil {
+		respondError(c, "failed to update booking guests", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "booking guests updated successfully", guests, "")
+}
+
+// GetRegistrationReport handles HTTP GET requests for the guests staying the night of ?date=
+// (today by default) at the caller's properties, with document numbers in clear.
+// With ?format=csv the report is downloaded as a CSV file for the authorities.
+func (h *GuestHandler) GetRegistrationReport(c *gin.Context) {
+	date, ok := frontDeskDate(c)
+	if !ok {
+		return
+	}
+	registrations, err := h.svc.RegistrationReport(c, date, middleware.PropertyIDs(c))
+	if err != nil {
+		respondError(c, "failed to get registration report", err)
+		return
+	}
+	if c.Query("format") != "csv" {
+		response.JSON(c, http.StatusOK, true, "registration report fetched successfully", registrations, "")
+		return
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"booking_id", "property_id", "room_number", "check_in_date", "check_out_date", "last_name", "first_name", "nationality", "date_of_birth", "document_type", "document_number"})
+	for _, r := range registrations {
+		w.Write([]string{
+			strconv.Itoa(r.BookingID),
+			strconv.Itoa(r.PropertyID),
+			r.RoomNumber,
+			r.CheckInDate.Format("2006-01-02"),
+			r.CheckOutDate.Format("2006-01-02"),
+			r.LastName,
+			r.FirstName,
+			r.Nationality,
+			r.DateOfBirth.Format("2006-01-02"),
+			r.DocumentType,
+			r.DocumentNumber,
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		respondError(c, "failed to write registration report", err)
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="registrations-`+date.Format("2006-01-02")+`.csv"`)
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"industry-api/internal/models"
+	"industry-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type mockGuestSvcRepo struct {
+	guests []models.BookingGuest
+}
+
+func (m *mockGuestSvcRepo) ReplaceBookingGuests(ctx context.Context, bookingID int, guests []models.BookingGuest) error {
+	m.guests = guests
+	return nil
+}
+func (m *mockGuestSvcRepo) ListBookingGuests(ctx context.Context, bookingID int) ([]models.BookingGuest, error) {
+	return m.guests, nil
+}
+func (m *mockGuestSvcRepo) ListGuestRegistrations(ctx context.Context, date time.Time, propertyIDs []int) ([]models.GuestRegistration, error) {
+	registrations := []models.GuestRegistration{}
+	for _, g := range m.guests {
+		registrations = append(registrations, models.GuestRegistration{BookingID: g.BookingID, RoomNumber: "101", LastName: g.LastName, FirstName: g.FirstName, DocumentType: g.DocumentType, DocumentCipher: g.DocumentCipher})
+	}
+	return registrations, nil
+}
+
+func TestGuestHandlers_ManifestAndReport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	in := time.Now().AddDate(0, 1, 0)
+	bookings := service.NewBookingService(&mockBookingSvcRepo{booking: &models.Booking{ID: 4, UserID: 5, PropertyID: 1, Status: models.BookingStatusConfirmed, CheckInDate: in, CheckOutDate: in.AddDate(0, 0, 1), Adults: 1}}, service.DefaultBookingConfig)
+	mr := &mockGuestSvcRepo{}
+	h := NewGuestHandler(service.NewGuestService(mr, service.GuestConfig{DocumentKey: bytes.Repeat([]byte{1}, 32)}), bookings)
+
+	r := gin.New()
+	r.PUT("/bookings/:id/guests", h.ReplaceGuests)
+	r.GET("/guests/registrations", h.GetRegistrationReport)
+
+	put := func(guests ...models.BookingGuestRequest) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(models.BookingGuestsRequest{Guests: guests})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/bookings/4/guests", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		return w
+	}
+	adult := models.BookingGuestRequest{FirstName: "Asha", LastName: "Rao", Nationality: "IN", DateOfBirth: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC), DocumentType: models.DocumentPassport, DocumentNumber: "P1234567"}
+
+	if w := put(adult, adult); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for two adults on a one-adult booking, got %d", w.Code)
+	}
+	w := put(adult)
+	if w.Code != http.StatusOK || strings.Contains(w.Body.String(), "P1234567") {
+		t.Fatalf("expected 200 with a masked document, got %d body=%s", w.Code, w.Body.String())
+	}
+	for i := range mr.guests {
+		mr.guests[i].BookingID = 4
+	}
+
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/guests/registrations?date="+in.Format("2006-01-02")+"&format=csv", nil)
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/csv") {
+		t.Fatalf("expected a CSV report, got %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[1], "4,0,101,") || !strings.HasSuffix(lines[1], ",passport,P1234567") {
+		t.Fatalf("unexpected report: %q", lines)
+	}
+}
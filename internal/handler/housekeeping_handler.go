@@ -0,0 +1,99 @@
+// Package handler contains HTTP request handlers for all API endpoints.
+// Handlers receive HTTP requests, validate input, call services, and send responses.
+package handler
+
+import (
+	"fmt"
+	"industry-api/internal/middleware"
+	"industry-api/internal/models"
+	"industry-api/internal/response"
+	"industry-api/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HousekeepingHandler handles HTTP requests related to the room housekeeping workflow.
+type HousekeepingHandler struct {
+	svc *service.HousekeepingService // Service layer for housekeeping business logic
+}
+
+// NewHousekeepingHandler creates and returns a new instance of HousekeepingHandler.
+// It accepts a HousekeepingService dependency for handling housekeeping operations.
+func NewHousekeepingHandler(svc *service.HousekeepingService) *HousekeepingHandler {
+	return &HousekeepingHandler{svc: svc}
+}
+
+// ChangeStatus handles HTTP PUT requests that move a room to a new housekeeping status on behalf
+// of the authenticated staff member.
+// Returns 403 Forbidden for a room outside the caller's properties, or 409 Conflict if the
+// transition is not allowed from the room's current status.
+func (h *HousekeepingHandler) ChangeStatus(c *gin.Context) {
+	roomID, ok := h.accessibleRoom(c)
+	if !ok {
+		return
+	}
+	// Parse and validate the JSON request body
+	var req models.HousekeepingStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, err.Error())
+		return
+	}
+
+	// Call service to apply the transition
+	room, err := h.svc.ChangeStatus(c, roomID, req.Status, currentUserID(c), req.Note)
+	if err != nil {
+		respondError(c, "failed to update housekeeping status", err)
+		return
+	}
+	// Return 200 OK with the updated room
+	response.JSON(c, http.StatusOK, true, "housekeeping status updated successfully", room, "")
+}
+
+// GetBoard handles HTTP GET requests for the housekeeping board grouped by property and floor.
+// An optional status query parameter restricts the board to rooms in that status, and the
+// board only covers the properties the caller may see.
+func (h *HousekeepingHandler) GetBoard(c *gin.Context) {
+	board, err := h.svc.Board(c, c.Query("status"), middleware.PropertyIDs(c))
+	if err != nil {
+		respondError(c, "failed to get housekeeping board", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "housekeeping board fetched successfully", board, "")
+}
+
+// GetHistory handles HTTP GET requests for the recent housekeeping events of a room.
+// Returns 403 Forbidden for a room outside the caller's properties.
+func (h *HousekeepingHandler) GetHistory(c *gin.Context) {
+	roomID, ok := h.accessibleRoom(c)
+	if !ok {
+		return
+	}
+	events, err := h.svc.History(c, roomID)
+	if err != nil {
+		respondError(c, "failed to get housekeeping history", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "housekeeping history fetched successfully", events, "")
+}
+
+// accessibleRoom reads the room in the id path parameter and checks it belongs to one of the
+// caller's properties. It writes the error response and returns false otherwise.
+func (h *HousekeepingHandler) accessibleRoom(c *gin.Context) (int, bool) {
+	roomID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid ID", nil, "ID must be a valid integer")
+		return 0, false
+	}
+	room, err := h.svc.GetRoom(c, roomID)
+	if err != nil {
+		respondError(c, "failed to get room", err)
+		return 0, false
+	}
+	if !canAccessProperty(c, room.PropertyID) {
+		response.JSON(c, http.StatusForbidden, false, "forbidden", nil, fmt.Sprintf("not assigned to property %d", room.PropertyID))
+		return 0, false
+	}
+	return roomID, true
+}
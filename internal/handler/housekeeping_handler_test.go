@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"industry-api/internal/middleware"
+	"industry-api/internal/models"
+	"industry-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type mockHousekeepingSvcRepo struct {
+	status     string
+	propertyID int
+	changedBy  *int
+}
+
+// GetHousekeepingRoom returns a room of property 1 unless the test sets another property.
+func (m *mockHousekeepingSvcRepo) GetHousekeepingRoom(ctx context.Context, roomID int) (*models.HousekeepingRoom, error) {
+	propertyID := m.propertyID
+	if propertyID == 0 {
+		propertyID = 1
+	}
+	return &models.HousekeepingRoom{RoomID: roomID, PropertyID: propertyID, Status: m.status}, nil
+}
+func (m *mockHousekeepingSvcRepo) SetHousekeepingStatus(ctx context.Context, roomID int, from, to string, changedBy *int, note string) (*models.HousekeepingRoom, error) {
+	m.changedBy = changedBy
+	return &models.HousekeepingRoom{RoomID: roomID, Status: to, UpdatedBy: changedBy}, nil
+}
+func (m *mockHousekeepingSvcRepo) ListHousekeepingRooms(ctx context.Context, status string, propertyIDs []int) ([]models.HousekeepingRoom, error) {
+	return []models.HousekeepingRoom{{RoomID: 1, Floor: 1, Status: m.status}}, nil
+}
+func (m *mockHousekeepingSvcRepo) ListHousekeepingEvents(ctx context.Context, roomID int, limit int) ([]models.HousekeepingEvent, error) {
+	return nil, errors.New("not-impl")
+}
+
+// housekeepingRouter serves the room routes behind the property scope, with staff member 5
+// assigned to property 1.
+func housekeepingRouter(h *HousekeepingHandler) *gin.Engine {
+	r := gin.New()
+	scope := middleware.PropertyScope(staticPropertyResolver{5: {1}})
+	r.PUT("/housekeeping/rooms/:id/status", middleware.Authenticate(), scope, h.ChangeStatus)
+	r.GET("/housekeeping/rooms/:id/history", middleware.Authenticate(), scope, h.GetHistory)
+	return r
+}
+
+func housekeepingRequest(r *gin.Engine, method, path string, body any) *httptest.ResponseRecorder {
+	b, _ := json.Marshal(body)
+	req, _ := http.NewRequest(method, path, bytes.NewBuffer(b))
+	req.Header.Set("Content-Type", "application/json")
+	claims := jwt.MapClaims{"user_id": "5", "role": models.RoleStaff, "exp": time.Now().Add(time.Hour).Unix()}
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(os.Getenv("JWT_SECRET")))
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestChangeHousekeepingStatusHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &mockHousekeepingSvcRepo{status: models.HousekeepingDirty}
+	r := housekeepingRouter(NewHousekeepingHandler(service.NewHousekeepingService(repo)))
+
+	w := housekeepingRequest(r, "PUT", "/housekeeping/rooms/1/status", map[string]any{"status": models.HousekeepingCleaning, "changed_by": 99})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	if repo.changedBy == nil || *repo.changedBy != 5 {
+		t.Fatalf("expected the change to be attributed to the caller, got %v", repo.changedBy)
+	}
+	if w := housekeepingRequest(r, "PUT", "/housekeeping/rooms/1/status", map[string]any{"status": models.HousekeepingInspected}); w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for dirty -> inspected, got %d", w.Code)
+	}
+}
+
+func TestHousekeepingHandlers_ForbidOtherProperties(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &mockHousekeepingSvcRepo{status: models.HousekeepingDirty, propertyID: 2}
+	r := housekeepingRouter(NewHousekeepingHandler(service.NewHousekeepingService(repo)))
+
+	if w := housekeepingRequest(r, "PUT", "/housekeeping/rooms/1/status", map[string]any{"status": models.HousekeepingCleaning}); w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 changing a room of another property, got %d", w.Code)
+	}
+	if w := housekeepingRequest(r, "GET", "/housekeeping/rooms/1/history", nil); w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 reading the history of another property, got %d", w.Code)
+	}
+	if repo.changedBy != nil {
+		t.Fatal("expected no change to be made")
+	}
+}
+
+func TestGetHousekeepingBoardHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewHousekeepingHandler(service.NewHousekeepingService(&mockHousekeepingSvcRepo{status: models.HousekeepingClean}))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/api/v1/housekeeping/board", nil)
+	h.GetBoard(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+}
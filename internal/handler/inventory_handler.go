@@ -0,0 +1,81 @@
+// Package handler contains HTTP request handlers for all API endpoints.
+// Handlers receive HTTP requests, validate input, call services, and send responses.
+package handler
+
+import (
+	"errors"
+	"industry-api/internal/middleware"
+	"industry-api/internal/models"
+	"industry-api/internal/response"
+	"industry-api/internal/service"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InventoryHandler handles HTTP requests related to room inventory views.
+type InventoryHandler struct {
+	svc *service.InventoryService // Service layer for inventory business logic
+}
+
+// NewInventoryHandler creates and returns a new instance of InventoryHandler.
+// It accepts an InventoryService dependency for handling inventory operations.
+func NewInventoryHandler(svc *service.InventoryService) *InventoryHandler {
+	return &InventoryHandler{svc: svc}
+}
+
+// InventoryCalendarQuery represents the query parameters for the inventory calendar.
+type InventoryCalendarQuery struct {
+	From     string `form:"from" binding:"required"` // First night (YYYY-MM-DD)
+	To       string `form:"to" binding:"required"`   // Last night (YYYY-MM-DD)
+	Floor    *int   `form:"floor"`                   // Optional floor filter
+	RoomType string `form:"room_type"`               // Optional room type filter
+}
+
+// GetCalendar handles HTTP GET requests for the tape-chart inventory calendar.
+// It returns rooms as rows and nights as columns, with per-day counts for each room type.
+func (h *InventoryHandler) GetCalendar(c *gin.Context) {
+	var req InventoryCalendarQuery
+	// Parse and validate query parameters
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, err.Error())
+		return
+	}
+
+	// Parse the date range
+	from, err := time.Parse("2006-01-02", req.From)
+	if err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, "from must be in YYYY-MM-DD format")
+		return
+	}
+	to, err := time.Parse("2006-01-02", req.To)
+	if err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, "to must be in YYYY-MM-DD format")
+		return
+	}
+
+	filter := models.InventoryFilter{
+		From:     from,
+		To:       to,
+		Floor:    req.Floor,
+		RoomType: req.RoomType,
+		// Limit the calendar to the properties the caller may see
+		PropertyIDs: middleware.PropertyIDs(c),
+	}
+
+	// Call service to build the calendar
+	calendar, err := h.svc.GetCalendar(c, filter)
+	if err != nil {
+		// Return 400 Bad Request if the range is invalid
+		if errors.Is(err, service.ErrInvalidDateRange) {
+			response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, "to must not be before from and the range may span at most 93 nights")
+			return
+		}
+		// Return 500 Internal Server Error for other errors
+		response.JSON(c, http.StatusInternalServerError, false, "failed to get inventory calendar", nil, err.Error())
+		return
+	}
+	// Return 200 OK with the calendar
+	response.JSON(c, http.StatusOK, true, "inventory calendar fetched successfully", calendar, "")
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"industry-api/internal/models"
+	"industry-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type mockInventorySvcRepo struct {
+	cells func(ctx context.Context, filter models.InventoryFilter) ([]models.InventoryCell, error)
+}
+
+func (m *mockInventorySvcRepo) GetCalendarCells(ctx context.Context, filter models.InventoryFilter) ([]models.InventoryCell, error) {
+	return m.cells(ctx, filter)
+}
+
+func TestGetCalendarHandler_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mr := &mockInventorySvcRepo{
+		cells: func(ctx context.Context, f models.InventoryFilter) ([]models.InventoryCell, error) {
+			return []models.InventoryCell{{RoomID: 1, RoomNumber: "101", RoomType: "double", Floor: 1, Date: f.From, Status: models.CellStatusFree}}, nil
+		},
+	}
+	h := NewInventoryHandler(service.NewInventoryService(mr))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/api/v1/inventory/calendar?from=2025-01-10&to=2025-01-12&floor=1", nil)
+
+	h.GetCalendar(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+}
+
+func TestGetCalendarHandler_InvalidRange(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mr := &mockInventorySvcRepo{
+		cells: func(ctx context.Context, f models.InventoryFilter) ([]models.InventoryCell, error) { return nil, nil },
+	}
+	h := NewInventoryHandler(service.NewInventoryService(mr))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/api/v1/inventory/calendar?from=2025-01-12&to=2025-01-10", nil)
+
+	h.GetCalendar(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
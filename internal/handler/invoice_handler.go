@@ -0,0 +1,139 @@
+// Package handler contains HTTP request handlers for all API endpoints.
+// Handlers receive HTTP requests, validate input, call services, and send responses.
+package handler
+
+import (
+	"errors"
+	"industry-api/internal/models"
+	"industry-api/internal/render"
+	"industry-api/internal/response"
+	"industry-api/internal/service"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InvoiceHandler handles HTTP requests related to invoices and credit notes.
+type InvoiceHandler struct {
+	svc      *service.InvoiceService // Service layer for business logic
+	bookings *service.BookingService // Loads the bookings invoices belong to
+}
+
+// NewInvoiceHandler creates and returns a new instance of InvoiceHandler.
+// It accepts the InvoiceService handling invoices and the BookingService owning the bookings.
+func NewInvoiceHandler(svc *service.InvoiceService, bookings *service.BookingService) *InvoiceHandler {
+	return &InvoiceHandler{svc: svc, bookings: bookings}
+}
+
+// IssueInvoice handles HTTP POST requests that issue an invoice for a booking.
+// Returns 201 Created with the invoice, or 409 Conflict if the booking already has one that
+// has not been credited.
+func (h *InvoiceHandler) IssueInvoice(c *gin.Context) {
+	var req models.InvoiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, err.Error())
+		return
+	}
+	booking, ok := loadAccessibleBooking(c, h.bookings)
+	if !ok {
+		return
+	}
+
+	invoice, err := h.svc.IssueInvoice(c, booking, &req, currentUserID(c))
+	if err != nil {
+		respondError(c, "failed to issue invoice", err)
+		return
+	}
+	response.JSON(c, http.StatusCreated, true, "invoice issued successfully", invoice, "")
+}
+
+// ListInvoices handles HTTP GET requests for the invoices and credit notes of a booking.
+// Guests only see the invoices of their own bookings.
+func (h *InvoiceHandler) ListInvoices(c *gin.Context) {
+	booking, ok := loadAccessibleBooking(c, h.bookings)
+	if !ok {
+		return
+	}
+	invoices, err := h.svc.ListBookingInvoices(c, booking.ID)
+	if err != nil {
+		respondError(c, "failed to list invoices", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "invoices fetched successfully", invoices, "")
+}
+
+// GetInvoice handles HTTP GET requests for an invoice or credit note. With ?format=html or
+// ?format=pdf the document is downloaded in that format instead of returned as JSON.
+func (h *InvoiceHandler) GetInvoice(c *gin.Context) {
+	invoice, ok := h.accessibleInvoice(c)
+	if !ok {
+		return
+	}
+
+	filename := `attachment; filename="` + invoice.InvoiceNumber
+	switch c.DefaultQuery("format", "json") {
+	case "json":
+		response.JSON(c, http.StatusOK, true, "invoice fetched successfully", invoice, "")
+	case "html":
+		page, err := render.InvoiceHTML(invoice)
+		if err != nil {
+			respondError(c, "failed to render invoice", err)
+			return
+		}
+		c.Header("Content-Disposition", filename+`.html"`)
+		c.Data(http.StatusOK, "text/html; charset=utf-8", page)
+	case "pdf":
+		c.Header("Content-Disposition", filename+`.pdf"`)
+		c.Data(http.StatusOK, "application/pdf", render.InvoicePDF(invoice))
+	default:
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, "format must be json, html or pdf")
+	}
+}
+
+// CreditInvoice handles HTTP POST requests that reverse an invoice with a credit note.
+// Returns 201 Created with the credit note, or 409 Conflict if the invoice was already credited.
+func (h *InvoiceHandler) CreditInvoice(c *gin.Context) {
+	var req models.CreditNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, err.Error())
+		return
+	}
+	invoice, ok := h.accessibleInvoice(c)
+	if !ok {
+		return
+	}
+
+	note, err := h.svc.CreditInvoice(c, invoice, req.Reason, currentUserID(c))
+	if err != nil {
+		respondError(c, "failed to credit invoice", err)
+		return
+	}
+	response.JSON(c, http.StatusCreated, true, "credit note issued successfully", note, "")
+}
+
+// accessibleInvoice loads the invoice in the id path parameter and checks the caller may see
+// its booking. It writes the error response and returns false otherwise.
+func (h *InvoiceHandler) accessibleInvoice(c *gin.Context) (*models.Invoice, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid ID", nil, "ID must be a valid integer")
+		return nil, false
+	}
+	invoice, err := h.svc.GetInvoice(c, id)
+	if err != nil {
+		respondError(c, "failed to get invoice", err)
+		return nil, false
+	}
+	booking, err := h.bookings.GetBooking(c, invoice.BookingID)
+	if err != nil {
+		respondError(c, "failed to get invoice", err)
+		return nil, false
+	}
+	if !canAccessBooking(c, booking) {
+		response.JSON(c, http.StatusNotFound, false, "failed to get invoice", nil, "invoice not found")
+		return nil, false
+	}
+	return invoice, true
+}
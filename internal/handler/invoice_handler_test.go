@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"industry-api/internal/models"
+	"industry-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type mockInvoiceSvcRepo struct {
+	invoice *models.Invoice
+}
+
+func (m *mockInvoiceSvcRepo) IssueInvoice(ctx context.Context, invoice *models.Invoice) error {
+	return nil
+}
+func (m *mockInvoiceSvcRepo) GetInvoice(ctx context.Context, id int) (*models.Invoice, error) {
+	return m.invoice, nil
+}
+func (m *mockInvoiceSvcRepo) ListBookingInvoices(ctx context.Context, bookingID int) ([]models.Invoice, error) {
+	return nil, nil
+}
+
+func TestGetInvoice_Formats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	bookings := service.NewBookingService(&mockBookingSvcRepo{booking: &models.Booking{ID: 4, UserID: 5, PropertyID: 1, Status: models.BookingStatusCheckedOut}}, service.DefaultBookingConfig)
+	issued := time.Date(2030, 5, 3, 10, 0, 0, 0, time.UTC)
+	repo := &mockInvoiceSvcRepo{invoice: &models.Invoice{ID: 9, BookingID: 4, Kind: models.InvoiceKindInvoice, InvoiceNumber: "INV-LON1-000001", BillToName: "Asha Rao",
+		Lines: []models.InvoiceLine{{Description: "Room 101", Quantity: 1, UnitPrice: 100, Amount: 100}}, Subtotal: 100, Total: 100, BalanceDue: 100, IssuedAt: issued, StayFrom: issued, StayTo: issued}}
+	h := NewInvoiceHandler(service.NewInvoiceService(repo, nil), bookings)
+
+	r := gin.New()
+	r.GET("/invoices/:id", h.GetInvoice)
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/invoices/9"+query, nil)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := get(""); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"invoice_number":"INV-LON1-000001"`) {
+		t.Fatalf("expected the invoice as JSON, got %d %s", w.Code, w.Body.String())
+	}
+	if w := get("?format=html"); w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") || !strings.Contains(w.Body.String(), "Room 101") {
+		t.Fatalf("expected an HTML invoice, got %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+	w := get("?format=pdf")
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/pdf" || !strings.HasPrefix(w.Body.String(), "%PDF-") ||
+		!strings.Contains(w.Header().Get("Content-Disposition"), "INV-LON1-000001.pdf") {
+		t.Fatalf("expected a PDF download, got %d %s %s", w.Code, w.Header().Get("Content-Type"), w.Header().Get("Content-Disposition"))
+	}
+	if w := get("?format=xml"); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an unknown format, got %d", w.Code)
+	}
+}
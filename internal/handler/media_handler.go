@@ -0,0 +1,234 @@
+// Package handler contains HTTP request handlers for all API endpoints.
+// Handlers receive HTTP requests, validate input, call services, and send responses.
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"industry-api/internal/middleware"
+	"industry-api/internal/models"
+	"industry-api/internal/response"
+	"industry-api/internal/service"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MediaHandler handles HTTP requests related to room and room type photos.
+type MediaHandler struct {
+	svc *service.MediaService // Service layer for photo business logic
+}
+
+// NewMediaHandler creates and returns a new instance of MediaHandler.
+// It accepts a MediaService dependency for handling photo operations.
+func NewMediaHandler(svc *service.MediaService) *MediaHandler {
+	return &MediaHandler{svc: svc}
+}
+
+// UploadRoomMedia handles HTTP POST multipart uploads of a photo for a single room.
+// The image is sent in the "file" field with an optional "caption" field.
+func (h *MediaHandler) UploadRoomMedia(c *gin.Context) {
+	owner, ok := roomMediaOwner(c)
+	if !ok || !h.canManage(c, owner) {
+		return
+	}
+	h.upload(c, owner)
+}
+
+// UploadRoomTypeMedia handles HTTP POST multipart uploads of a photo shared by every room of a type.
+// The image is sent in the "file" field with an optional "caption" field.
+func (h *MediaHandler) UploadRoomTypeMedia(c *gin.Context) {
+	owner := models.MediaOwner{RoomType: c.Param("type")}
+	if !h.canManage(c, owner) {
+		return
+	}
+	h.upload(c, owner)
+}
+
+// ListRoomMedia handles HTTP GET requests for the ordered photos of a room.
+func (h *MediaHandler) ListRoomMedia(c *gin.Context) {
+	owner, ok := roomMediaOwner(c)
+	if !ok {
+		return
+	}
+	h.list(c, owner)
+}
+
+// ListRoomTypeMedia handles HTTP GET requests for the ordered photos of a room type.
+func (h *MediaHandler) ListRoomTypeMedia(c *gin.Context) {
+	h.list(c, models.MediaOwner{RoomType: c.Param("type")})
+}
+
+// ReorderRoomMedia handles HTTP PUT requests that set the display order of a room's photos.
+func (h *MediaHandler) ReorderRoomMedia(c *gin.Context) {
+	owner, ok := roomMediaOwner(c)
+	if !ok || !h.canManage(c, owner) {
+		return
+	}
+	h.reorder(c, owner)
+}
+
+// ReorderRoomTypeMedia handles HTTP PUT requests that set the display order of a room type's photos.
+func (h *MediaHandler) ReorderRoomTypeMedia(c *gin.Context) {
+	owner := models.MediaOwner{RoomType: c.Param("type")}
+	if !h.canManage(c, owner) {
+		return
+	}
+	h.reorder(c, owner)
+}
+
+// UpdateCaption handles HTTP PUT requests that change the caption of a photo.
+func (h *MediaHandler) UpdateCaption(c *gin.Context) {
+	id, ok := h.manageableMedia(c)
+	if !ok {
+		return
+	}
+	var req models.UpdateMediaCaptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, err.Error())
+		return
+	}
+
+	media, err := h.svc.UpdateCaption(c, id, req.Caption)
+	if err != nil {
+		respondError(c, "failed to update media", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "media updated successfully", media, "")
+}
+
+// DeleteMedia handles HTTP DELETE requests that remove a photo and its files.
+func (h *MediaHandler) DeleteMedia(c *gin.Context) {
+	id, ok := h.manageableMedia(c)
+	if !ok {
+		return
+	}
+	if err := h.svc.Delete(c, id); err != nil {
+		respondError(c, "failed to delete media", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "media deleted successfully", nil, "")
+}
+
+// upload reads the multipart file and caption and hands them to the service.
+func (h *MediaHandler) upload(c *gin.Context, owner models.MediaOwner) {
+	// Cap the request body so oversized uploads are rejected without being buffered
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, service.MaxMediaBytes+1<<20)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			response.JSON(c, http.StatusRequestEntityTooLarge, false, "file too large", nil, err.Error())
+			return
+		}
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, "file is required")
+		return
+	}
+	if fileHeader.Size > service.MaxMediaBytes {
+		response.JSON(c, http.StatusRequestEntityTooLarge, false, "file too large", nil, "file exceeds the upload limit")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, err.Error())
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, service.MaxMediaBytes+1))
+	if err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, err.Error())
+		return
+	}
+
+	media, err := h.svc.Upload(c, owner, data, c.PostForm("caption"))
+	if err != nil {
+		respondError(c, "failed to upload media", err)
+		return
+	}
+	response.JSON(c, http.StatusCreated, true, "media uploaded successfully", media, "")
+}
+
+// list returns the ordered photos of an owner.
+func (h *MediaHandler) list(c *gin.Context, owner models.MediaOwner) {
+	media, err := h.svc.List(c, owner)
+	if err != nil {
+		respondError(c, "failed to get media", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "media fetched successfully", media, "")
+}
+
+// reorder applies a new display order to an owner's photos.
+func (h *MediaHandler) reorder(c *gin.Context, owner models.MediaOwner) {
+	var req models.ReorderMediaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, err.Error())
+		return
+	}
+	media, err := h.svc.Reorder(c, owner, req.MediaIDs)
+	if err != nil {
+		respondError(c, "failed to reorder media", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "media reordered successfully", media, "")
+}
+
+// roomMediaOwner parses the :id route parameter into a room owner.
+// It writes a 400 response and returns false if the parameter is not a valid integer.
+func roomMediaOwner(c *gin.Context) (models.MediaOwner, bool) {
+	roomID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid ID", nil, "ID must be a valid integer")
+		return models.MediaOwner{}, false
+	}
+	return models.MediaOwner{RoomID: &roomID}, true
+}
+
+// canManage reports whether the caller may change the photos of owner. A room's photos belong to
+// the room's property, so the caller must be assigned to it; room type photos are shared by every
+// property and need access to all of them.
+// It writes an error response and returns false otherwise.
+func (h *MediaHandler) canManage(c *gin.Context, owner models.MediaOwner) bool {
+	propertyID, err := h.svc.OwnerPropertyID(c, owner)
+	if err != nil {
+		respondError(c, "failed to get media owner", err)
+		return false
+	}
+	if propertyID == nil {
+		if middleware.PropertyIDs(c) != nil {
+			response.JSON(c, http.StatusForbidden, false, "forbidden", nil, "room type photos are shared by every property")
+			return false
+		}
+		return true
+	}
+	if !canAccessProperty(c, *propertyID) {
+		response.JSON(c, http.StatusForbidden, false, "forbidden", nil, fmt.Sprintf("not assigned to property %d", *propertyID))
+		return false
+	}
+	return true
+}
+
+// manageableMedia parses the :id route parameter and checks that the caller may change that photo.
+// It writes an error response and returns false if the ID is invalid, the photo does not exist or
+// the caller may not change it.
+func (h *MediaHandler) manageableMedia(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid ID", nil, "ID must be a valid integer")
+		return 0, false
+	}
+	media, err := h.svc.Get(c, id)
+	if err != nil {
+		respondError(c, "failed to get media", err)
+		return 0, false
+	}
+	owner := models.MediaOwner{RoomID: media.RoomID}
+	if media.RoomType != nil {
+		owner.RoomType = *media.RoomType
+	}
+	return id, h.canManage(c, owner)
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"industry-api/internal/middleware"
+	"industry-api/internal/models"
+	"industry-api/internal/service"
+	"industry-api/internal/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+type mockMediaSvcRepo struct {
+	add        func(ctx context.Context, media *models.RoomMedia) error
+	media      *models.RoomMedia // Photo returned by GetMediaByID; not found when nil
+	propertyID int               // Property of every room
+}
+
+func (m *mockMediaSvcRepo) AddMedia(ctx context.Context, media *models.RoomMedia) error {
+	return m.add(ctx, media)
+}
+func (m *mockMediaSvcRepo) GetMediaByID(ctx context.Context, id int) (*models.RoomMedia, error) {
+	if m.media == nil {
+		return nil, service.ErrNotFound
+	}
+	return m.media, nil
+}
+func (m *mockMediaSvcRepo) ListMedia(ctx context.Context, owner models.MediaOwner) ([]models.RoomMedia, error) {
+	return nil, errors.New("not-impl")
+}
+func (m *mockMediaSvcRepo) ListMediaForRooms(ctx context.Context, roomIDs []int, roomTypes []string) ([]models.RoomMedia, error) {
+	return nil, errors.New("not-impl")
+}
+func (m *mockMediaSvcRepo) UpdateMediaCaption(ctx context.Context, id int, caption string) (*models.RoomMedia, error) {
+	return nil, errors.New("not-impl")
+}
+func (m *mockMediaSvcRepo) ReorderMedia(ctx context.Context, owner models.MediaOwner, ids []int) error {
+	return errors.New("not-impl")
+}
+func (m *mockMediaSvcRepo) DeleteMedia(ctx context.Context, id int) error {
+	return errors.New("not-impl")
+}
+func (m *mockMediaSvcRepo) GetRoomPropertyID(ctx context.Context, roomID int) (int, error) {
+	return m.propertyID, nil
+}
+
+// multipartUpload builds a multipart request body with a single file field.
+func multipartUpload(t *testing.T, content []byte) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	mw := multipart.NewWriter(body)
+	fw, err := mw.CreateFormFile("file", "photo.png")
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	fw.Write(content)
+	mw.WriteField("caption", "Lobby")
+	mw.Close()
+	return body, mw.FormDataContentType()
+}
+
+func newMediaHandler(t *testing.T, mr *mockMediaSvcRepo) *MediaHandler {
+	store, err := storage.NewLocalStorage(t.TempDir(), "/media")
+	if err != nil {
+		t.Fatalf("storage: %v", err)
+	}
+	return NewMediaHandler(service.NewMediaService(mr, store))
+}
+
+func TestUploadRoomMediaHandler_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := newMediaHandler(t, &mockMediaSvcRepo{
+		add: func(ctx context.Context, media *models.RoomMedia) error { media.ID = 1; return nil },
+	})
+
+	var img bytes.Buffer
+	png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 20, 10)))
+	body, contentType := multipartUpload(t, img.Bytes())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/api/v1/rooms/3/media", body)
+	c.Request.Header.Set("Content-Type", contentType)
+	c.Params = gin.Params{{Key: "id", Value: "3"}}
+
+	h.UploadRoomMedia(c)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+}
+
+func TestUploadRoomMediaHandler_RejectsNonImage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := newMediaHandler(t, &mockMediaSvcRepo{
+		add: func(ctx context.Context, media *models.RoomMedia) error { return nil },
+	})
+	body, contentType := multipartUpload(t, []byte("<html>not an image</html>"))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/api/v1/rooms/3/media", body)
+	c.Request.Header.Set("Content-Type", contentType)
+	c.Params = gin.Params{{Key: "id", Value: "3"}}
+
+	h.UploadRoomMedia(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestDeleteMediaHandler_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := newMediaHandler(t, &mockMediaSvcRepo{})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("DELETE", "/api/v1/media/9", nil)
+	c.Params = gin.Params{{Key: "id", Value: "9"}}
+
+	h.DeleteMedia(c)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+}
+
+func TestMediaHandlers_ForbidOtherProperties(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	roomID := 3
+	h := newMediaHandler(t, &mockMediaSvcRepo{media: &models.RoomMedia{ID: 9, RoomID: &roomID}, propertyID: 2})
+	r := gin.New()
+	scope := middleware.PropertyScope(staticPropertyResolver{5: {1}})
+	r.PUT("/rooms/:id/media/order", middleware.Authenticate(), scope, h.ReorderRoomMedia)
+	r.PUT("/room-types/:type/media/order", middleware.Authenticate(), scope, h.ReorderRoomTypeMedia)
+	r.PUT("/media/:id", middleware.Authenticate(), scope, h.UpdateCaption)
+	r.DELETE("/media/:id", middleware.Authenticate(), scope, h.DeleteMedia)
+
+	requests := []struct {
+		method, path string
+		body         any
+	}{
+		{"PUT", "/rooms/3/media/order", map[string]any{"media_ids": []int{9}}},
+		{"PUT", "/room-types/suite/media/order", map[string]any{"media_ids": []int{}}},
+		{"PUT", "/media/9", map[string]any{"caption": "Lobby"}},
+		{"DELETE", "/media/9", nil},
+	}
+	for _, req := range requests {
+		if w := housekeepingRequest(r, req.method, req.path, req.body); w.Code != http.StatusForbidden {
+			t.Fatalf("%s %s: expected 403, got %d body=%s", req.method, req.path, w.Code, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// Package handler contains HTTP request handlers for all API endpoints.
+// Handlers receive HTTP requests, validate input, call services, and send responses.
+package handler
+
+import (
+	"industry-api/internal/response"
+	"industry-api/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationHandler handles HTTP requests related to the notification outbox.
+type NotificationHandler struct {
+	svc *service.NotificationService // Service layer for business logic
+}
+
+// NewNotificationHandler creates and returns a new instance of NotificationHandler.
+// It accepts a NotificationService dependency for inspecting and retrying outbox events.
+func NewNotificationHandler(svc *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{svc: svc}
+}
+
+// OutboxQuery represents the query parameters of the outbox listing.
+type OutboxQuery struct {
+	Status string `form:"status"` // pending, sent or dead; every event when empty
+	Limit  int    `form:"limit"`  // Maximum number of events (default 50, at most 500)
+}
+
+// ListEvents handles HTTP GET requests for the most recent outbox events with their delivery
+// state, such as the dead letters with ?status=dead.
+func (h *NotificationHandler) ListEvents(c *gin.Context) {
+	var req OutboxQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, err.Error())
+		return
+	}
+	events, err := h.svc.ListEvents(c, req.Status, req.Limit)
+	if err != nil {
+		respondError(c, "failed to list outbox events", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "outbox events fetched successfully", events, "")
+}
+
+// RetryEvent handles HTTP POST requests that queue a dead outbox event for delivery again.
+// Returns 409 Conflict for an event that is not dead.
+func (h *NotificationHandler) RetryEvent(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid ID", nil, "ID must be a valid integer")
+		return
+	}
+	event, err := h.svc.RetryEvent(c, id)
+	if err != nil {
+		respondError(c, "failed to retry outbox event", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "outbox event queued for delivery", event, "")
+}
@@ -54,8 +54,8 @@ func (h *PaymentHandler) InitiatePayment(c *gin.Context) {
 	pmt, err := h.svc.InitiatePaymet(c, payment)
 
 	if err != nil {
-		// Return 500 Internal Server Error if service call fails
-		response.JSON(c, http.StatusInternalServerError, false, "failed to initiate payment", nil, err.Error())
+		// Return 409 Conflict if the booking's hold has expired, 404 if it does not exist, 500 otherwise
+		respondError(c, "failed to initiate payment", err)
 		return
 	}
 	// Return 201 Created with the initiated payment
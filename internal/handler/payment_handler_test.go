@@ -5,11 +5,14 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"industry-api/internal/models"
+	"industry-api/internal/repository"
 	"industry-api/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -18,9 +21,13 @@ import (
 type mockPaymentSvcRepo struct {
 	init   func(ctx context.Context, p *models.Payment) (*models.Payment, error)
 	update func(ctx context.Context, p *models.Payment, id int) (*models.Payment, error)
+	hold   error
 }
 
-func (m *mockPaymentSvcRepo) InitiatePayment(ctx context.Context, p *models.Payment) (*models.Payment, error) {
+func (m *mockPaymentSvcRepo) InitiatePayment(ctx context.Context, p *models.Payment, holdUntil *time.Time) (*models.Payment, error) {
+	if holdUntil != nil && m.hold != nil {
+		return nil, m.hold
+	}
 	return m.init(ctx, p)
 }
 func (m *mockPaymentSvcRepo) UpdatePayment(ctx context.Context, p *models.Payment, id int) (*models.Payment, error) {
@@ -35,7 +42,7 @@ func TestInitiatePaymentHandler_Success(t *testing.T) {
 			return nil, errors.New("not-impl")
 		},
 	}
-	h := NewPaymentHandler(service.NewPaymentService(mr))
+	h := NewPaymentHandler(service.NewPaymentService(mr, 0))
 
 	reqBody := models.PaymentRequest{BookingID: 1, Amount: 100, PaymentMethod: "card", TransactionID: "tx-1"}
 	b, _ := json.Marshal(reqBody)
@@ -60,7 +67,7 @@ func TestInitiatePaymentHandler_ValidationError(t *testing.T) {
 			return nil, errors.New("not-impl")
 		},
 	}
-	h := NewPaymentHandler(service.NewPaymentService(mr))
+	h := NewPaymentHandler(service.NewPaymentService(mr, 0))
 
 	// missing required fields
 	reqBody := models.PaymentRequest{BookingID: 0, Amount: 0}
@@ -77,3 +84,25 @@ func TestInitiatePaymentHandler_ValidationError(t *testing.T) {
 		t.Fatalf("expected 400, got %d", w.Code)
 	}
 }
+
+func TestInitiatePaymentHandler_ExpiredHold(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mr := &mockPaymentSvcRepo{
+		init: func(ctx context.Context, p *models.Payment) (*models.Payment, error) {
+			return nil, errors.New("should not be called")
+		},
+		hold: fmt.Errorf("booking is cancelled: %w", repository.ErrConflict),
+	}
+	h := NewPaymentHandler(service.NewPaymentService(mr, 15*time.Minute))
+
+	b, _ := json.Marshal(models.PaymentRequest{BookingID: 1, Amount: 1000, PaymentMethod: "card", TransactionID: "tx-1"})
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/api/v1/payments/initiate", bytes.NewBuffer(b))
+	c.Request.Header.Set("Content-Type", "application/json")
+	h.InitiatePayment(c)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for an expired hold, got %d body=%s", w.Code, w.Body.String())
+	}
+}
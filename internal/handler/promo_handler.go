@@ -0,0 +1,81 @@
+// Package handler contains HTTP request handlers for all API endpoints.
+// Handlers receive HTTP requests, validate input, call services, and send responses.
+package handler
+
+import (
+	"industry-api/internal/models"
+	"industry-api/internal/response"
+	"industry-api/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PromoHandler handles HTTP requests related to promo codes.
+type PromoHandler struct {
+	svc *service.PromoService // Service layer for business logic
+}
+
+// NewPromoHandler creates and returns a new instance of PromoHandler.
+// It accepts a PromoService dependency for handling promo code operations.
+func NewPromoHandler(svc *service.PromoService) *PromoHandler {
+	return &PromoHandler{svc: svc}
+}
+
+// CreatePromoCode handles HTTP POST requests to create a promo code.
+// Returns 201 Created with the code, or 409 Conflict if the code is already in use.
+func (h *PromoHandler) CreatePromoCode(c *gin.Context) {
+	var req models.PromoCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, err.Error())
+		return
+	}
+
+	promo, err := h.svc.CreatePromoCode(c, &models.PromoCode{
+		Code:           req.Code,
+		Description:    req.Description,
+		DiscountType:   req.DiscountType,
+		DiscountValue:  req.DiscountValue,
+		StayFrom:       req.StayFrom,
+		StayTo:         req.StayTo,
+		BookFrom:       req.BookFrom,
+		BookTo:         req.BookTo,
+		MinNights:      req.MinNights,
+		RoomTypes:      req.RoomTypes,
+		MaxUses:        req.MaxUses,
+		MaxUsesPerUser: req.MaxUsesPerUser,
+		Stackable:      req.Stackable,
+	})
+	if err != nil {
+		respondError(c, "failed to create promo code", err)
+		return
+	}
+	response.JSON(c, http.StatusCreated, true, "promo code created successfully", promo, "")
+}
+
+// ListPromoCodes handles HTTP GET requests for every promo code with its usage.
+func (h *PromoHandler) ListPromoCodes(c *gin.Context) {
+	promos, err := h.svc.ListPromoCodes(c)
+	if err != nil {
+		respondError(c, "failed to list promo codes", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "promo codes fetched successfully", promos, "")
+}
+
+// DeactivatePromoCode handles HTTP DELETE requests that stop a promo code from being redeemed.
+// Bookings that already redeemed the code keep their discount.
+func (h *PromoHandler) DeactivatePromoCode(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid ID", nil, "ID must be a valid integer")
+		return
+	}
+	promo, err := h.svc.DeactivatePromoCode(c, id)
+	if err != nil {
+		respondError(c, "failed to deactivate promo code", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "promo code deactivated successfully", promo, "")
+}
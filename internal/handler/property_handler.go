@@ -0,0 +1,88 @@
+// Package handler contains HTTP request handlers for all API endpoints.
+// Handlers receive HTTP requests, validate input, call services, and send responses.
+package handler
+
+import (
+	"industry-api/internal/middleware"
+	"industry-api/internal/models"
+	"industry-api/internal/response"
+	"industry-api/internal/service"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PropertyHandler handles HTTP requests related to hotel properties and staff assignments.
+type PropertyHandler struct {
+	svc *service.PropertyService // Service layer for property business logic
+}
+
+// NewPropertyHandler creates and returns a new instance of PropertyHandler.
+// It accepts a PropertyService dependency for handling property operations.
+func NewPropertyHandler(svc *service.PropertyService) *PropertyHandler {
+	return &PropertyHandler{svc: svc}
+}
+
+// CreateProperty handles HTTP POST requests to create a new property.
+// Returns 201 Created with the property, or 409 Conflict if the code is already in use.
+func (h *PropertyHandler) CreateProperty(c *gin.Context) {
+	var req models.PropertyRequest
+	// Parse and validate the JSON request body
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, err.Error())
+		return
+	}
+
+	property := &models.Property{
+		Code:     req.Code,
+		Name:     req.Name,
+		Address:  req.Address,
+		Timezone: req.Timezone,
+	}
+	created, err := h.svc.CreateProperty(c, property)
+	if err != nil {
+		respondError(c, "failed to create property", err)
+		return
+	}
+	response.JSON(c, http.StatusCreated, true, "property created successfully", created, "")
+}
+
+// ListProperties handles HTTP GET requests for the properties the caller may see.
+func (h *PropertyHandler) ListProperties(c *gin.Context) {
+	properties, err := h.svc.ListProperties(c, middleware.PropertyIDs(c))
+	if err != nil {
+		respondError(c, "failed to get properties", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "properties fetched successfully", properties, "")
+}
+
+// AssignUser handles HTTP PUT requests that replace the properties a user is assigned to.
+func (h *PropertyHandler) AssignUser(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid ID", nil, "ID must be a valid integer")
+		return
+	}
+	var req models.UserPropertiesRequest
+	// Parse and validate the JSON request body
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, err.Error())
+		return
+	}
+
+	ids, err := h.svc.AssignUser(c, userID, req.PropertyIDs)
+	if err != nil {
+		respondError(c, "failed to assign user to properties", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "user properties updated successfully", gin.H{"user_id": userID, "property_ids": ids}, "")
+}
+
+// canAccessProperty reports whether the property scope resolved for the request includes propertyID.
+func canAccessProperty(c *gin.Context, propertyID int) bool {
+	scope := middleware.PropertyIDs(c)
+	return scope == nil || slices.Contains(scope, propertyID)
+}
@@ -0,0 +1,108 @@
+// Package handler contains HTTP request handlers for all API endpoints.
+// Handlers receive HTTP requests, validate input, call services, and send responses.
+package handler
+
+import (
+	"errors"
+	"industry-api/internal/middleware"
+	"industry-api/internal/models"
+	"industry-api/internal/response"
+	"industry-api/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReservationHandler handles HTTP requests related to group reservations.
+type ReservationHandler struct {
+	svc *service.ReservationService // Service layer for business logic
+}
+
+// NewReservationHandler creates and returns a new instance of ReservationHandler.
+// It accepts a ReservationService dependency for handling reservation operations.
+func NewReservationHandler(svc *service.ReservationService) *ReservationHandler {
+	return &ReservationHandler{svc: svc}
+}
+
+// CreateReservation handles HTTP POST requests that book several rooms under one confirmation code.
+// The caller is the lead guest; staff may book for another user with user_id.
+// Returns 201 Created with the reservation, or 409 Conflict with the taken dates if any room
+// is unavailable, in which case nothing is booked.
+func (h *ReservationHandler) CreateReservation(c *gin.Context) {
+	var req models.ReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, err.Error())
+		return
+	}
+	userID, ok := bookingUserID(c, req.UserID)
+	if !ok {
+		return
+	}
+	req.UserID = userID
+
+	reservation, err := h.svc.CreateReservation(c, &req)
+	if err != nil {
+		var conflict *service.BookingConflictError
+		if errors.As(err, &conflict) {
+			response.JSON(c, http.StatusConflict, false, "room is already booked for the selected dates", conflict.Conflict, err.Error())
+			return
+		}
+		respondError(c, "failed to create reservation", err)
+		return
+	}
+	response.JSON(c, http.StatusCreated, true, "reservation created successfully", reservation, "")
+}
+
+// GetReservation handles HTTP GET requests for a reservation with its bookings and totals.
+// Guests only see their own reservations and staff only the reservations of their properties.
+func (h *ReservationHandler) GetReservation(c *gin.Context) {
+	reservation, ok := h.accessibleReservation(c)
+	if !ok {
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "reservation fetched successfully", reservation, "")
+}
+
+// PayReservation handles HTTP POST requests that start one payment for a whole reservation.
+// The amount is split into per-booking payments, which are completed through the payment endpoints.
+func (h *ReservationHandler) PayReservation(c *gin.Context) {
+	var req models.ReservationPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, err.Error())
+		return
+	}
+	reservation, ok := h.accessibleReservation(c)
+	if !ok {
+		return
+	}
+
+	payment, err := h.svc.PayReservation(c, reservation.ID, &req)
+	if err != nil {
+		respondError(c, "failed to pay reservation", err)
+		return
+	}
+	response.JSON(c, http.StatusCreated, true, "reservation payment initiated successfully", payment, "")
+}
+
+// accessibleReservation loads the reservation in the id path parameter and checks the caller may
+// act on it: guests only reach reservations they lead and staff only those of their properties.
+// It writes the error response and returns false otherwise.
+func (h *ReservationHandler) accessibleReservation(c *gin.Context) (*models.Reservation, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid ID", nil, "ID must be a valid integer")
+		return nil, false
+	}
+	reservation, err := h.svc.GetReservation(c, id)
+	if err != nil {
+		respondError(c, "failed to get reservation", err)
+		return nil, false
+	}
+	userID, role, _ := middleware.CurrentUser(c)
+	if (role == models.RoleGuest && reservation.LeadUserID != userID) || !canAccessProperty(c, reservation.PropertyID) {
+		response.JSON(c, http.StatusNotFound, false, "failed to get reservation", nil, "reservation not found")
+		return nil, false
+	}
+	return reservation, true
+}
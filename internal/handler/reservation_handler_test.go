@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"industry-api/internal/middleware"
+	"industry-api/internal/models"
+	"industry-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type mockReservationSvcRepo struct {
+	reservation *models.Reservation
+	conflict    bool
+}
+
+func (m *mockReservationSvcRepo) CreateReservation(ctx context.Context, reservation *models.Reservation) error {
+	if m.conflict {
+		return &service.BookingConflictError{Conflict: models.BookingConflict{BookingID: 7, RoomID: reservation.Bookings[1].RoomID}}
+	}
+	reservation.ID = 3
+	m.reservation = reservation
+	return nil
+}
+func (m *mockReservationSvcRepo) GetReservation(ctx context.Context, id int) (*models.Reservation, error) {
+	copied := *m.reservation
+	return &copied, nil
+}
+func (m *mockReservationSvcRepo) GetReservationPaidAmounts(ctx context.Context, id int) (map[int]int64, error) {
+	return map[int]int64{}, nil
+}
+func (m *mockReservationSvcRepo) GetReservationPendingAmounts(ctx context.Context, id int) (map[int]int64, error) {
+	return map[int]int64{}, nil
+}
+func (m *mockReservationSvcRepo) AddReservationPayments(ctx context.Context, payments []*models.Payment, committed map[int]int64, holdUntil *time.Time) error {
+	return nil
+}
+
+func TestReservationHandlers_CreateAndAccess(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	os.Setenv("JWT_SECRET", "reservation-handler-test-secret")
+	mr := &mockReservationSvcRepo{conflict: true}
+	bookings := service.NewBookingService(&mockBookingSvcRepo{}, service.DefaultBookingConfig)
+	h := NewReservationHandler(service.NewReservationService(mr, bookings))
+
+	r := gin.New()
+	r.POST("/reservations", middleware.Authenticate(), h.CreateReservation)
+	r.GET("/reservations/:id", middleware.Authenticate(), middleware.PropertyScope(nil), h.GetReservation)
+
+	in := time.Now().AddDate(0, 1, 0)
+	create := func(userID int) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(models.ReservationRequest{UserID: userID, Rooms: []models.ReservationRoomRequest{
+			{RoomID: 1, CheckInDate: in, CheckOutDate: in.AddDate(0, 0, 1), Adults: 2},
+			{RoomID: 2, CheckInDate: in, CheckOutDate: in.AddDate(0, 0, 1), Adults: 1},
+		}})
+		claims := jwt.MapClaims{"user_id": "5", "role": models.RoleGuest, "exp": time.Now().Add(time.Hour).Unix()}
+		token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(os.Getenv("JWT_SECRET")))
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/reservations", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// one taken room fails the whole reservation
+	if w := create(0); w.Code != http.StatusConflict || mr.reservation != nil {
+		t.Fatalf("expected 409 and nothing booked, got %d body=%s", w.Code, w.Body.String())
+	}
+	mr.conflict = false
+	if w := create(9); w.Code != http.StatusForbidden || mr.reservation != nil {
+		t.Fatalf("expected 403 for a guest booking for someone else, got %d", w.Code)
+	}
+	if w := create(0); w.Code != http.StatusCreated || len(mr.reservation.Bookings) != 2 || mr.reservation.LeadUserID != 5 {
+		t.Fatalf("expected 201 with two bookings for the caller, got %d body=%s", w.Code, w.Body.String())
+	}
+
+	get := func(userID int) *httptest.ResponseRecorder {
+		claims := jwt.MapClaims{"user_id": fmt.Sprint(userID), "role": models.RoleGuest, "exp": time.Now().Add(time.Hour).Unix()}
+		token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(os.Getenv("JWT_SECRET")))
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/reservations/3", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		r.ServeHTTP(w, req)
+		return w
+	}
+	if w := get(9); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for another guest's reservation, got %d", w.Code)
+	}
+	if w := get(5); w.Code != http.StatusOK {
+		t.Fatalf("expected the lead guest to see the reservation, got %d body=%s", w.Code, w.Body.String())
+	}
+}
@@ -3,10 +3,14 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
+	"industry-api/internal/middleware"
 	"industry-api/internal/models"
 	"industry-api/internal/response"
 	"industry-api/internal/service"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -35,8 +39,15 @@ func (h *RoomHandler) AddRoom(c *gin.Context) {
 		return
 	}
 
+	// Staff may only add rooms to the properties they are assigned to
+	if !canAccessProperty(c, req.PropertyID) {
+		response.JSON(c, http.StatusForbidden, false, "forbidden", nil, fmt.Sprintf("not assigned to property %d", req.PropertyID))
+		return
+	}
+
 	// Convert request to domain model
 	room := &models.Room{
+		PropertyID:  req.PropertyID,
 		RoomNumber:  req.RoomNumber,
 		RoomType:    req.RoomType,
 		Description: req.Description,
@@ -49,8 +60,8 @@ func (h *RoomHandler) AddRoom(c *gin.Context) {
 	// Call service to create the room
 	createdRoom, err := h.svc.AddRoom(c, room)
 	if err != nil {
-		// Return 500 Internal Server Error if service call fails
-		response.JSON(c, http.StatusInternalServerError, false, "failed to add room", nil, err.Error())
+		// Return 409 for a duplicate room number, 404 for an unknown property, otherwise 500
+		respondError(c, "failed to add room", err)
 		return
 	}
 
@@ -60,10 +71,10 @@ func (h *RoomHandler) AddRoom(c *gin.Context) {
 }
 
 // GetRoomsList handles HTTP GET requests to retrieve all rooms in the system.
-// It fetches the list of all rooms from the service layer and returns them.
+// It fetches the rooms of the properties the caller may see (optionally narrowed by property_id).
 func (h *RoomHandler) GetRoomsList(c *gin.Context) {
 	// Call service to get all rooms
-	rooms, err := h.svc.GetRoomsList(c)
+	rooms, err := h.svc.GetRoomsList(c, middleware.PropertyIDs(c))
 	if err != nil {
 		// Return 400 Bad Request if service call fails
 		response.JSON(c, http.StatusBadRequest, false, "failed to get rooms", nil, err.Error())
@@ -74,10 +85,11 @@ func (h *RoomHandler) GetRoomsList(c *gin.Context) {
 }
 
 // GetAvailableRooms handles HTTP GET requests to retrieve all available rooms.
-// It fetches rooms that are available for booking (with caching support).
+// It fetches rooms that are available for booking (with caching support), limited to the
+// properties the caller may see and optionally narrowed by property_id.
 func (h *RoomHandler) GetAvailableRooms(c *gin.Context) {
 	// Call service to get available rooms (may be cached)
-	rooms, err := h.svc.GetAvailableRooms(c)
+	rooms, err := h.svc.GetAvailableRooms(c, middleware.PropertyIDs(c))
 	if err != nil {
 		// Return 400 Bad Request if service call fails
 		response.JSON(c, http.StatusBadRequest, false, "failed to get available rooms", nil, err.Error())
@@ -86,3 +98,74 @@ func (h *RoomHandler) GetAvailableRooms(c *gin.Context) {
 	// Return 200 OK with the list of available rooms
 	response.JSON(c, http.StatusOK, true, "available rooms fetched successfully", rooms, "")
 }
+
+// RoomSearchQuery represents the query parameters for the faceted room search.
+// Amenity lists may be given as repeated parameters or as a comma-separated value.
+type RoomSearchQuery struct {
+	AmenitiesAll []string `form:"amenities_all"`                           // Rooms must have all of these amenities
+	AmenitiesAny []string `form:"amenities_any"`                           // Rooms must have any of these amenities
+	MinPrice     *float64 `form:"min_price" binding:"omitempty,min=0"`     // Minimum price per night
+	MaxPrice     *float64 `form:"max_price" binding:"omitempty,min=0"`     // Maximum price per night
+	MinCapacity  *int     `form:"capacity" binding:"omitempty,min=1"`      // Minimum guest capacity
+	Floor        *int     `form:"floor"`                                   // Floor number
+	RoomType     string   `form:"room_type"`                               // Room type
+	Sort         string   `form:"sort"`                                    // Sort field
+	Order        string   `form:"order"`                                   // Sort direction (asc or desc)
+	Page         int      `form:"page" binding:"omitempty,min=1"`          // Page number
+	Limit        int      `form:"limit" binding:"omitempty,min=1,max=100"` // Rooms per page
+}
+
+// SearchRooms handles HTTP GET requests for the faceted room search.
+// It supports amenity, price, capacity, floor and type filters with sorting and pagination,
+// and returns facet counts for amenities and room types.
+func (h *RoomHandler) SearchRooms(c *gin.Context) {
+	var req RoomSearchQuery
+	// Parse and validate query parameters
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, err.Error())
+		return
+	}
+
+	filter := models.RoomSearchFilter{
+		PropertyIDs:  middleware.PropertyIDs(c),
+		AmenitiesAll: splitList(req.AmenitiesAll),
+		AmenitiesAny: splitList(req.AmenitiesAny),
+		MinPrice:     req.MinPrice,
+		MaxPrice:     req.MaxPrice,
+		MinCapacity:  req.MinCapacity,
+		Floor:        req.Floor,
+		RoomType:     req.RoomType,
+		Sort:         req.Sort,
+		Order:        req.Order,
+		Page:         req.Page,
+		Limit:        req.Limit,
+	}
+
+	// Call service to run the search
+	result, err := h.svc.SearchRooms(c, filter)
+	if err != nil {
+		// Return 400 Bad Request for invalid filters
+		if errors.Is(err, service.ErrValidation) {
+			response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, err.Error())
+			return
+		}
+		// Return 500 Internal Server Error for other errors
+		response.JSON(c, http.StatusInternalServerError, false, "failed to search rooms", nil, err.Error())
+		return
+	}
+	// Return 200 OK with the matching rooms and facets
+	response.JSON(c, http.StatusOK, true, "rooms fetched successfully", result, "")
+}
+
+// splitList flattens repeated and comma-separated query values into a list of trimmed, non-empty items.
+func splitList(values []string) []string {
+	var items []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
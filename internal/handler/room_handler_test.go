@@ -9,6 +9,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"industry-api/internal/middleware"
 	"industry-api/internal/models"
 	"industry-api/internal/service"
 
@@ -17,30 +18,38 @@ import (
 
 type mockRoomSvcRepo struct {
 	add       func(ctx context.Context, room *models.Room) error
-	list      func(ctx context.Context) ([]*models.Room, error)
-	available func(ctx context.Context) ([]*models.Room, error)
+	list      func(ctx context.Context, propertyIDs []int) ([]*models.Room, error)
+	available func(ctx context.Context, propertyIDs []int) ([]*models.Room, error)
+	search    func(ctx context.Context, filter models.RoomSearchFilter) (*models.RoomSearchResponse, error)
 }
 
 func (m *mockRoomSvcRepo) AddRoom(ctx context.Context, room *models.Room) error {
 	return m.add(ctx, room)
 }
-func (m *mockRoomSvcRepo) GetRoomsList(ctx context.Context) ([]*models.Room, error) {
-	return m.list(ctx)
+func (m *mockRoomSvcRepo) GetRoomsList(ctx context.Context, propertyIDs []int) ([]*models.Room, error) {
+	return m.list(ctx, propertyIDs)
 }
-func (m *mockRoomSvcRepo) GetAvailableRooms(ctx context.Context) ([]*models.Room, error) {
-	return m.available(ctx)
+func (m *mockRoomSvcRepo) GetAvailableRooms(ctx context.Context, propertyIDs []int) ([]*models.Room, error) {
+	return m.available(ctx, propertyIDs)
+}
+func (m *mockRoomSvcRepo) SearchRooms(ctx context.Context, filter models.RoomSearchFilter) (*models.RoomSearchResponse, error) {
+	return m.search(ctx, filter)
 }
 
 func TestAddRoomHandler_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mr := &mockRoomSvcRepo{
-		add:       func(ctx context.Context, room *models.Room) error { room.ID = 1; return nil },
-		list:      func(ctx context.Context) ([]*models.Room, error) { return nil, errors.New("not-impl") },
-		available: func(ctx context.Context) ([]*models.Room, error) { return nil, errors.New("not-impl") },
+		add: func(ctx context.Context, room *models.Room) error { room.ID = 1; return nil },
+		list: func(ctx context.Context, propertyIDs []int) ([]*models.Room, error) {
+			return nil, errors.New("not-impl")
+		},
+		available: func(ctx context.Context, propertyIDs []int) ([]*models.Room, error) {
+			return nil, errors.New("not-impl")
+		},
 	}
-	h := NewRoomHandler(service.NewRoomService(mr))
+	h := NewRoomHandler(service.NewRoomService(mr, nil))
 
-	reqBody := models.RoomRequest{RoomNumber: "101", RoomType: "Deluxe", Description: "test room", Price: 100, Capacity: 2, Floor: 1, Amenities: []string{"wifi"}}
+	reqBody := models.RoomRequest{PropertyID: 1, RoomNumber: "101", RoomType: "Deluxe", Description: "test room", Price: 100, Capacity: 2, Floor: 1, Amenities: []string{"wifi"}}
 	b, _ := json.Marshal(reqBody)
 
 	w := httptest.NewRecorder()
@@ -59,12 +68,14 @@ func TestGetRoomsListHandler(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mr := &mockRoomSvcRepo{
 		add: func(ctx context.Context, room *models.Room) error { return errors.New("not-impl") },
-		list: func(ctx context.Context) ([]*models.Room, error) {
+		list: func(ctx context.Context, propertyIDs []int) ([]*models.Room, error) {
 			return []*models.Room{{ID: 1, RoomNumber: "101"}}, nil
 		},
-		available: func(ctx context.Context) ([]*models.Room, error) { return nil, errors.New("not-impl") },
+		available: func(ctx context.Context, propertyIDs []int) ([]*models.Room, error) {
+			return nil, errors.New("not-impl")
+		},
 	}
-	h := NewRoomHandler(service.NewRoomService(mr))
+	h := NewRoomHandler(service.NewRoomService(mr, nil))
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -74,3 +85,77 @@ func TestGetRoomsListHandler(t *testing.T) {
 		t.Fatalf("expected 200, got %d", w.Code)
 	}
 }
+
+func TestSearchRoomsHandler_ParsesAmenities(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var got models.RoomSearchFilter
+	mr := &mockRoomSvcRepo{
+		search: func(ctx context.Context, filter models.RoomSearchFilter) (*models.RoomSearchResponse, error) {
+			got = filter
+			return &models.RoomSearchResponse{Page: filter.Page, Limit: filter.Limit}, nil
+		},
+	}
+	h := NewRoomHandler(service.NewRoomService(mr, nil))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/api/v1/rooms?amenities_all=wifi,tv&amenities_all=minibar&amenities_any=balcony&sort=price&order=desc", nil)
+	h.SearchRooms(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	if len(got.AmenitiesAll) != 3 || got.AmenitiesAll[2] != "minibar" || len(got.AmenitiesAny) != 1 {
+		t.Fatalf("unexpected amenity filters: %+v", got)
+	}
+}
+
+func TestSearchRoomsHandler_InvalidSort(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mr := &mockRoomSvcRepo{}
+	h := NewRoomHandler(service.NewRoomService(mr, nil))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/api/v1/rooms?sort=unknown", nil)
+	h.SearchRooms(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestRoomHandlers_ApplyPropertyScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var gotScope []int
+	mr := &mockRoomSvcRepo{
+		add: func(ctx context.Context, room *models.Room) error { return errors.New("should not be called") },
+		list: func(ctx context.Context, propertyIDs []int) ([]*models.Room, error) {
+			gotScope = propertyIDs
+			return []*models.Room{}, nil
+		},
+	}
+	h := NewRoomHandler(service.NewRoomService(mr, nil))
+
+	// listing passes the property filter through to the repository
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/api/v1/rooms/allRoomsList?property_id=2", nil)
+	middleware.PublicPropertyScope(nil)(c)
+	h.GetRoomsList(c)
+	if w.Code != http.StatusOK || len(gotScope) != 1 || gotScope[0] != 2 {
+		t.Fatalf("expected scope [2], got %v (status %d)", gotScope, w.Code)
+	}
+
+	// adding a room outside the scope is forbidden
+	b, _ := json.Marshal(models.RoomRequest{PropertyID: 3, RoomNumber: "101", RoomType: "Deluxe", Description: "d", Price: 100, Capacity: 2, Floor: 1, Amenities: []string{"wifi"}})
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/api/v1/rooms/add?property_id=2", bytes.NewBuffer(b))
+	c.Request.Header.Set("Content-Type", "application/json")
+	middleware.PropertyScope(nil)(c)
+	h.AddRoom(c)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d body=%s", w.Code, w.Body.String())
+	}
+}
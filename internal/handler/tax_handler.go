@@ -0,0 +1,116 @@
+// Package handler contains HTTP request handlers for all API endpoints.
+// Handlers receive HTTP requests, validate input, call services, and send responses.
+package handler
+
+import (
+	"industry-api/internal/middleware"
+	"industry-api/internal/models"
+	"industry-api/internal/response"
+	"industry-api/internal/service"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TaxHandler handles HTTP requests related to tax rates and the tax report.
+type TaxHandler struct {
+	svc *service.TaxService // Service layer for business logic
+}
+
+// NewTaxHandler creates and returns a new instance of TaxHandler.
+// It accepts a TaxService dependency for handling tax operations.
+func NewTaxHandler(svc *service.TaxService) *TaxHandler {
+	return &TaxHandler{svc: svc}
+}
+
+// TaxReportQuery represents the query parameters for the tax report.
+type TaxReportQuery struct {
+	From string `form:"from" binding:"required"` // First arrival date (YYYY-MM-DD)
+	To   string `form:"to" binding:"required"`   // Last arrival date (YYYY-MM-DD)
+}
+
+// CreateTaxRate handles HTTP POST requests to configure a tax or fee.
+// Returns 201 Created with the rate; it applies to bookings priced from then on.
+func (h *TaxHandler) CreateTaxRate(c *gin.Context) {
+	var req models.TaxRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, err.Error())
+		return
+	}
+	if req.PropertyID != nil && !canAccessProperty(c, *req.PropertyID) {
+		response.JSON(c, http.StatusNotFound, false, "failed to create tax rate", nil, "property not found")
+		return
+	}
+
+	rate, err := h.svc.CreateTaxRate(c, &models.TaxRate{
+		PropertyID:     req.PropertyID,
+		Code:           req.Code,
+		Name:           req.Name,
+		Basis:          req.Basis,
+		Period:         req.Period,
+		Rate:           req.Rate,
+		Inclusive:      req.Inclusive,
+		ChildrenExempt: req.ChildrenExempt,
+		ValidFrom:      req.ValidFrom,
+		ValidTo:        req.ValidTo,
+	})
+	if err != nil {
+		respondError(c, "failed to create tax rate", err)
+		return
+	}
+	response.JSON(c, http.StatusCreated, true, "tax rate created successfully", rate, "")
+}
+
+// ListTaxRates handles HTTP GET requests for the tax rates of the caller's properties.
+func (h *TaxHandler) ListTaxRates(c *gin.Context) {
+	rates, err := h.svc.ListTaxRates(c, middleware.PropertyIDs(c))
+	if err != nil {
+		respondError(c, "failed to list tax rates", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "tax rates fetched successfully", rates, "")
+}
+
+// DeleteTaxRate handles HTTP DELETE requests that remove a tax rate.
+// Bookings already charged with the rate keep their tax lines.
+func (h *TaxHandler) DeleteTaxRate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid ID", nil, "ID must be a valid integer")
+		return
+	}
+	if err := h.svc.DeleteTaxRate(c, id); err != nil {
+		respondError(c, "failed to delete tax rate", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "tax rate deleted successfully", nil, "")
+}
+
+// GetTaxReport handles HTTP GET requests for the tax charged on stays arriving between the
+// from and to dates, per property and tax code.
+func (h *TaxHandler) GetTaxReport(c *gin.Context) {
+	var req TaxReportQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, err.Error())
+		return
+	}
+	from, err := time.Parse("2006-01-02", req.From)
+	if err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, "from must be in YYYY-MM-DD format")
+		return
+	}
+	to, err := time.Parse("2006-01-02", req.To)
+	if err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, "to must be in YYYY-MM-DD format")
+		return
+	}
+
+	report, err := h.svc.TaxReport(c, from, to, middleware.PropertyIDs(c))
+	if err != nil {
+		respondError(c, "failed to build tax report", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "tax report fetched successfully", report, "")
+}
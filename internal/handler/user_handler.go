@@ -5,6 +5,7 @@ package handler
 import (
 	"bytes"
 	"fmt"
+	"industry-api/internal/middleware"
 	"industry-api/internal/models"
 	"industry-api/internal/response"
 	"industry-api/internal/service"
@@ -28,6 +29,7 @@ func NewUserHandler(svc *service.UserService) *UserHandler {
 
 // Register handles HTTP POST requests for user registration.
 // It validates the registration request, creates a new user account, and returns the created user.
+// Accounts are created as guests; only an authenticated admin may give a new account another role.
 func (h *UserHandler) Register(c *gin.Context) {
 	var req models.RegisterRequest
 
@@ -41,6 +43,18 @@ func (h *UserHandler) Register(c *gin.Context) {
 		response.JSON(c, http.StatusBadRequest, false, "all fields are required", nil, "missing required fields")
 		return
 	}
+	role := models.RoleGuest
+	if req.Role != "" && req.Role != models.RoleGuest {
+		if _, callerRole, ok := middleware.CurrentUser(c); !ok || callerRole != models.RoleAdmin {
+			response.JSON(c, http.StatusForbidden, false, "forbidden", nil, "only admins may assign roles")
+			return
+		}
+		if req.Role != models.RoleAdmin && req.Role != models.RoleStaff {
+			response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, fmt.Sprintf("unknown role %q", req.Role))
+			return
+		}
+		role = req.Role
+	}
 
 	// Convert request to domain model
 	user := &models.User{
@@ -48,7 +62,7 @@ func (h *UserHandler) Register(c *gin.Context) {
 		Email:    req.Email,
 		Password: req.Password,
 		Phone:    req.Phone,
-		Role:     req.Role,
+		Role:     role,
 	}
 	// Call service to create the user account
 	createdUser, err := h.svc.CreateUser(c.Request.Context(), user)
@@ -113,7 +127,7 @@ func (h *UserHandler) GetUserList(c *gin.Context) {
 	}
 
 	// Call service to get filtered user list
-	users, err := h.svc.GetUserList(c, req.Role, req.IsActive, req.Search, req.Page, req.Limit)
+	users, err := h.svc.GetUserList(c, req.Role, req.IsActive, req.Search, middleware.PropertyIDs(c), req.Page, req.Limit)
 	if err != nil {
 		// Return 500 Internal Server Error if service call fails
 		response.JSON(c, http.StatusInternalServerError, false, "failed to get user list", nil, err.Error())
@@ -139,7 +153,7 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 		return
 	}
 	// Call service to retrieve user by ID
-	user, err := h.svc.GetUserByID(c, userID)
+	user, err := h.svc.GetUserByID(c, userID, middleware.PropertyIDs(c))
 	if err != nil {
 		// Return 404 Not Found if user doesn't exist
 		if err.Error() == "user not found" {
@@ -195,7 +209,7 @@ func (h *UserHandler) UpdateUserStatus(c *gin.Context) {
 	}
 
 	// Call service to update user status
-	user, err := h.svc.UpdateUserStatus(c, userID, *req.IsActive)
+	user, err := h.svc.UpdateUserStatus(c, userID, *req.IsActive, middleware.PropertyIDs(c))
 	if err != nil {
 		// Return 404 Not Found if user doesn't exist
 		if err.Error() == "user not found" {
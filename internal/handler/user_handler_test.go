@@ -8,11 +8,14 @@ import (
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
+	"industry-api/internal/middleware"
 	"industry-api/internal/models"
 	"industry-api/internal/service"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -22,7 +25,7 @@ type mockRepo struct {
 	CreateUserFn       func(ctx context.Context, user *models.User) error
 	GetUserByIDFn      func(ctx context.Context, id int) (*models.User, error)
 	UpdateUserStatusFn func(ctx context.Context, id int, isActive bool) (*models.User, error)
-	GetUserListFn      func(ctx context.Context, role string, isActive *bool, search string, page, limit int) (*models.UserListResponse, error)
+	GetUserListFn      func(ctx context.Context, role string, isActive *bool, search string, propertyIDs []int, page, limit int) (*models.UserListResponse, error)
 }
 
 func (m *mockRepo) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
@@ -37,8 +40,8 @@ func (m *mockRepo) GetUserByID(ctx context.Context, id int) (*models.User, error
 func (m *mockRepo) UpdateUserStatus(ctx context.Context, id int, isActive bool) (*models.User, error) {
 	return m.UpdateUserStatusFn(ctx, id, isActive)
 }
-func (m *mockRepo) GetUserList(ctx context.Context, role string, isActive *bool, search string, page, limit int) (*models.UserListResponse, error) {
-	return m.GetUserListFn(ctx, role, isActive, search, page, limit)
+func (m *mockRepo) GetUserList(ctx context.Context, role string, isActive *bool, search string, propertyIDs []int, page, limit int) (*models.UserListResponse, error) {
+	return m.GetUserListFn(ctx, role, isActive, search, propertyIDs, page, limit)
 }
 
 func TestRegisterHandler_Success(t *testing.T) {
@@ -73,6 +76,44 @@ func TestRegisterHandler_Success(t *testing.T) {
 	}
 }
 
+func TestRegisterHandler_OnlyAdminsAssignRoles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var created *models.User
+	mr := &mockRepo{
+		GetUserByEmailFn: func(ctx context.Context, email string) (*models.User, error) { return nil, context.Canceled },
+		CreateUserFn:     func(ctx context.Context, user *models.User) error { created = user; return nil },
+	}
+	r := gin.New()
+	r.POST("/auth/register", middleware.Authenticate(), NewUserHandler(service.NewUserService(mr)).Register)
+	register := func(role, callerRole string) *httptest.ResponseRecorder {
+		b, _ := json.Marshal(models.RegisterRequest{Name: "Test", Email: "t@example.com", Password: "pw12345", Phone: "1234567890", Role: role})
+		req, _ := http.NewRequest("POST", "/auth/register", bytes.NewBuffer(b))
+		req.Header.Set("Content-Type", "application/json")
+		if callerRole != "" {
+			claims := jwt.MapClaims{"user_id": "1", "role": callerRole, "exp": time.Now().Add(time.Hour).Unix()}
+			token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(os.Getenv("JWT_SECRET")))
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := register("", ""); w.Code != http.StatusCreated || created.Role != models.RoleGuest {
+		t.Fatalf("expected a guest account, got %d role=%v", w.Code, created)
+	}
+	created = nil
+	if w := register(models.RoleAdmin, ""); w.Code != http.StatusForbidden || created != nil {
+		t.Fatalf("expected 403 for an anonymous admin registration, got %d", w.Code)
+	}
+	if w := register(models.RoleStaff, models.RoleStaff); w.Code != http.StatusForbidden || created != nil {
+		t.Fatalf("expected 403 for a staff member assigning a role, got %d", w.Code)
+	}
+	if w := register(models.RoleStaff, models.RoleAdmin); w.Code != http.StatusCreated || created.Role != models.RoleStaff {
+		t.Fatalf("expected an admin to create a staff account, got %d", w.Code)
+	}
+}
+
 func TestLoginHandler_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	// prepare password hash
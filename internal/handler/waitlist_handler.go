@@ -0,0 +1,120 @@
+// Package handler contains HTTP request handlers for all API endpoints.
+// Handlers receive HTTP requests, validate input, call services, and send responses.
+package handler
+
+import (
+	"errors"
+	"industry-api/internal/middleware"
+	"industry-api/internal/models"
+	"industry-api/internal/response"
+	"industry-api/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WaitlistHandler handles HTTP requests related to the waitlist for sold-out dates.
+type WaitlistHandler struct {
+	svc *service.WaitlistService // Service layer for business logic
+}
+
+// NewWaitlistHandler creates and returns a new instance of WaitlistHandler.
+// It accepts a WaitlistService dependency for handling waitlist operations.
+func NewWaitlistHandler(svc *service.WaitlistService) *WaitlistHandler {
+	return &WaitlistHandler{svc: svc}
+}
+
+// JoinWaitlist handles HTTP POST requests that put the caller on the waitlist for a room type
+// and date range. Returns 201 Created with the waiting entry.
+func (h *WaitlistHandler) JoinWaitlist(c *gin.Context) {
+	var req models.WaitlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, err.Error())
+		return
+	}
+	userID, _, _ := middleware.CurrentUser(c)
+
+	entry, err := h.svc.Join(c, &models.WaitlistEntry{
+		PropertyID:   req.PropertyID,
+		UserID:       userID,
+		RoomType:     req.RoomType,
+		CheckInDate:  req.CheckInDate,
+		CheckOutDate: req.CheckOutDate,
+		Adults:       req.Adults,
+		Children:     req.Children,
+	})
+	if err != nil {
+		respondError(c, "failed to join waitlist", err)
+		return
+	}
+	response.JSON(c, http.StatusCreated, true, "joined waitlist successfully", entry, "")
+}
+
+// ListWaitlist handles HTTP GET requests for the caller's waitlist entries, newest first.
+func (h *WaitlistHandler) ListWaitlist(c *gin.Context) {
+	userID, _, _ := middleware.CurrentUser(c)
+	entries, err := h.svc.ListEntries(c, userID)
+	if err != nil {
+		respondError(c, "failed to list waitlist entries", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "waitlist entries fetched successfully", entries, "")
+}
+
+// LeaveWaitlist handles HTTP DELETE requests that take an entry off the waitlist.
+// Guests only leave with their own entries and staff only with entries of their properties.
+// Returns 409 Conflict once the entry was booked, expired or already cancelled.
+func (h *WaitlistHandler) LeaveWaitlist(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.JSON(c, http.StatusBadRequest, false, "invalid ID", nil, "ID must be a valid integer")
+		return
+	}
+	entry, err := h.svc.GetEntry(c, id)
+	if err != nil {
+		respondError(c, "failed to leave waitlist", err)
+		return
+	}
+	userID, role, _ := middleware.CurrentUser(c)
+	if (role == models.RoleGuest && entry.UserID != userID) || !canAccessProperty(c, entry.PropertyID) {
+		response.JSON(c, http.StatusNotFound, false, "failed to leave waitlist", nil, "waitlist entry not found")
+		return
+	}
+
+	entry, err = h.svc.Leave(c, id)
+	if err != nil {
+		respondError(c, "failed to leave waitlist", err)
+		return
+	}
+	response.JSON(c, http.StatusOK, true, "left waitlist successfully", entry, "")
+}
+
+// ClaimOffer handles HTTP POST requests that turn a waitlist offer into a booking.
+// The signed token from the offer link is read from the token query parameter or the JSON body;
+// it identifies the guest, so no login is needed. Returns 201 Created with the booking, or
+// 409 Conflict if the offer lapsed or the room was taken, in which case the guest keeps their
+// place in line.
+func (h *WaitlistHandler) ClaimOffer(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		var req models.WaitlistClaimRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, err.Error())
+			return
+		}
+		token = req.Token
+	}
+
+	booking, err := h.svc.Claim(c, token)
+	if err != nil {
+		var conflict *service.BookingConflictError
+		if errors.As(err, &conflict) {
+			response.JSON(c, http.StatusConflict, false, "room is already booked for the selected dates", conflict.Conflict, err.Error())
+			return
+		}
+		respondError(c, "failed to claim waitlist offer", err)
+		return
+	}
+	response.JSON(c, http.StatusCreated, true, "waitlist offer claimed successfully", booking, "")
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"industry-api/internal/middleware"
+	"industry-api/internal/models"
+	"industry-api/internal/notify"
+	"industry-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type mockWaitlistSvcRepo struct {
+	entry *models.WaitlistEntry
+}
+
+func (m *mockWaitlistSvcRepo) AddWaitlistEntry(ctx context.Context, entry *models.WaitlistEntry) error {
+	entry.ID, entry.Status = 2, models.WaitlistWaiting
+	m.entry = entry
+	return nil
+}
+func (m *mockWaitlistSvcRepo) GetWaitlistEntry(ctx context.Context, id int) (*models.WaitlistEntry, error) {
+	copied := *m.entry
+	return &copied, nil
+}
+func (m *mockWaitlistSvcRepo) ListWaitlistEntries(ctx context.Context, userID int) ([]models.WaitlistEntry, error) {
+	return []models.WaitlistEntry{*m.entry}, nil
+}
+func (m *mockWaitlistSvcRepo) CancelWaitlistEntry(ctx context.Context, id int) (*models.WaitlistEntry, error) {
+	m.entry.Status = models.WaitlistCancelled
+	return m.entry, nil
+}
+func (m *mockWaitlistSvcRepo) ExpireWaitlistOffers(ctx context.Context, now time.Time) (int, error) {
+	return 0, nil
+}
+func (m *mockWaitlistSvcRepo) OfferWaitlistRooms(ctx context.Context, expiresAt time.Time, limit int) ([]models.WaitlistEntry, error) {
+	return nil, nil
+}
+func (m *mockWaitlistSvcRepo) ClaimWaitlistOffer(ctx context.Context, id int, booking *models.Booking, now time.Time) error {
+	return nil
+}
+
+func TestWaitlistHandlers_JoinAndLeave(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	os.Setenv("JWT_SECRET", "waitlist-handler-test-secret")
+	mr := &mockWaitlistSvcRepo{}
+	bookings := service.NewBookingService(&mockBookingSvcRepo{}, service.DefaultBookingConfig)
+	h := NewWaitlistHandler(service.NewWaitlistService(mr, bookings, notify.LogNotifier{}, service.WaitlistConfig{OfferTTL: time.Hour, Secret: []byte("secret")}))
+
+	r := gin.New()
+	r.Use(middleware.Authenticate())
+	r.POST("/waitlist", h.JoinWaitlist)
+	r.DELETE("/waitlist/:id", middleware.PropertyScope(nil), h.LeaveWaitlist)
+	r.POST("/waitlist/claim", h.ClaimOffer)
+
+	send := func(method, path string, body []byte, userID int) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		if userID > 0 {
+			claims := jwt.MapClaims{"user_id": fmt.Sprint(userID), "role": models.RoleGuest, "exp": time.Now().Add(time.Hour).Unix()}
+			token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(os.Getenv("JWT_SECRET")))
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	in := time.Now().AddDate(0, 1, 0)
+	body, _ := json.Marshal(models.WaitlistRequest{PropertyID: 1, RoomType: "Deluxe", CheckInDate: in, CheckOutDate: in.AddDate(0, 0, 2), Adults: 2})
+	if w := send("POST", "/waitlist", body, 5); w.Code != http.StatusCreated || mr.entry.UserID != 5 {
+		t.Fatalf("expected 201 for the caller, got %d body=%s", w.Code, w.Body.String())
+	}
+	if w := send("DELETE", "/waitlist/2", nil, 9); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for another guest's entry, got %d", w.Code)
+	}
+	if w := send("DELETE", "/waitlist/2", nil, 5); w.Code != http.StatusOK || mr.entry.Status != models.WaitlistCancelled {
+		t.Fatalf("expected the guest to leave the waitlist, got %d body=%s", w.Code, w.Body.String())
+	}
+	if w := send("POST", "/waitlist/claim?token=2.1.forged", nil, 0); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a forged token, got %d", w.Code)
+	}
+}
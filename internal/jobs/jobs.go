@@ -0,0 +1,41 @@
+// Package jobs runs periodic background work such as releasing expired booking holds.
+// Jobs must be safe to run on several API instances at once; they coordinate through the
+// database (for example with SELECT ... FOR UPDATE SKIP LOCKED) rather than in memory.
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Job performs one pass of background work and returns how many items it processed.
+type Job func(ctx context.Context) (int, error)
+
+// Every runs job immediately and then every interval until ctx is cancelled.
+// Failures are logged and retried on the next tick, so a temporary database outage does not
+// stop the job.
+func Every(ctx context.Context, interval time.Duration, name string, job Job) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		runOnce(ctx, name, job)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce runs a single pass of job and logs its outcome.
+func runOnce(ctx context.Context, name string, job Job) {
+	processed, err := job(ctx)
+	if err != nil {
+		log.Printf("job %s failed after %d items: %v", name, processed, err)
+		return
+	}
+	if processed > 0 {
+		log.Printf("job %s processed %d items", name, processed)
+	}
+}
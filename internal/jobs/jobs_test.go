@@ -0,0 +1,40 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEvery_RunsUntilCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var runs atomic.Int32
+	done := make(chan struct{})
+	go func() {
+		Every(ctx, 5*time.Millisecond, "test", func(ctx context.Context) (int, error) {
+			// failures must not stop the job
+			if runs.Add(1) == 1 {
+				return 0, errors.New("temporary failure")
+			}
+			return 1, nil
+		})
+		close(done)
+	}()
+
+	deadline := time.After(time.Second)
+	for runs.Load() < 3 {
+		select {
+		case <-deadline:
+			t.Fatalf("expected the job to keep running, got %d runs", runs.Load())
+		case <-time.After(time.Millisecond):
+		}
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("expected Every to return after cancellation")
+	}
+}
@@ -0,0 +1,117 @@
+// Package middleware provides Gin middleware shared by the API routes.
+// It covers authentication of Bearer tokens, role checks, property scoping and idempotent retries.
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"industry-api/internal/response"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Context keys under which the authenticated caller is stored.
+const (
+	userIDKey = "auth_user_id"
+	roleKey   = "auth_role"
+)
+
+// Authenticate parses an optional "Authorization: Bearer <token>" header issued by the login endpoint.
+// Requests without the header continue anonymously so that public endpoints keep working;
+// a header carrying an invalid or expired token is rejected with 401 Unauthorized.
+func Authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		if header == "" {
+			c.Next()
+			return
+		}
+
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || strings.TrimSpace(token) == "" {
+			response.JSON(c, http.StatusUnauthorized, false, "unauthorized", nil, "authorization header must use the Bearer scheme")
+			c.Abort()
+			return
+		}
+
+		userID, role, err := parseToken(strings.TrimSpace(token))
+		if err != nil {
+			response.JSON(c, http.StatusUnauthorized, false, "unauthorized", nil, err.Error())
+			c.Abort()
+			return
+		}
+
+		c.Set(userIDKey, userID)
+		c.Set(roleKey, role)
+		c.Next()
+	}
+}
+
+// RequireRoles rejects anonymous callers with 401 Unauthorized and callers whose role
+// is not listed with 403 Forbidden. It must run after Authenticate.
+func RequireRoles(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, role, ok := CurrentUser(c)
+		if !ok {
+			response.JSON(c, http.StatusUnauthorized, false, "unauthorized", nil, "authentication required")
+			c.Abort()
+			return
+		}
+		for _, allowed := range roles {
+			if role == allowed {
+				c.Next()
+				return
+			}
+		}
+		response.JSON(c, http.StatusForbidden, false, "forbidden", nil, fmt.Sprintf("role %q may not access this resource", role))
+		c.Abort()
+	}
+}
+
+// CurrentUser returns the authenticated caller's user ID and role.
+// ok is false for anonymous requests.
+func CurrentUser(c *gin.Context) (userID int, role string, ok bool) {
+	id, exists := c.Get(userIDKey)
+	if !exists {
+		return 0, "", false
+	}
+	return id.(int), c.GetString(roleKey), true
+}
+
+// parseToken verifies an HS256 token signed with JWT_SECRET and extracts the user ID and role claims.
+func parseToken(raw string) (int, string, error) {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		return 0, "", errors.New("JWT_SECRET is not set")
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte(secret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid token: %w", err)
+	}
+
+	// The user_id claim is written from models.User.ID, which is a string
+	var userID int
+	switch v := claims["user_id"].(type) {
+	case string:
+		userID, err = strconv.Atoi(v)
+		if err != nil {
+			return 0, "", errors.New("invalid token: malformed user_id claim")
+		}
+	case float64:
+		userID = int(v)
+	default:
+		return 0, "", errors.New("invalid token: missing user_id claim")
+	}
+
+	role, _ := claims["role"].(string)
+	return userID, role, nil
+}
@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"industry-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func signToken(t *testing.T, userID, role string) string {
+	t.Helper()
+	claims := jwt.MapClaims{"user_id": userID, "role": role, "exp": time.Now().Add(time.Hour).Unix()}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(os.Getenv("JWT_SECRET")))
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return token
+}
+
+type staticResolver map[int][]int
+
+func (r staticResolver) UserPropertyIDs(ctx context.Context, userID int) ([]int, error) {
+	return r[userID], nil
+}
+
+// newScopedRouter returns a router whose /scope and /public routes echo the resolved property scope.
+func newScopedRouter(resolver PropertyResolver) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/scope", Authenticate(), PropertyScope(resolver), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"scope": PropertyIDs(c)})
+	})
+	r.GET("/public", Authenticate(), PublicPropertyScope(resolver), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"scope": PropertyIDs(c)})
+	})
+	r.GET("/staff", Authenticate(), RequireRoles(models.RoleAdmin, models.RoleStaff), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+func serve(r *gin.Engine, path, token string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuthenticate_RejectsInvalidToken(t *testing.T) {
+	os.Setenv("JWT_SECRET", "middleware-test-secret")
+	r := newScopedRouter(staticResolver{})
+
+	if w := serve(r, "/scope", ""); w.Code != http.StatusOK {
+		t.Fatalf("expected anonymous request to pass, got %d", w.Code)
+	}
+	if w := serve(r, "/scope", "not-a-token"); w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for invalid token, got %d", w.Code)
+	}
+}
+
+func TestRequireRoles(t *testing.T) {
+	os.Setenv("JWT_SECRET", "middleware-test-secret")
+	r := newScopedRouter(staticResolver{})
+
+	if w := serve(r, "/staff", ""); w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for anonymous caller, got %d", w.Code)
+	}
+	if w := serve(r, "/staff", signToken(t, "1", models.RoleGuest)); w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for guest, got %d", w.Code)
+	}
+	if w := serve(r, "/staff", signToken(t, "1", models.RoleStaff)); w.Code != http.StatusOK {
+		t.Fatalf("expected 200 for staff, got %d", w.Code)
+	}
+}
+
+func TestPropertyScope_LimitsStaff(t *testing.T) {
+	os.Setenv("JWT_SECRET", "middleware-test-secret")
+	r := newScopedRouter(staticResolver{7: {2, 3}})
+	staff := signToken(t, "7", models.RoleStaff)
+
+	tests := []struct {
+		name  string
+		path  string
+		token string
+		code  int
+		body  string
+	}{
+		{"anonymous sees nothing", "/scope", "", http.StatusOK, `{"scope":[]}`},
+		{"anonymous filter", "/scope?property_id=5", "", http.StatusOK, `{"scope":[]}`},
+		{"anonymous sees the public catalog", "/public", "", http.StatusOK, `{"scope":null}`},
+		{"anonymous public filter", "/public?property_id=5", "", http.StatusOK, `{"scope":[5]}`},
+		{"staff public catalog", "/public", staff, http.StatusOK, `{"scope":[2,3]}`},
+		{"guest sees everything", "/scope", signToken(t, "9", models.RoleGuest), http.StatusOK, `{"scope":null}`},
+		{"admin filter", "/scope?property_id=5", signToken(t, "1", models.RoleAdmin), http.StatusOK, `{"scope":[5]}`},
+		{"staff default", "/scope", staff, http.StatusOK, `{"scope":[2,3]}`},
+		{"staff assigned filter", "/scope?property_id=3", staff, http.StatusOK, `{"scope":[3]}`},
+		{"staff other property", "/scope?property_id=5", staff, http.StatusForbidden, ""},
+		{"unassigned staff", "/scope", signToken(t, "8", models.RoleStaff), http.StatusOK, `{"scope":[]}`},
+		{"bad filter", "/scope?property_id=x", "", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		w := serve(r, tt.path, tt.token)
+		if w.Code != tt.code {
+			t.Fatalf("%s: expected %d, got %d body=%s", tt.name, tt.code, w.Code, w.Body.String())
+		}
+		if tt.body != "" && w.Body.String() != tt.body {
+			t.Fatalf("%s: expected body %s, got %s", tt.name, tt.body, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,149 @@
+// Package middleware provides Gin middleware shared by the API routes.
+// It covers authentication of Bearer tokens, role checks, property scoping and idempotent retries.
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"industry-api/internal/models"
+	"industry-api/internal/repository"
+	"industry-api/internal/response"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying the client's idempotency key.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayHeader is set on responses replayed from an earlier request.
+	IdempotentReplayHeader = "Idempotent-Replayed"
+	// maxIdempotencyKeyLength bounds the idempotency keys accepted from clients.
+	maxIdempotencyKeyLength = 255
+)
+
+// IdempotencyStore keeps the first response sent for each idempotency key of a caller.
+type IdempotencyStore interface {
+	Claim(ctx context.Context, caller, key, requestHash string) (*models.IdempotencyRecord, error)
+	Complete(ctx context.Context, caller, key string, statusCode int, body []byte) error
+	Release(ctx context.Context, caller, key string) error
+}
+
+// Idempotency makes a route safe to retry when the client sends an Idempotency-Key header.
+// The first response for a key is stored and replayed to retries with the same method, URI
+// (query string included) and body; reusing the key for a different request gets 422
+// Unprocessable Entity and a retry that arrives while the original is still running gets 409
+// Conflict. Server errors are not stored so the request can be retried. Requests without the
+// header are not affected.
+// It must run after Authenticate, since keys are scoped to the caller; anonymous requests with a
+// key get 401 Unauthorized.
+func Idempotency(store IdempotencyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, fmt.Sprintf("%s must be at most %d characters", IdempotencyKeyHeader, maxIdempotencyKeyLength))
+			c.Abort()
+			return
+		}
+		userID, _, ok := CurrentUser(c)
+		if !ok {
+			response.JSON(c, http.StatusUnauthorized, false, "unauthorized", nil, fmt.Sprintf("authentication required to use %s", IdempotencyKeyHeader))
+			c.Abort()
+			return
+		}
+
+		// The body is read up front to fingerprint the request and then handed back to the handler
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, "failed to read request body")
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		caller := "user:" + strconv.Itoa(userID)
+		hash := requestHash(c.Request.Method, c.Request.URL.RequestURI(), body)
+		stored, err := store.Claim(c, caller, key, hash)
+		if err != nil {
+			// The key may be released by its request right now; the client can retry shortly
+			if errors.Is(err, repository.ErrConflict) {
+				response.JSON(c, http.StatusConflict, false, "request in progress", nil, fmt.Sprintf("a request with this %s is still being processed", IdempotencyKeyHeader))
+			} else {
+				response.JSON(c, http.StatusInternalServerError, false, "failed to check idempotency key", nil, err.Error())
+			}
+			c.Abort()
+			return
+		}
+		if stored != nil {
+			replayIdempotent(c, stored, hash)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// The outcome is saved even if the client has gone away, since that client is the one retrying
+		ctx := context.WithoutCancel(c.Request.Context())
+		if recorder.Status() >= http.StatusInternalServerError {
+			if err := store.Release(ctx, caller, key); err != nil {
+				log.Printf("failed to release idempotency key %q: %v", key, err)
+			}
+			return
+		}
+		if err := store.Complete(ctx, caller, key, recorder.Status(), recorder.body.Bytes()); err != nil {
+			log.Printf("failed to store response for idempotency key %q: %v", key, err)
+		}
+	}
+}
+
+// replayIdempotent answers a request whose key was already used.
+func replayIdempotent(c *gin.Context, stored *models.IdempotencyRecord, hash string) {
+	switch {
+	case stored.RequestHash != hash:
+		response.JSON(c, http.StatusUnprocessableEntity, false, "idempotency key reused", nil, fmt.Sprintf("%s was already used for a different request", IdempotencyKeyHeader))
+	case stored.StatusCode == nil:
+		response.JSON(c, http.StatusConflict, false, "request in progress", nil, fmt.Sprintf("a request with this %s is still being processed", IdempotencyKeyHeader))
+	default:
+		c.Header(IdempotentReplayHeader, strconv.FormatBool(true))
+		c.Data(*stored.StatusCode, "application/json; charset=utf-8", stored.ResponseBody)
+	}
+}
+
+// requestHash fingerprints a request so a reused key can be told apart from a genuine retry.
+func requestHash(method, uri string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + uri + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder copies everything written to the response so it can be stored.
+type responseRecorder struct {
+	gin.ResponseWriter              // Writer the response is passed through to
+	body               bytes.Buffer // Copy of the response body
+}
+
+// Write passes b through to the client and keeps a copy.
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// WriteString passes s through to the client and keeps a copy.
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"industry-api/internal/models"
+	"industry-api/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// memoryIdempotencyStore keeps idempotency records in memory, keyed by caller and key.
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]*models.IdempotencyRecord
+}
+
+func (s *memoryIdempotencyStore) Claim(ctx context.Context, caller, key, requestHash string) (*models.IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.records[caller+"|"+key]; ok {
+		return existing, nil
+	}
+	s.records[caller+"|"+key] = &models.IdempotencyRecord{Caller: caller, Key: key, RequestHash: requestHash}
+	return nil, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(ctx context.Context, caller, key string, statusCode int, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record := s.records[caller+"|"+key]
+	record.StatusCode = &statusCode
+	record.ResponseBody = body
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(ctx context.Context, caller, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, caller+"|"+key)
+	return nil
+}
+
+// releasingIdempotencyStore reports every key as being released by its request.
+type releasingIdempotencyStore struct {
+	memoryIdempotencyStore
+}
+
+func (s *releasingIdempotencyStore) Claim(ctx context.Context, caller, key, requestHash string) (*models.IdempotencyRecord, error) {
+	return nil, fmt.Errorf("idempotency key is being released: %w", repository.ErrConflict)
+}
+
+// newIdempotentRouter returns a router whose /create route counts its calls and answers with
+// the given status; a request to /slow blocks until release is closed.
+func newIdempotentRouter(store IdempotencyStore, status *int, calls *int, started chan struct{}, release chan struct{}) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	os.Setenv("JWT_SECRET", "middleware-test-secret")
+	r := gin.New()
+	r.POST("/create", Authenticate(), Idempotency(store), func(c *gin.Context) {
+		*calls++
+		c.JSON(*status, gin.H{"call": *calls})
+	})
+	r.POST("/slow", Authenticate(), Idempotency(store), func(c *gin.Context) {
+		close(started)
+		<-release
+		c.Status(http.StatusCreated)
+	})
+	return r
+}
+
+func post(r *gin.Engine, path, key, token, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotency_ReplaysFirstResponse(t *testing.T) {
+	store := &memoryIdempotencyStore{records: map[string]*models.IdempotencyRecord{}}
+	status, calls := http.StatusCreated, 0
+	r := newIdempotentRouter(store, &status, &calls, nil, nil)
+	token := signToken(t, "5", models.RoleGuest)
+
+	first := post(r, "/create", "k1", token, `{"room_id":1}`)
+	retry := post(r, "/create", "k1", token, `{"room_id":1}`)
+	if calls != 1 {
+		t.Fatalf("expected the handler to run once, ran %d times", calls)
+	}
+	if retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() || retry.Header().Get(IdempotentReplayHeader) != "true" {
+		t.Fatalf("expected replay of %d %s, got %d %s", first.Code, first.Body.String(), retry.Code, retry.Body.String())
+	}
+
+	// the same key with another body or query string is rejected
+	if w := post(r, "/create", "k1", token, `{"room_id":2}`); w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 for a reused key, got %d", w.Code)
+	}
+	if w := post(r, "/create?preview=true", "k1", token, `{"room_id":1}`); w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 for a reused key with another query string, got %d", w.Code)
+	}
+	// requests without a key, or from another caller, are not affected
+	post(r, "/create", "", token, `{"room_id":1}`)
+	post(r, "/create", "k1", signToken(t, "9", models.RoleGuest), `{"room_id":1}`)
+	if calls != 3 {
+		t.Fatalf("expected 3 handler calls, got %d", calls)
+	}
+}
+
+func TestIdempotency_RequiresAuthentication(t *testing.T) {
+	store := &memoryIdempotencyStore{records: map[string]*models.IdempotencyRecord{}}
+	status, calls := http.StatusCreated, 0
+	r := newIdempotentRouter(store, &status, &calls, nil, nil)
+
+	if w := post(r, "/create", "k1", "", `{}`); w.Code != http.StatusUnauthorized || calls != 0 || len(store.records) != 0 {
+		t.Fatalf("expected 401 for an anonymous key, got %d after %d calls", w.Code, calls)
+	}
+}
+
+func TestIdempotency_ServerErrorsAreNotStored(t *testing.T) {
+	store := &memoryIdempotencyStore{records: map[string]*models.IdempotencyRecord{}}
+	status, calls := http.StatusInternalServerError, 0
+	r := newIdempotentRouter(store, &status, &calls, nil, nil)
+
+	token := signToken(t, "5", models.RoleGuest)
+	post(r, "/create", "k1", token, `{}`)
+	status = http.StatusCreated
+	if w := post(r, "/create", "k1", token, `{}`); w.Code != http.StatusCreated || calls != 2 {
+		t.Fatalf("expected the retry to run again after a server error, got %d after %d calls", w.Code, calls)
+	}
+}
+
+func TestIdempotency_ConcurrentRetryConflicts(t *testing.T) {
+	store := &memoryIdempotencyStore{records: map[string]*models.IdempotencyRecord{}}
+	started, release := make(chan struct{}), make(chan struct{})
+	status, calls := http.StatusCreated, 0
+	r := newIdempotentRouter(store, &status, &calls, started, release)
+
+	token := signToken(t, "5", models.RoleGuest)
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- post(r, "/slow", "k1", token, `{}`) }()
+	<-started
+	if w := post(r, "/slow", "k1", token, `{}`); w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 while the original is running, got %d", w.Code)
+	}
+	close(release)
+	if w := <-done; w.Code != http.StatusCreated {
+		t.Fatalf("expected the original request to succeed, got %d", w.Code)
+	}
+}
+
+func TestIdempotency_KeyBeingReleasedConflicts(t *testing.T) {
+	status, calls := http.StatusCreated, 0
+	r := newIdempotentRouter(&releasingIdempotencyStore{}, &status, &calls, nil, nil)
+
+	if w := post(r, "/create", "k1", signToken(t, "5", models.RoleGuest), `{}`); w.Code != http.StatusConflict || calls != 0 {
+		t.Fatalf("expected 409 without running the request, got %d after %d calls", w.Code, calls)
+	}
+}
@@ -0,0 +1,101 @@
+// Package middleware provides Gin middleware shared by the API routes.
+// It covers authentication of Bearer tokens, role checks, property scoping and idempotent retries.
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"industry-api/internal/models"
+	"industry-api/internal/response"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// propertyScopeKey is the context key under which the resolved property scope is stored.
+const propertyScopeKey = "property_scope"
+
+// PropertyResolver looks up the properties a staff user is assigned to.
+type PropertyResolver interface {
+	UserPropertyIDs(ctx context.Context, userID int) ([]int, error)
+}
+
+// PropertyScope resolves which properties the request may see and stores the result for handlers.
+// The optional property_id query parameter narrows the scope to one property.
+// Staff users are limited to their assigned properties and receive 403 Forbidden when asking
+// for another one; anonymous callers see no property, and admins and guests may see all of them.
+// It must run after Authenticate.
+func PropertyScope(resolver PropertyResolver) gin.HandlerFunc {
+	return propertyScope(resolver, false)
+}
+
+// PublicPropertyScope is PropertyScope for the public catalog of properties and rooms, where
+// anonymous callers may see every property like guests do.
+func PublicPropertyScope(resolver PropertyResolver) gin.HandlerFunc {
+	return propertyScope(resolver, true)
+}
+
+// propertyScope implements PropertyScope, letting anonymous callers see every property when public is set.
+func propertyScope(resolver PropertyResolver, public bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Parse the optional property filter
+		var requested *int
+		if raw := c.Query("property_id"); raw != "" {
+			id, err := strconv.Atoi(raw)
+			if err != nil || id <= 0 {
+				response.JSON(c, http.StatusBadRequest, false, "invalid request", nil, "property_id must be a positive integer")
+				c.Abort()
+				return
+			}
+			requested = &id
+		}
+
+		// Staff only see the properties they are assigned to
+		userID, role, ok := CurrentUser(c)
+		if ok && role == models.RoleStaff {
+			assigned, err := resolver.UserPropertyIDs(c, userID)
+			if err != nil {
+				response.JSON(c, http.StatusInternalServerError, false, "failed to resolve properties", nil, err.Error())
+				c.Abort()
+				return
+			}
+			if assigned == nil {
+				assigned = []int{}
+			}
+			if requested != nil {
+				if !slices.Contains(assigned, *requested) {
+					response.JSON(c, http.StatusForbidden, false, "forbidden", nil, fmt.Sprintf("not assigned to property %d", *requested))
+					c.Abort()
+					return
+				}
+				assigned = []int{*requested}
+			}
+			c.Set(propertyScopeKey, assigned)
+			c.Next()
+			return
+		}
+
+		// Anonymous callers only see the public catalog
+		if !ok && !public {
+			c.Set(propertyScopeKey, []int{})
+			c.Next()
+			return
+		}
+		if requested != nil {
+			c.Set(propertyScopeKey, []int{*requested})
+		}
+		c.Next()
+	}
+}
+
+// PropertyIDs returns the property scope resolved by PropertyScope.
+// A nil result means the request may see every property; an empty, non-nil result means none.
+func PropertyIDs(c *gin.Context) []int {
+	scope, exists := c.Get(propertyScopeKey)
+	if !exists {
+		return nil
+	}
+	return scope.([]int)
+}
@@ -3,33 +3,204 @@ package models
 
 import "time"
 
+// Booking lifecycle statuses.
+const (
+	BookingStatusPending    = "pending"     // Created, awaiting confirmation
+	BookingStatusConfirmed  = "confirmed"   // Confirmed and holding the room
+	BookingStatusCheckedIn  = "checked_in"  // Guest has arrived
+	BookingStatusCheckedOut = "checked_out" // Guest has left
+	BookingStatusCancelled  = "cancelled"   // Cancelled before arrival
+	BookingStatusNoShow     = "no_show"     // Guest never arrived
+)
+
+// Booking payment statuses.
+const (
+	PaymentStatusPending   = "pending"    // Nothing has been paid yet
+	PaymentStatusPaid      = "paid"       // The booking has been paid
+	PaymentStatusRefundDue = "refund_due" // Money must be returned to the guest
+)
+
 // Booking represents a hotel room booking record stored in the database.
 type Booking struct {
-	ID              int       `json:"id"`               // Unique booking identifier
-	UserID          int       `json:"user_id"`          // ID of the user making the booking
-	RoomID          int       `json:"room_id"`          // ID of the room being booked
-	CheckInDate     time.Time `json:"check_in_date"`    // Date and time of guest arrival
-	CheckOutDate    time.Time `json:"check_out_date"`   // Date and time of guest departure
-	Adults          int       `json:"adults"`           // Number of adults in the booking
-	Children        int       `json:"children"`         // Number of children in the booking
-	TotalAmount     float64   `json:"total_amount"`     // Total cost of the booking
-	Status          string    `json:"status"`           // Booking status (e.g., "confirmed", "cancelled")
-	PaymentStatus   string    `json:"payment_status"`   // Payment status (e.g., "pending", "completed")
-	SpecialRequests string    `json:"special_requests"` // Any special requests from the guest
-	CreatedAt       time.Time `json:"created_at"`       // Timestamp when the booking was created
-	UpdatedAt       time.Time `json:"updated_at"`       // Timestamp of the last update
+	ID              int                `json:"id"`               // Unique booking identifier
+	PropertyID      int                `json:"property_id"`      // Property of the booked room
+	UserID          int                `json:"user_id"`          // ID of the user making the booking
+	RoomID          int                `json:"room_id"`          // ID of the room being booked
+	CheckInDate     time.Time          `json:"check_in_date"`    // Date and time of guest arrival
+	CheckOutDate    time.Time          `json:"check_out_date"`   // Date and time of guest departure
+	Adults          int                `json:"adults"`           // Number of adults in the booking
+	Children        int                `json:"children"`         // Number of children in the booking
+	TotalAmount     float64            `json:"total_amount"`     // Total cost of the booking (computed by the server)
+	PriceBreakdown  *PriceBreakdown    `json:"price_breakdown"`  // Itemised price used to compute TotalAmount
+	Status          string             `json:"status"`           // Booking status (one of the BookingStatus* values)
+	PaymentStatus   string             `json:"payment_status"`   // Payment status (one of the PaymentStatus* values)
+	Cancellation    *CancellationQuote `json:"cancellation"`     // Penalty and refund applied when the booking was cancelled
+	SpecialRequests string             `json:"special_requests"` // Any special requests from the guest
+	Version         int                `json:"version"`          // Incremented by every modification
+	CheckedInAt     *time.Time         `json:"checked_in_at"`    // Time the guest checked in
+	CheckedOutAt    *time.Time         `json:"checked_out_at"`   // Time the guest checked out
+	HoldExpiresAt   *time.Time         `json:"hold_expires_at"`  // Time an unpaid pending booking releases its room
+	ReservationID   *int               `json:"reservation_id"`   // Group reservation the booking belongs to, if any
+	PromoCodes      []string           `json:"-"`                // Promo codes to redeem when the booking is created
+	Redemptions     []PromoRedemption  `json:"-"`                // Discounts applied from PromoCodes, recorded with the booking
+	CreatedAt       time.Time          `json:"created_at"`       // Timestamp when the booking was created
+	UpdatedAt       time.Time          `json:"updated_at"`       // Timestamp of the last update
 }
 
 // BookingRequest represents the HTTP request body for creating a new booking.
 type BookingRequest struct {
-	UserID          int       `json:"user_id" binding:"required"`        // ID of the user making the booking
+	UserID          int       `json:"user_id"`                           // User the booking is for; staff may book for a guest, everyone else books for themselves
 	RoomID          int       `json:"room_id" binding:"required"`        // ID of the room to book
 	CheckInDate     time.Time `json:"check_in_date" binding:"required"`  // Date of arrival
 	CheckOutDate    time.Time `json:"check_out_date" binding:"required"` // Date of departure
 	Adults          int       `json:"adults" binding:"required"`         // Number of adults
-	Children        int       `json:"children" binding:"required"`       // Number of children
+	Children        int       `json:"children"`                          // Number of children
 	SpecialRequests string    `json:"special_requests"`                  // Optional special requests
-	Status          string    `json:"status" binding:"required"`         // Initial booking status
-	PaymentStatus   string    `json:"payment_status" binding:"required"` // Initial payment status
-	TotalAmount     float64   `json:"total_amount" binding:"required"`   // Total booking amount
+	TotalAmount     float64   `json:"total_amount"`                      // Optional expected total; rejected if it differs from the computed price
+	PromoCodes      []string  `json:"promo_codes"`                       // Optional promo codes to redeem
+}
+
+// BookingConflict describes an existing booking that occupies some of the requested nights.
+type BookingConflict struct {
+	BookingID       int       `json:"booking_id"`                  // ID of the conflicting booking
+	RoomID          int       `json:"room_id"`                     // Room both bookings want
+	CheckInDate     time.Time `json:"check_in_date"`               // Arrival of the conflicting booking
+	CheckOutDate    time.Time `json:"check_out_date"`              // Departure of the conflicting booking
+	ExternalBlockID *int      `json:"external_block_id,omitempty"` // External reservation holding the nights instead of a booking
+}
+
+// PriceLine is one item of a booking price breakdown.
+type PriceLine struct {
+	Code        string  `json:"code"`        // Machine-readable item code (e.g., "room", "extra_adult")
+	Description string  `json:"description"` // Human-readable description
+	Quantity    int     `json:"quantity"`    // Number of units (e.g., guest nights)
+	UnitPrice   float64 `json:"unit_price"`  // Price per unit
+	Amount      float64 `json:"amount"`      // Quantity times unit price
+}
+
+// PriceBreakdown is the itemised price of a stay.
+// Taxes are listed apart from the charges: exclusive taxes are added to the total, inclusive
+// ones are already part of the charges.
+type PriceBreakdown struct {
+	Nights     int         `json:"nights"`      // Number of nights between check-in and check-out
+	Lines      []PriceLine `json:"lines"`       // Charges and discounts
+	Taxes      []TaxLine   `json:"taxes"`       // Taxes and fees on the charges
+	TaxTotal   float64     `json:"tax_total"`   // Sum of all tax amounts, inclusive and exclusive
+	Total      float64     `json:"total"`       // Sum of all line amounts plus exclusive taxes
+	FirstNight float64     `json:"first_night"` // Price of the first night with its percentage discounts and nightly taxes; per-stay amounts are left out
+}
+
+// BookingStatusChange is one entry of a booking's status history.
+type BookingStatusChange struct {
+	ID         int       `json:"id"`          // Unique history entry identifier
+	BookingID  int       `json:"booking_id"`  // Booking whose status changed
+	FromStatus *string   `json:"from_status"` // Status before the change (nil when the booking was created)
+	ToStatus   string    `json:"to_status"`   // Status after the change
+	ChangedBy  *int      `json:"changed_by"`  // User who made the change (nil for system changes)
+	Reason     string    `json:"reason"`      // Optional reason for the change
+	CreatedAt  time.Time `json:"created_at"`  // Timestamp of the change
+}
+
+// BookingTransitionRequest represents the optional HTTP request body of a booking status change.
+type BookingTransitionRequest struct {
+	Reason string `json:"reason" binding:"max=500"` // Optional reason recorded in the history
+}
+
+// BookingUpdateRequest represents the HTTP request body for modifying a booking.
+// Omitted fields keep their current value.
+type BookingUpdateRequest struct {
+	RoomID          *int       `json:"room_id"`                  // Move the booking to another room
+	CheckInDate     *time.Time `json:"check_in_date"`            // New arrival date
+	CheckOutDate    *time.Time `json:"check_out_date"`           // New departure date
+	Adults          *int       `json:"adults"`                   // New number of adults
+	Children        *int       `json:"children"`                 // New number of children
+	SpecialRequests *string    `json:"special_requests"`         // New special requests
+	Version         int        `json:"version"`                  // Optional expected version; rejected if the booking changed since
+	Reason          string     `json:"reason" binding:"max=500"` // Optional reason recorded in the change log
+}
+
+// BookingSnapshot is the priced stay of a booking at one version.
+type BookingSnapshot struct {
+	RoomID       int       `json:"room_id"`        // Booked room
+	CheckInDate  time.Time `json:"check_in_date"`  // Arrival date
+	CheckOutDate time.Time `json:"check_out_date"` // Departure date
+	Adults       int       `json:"adults"`         // Number of adults
+	Children     int       `json:"children"`       // Number of children
+	TotalAmount  float64   `json:"total_amount"`   // Price of the stay
+}
+
+// BookingChange is one entry of a booking's versioned change log.
+type BookingChange struct {
+	ID              int             `json:"id"`               // Unique change identifier
+	BookingID       int             `json:"booking_id"`       // Modified booking
+	Version         int             `json:"version"`          // Booking version created by this change
+	ChangedBy       *int            `json:"changed_by"`       // User who made the change
+	Reason          string          `json:"reason"`           // Optional reason for the change
+	Before          BookingSnapshot `json:"before"`           // Stay before the change
+	After           BookingSnapshot `json:"after"`            // Stay after the change
+	PriceDifference float64         `json:"price_difference"` // New total minus old total
+	BalanceDue      float64         `json:"balance_due"`      // Amount the guest still owes after the change
+	RefundDue       float64         `json:"refund_due"`       // Amount paid beyond the new total
+	CreatedAt       time.Time       `json:"created_at"`       // Timestamp of the change
+}
+
+// BookingModification is the response of a booking modification.
+type BookingModification struct {
+	Booking    *Booking       `json:"booking"`     // Booking after the change
+	Change     *BookingChange `json:"change"`      // Change log entry that was recorded
+	AmountPaid float64        `json:"amount_paid"` // Sum of the booking's paid payments
+}
+
+// Snapshot returns the priced stay of the booking.
+func (b *Booking) Snapshot() BookingSnapshot {
+	return BookingSnapshot{
+		RoomID:       b.RoomID,
+		CheckInDate:  b.CheckInDate,
+		CheckOutDate: b.CheckOutDate,
+		Adults:       b.Adults,
+		Children:     b.Children,
+		TotalAmount:  b.TotalAmount,
+	}
+}
+
+// BookingFilter holds the filters, sorting and pagination of a booking list query.
+// Date ranges are inclusive and compare calendar dates.
+type BookingFilter struct {
+	PropertyIDs   []int      // Restrict to these properties (nil means every property)
+	UserID        *int       // Restrict to one guest's bookings
+	RoomID        *int       // Restrict to one room
+	Statuses      []string   // Restrict to these booking statuses
+	PaymentStatus string     // Restrict to one payment status
+	CheckInFrom   *time.Time // Earliest arrival date
+	CheckInTo     *time.Time // Latest arrival date
+	CheckOutFrom  *time.Time // Earliest departure date
+	CheckOutTo    *time.Time // Latest departure date
+	CreatedFrom   *time.Time // Earliest creation date
+	CreatedTo     *time.Time // Latest creation date
+	Sort          string     // Sort field (check_in_date, check_out_date, created_at, total_amount, status)
+	Order         string     // Sort direction (asc or desc)
+	Page          int        // Page number (1-based)
+	Limit         int        // Items per page (0 returns every booking)
+}
+
+// BookingListResponse represents a paginated list of bookings.
+type BookingListResponse struct {
+	Page       int       `json:"page"`        // Current page number
+	Limit      int       `json:"limit"`       // Items per page
+	Total      int       `json:"total"`       // Total number of matching bookings
+	TotalPages int       `json:"total_pages"` // Total number of pages
+	Bookings   []Booking `json:"bookings"`    // Bookings on this page
+}
+
+// CheckInRequest represents the optional HTTP request body of a booking check-in.
+type CheckInRequest struct {
+	RoomID       *int   `json:"room_id"`                  // Assign a different room of the same property
+	EarlyCheckIn bool   `json:"early_check_in"`           // Allow checking in before the arrival date
+	Reason       string `json:"reason" binding:"max=500"` // Optional reason recorded in the history
+}
+
+// CheckOutRequest represents the optional HTTP request body of a booking check-out.
+type CheckOutRequest struct {
+	OverrideBalance bool   `json:"override_balance"`         // Allow checking out with an outstanding balance
+	Reason          string `json:"reason" binding:"max=500"` // Optional reason recorded in the history
 }
@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// Calendar feed scopes: whose events a feed lists.
+const (
+	CalendarFeedRoom     = "room"     // One room
+	CalendarFeedProperty = "property" // Every room of a property
+	CalendarFeedStaff    = "staff"    // Every room of the properties a staff member is assigned to
+)
+
+// Calendar entry kinds.
+const (
+	CalendarEntryBooking     = "booking"     // A stay from check-in to check-out
+	CalendarEntryMaintenance = "maintenance" // A room maintenance window
+)
+
+// CalendarFilter selects the entries of a calendar feed. Entries overlapping From to To are listed.
+type CalendarFilter struct {
+	RoomID      *int      // Room to list (nil for every room)
+	PropertyIDs []int     // Properties to list (nil for all)
+	From        time.Time // Start of the window
+	To          time.Time // End of the window
+}
+
+// CalendarEntry is a booking or maintenance window shown on a calendar feed.
+type CalendarEntry struct {
+	Kind       string    // CalendarEntryBooking or CalendarEntryMaintenance
+	ID         int       // Booking or maintenance record ID
+	PropertyID int       // Property of the room
+	RoomID     int       // Room booked or maintained
+	RoomNumber string    // Room number shown in the summary
+	Start      time.Time // Check-in date or maintenance start
+	End        time.Time // Check-out date or maintenance end
+	Status     string    // Booking or maintenance status
+	Adults     int       // Adults of a booking
+	Children   int       // Children of a booking
+	Reason     string    // Reason of a maintenance window
+	Sequence   int       // Revision of the entry (the booking version)
+	UpdatedAt  time.Time // Last change of the entry
+}
+
+// CalendarEvent is an event of an iCalendar feed.
+type CalendarEvent struct {
+	UID          string    // Globally unique event identifier
+	Summary      string    // Event title
+	Description  string    // Event details
+	Status       string    // iCalendar status: TENTATIVE, CONFIRMED or CANCELLED
+	Start        time.Time // Start of the event
+	End          time.Time // End of the event (exclusive)
+	AllDay       bool      // Whether Start and End are dates rather than times
+	Sequence     int       // Revision number of the event
+	LastModified time.Time // Last change of the event
+}
+
+// CalendarFeedLink is a signed link to a calendar feed.
+type CalendarFeedLink struct {
+	Scope string `json:"scope"` // One of the CalendarFeed* scopes
+	ID    int    `json:"id"`    // Room, property or user the feed is for
+	Token string `json:"token"` // Signed token authorising the feed
+	URL   string `json:"url"`   // Feed URL to subscribe to
+}
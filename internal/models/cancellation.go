@@ -0,0 +1,38 @@
+// Package models defines all data structures used throughout the application.
+package models
+
+import "time"
+
+// Cancellation penalty types.
+const (
+	PenaltyPercentage = "percentage"  // A percentage of the booking total
+	PenaltyFirstNight = "first_night" // The price of the first night
+)
+
+// CancellationPolicy decides what a guest is charged for cancelling a booking.
+type CancellationPolicy struct {
+	FreeCancellationHours int     `json:"free_cancellation_hours"` // Cancelling at least this many hours before check-in is free
+	PenaltyType           string  `json:"penalty_type"`            // Penalty charged after the free period (one of the Penalty* values)
+	PenaltyPercent        float64 `json:"penalty_percent"`         // Percentage of the total charged when PenaltyType is percentage
+}
+
+// CancellationQuote is the penalty and refund of cancelling a booking at a given time.
+type CancellationQuote struct {
+	BookingID          int                `json:"booking_id"`            // Booking being cancelled
+	Policy             CancellationPolicy `json:"policy"`                // Policy the quote was computed with
+	QuotedAt           time.Time          `json:"quoted_at"`             // Time the cancellation was priced
+	FreeUntil          time.Time          `json:"free_until"`            // Last moment the booking could be cancelled for free
+	HoursBeforeCheckIn float64            `json:"hours_before_check_in"` // Hours left until check-in when quoted
+	TotalAmount        float64            `json:"total_amount"`          // Total price of the booking
+	Penalty            float64            `json:"penalty"`               // Amount kept by the hotel
+	AmountPaid         float64            `json:"amount_paid"`           // Sum of the booking's paid payments
+	Refund             float64            `json:"refund"`                // Amount to return to the guest
+	PenaltyDue         float64            `json:"penalty_due,omitempty"` // Unpaid part of a no-show penalty, still owed by the guest
+}
+
+// CancellationResult is the response of a booking cancellation or cancellation preview.
+type CancellationResult struct {
+	Preview bool              `json:"preview"` // True when nothing was changed
+	Booking *Booking          `json:"booking"` // Booking after the cancellation (unchanged in a preview)
+	Quote   CancellationQuote `json:"quote"`   // Penalty and refund breakdown
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// ExternalCalendar is the iCal feed of an external booking platform listing a room, with the
+// state of its last sync.
+type ExternalCalendar struct {
+	ID            int        `json:"id"`              // Unique calendar identifier
+	PropertyID    int        `json:"property_id"`     // Property of the room
+	RoomID        int        `json:"room_id"`         // Room the external reservations block
+	Name          string     `json:"name"`            // Label such as the platform name
+	URL           string     `json:"url"`             // iCal feed URL
+	Active        bool       `json:"active"`          // Whether the feed is synced
+	ETag          *string    `json:"-"`               // ETag of the last download
+	LastModified  *string    `json:"-"`               // Last-Modified of the last download
+	LastSyncedAt  *time.Time `json:"last_synced_at"`  // Start of the last sync attempt
+	LastSuccessAt *time.Time `json:"last_success_at"` // Last sync that succeeded
+	LastError     *string    `json:"last_error"`      // Error of the last sync attempt (nil when it succeeded)
+	BlockCount    int        `json:"block_count"`     // Current external blocks from the feed
+	Conflicts     int        `json:"conflicts"`       // Active bookings overlapping those blocks
+	CreatedBy     *int       `json:"created_by"`      // Admin who attached the feed
+	CreatedAt     time.Time  `json:"created_at"`      // When the feed was attached
+}
+
+// ExternalCalendarRequest represents the HTTP request body for attaching an external iCal feed to a room.
+type ExternalCalendarRequest struct {
+	RoomID int    `json:"room_id" binding:"required"` // Room the feed belongs to
+	Name   string `json:"name"`                       // Label such as the platform name
+	URL    string `json:"url" binding:"required"`     // iCal feed URL (http, https or webcal)
+}
+
+// ExternalBlock holds a room's nights taken by a reservation on an external platform.
+// Nights run from StartDate up to but excluding EndDate.
+type ExternalBlock struct {
+	ID         int       `json:"id"`          // Unique block identifier
+	CalendarID int       `json:"calendar_id"` // Feed the block was imported from
+	PropertyID int       `json:"property_id"` // Property of the room
+	RoomID     int       `json:"room_id"`     // Blocked room
+	UID        string    `json:"uid"`         // UID of the event in the feed
+	Summary    string    `json:"summary"`     // Event title from the feed
+	StartDate  time.Time `json:"start_date"`  // First blocked night
+	EndDate    time.Time `json:"end_date"`    // Night after the last blocked one
+	UpdatedAt  time.Time `json:"updated_at"`  // Last sync that changed the block
+}
@@ -0,0 +1,77 @@
+// Package models defines all data structures used throughout the application.
+package models
+
+import "time"
+
+// Folio entry kinds.
+const (
+	FolioCharge = "charge" // Adds to the balance
+	FolioCredit = "credit" // Reduces the balance
+)
+
+// Folio line types, covering the room charge and payments besides the posted entries.
+const (
+	FolioLineRoom    = "room"    // Price of the stay
+	FolioLinePayment = "payment" // Payment received for the booking
+)
+
+// FolioCategories are the categories incidental charges and credits can be posted under.
+var FolioCategories = []string{"minibar", "room_service", "restaurant", "laundry", "parking", "telephone", "adjustment", "other"}
+
+// FolioEntry is a charge or credit posted to a booking's folio during the stay.
+type FolioEntry struct {
+	ID          int        `json:"id"`          // Unique entry identifier
+	BookingID   int        `json:"booking_id"`  // Booking the entry is posted to
+	Kind        string     `json:"kind"`        // FolioCharge or FolioCredit
+	Category    string     `json:"category"`    // One of FolioCategories
+	Description string     `json:"description"` // Free-text description (e.g., "2x sparkling water")
+	Amount      float64    `json:"amount"`      // Net amount, always positive
+	TaxAmount   float64    `json:"tax_amount"`  // Tax on top of the net amount
+	PostedBy    *int       `json:"posted_by"`   // Staff member who posted the entry
+	PostedAt    time.Time  `json:"posted_at"`   // Timestamp when the entry was posted
+	VoidedBy    *int       `json:"voided_by"`   // Staff member who voided the entry
+	VoidedAt    *time.Time `json:"voided_at"`   // Timestamp when the entry was voided (nil while it counts)
+	VoidReason  *string    `json:"void_reason"` // Why the entry was voided
+}
+
+// FolioEntryRequest represents the HTTP request body for posting a charge or credit.
+type FolioEntryRequest struct {
+	Kind        string  `json:"kind" binding:"required,oneof=charge credit"` // FolioCharge or FolioCredit
+	Category    string  `json:"category" binding:"required"`                 // One of FolioCategories
+	Description string  `json:"description" binding:"max=200"`               // Free-text description
+	Amount      float64 `json:"amount" binding:"required,gt=0"`              // Net amount
+	TaxAmount   float64 `json:"tax_amount" binding:"gte=0"`                  // Tax on top of the net amount
+}
+
+// FolioVoidRequest represents the HTTP request body for voiding a folio entry.
+type FolioVoidRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"` // Why the entry is voided
+}
+
+// FolioLine is one line of a folio statement with the balance after it.
+type FolioLine struct {
+	Type        string    `json:"type"`            // FolioLineRoom, FolioCharge, FolioCredit or FolioLinePayment
+	EntryID     *int      `json:"entry_id"`        // Posted entry of a charge or credit line
+	PaymentID   *int      `json:"payment_id"`      // Payment of a payment line
+	Category    string    `json:"category"`        // Category of a charge or credit line
+	Description string    `json:"description"`     // Human-readable description
+	Amount      float64   `json:"amount"`          // Signed amount including tax (negative for credits and payments)
+	TaxAmount   float64   `json:"tax_amount"`      // Tax included in the amount
+	PostedBy    *int      `json:"posted_by"`       // Staff member who posted the line
+	PostedAt    time.Time `json:"posted_at"`       // When the line was posted
+	Voided      bool      `json:"voided"`          // Whether the entry was voided; voided lines do not count
+	VoidReason  *string   `json:"void_reason"`     // Why the entry was voided
+	Balance     float64   `json:"running_balance"` // Balance after the line
+}
+
+// Folio is the statement of a booking: the room charge, incidental charges and credits, and the
+// payments applied against them.
+type Folio struct {
+	BookingID  int         `json:"booking_id"`  // Booking the folio belongs to
+	Lines      []FolioLine `json:"lines"`       // Lines in posting order
+	RoomCharge float64     `json:"room_charge"` // Price of the stay including taxes
+	Charges    float64     `json:"charges"`     // Incidental charges including tax
+	Credits    float64     `json:"credits"`     // Credits including tax
+	Payments   float64     `json:"payments"`    // Payments received
+	Balance    float64     `json:"balance"`     // Amount still owed (negative when overpaid)
+}
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// Identity document types accepted in a guest manifest.
+const (
+	DocumentPassport       = "passport"        // Passport
+	DocumentNationalID     = "national_id"     // National identity card
+	DocumentDrivingLicence = "driving_licence" // Driving licence
+)
+
+// BookingGuest is one person staying under a booking, as required for guest registration.
+// The document number is stored encrypted and is masked in API responses other than the
+// registration report.
+type BookingGuest struct {
+	ID             int       `json:"id"`              // Unique guest identifier
+	BookingID      int       `json:"booking_id"`      // Booking the guest stays under
+	FirstName      string    `json:"first_name"`      // Given name
+	LastName       string    `json:"last_name"`       // Family name
+	Nationality    string    `json:"nationality"`     // ISO 3166-1 alpha-2 country code (e.g., "IN")
+	DateOfBirth    time.Time `json:"date_of_birth"`   // Date of birth
+	IsChild        bool      `json:"is_child"`        // Counted as a child on the arrival date
+	DocumentType   string    `json:"document_type"`   // Identity document type (one of the Document* values)
+	DocumentNumber string    `json:"document_number"` // Identity document number (masked unless exported)
+	DocumentCipher []byte    `json:"-"`               // Encrypted document number as stored
+	CreatedAt      time.Time `json:"created_at"`      // Timestamp when the guest was recorded
+}
+
+// BookingGuestRequest is one guest of a guest manifest request.
+type BookingGuestRequest struct {
+	FirstName      string    `json:"first_name" binding:"required"`    // Given name
+	LastName       string    `json:"last_name" binding:"required"`     // Family name
+	Nationality    string    `json:"nationality" binding:"required"`   // ISO 3166-1 alpha-2 country code
+	DateOfBirth    time.Time `json:"date_of_birth" binding:"required"` // Date of birth
+	DocumentType   string    `json:"document_type"`                    // Identity document type (required for adults)
+	DocumentNumber string    `json:"document_number"`                  // Identity document number (required for adults)
+}
+
+// BookingGuestsRequest represents the HTTP request body replacing a booking's guest manifest.
+type BookingGuestsRequest struct {
+	Guests []BookingGuestRequest `json:"guests" binding:"required,min=1,dive"` // Every guest of the booking
+}
+
+// GuestRegistration is one line of the registration report handed to the authorities:
+// a guest staying at a property on the report date.
+type GuestRegistration struct {
+	BookingID      int       `json:"booking_id"`      // Booking the guest stays under
+	PropertyID     int       `json:"property_id"`     // Property of the booked room
+	RoomNumber     string    `json:"room_number"`     // Booked room
+	CheckInDate    time.Time `json:"check_in_date"`   // Arrival date of the booking
+	CheckOutDate   time.Time `json:"check_out_date"`  // Departure date of the booking
+	FirstName      string    `json:"first_name"`      // Given name
+	LastName       string    `json:"last_name"`       // Family name
+	Nationality    string    `json:"nationality"`     // ISO 3166-1 alpha-2 country code
+	DateOfBirth    time.Time `json:"date_of_birth"`   // Date of birth
+	DocumentType   string    `json:"document_type"`   // Identity document type
+	DocumentNumber string    `json:"document_number"` // Decrypted identity document number
+	DocumentCipher []byte    `json:"-"`               // Encrypted document number as stored
+}
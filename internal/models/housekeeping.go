@@ -0,0 +1,51 @@
+// Package models defines all data structures used throughout the application.
+package models
+
+import "time"
+
+// Housekeeping statuses a room moves through between guests.
+const (
+	HousekeepingDirty      = "dirty"        // Guest has left; room needs cleaning
+	HousekeepingCleaning   = "cleaning"     // Cleaning is in progress
+	HousekeepingClean      = "clean"        // Cleaned and waiting for inspection
+	HousekeepingInspected  = "inspected"    // Inspected and ready to sell
+	HousekeepingOutOfOrder = "out_of_order" // Cannot be sold until repaired
+)
+
+// HousekeepingEvent represents a single recorded housekeeping status change.
+type HousekeepingEvent struct {
+	ID         int       `json:"id"`          // Unique event identifier
+	RoomID     int       `json:"room_id"`     // Room whose status changed
+	FromStatus string    `json:"from_status"` // Status before the change
+	ToStatus   string    `json:"to_status"`   // Status after the change
+	ChangedBy  *int      `json:"changed_by"`  // Staff member who made the change (nil for automatic changes)
+	Note       string    `json:"note"`        // Optional note (e.g., reason for out of order)
+	CreatedAt  time.Time `json:"created_at"`  // Timestamp of the change
+}
+
+// HousekeepingRoom is a room's entry on the housekeeping board.
+type HousekeepingRoom struct {
+	RoomID     int       `json:"room_id"`     // ID of the room
+	PropertyID int       `json:"property_id"` // Property the room belongs to
+	RoomNumber string    `json:"room_number"` // Room number
+	RoomType   string    `json:"room_type"`   // Type of room
+	Floor      int       `json:"floor"`       // Floor number
+	Status     string    `json:"status"`      // Current housekeeping status
+	UpdatedAt  time.Time `json:"updated_at"`  // When the status last changed
+	UpdatedBy  *int      `json:"updated_by"`  // Staff member who last changed it
+}
+
+// HousekeepingFloor groups the board entries of a single floor of a property.
+type HousekeepingFloor struct {
+	PropertyID int                `json:"property_id"` // Property the floor belongs to
+	Floor      int                `json:"floor"`       // Floor number
+	Counts     map[string]int     `json:"counts"`      // Number of rooms per housekeeping status
+	Rooms      []HousekeepingRoom `json:"rooms"`       // Rooms on the floor ordered by room number
+}
+
+// HousekeepingStatusRequest represents the HTTP request body for changing a room's housekeeping status.
+// The change is attributed to the authenticated staff member.
+type HousekeepingStatusRequest struct {
+	Status string `json:"status" binding:"required"` // Target status
+	Note   string `json:"note" binding:"max=500"`    // Optional note
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// IdempotencyRecord is the stored outcome of a request sent with an Idempotency-Key header.
+// StatusCode is nil while the original request is still being processed.
+type IdempotencyRecord struct {
+	Caller       string     `json:"caller"`        // Who sent the request (e.g. "user:5" or "ip:203.0.113.7")
+	Key          string     `json:"key"`           // Value of the Idempotency-Key header
+	RequestHash  string     `json:"request_hash"`  // SHA-256 of the method, path and body of the original request
+	StatusCode   *int       `json:"status_code"`   // HTTP status of the stored response, nil while in flight
+	ResponseBody []byte     `json:"response_body"` // Body of the stored response
+	CreatedAt    time.Time  `json:"created_at"`    // When the key was first used
+	CompletedAt  *time.Time `json:"completed_at"`  // When the response was stored
+}
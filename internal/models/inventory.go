@@ -0,0 +1,69 @@
+// Package models defines all data structures used throughout the application.
+package models
+
+import "time"
+
+// Inventory cell statuses used by the tape-chart calendar.
+const (
+	CellStatusFree        = "free"        // Room can be sold for the night
+	CellStatusBooked      = "booked"      // Room is occupied by a booking for the night
+	CellStatusMaintenance = "maintenance" // Room is under an active maintenance window
+	CellStatusBlocked     = "blocked"     // Room has been taken off sale
+)
+
+// InventoryCell represents a single room/night as returned by the repository.
+type InventoryCell struct {
+	RoomID     int       `json:"room_id"`     // ID of the room
+	PropertyID int       `json:"property_id"` // Property the room belongs to
+	RoomNumber string    `json:"room_number"` // Room number
+	RoomType   string    `json:"room_type"`   // Type of room
+	Floor      int       `json:"floor"`       // Floor number
+	Date       time.Time `json:"date"`        // Night the cell describes
+	Status     string    `json:"status"`      // One of the CellStatus* values
+	BookingID  *int      `json:"booking_id"`  // Booking occupying the night (only when booked)
+}
+
+// CalendarDay is one cell of a room row in the inventory calendar.
+type CalendarDay struct {
+	Date      string `json:"date"`                 // Night in YYYY-MM-DD format
+	Status    string `json:"status"`               // One of the CellStatus* values
+	BookingID *int   `json:"booking_id,omitempty"` // Booking occupying the night (only when booked)
+}
+
+// CalendarRoom is one row of the inventory calendar.
+type CalendarRoom struct {
+	RoomID     int           `json:"room_id"`     // ID of the room
+	PropertyID int           `json:"property_id"` // Property the room belongs to
+	RoomNumber string        `json:"room_number"` // Room number
+	RoomType   string        `json:"room_type"`   // Type of room
+	Floor      int           `json:"floor"`       // Floor number
+	Days       []CalendarDay `json:"days"`        // One entry per night in the requested range
+}
+
+// RoomTypeDayCount holds per-day inventory counts for a single room type.
+type RoomTypeDayCount struct {
+	Date        string `json:"date"`        // Night in YYYY-MM-DD format
+	RoomType    string `json:"room_type"`   // Type of room
+	Total       int    `json:"total"`       // Number of rooms of this type
+	Free        int    `json:"free"`        // Rooms still sellable
+	Booked      int    `json:"booked"`      // Rooms occupied by bookings
+	Maintenance int    `json:"maintenance"` // Rooms under maintenance
+	Blocked     int    `json:"blocked"`     // Rooms taken off sale
+}
+
+// InventoryCalendar represents the HTTP response for the inventory calendar.
+type InventoryCalendar struct {
+	From   string             `json:"from"`   // First night in the range (YYYY-MM-DD)
+	To     string             `json:"to"`     // Last night in the range (YYYY-MM-DD)
+	Rooms  []CalendarRoom     `json:"rooms"`  // Rooms as rows, nights as columns
+	Counts []RoomTypeDayCount `json:"counts"` // Per-day counts for each room type
+}
+
+// InventoryFilter holds the optional filters for the inventory calendar.
+type InventoryFilter struct {
+	From        time.Time // First night to include
+	To          time.Time // Last night to include
+	Floor       *int      // Restrict to a single floor
+	RoomType    string    // Restrict to a single room type
+	PropertyIDs []int     // Restrict to these properties (nil means every property)
+}
@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// Invoice document kinds.
+const (
+	InvoiceKindInvoice    = "invoice"     // Bill for a booking's charges
+	InvoiceKindCreditNote = "credit_note" // Full reversal of an invoice
+)
+
+// InvoiceLine is a charge or discount on an invoice.
+type InvoiceLine struct {
+	Description string  `json:"description"` // Human-readable description
+	Quantity    int     `json:"quantity"`    // Number of units
+	UnitPrice   float64 `json:"unit_price"`  // Price per unit
+	Amount      float64 `json:"amount"`      // Quantity times unit price, before exclusive taxes
+}
+
+// InvoiceTax is a tax on an invoice.
+type InvoiceTax struct {
+	Code        string  `json:"code"`        // Reporting code of the tax
+	Description string  `json:"description"` // Human-readable description
+	Inclusive   bool    `json:"inclusive"`   // Whether the amount is already part of the lines
+	Amount      float64 `json:"amount"`      // Tax amount
+}
+
+// Invoice is an issued invoice or credit note. Issued documents are immutable snapshots of the
+// booking's charges, taxes and payments; corrections are made with a credit note.
+type Invoice struct {
+	ID                int           `json:"id"`                  // Unique invoice identifier
+	PropertyID        int           `json:"property_id"`         // Issuing property
+	BookingID         int           `json:"booking_id"`          // Booking invoiced
+	Kind              string        `json:"kind"`                // InvoiceKindInvoice or InvoiceKindCreditNote
+	Number            int           `json:"number"`              // Sequential number within the property and kind
+	InvoiceNumber     string        `json:"invoice_number"`      // Formatted number (e.g., "INV-LON1-000042")
+	CorrectsInvoiceID *int          `json:"corrects_invoice_id"` // Invoice a credit note reverses
+	CreditNoteID      *int          `json:"credit_note_id"`      // Credit note reversing an invoice, if any
+	Reason            string        `json:"reason"`              // Why a credit note was issued
+	PropertyName      string        `json:"property_name"`       // Issuer name
+	PropertyAddress   string        `json:"property_address"`    // Issuer address
+	BillToName        string        `json:"bill_to_name"`        // Guest or company billed
+	BillToAddress     string        `json:"bill_to_address"`     // Billing address
+	BillToTaxID       string        `json:"bill_to_tax_id"`      // Tax identifier of a company billed
+	StayFrom          time.Time     `json:"stay_from"`           // Arrival date
+	StayTo            time.Time     `json:"stay_to"`             // Departure date
+	Lines             []InvoiceLine `json:"lines"`               // Charges and discounts
+	Taxes             []InvoiceTax  `json:"taxes"`               // Taxes on the lines
+	Subtotal          float64       `json:"subtotal"`            // Sum of the lines
+	TaxTotal          float64       `json:"tax_total"`           // Exclusive taxes added to the subtotal
+	IncludedTax       float64       `json:"included_tax"`        // Inclusive taxes already part of the subtotal
+	Total             float64       `json:"total"`               // Subtotal plus exclusive taxes
+	AmountPaid        float64       `json:"amount_paid"`         // Payments received when the invoice was issued
+	BalanceDue        float64       `json:"balance_due"`         // Total less the amount paid
+	IssuedBy          *int          `json:"issued_by"`           // Staff member who issued the document
+	IssuedAt          time.Time     `json:"issued_at"`           // Timestamp when the document was issued
+}
+
+// InvoiceRequest represents the HTTP request body for issuing an invoice.
+// Without a name the booking's guest is billed.
+type InvoiceRequest struct {
+	BillToName    string `json:"bill_to_name" binding:"max=200"`    // Guest or company billed
+	BillToAddress string `json:"bill_to_address" binding:"max=500"` // Billing address
+	BillToTaxID   string `json:"bill_to_tax_id" binding:"max=50"`   // Tax identifier of a company billed
+}
+
+// CreditNoteRequest represents the HTTP request body for crediting an invoice.
+type CreditNoteRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"` // Why the invoice is credited
+}
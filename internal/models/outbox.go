@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Domain events written to the outbox for guest notifications.
+const (
+	EventBookingCreated   = "booking.created"   // A booking was made
+	EventBookingConfirmed = "booking.confirmed" // A booking was confirmed
+	EventBookingCancelled = "booking.cancelled" // A booking was cancelled, by the guest, staff or an expired hold
+	EventPaymentSucceeded = "payment.succeeded" // A payment for a booking went through
+)
+
+// Outbox event delivery statuses.
+const (
+	OutboxStatusPending = "pending" // Waiting for its first or next delivery attempt
+	OutboxStatusSent    = "sent"    // Delivered on every channel
+	OutboxStatusDead    = "dead"    // Out of attempts; kept for inspection and manual retry
+)
+
+// OutboxEvent is a domain event waiting to be, or already, delivered to the booking's guest.
+type OutboxEvent struct {
+	ID                int64             `json:"id"`                 // Unique event identifier
+	EventType         string            `json:"event_type"`         // What happened, such as booking.created
+	BookingID         int               `json:"booking_id"`         // Booking the event is about
+	Payload           OutboxPayload     `json:"payload"`            // Details of the event
+	Status            string            `json:"status"`             // pending, sent or dead
+	Attempts          int               `json:"attempts"`           // Failed delivery attempts so far
+	NextAttemptAt     time.Time         `json:"next_attempt_at"`    // Earliest time of the next attempt
+	DeliveredChannels []string          `json:"delivered_channels"` // Channels that already delivered the event
+	LastError         *string           `json:"last_error"`         // Error of the last failed attempt
+	CreatedAt         time.Time         `json:"created_at"`         // When the change happened
+	SentAt            *time.Time        `json:"sent_at"`            // When the last channel delivered it
+	Guest             NotificationGuest `json:"-"`                  // Guest and stay, loaded when the event is claimed for delivery
+}
+
+// OutboxPayload holds the details of an event that are not read from its booking.
+type OutboxPayload struct {
+	FromStatus    string `json:"from_status,omitempty"`    // Booking status before a status change
+	Reason        string `json:"reason,omitempty"`         // Reason given for a status change
+	PaymentID     int    `json:"payment_id,omitempty"`     // Payment that succeeded
+	PaymentAmount int64  `json:"payment_amount,omitempty"` // Amount paid in cents
+}
+
+// NotificationGuest holds what a notification tells the guest about their booking.
+type NotificationGuest struct {
+	Name         string             // Guest's name
+	Email        string             // Address for email notifications
+	Phone        string             // Number for SMS notifications
+	PropertyName string             // Hotel of the booking
+	RoomNumber   string             // Booked room
+	CheckInDate  time.Time          // Arrival date
+	CheckOutDate time.Time          // Departure date
+	TotalAmount  float64            // Total price of the booking
+	Cancellation *CancellationQuote // Penalty and refund of a cancelled booking
+}
@@ -7,6 +7,7 @@ import "time"
 type Payment struct {
 	ID            int        `json:"id"`             // Unique payment identifier
 	BookingID     int        `json:"booking_id"`     // ID of the associated booking
+	ReservationID *int       `json:"reservation_id"` // Group reservation the payment was made for, if any
 	Amount        int64      `json:"amount"`         // Payment amount in cents (to avoid floating point issues)
 	PaymentMethod string     `json:"payment_method"` // Payment method used (e.g., "credit_card", "debit_card")
 	TransactionID *string    `json:"transaction_id"` // External transaction ID from payment gateway
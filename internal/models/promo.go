@@ -0,0 +1,57 @@
+// Package models defines all data structures used throughout the application.
+package models
+
+import "time"
+
+// Promo code discount types.
+const (
+	DiscountPercentage = "percentage" // Percentage off the stay price
+	DiscountFixed      = "fixed"      // Fixed amount off the stay price
+)
+
+// PromoCode is a marketing discount guests can redeem when booking.
+// Date windows are inclusive and nil bounds are open; nil usage limits are unlimited.
+type PromoCode struct {
+	ID             int        `json:"id"`                // Unique promo code identifier
+	Code           string     `json:"code"`              // Code entered by the guest (upper case, e.g., "SUMMER10")
+	Description    string     `json:"description"`       // Campaign description
+	DiscountType   string     `json:"discount_type"`     // Discount type (one of the Discount* values)
+	DiscountValue  float64    `json:"discount_value"`    // Percentage (0-100] or amount off
+	StayFrom       *time.Time `json:"stay_from"`         // First night the code applies to
+	StayTo         *time.Time `json:"stay_to"`           // Last night the code applies to
+	BookFrom       *time.Time `json:"book_from"`         // Start of the booking window
+	BookTo         *time.Time `json:"book_to"`           // End of the booking window
+	MinNights      int        `json:"min_nights"`        // Minimum number of nights of the stay
+	RoomTypes      []string   `json:"room_types"`        // Eligible room types (empty means every type)
+	MaxUses        *int       `json:"max_uses"`          // Total number of redemptions allowed
+	MaxUsesPerUser *int       `json:"max_uses_per_user"` // Redemptions allowed per guest
+	TimesUsed      int        `json:"times_used"`        // Redemptions so far
+	Stackable      bool       `json:"stackable"`         // Whether the code can be combined with other stackable codes
+	IsActive       bool       `json:"is_active"`         // Whether the code can be redeemed
+	CreatedAt      time.Time  `json:"created_at"`        // Timestamp when the code was created
+	UpdatedAt      time.Time  `json:"updated_at"`        // Timestamp of the last update
+}
+
+// PromoCodeRequest represents the HTTP request body for creating a promo code.
+type PromoCodeRequest struct {
+	Code           string     `json:"code" binding:"required,max=40"`                          // Code entered by guests
+	Description    string     `json:"description"`                                             // Campaign description
+	DiscountType   string     `json:"discount_type" binding:"required,oneof=percentage fixed"` // Discount type
+	DiscountValue  float64    `json:"discount_value" binding:"required,gt=0"`                  // Percentage or amount off
+	StayFrom       *time.Time `json:"stay_from"`                                               // First night the code applies to
+	StayTo         *time.Time `json:"stay_to"`                                                 // Last night the code applies to
+	BookFrom       *time.Time `json:"book_from"`                                               // Start of the booking window
+	BookTo         *time.Time `json:"book_to"`                                                 // End of the booking window
+	MinNights      int        `json:"min_nights" binding:"omitempty,min=1"`                    // Minimum nights (1 by default)
+	RoomTypes      []string   `json:"room_types"`                                              // Eligible room types
+	MaxUses        *int       `json:"max_uses" binding:"omitempty,min=1"`                      // Total redemptions allowed
+	MaxUsesPerUser *int       `json:"max_uses_per_user" binding:"omitempty,min=1"`             // Redemptions allowed per guest
+	Stackable      bool       `json:"stackable"`                                               // Whether the code can be combined
+}
+
+// PromoRedemption is a promo code applied to a booking and the discount it gave.
+type PromoRedemption struct {
+	PromoCodeID    int     `json:"promo_code_id"`   // Redeemed promo code
+	Code           string  `json:"code"`            // Code as entered
+	DiscountAmount float64 `json:"discount_amount"` // Amount taken off the booking
+}
@@ -0,0 +1,35 @@
+// Package models defines all data structures used throughout the application.
+package models
+
+import "time"
+
+// User roles recognised by the API.
+const (
+	RoleAdmin = "admin" // Full access to every property
+	RoleStaff = "staff" // Access limited to the properties the user is assigned to
+	RoleGuest = "guest" // Hotel guest
+)
+
+// Property represents a hotel operated by the group.
+type Property struct {
+	ID        int       `json:"id"`         // Unique property identifier
+	Code      string    `json:"code"`       // Short unique code (e.g., "LON1")
+	Name      string    `json:"name"`       // Display name of the hotel
+	Address   string    `json:"address"`    // Postal address
+	Timezone  string    `json:"timezone"`   // IANA time zone of the hotel (e.g., "Europe/London")
+	CreatedAt time.Time `json:"created_at"` // Timestamp when the property was created
+	UpdatedAt time.Time `json:"updated_at"` // Timestamp of the last update
+}
+
+// PropertyRequest represents the HTTP request body for creating a property.
+type PropertyRequest struct {
+	Code     string `json:"code" binding:"required,max=20"`  // Short unique code
+	Name     string `json:"name" binding:"required,max=100"` // Display name
+	Address  string `json:"address"`                         // Postal address
+	Timezone string `json:"timezone"`                        // IANA time zone (defaults to UTC)
+}
+
+// UserPropertiesRequest represents the HTTP request body for assigning a user to properties.
+type UserPropertiesRequest struct {
+	PropertyIDs []int `json:"property_ids" binding:"required"` // Properties the user may access (replaces existing assignments)
+}
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// Reservation groups several room bookings of one property under a single confirmation code
+// and lead guest. Totals are derived from its bookings when it is loaded.
+type Reservation struct {
+	ID               int       `json:"id"`                // Unique reservation identifier
+	ConfirmationCode string    `json:"confirmation_code"` // Code quoted by the guest (e.g. "K7PQ2MXA")
+	PropertyID       int       `json:"property_id"`       // Property of every booked room
+	LeadUserID       int       `json:"lead_user_id"`      // Guest the rooms are booked for
+	SpecialRequests  string    `json:"special_requests"`  // Requests that apply to the whole group
+	Bookings         []Booking `json:"bookings"`          // One booking per room, ordered by arrival
+	TotalAmount      float64   `json:"total_amount"`      // Amount owed for all bookings (penalties only for cancelled ones)
+	AmountPaid       float64   `json:"amount_paid"`       // Sum of the bookings' paid payments
+	BalanceDue       float64   `json:"balance_due"`       // What is left to pay
+	CreatedAt        time.Time `json:"created_at"`        // Timestamp when the reservation was created
+	UpdatedAt        time.Time `json:"updated_at"`        // Timestamp of the last update
+}
+
+// ReservationRoomRequest is one room of a group reservation request.
+type ReservationRoomRequest struct {
+	RoomID          int       `json:"room_id" binding:"required"`        // ID of the room to book
+	CheckInDate     time.Time `json:"check_in_date" binding:"required"`  // Date of arrival
+	CheckOutDate    time.Time `json:"check_out_date" binding:"required"` // Date of departure
+	Adults          int       `json:"adults" binding:"required"`         // Number of adults in the room
+	Children        int       `json:"children"`                          // Number of children in the room
+	SpecialRequests string    `json:"special_requests"`                  // Optional requests for this room
+}
+
+// ReservationRequest represents the HTTP request body for creating a group reservation.
+type ReservationRequest struct {
+	UserID          int                      `json:"user_id"`                             // Lead guest; staff may book for a guest, everyone else books for themselves
+	SpecialRequests string                   `json:"special_requests"`                    // Optional requests for the whole group
+	Rooms           []ReservationRoomRequest `json:"rooms" binding:"required,min=1,dive"` // Rooms to book together
+	TotalAmount     float64                  `json:"total_amount"`                        // Optional expected total; rejected if it differs from the computed price
+}
+
+// ReservationPaymentRequest represents the HTTP request body for paying a group reservation.
+type ReservationPaymentRequest struct {
+	Amount        int64  `json:"amount" binding:"required,min=1"`   // Amount to pay in cents
+	PaymentMethod string `json:"payment_method" binding:"required"` // Payment method
+	TransactionID string `json:"transaction_id" binding:"required"` // Transaction ID from payment gateway
+}
+
+// ReservationPayment is a reservation payment split into one payment per booking.
+type ReservationPayment struct {
+	ReservationID int       `json:"reservation_id"` // Reservation being paid
+	Amount        int64     `json:"amount"`         // Total amount in cents
+	Payments      []Payment `json:"payments"`       // Per-booking payments making up the amount
+}
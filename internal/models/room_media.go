@@ -0,0 +1,46 @@
+// Package models defines all data structures used throughout the application.
+package models
+
+import "time"
+
+// RoomMedia represents a photo attached to a single room or to every room of a room type.
+// Exactly one of RoomID and RoomType is set.
+type RoomMedia struct {
+	ID           int       `json:"id"`            // Unique media identifier
+	RoomID       *int      `json:"room_id"`       // Room the photo belongs to (nil for room type photos)
+	RoomType     *string   `json:"room_type"`     // Room type the photo belongs to (nil for room photos)
+	StorageKey   string    `json:"-"`             // Key of the original image in storage
+	ThumbnailKey string    `json:"-"`             // Key of the generated thumbnail in storage
+	URL          string    `json:"url"`           // Public URL of the original image
+	ThumbnailURL string    `json:"thumbnail_url"` // Public URL of the thumbnail
+	ContentType  string    `json:"content_type"`  // Detected MIME type (e.g., "image/jpeg")
+	SizeBytes    int64     `json:"size_bytes"`    // Size of the original image in bytes
+	Caption      string    `json:"caption"`       // Optional caption shown with the photo
+	Position     int       `json:"position"`      // Display order (0 is shown first)
+	CreatedAt    time.Time `json:"created_at"`    // Timestamp when the photo was uploaded
+	UpdatedAt    time.Time `json:"updated_at"`    // Timestamp of the last update
+}
+
+// MediaOwner identifies what a set of photos belongs to: a room or a room type.
+type MediaOwner struct {
+	RoomID   *int   // Room the photos belong to
+	RoomType string // Room type the photos belong to (used when RoomID is nil)
+}
+
+// MediaLink is the compact photo representation embedded in room responses.
+type MediaLink struct {
+	ID           int    `json:"id"`            // Media identifier
+	URL          string `json:"url"`           // Public URL of the original image
+	ThumbnailURL string `json:"thumbnail_url"` // Public URL of the thumbnail
+	Caption      string `json:"caption"`       // Caption shown with the photo
+}
+
+// ReorderMediaRequest represents the HTTP request body for reordering photos.
+type ReorderMediaRequest struct {
+	MediaIDs []int `json:"media_ids" binding:"required,min=1"` // Every photo of the owner in the desired order
+}
+
+// UpdateMediaCaptionRequest represents the HTTP request body for changing a photo caption.
+type UpdateMediaCaptionRequest struct {
+	Caption string `json:"caption" binding:"max=500"` // New caption (empty clears it)
+}
@@ -5,22 +5,26 @@ import "time"
 
 // Room represents a hotel room record stored in the database.
 type Room struct {
-	ID          int       `json:"id"`           // Unique room identifier
-	RoomNumber  string    `json:"room_number"`  // Room number or identifier (e.g., "101", "Suite-A")
-	RoomType    string    `json:"room_type"`    // Type of room (e.g., "single", "double", "suite")
-	Description string    `json:"description"`  // Detailed description of the room
-	Price       float64   `json:"price"`        // Price per night
-	Capacity    int       `json:"capacity"`     // Maximum number of guests the room can accommodate
-	Floor       int       `json:"floor"`        // Floor number where the room is located
-	Amenities   []string  `json:"amenities"`    // List of amenities available in the room
-	IsAvailable bool      `json:"is_available"` // Whether the room is currently available for booking
-	CreatedAt   time.Time `json:"created_at"`   // Timestamp when the room record was created
-	UpdatedAt   time.Time `json:"updated_at"`   // Timestamp of the last update
+	ID                 int         `json:"id"`                  // Unique room identifier
+	PropertyID         int         `json:"property_id"`         // Property (hotel) the room belongs to
+	RoomNumber         string      `json:"room_number"`         // Room number or identifier (e.g., "101", "Suite-A")
+	RoomType           string      `json:"room_type"`           // Type of room (e.g., "single", "double", "suite")
+	Description        string      `json:"description"`         // Detailed description of the room
+	Price              float64     `json:"price"`               // Price per night
+	Capacity           int         `json:"capacity"`            // Maximum number of guests the room can accommodate
+	Floor              int         `json:"floor"`               // Floor number where the room is located
+	Amenities          []string    `json:"amenities"`           // List of amenities available in the room
+	IsAvailable        bool        `json:"is_available"`        // Whether the room is currently available for booking
+	HousekeepingStatus string      `json:"housekeeping_status"` // Housekeeping status (e.g., "dirty", "inspected")
+	Media              []MediaLink `json:"media"`               // Ordered photos (room photos first, then room type photos)
+	CreatedAt          time.Time   `json:"created_at"`          // Timestamp when the room record was created
+	UpdatedAt          time.Time   `json:"updated_at"`          // Timestamp of the last update
 }
 
 // RoomRequest represents the HTTP request body for creating or updating a room.
 type RoomRequest struct {
-	RoomNumber  string   `json:"room_number" binding:"required"` // Room number
+	PropertyID  int      `json:"property_id" binding:"required"` // Property the room belongs to
+	RoomNumber  string   `json:"room_number" binding:"required"` // Room number (unique within the property)
 	RoomType    string   `json:"room_type" binding:"required"`   // Type of room
 	Description string   `json:"description" binding:"required"` // Room description
 	Price       float64  `json:"price" binding:"required"`       // Price per night
@@ -28,3 +32,41 @@ type RoomRequest struct {
 	Floor       int      `json:"floor" binding:"required"`       // Floor number
 	Amenities   []string `json:"amenities" binding:"required"`   // List of amenities
 }
+
+// RoomSearchFilter holds the filters, sorting and pagination for the faceted room search.
+type RoomSearchFilter struct {
+	PropertyIDs  []int    // Restrict to these properties (nil means every property)
+	AmenitiesAll []string // Rooms must have every one of these amenities
+	AmenitiesAny []string // Rooms must have at least one of these amenities
+	MinPrice     *float64 // Minimum price per night (inclusive)
+	MaxPrice     *float64 // Maximum price per night (inclusive)
+	MinCapacity  *int     // Minimum guest capacity
+	Floor        *int     // Restrict to a single floor
+	RoomType     string   // Restrict to a single room type
+	Sort         string   // Sort field (price, capacity, floor, room_number, created_at)
+	Order        string   // Sort direction (asc or desc)
+	Page         int      // Page number (1-based)
+	Limit        int      // Items per page
+}
+
+// FacetCount holds the number of matching rooms for a single facet value.
+type FacetCount struct {
+	Value string `json:"value"` // Facet value (e.g., "wifi" or "suite")
+	Count int    `json:"count"` // Number of rooms matching the value
+}
+
+// RoomFacets holds facet counts used to render search filter chips.
+type RoomFacets struct {
+	Amenities []FacetCount `json:"amenities"`  // Rooms per amenity
+	RoomTypes []FacetCount `json:"room_types"` // Rooms per room type
+}
+
+// RoomSearchResponse represents the HTTP response for the faceted room search.
+type RoomSearchResponse struct {
+	Page       int        `json:"page"`        // Current page number
+	Limit      int        `json:"limit"`       // Items per page
+	Total      int        `json:"total"`       // Total number of matching rooms
+	TotalPages int        `json:"total_pages"` // Total number of pages
+	Rooms      []Room     `json:"rooms"`       // Rooms on this page
+	Facets     RoomFacets `json:"facets"`      // Facet counts for the current filters
+}
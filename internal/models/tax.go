@@ -0,0 +1,69 @@
+// Package models defines all data structures used throughout the application.
+package models
+
+import "time"
+
+// Tax rate bases: how the amount of a tax is computed.
+const (
+	TaxPercentage = "percentage" // Percentage of the stay price after discounts
+	TaxPerPerson  = "per_person" // Fixed amount per guest
+	TaxPerRoom    = "per_room"   // Fixed amount per booked room
+)
+
+// Tax rate periods: how often a fixed tax is charged.
+const (
+	TaxPerNight = "night" // Charged for every night of the stay within the validity dates
+	TaxPerStay  = "stay"  // Charged once when the arrival date is within the validity dates
+)
+
+// TaxRate is a configured tax or fee, such as VAT, a city tax or a service fee.
+// Inclusive rates are already contained in room prices and are only broken out; exclusive rates
+// are added on top.
+type TaxRate struct {
+	ID             int        `json:"id"`              // Unique tax rate identifier
+	PropertyID     *int       `json:"property_id"`     // Property the rate applies to (nil for every property)
+	Code           string     `json:"code"`            // Reporting code (e.g., "vat", "city_tax")
+	Name           string     `json:"name"`            // Name shown on the price breakdown
+	Basis          string     `json:"basis"`           // How the amount is computed (one of the Tax* basis values)
+	Period         string     `json:"period"`          // How often a fixed amount is charged (TaxPerNight or TaxPerStay)
+	Rate           float64    `json:"rate"`            // Percentage, or amount per person or room
+	Inclusive      bool       `json:"inclusive"`       // Whether room prices already include the tax
+	ChildrenExempt bool       `json:"children_exempt"` // Whether per-person taxes skip children
+	ValidFrom      *time.Time `json:"valid_from"`      // First night the rate applies to
+	ValidTo        *time.Time `json:"valid_to"`        // Last night the rate applies to
+	CreatedAt      time.Time  `json:"created_at"`      // Timestamp when the rate was created
+}
+
+// TaxRateRequest represents the HTTP request body for creating a tax rate.
+type TaxRateRequest struct {
+	PropertyID     *int       `json:"property_id"`                                                   // Property the rate applies to (omit for every property)
+	Code           string     `json:"code" binding:"required,max=40"`                                // Reporting code
+	Name           string     `json:"name" binding:"required,max=100"`                               // Name shown on the price breakdown
+	Basis          string     `json:"basis" binding:"required,oneof=percentage per_person per_room"` // How the amount is computed
+	Period         string     `json:"period" binding:"omitempty,oneof=night stay"`                   // How often a fixed amount is charged (night by default)
+	Rate           float64    `json:"rate" binding:"required,gt=0"`                                  // Percentage or amount
+	Inclusive      bool       `json:"inclusive"`                                                     // Whether room prices already include the tax
+	ChildrenExempt bool       `json:"children_exempt"`                                               // Whether per-person taxes skip children
+	ValidFrom      *time.Time `json:"valid_from"`                                                    // First night the rate applies to
+	ValidTo        *time.Time `json:"valid_to"`                                                      // Last night the rate applies to
+}
+
+// TaxLine is a tax or fee charged on a booking.
+type TaxLine struct {
+	TaxRateID   int     `json:"tax_rate_id"` // Rate the line was computed from
+	Code        string  `json:"code"`        // Reporting code of the rate
+	Description string  `json:"description"` // Human-readable description
+	Quantity    int     `json:"quantity"`    // Number of units (e.g., guest nights; 1 for percentages)
+	Rate        float64 `json:"rate"`        // Percentage or amount per unit
+	Inclusive   bool    `json:"inclusive"`   // Whether the amount is already part of the room charge
+	Amount      float64 `json:"amount"`      // Tax amount
+}
+
+// TaxReportLine is the tax collected under one code at one property over a reporting period.
+type TaxReportLine struct {
+	PropertyID int     `json:"property_id"` // Property that charged the tax
+	Code       string  `json:"code"`        // Reporting code
+	Inclusive  bool    `json:"inclusive"`   // Whether the tax was included in room prices
+	Bookings   int     `json:"bookings"`    // Number of bookings charged
+	Amount     float64 `json:"amount"`      // Total tax amount
+}
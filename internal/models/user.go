@@ -5,15 +5,16 @@ import "time"
 
 // User represents a user account in the system.
 type User struct {
-	ID        string    `json:"id"`         // Unique user identifier
-	Name      string    `json:"name"`       // User's full name
-	Email     string    `json:"email"`      // User's email address (unique)
-	Phone     string    `json:"phone"`      // User's phone number
-	Password  string    `json:"-"`          // Password hash (NOT sent in API responses for security)
-	Role      string    `json:"role"`       // User role (e.g., "admin", "guest", "staff")
-	IsActive  bool      `json:"is_active"`  // Whether the user account is active
-	CreatedAt time.Time `json:"created_at"` // Account creation timestamp
-	UpdatedAt time.Time `json:"updated_at"` // Last update timestamp
+	ID          string    `json:"id"`           // Unique user identifier
+	Name        string    `json:"name"`         // User's full name
+	Email       string    `json:"email"`        // User's email address (unique)
+	Phone       string    `json:"phone"`        // User's phone number
+	Password    string    `json:"-"`            // Password hash (NOT sent in API responses for security)
+	Role        string    `json:"role"`         // User role (e.g., "admin", "guest", "staff")
+	IsActive    bool      `json:"is_active"`    // Whether the user account is active
+	PropertyIDs []int     `json:"property_ids"` // Properties the user is assigned to (staff)
+	CreatedAt   time.Time `json:"created_at"`   // Account creation timestamp
+	UpdatedAt   time.Time `json:"updated_at"`   // Last update timestamp
 }
 
 // RegisterRequest represents the HTTP request body for user registration.
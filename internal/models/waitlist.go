@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Waitlist entry statuses.
+const (
+	WaitlistWaiting   = "waiting"   // Waiting for a matching room to be freed
+	WaitlistOffered   = "offered"   // A room is reserved for the guest until the offer expires
+	WaitlistBooked    = "booked"    // The guest claimed the offer and a booking was created
+	WaitlistExpired   = "expired"   // The guest did not claim the offer in time
+	WaitlistCancelled = "cancelled" // The guest left the waitlist
+)
+
+// WaitlistEntry is a guest's interest in a room type of a property for a date range.
+// Entries are offered freed rooms in the order they joined the waitlist.
+type WaitlistEntry struct {
+	ID             int        `json:"id"`               // Unique entry identifier
+	PropertyID     int        `json:"property_id"`      // Property the guest wants to stay at
+	UserID         int        `json:"user_id"`          // Guest on the waitlist
+	RoomType       string     `json:"room_type"`        // Room type wanted (e.g., "Deluxe")
+	CheckInDate    time.Time  `json:"check_in_date"`    // Date of arrival
+	CheckOutDate   time.Time  `json:"check_out_date"`   // Date of departure
+	Adults         int        `json:"adults"`           // Number of adults
+	Children       int        `json:"children"`         // Number of children
+	Status         string     `json:"status"`           // Entry status (one of the Waitlist* values)
+	OfferedRoomID  *int       `json:"offered_room_id"`  // Room reserved by the current offer
+	OfferExpiresAt *time.Time `json:"offer_expires_at"` // Time the current offer lapses
+	BookingID      *int       `json:"booking_id"`       // Booking created when the offer was claimed
+	CreatedAt      time.Time  `json:"created_at"`       // Time the guest joined the waitlist
+	UpdatedAt      time.Time  `json:"updated_at"`       // Timestamp of the last update
+}
+
+// WaitlistRequest represents the HTTP request body for joining the waitlist.
+type WaitlistRequest struct {
+	PropertyID   int       `json:"property_id" binding:"required"`    // Property the guest wants to stay at
+	RoomType     string    `json:"room_type" binding:"required"`      // Room type wanted
+	CheckInDate  time.Time `json:"check_in_date" binding:"required"`  // Date of arrival
+	CheckOutDate time.Time `json:"check_out_date" binding:"required"` // Date of departure
+	Adults       int       `json:"adults" binding:"required"`         // Number of adults
+	Children     int       `json:"children"`                          // Number of children
+}
+
+// WaitlistClaimRequest represents the HTTP request body for claiming a waitlist offer.
+type WaitlistClaimRequest struct {
+	Token string `json:"token" binding:"required"` // Signed token from the offer link
+}
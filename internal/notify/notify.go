@@ -0,0 +1,32 @@
+// Package notify delivers messages to users, such as waitlist offers and booking notifications.
+// Each Notifier implementation decides the channel used to reach the user; LogNotifier only
+// writes messages to the application log and is used until a real channel is configured.
+// Senders deliver already rendered messages to an email address or phone number; see sender.go.
+package notify
+
+import (
+	"context"
+	"log"
+)
+
+// Message is a notification addressed to a user.
+type Message struct {
+	UserID  int    // User to notify; the notifier resolves their contact details
+	Subject string // Short summary of the message
+	Body    string // Message text
+	Link    string // Optional link the user should follow
+}
+
+// Notifier sends messages to users.
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// LogNotifier writes messages to the application log instead of sending them.
+type LogNotifier struct{}
+
+// Notify logs msg and never fails.
+func (LogNotifier) Notify(ctx context.Context, msg Message) error {
+	log.Printf("notify user %d: %s - %s %s", msg.UserID, msg.Subject, msg.Body, msg.Link)
+	return nil
+}
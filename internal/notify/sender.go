@@ -0,0 +1,139 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Channels a Sender can deliver on.
+const (
+	ChannelEmail = "email" // Envelope.To is an email address
+	ChannelSMS   = "sms"   // Envelope.To is a phone number
+)
+
+// Envelope is a rendered message addressed to an email address or phone number.
+type Envelope struct {
+	To      string // Recipient address on the sender's channel
+	Subject string // Subject line; unused by SMS
+	Body    string // Message text
+}
+
+// Sender delivers envelopes on one channel. Implementations for other providers can be plugged
+// in wherever a Sender is accepted.
+type Sender interface {
+	Channel() string
+	Send(ctx context.Context, env Envelope) error
+}
+
+// LogSender writes envelopes to the application log instead of sending them.
+type LogSender struct {
+	Kind string // Channel the sender stands in for
+}
+
+// Channel returns the channel the sender stands in for.
+func (s LogSender) Channel() string { return s.Kind }
+
+// Send logs env and never fails.
+func (s LogSender) Send(ctx context.Context, env Envelope) error {
+	log.Printf("%s to %s: %s - %s", s.Kind, env.To, env.Subject, env.Body)
+	return nil
+}
+
+// FileSender writes each envelope to its own file in Dir, so messages can be read during local runs.
+type FileSender struct {
+	Kind string // Channel the sender stands in for
+	Dir  string // Directory the messages are written to; created when missing
+}
+
+// Channel returns the channel the sender stands in for.
+func (s FileSender) Channel() string { return s.Kind }
+
+// Send writes env to a new file named after the current time and the channel.
+func (s FileSender) Send(ctx context.Context, env Envelope) error {
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create message directory: %w", err)
+	}
+	f, err := os.CreateTemp(s.Dir, time.Now().UTC().Format("20060102T150405")+"-"+s.Kind+"-*.txt")
+	if err != nil {
+		return fmt.Errorf("failed to create message file: %w", err)
+	}
+	_, err = fmt.Fprintf(f, "To: %s\nSubject: %s\n\n%s\n", env.To, env.Subject, env.Body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write message file: %w", err)
+	}
+	return nil
+}
+
+// SMTPSender sends email through an SMTP server, authenticating with PLAIN auth when a
+// username is set.
+type SMTPSender struct {
+	Addr     string // Server host:port
+	From     string // Sender address
+	Username string // Optional login
+	Password string // Password of Username
+}
+
+// Channel returns ChannelEmail.
+func (s SMTPSender) Channel() string { return ChannelEmail }
+
+// Send sends env as a plain-text email.
+func (s SMTPSender) Send(ctx context.Context, env Envelope) error {
+	var auth smtp.Auth
+	if s.Username != "" {
+		host, _, _ := strings.Cut(s.Addr, ":")
+		auth = smtp.PlainAuth("", s.Username, s.Password, host)
+	}
+	msg := "From: " + s.From + "\r\n" +
+		"To: " + env.To + "\r\n" +
+		"Subject: " + env.Subject + "\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=utf-8\r\n" +
+		"\r\n" + strings.ReplaceAll(env.Body, "\n", "\r\n")
+	if err := smtp.SendMail(s.Addr, auth, s.From, []string{env.To}, []byte(msg)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// SendersFromEnv builds the email and SMS senders from NOTIFY_EMAIL_SENDER and
+// NOTIFY_SMS_SENDER. Each is log (the default), file (writing to NOTIFY_FILE_DIR, ./notifications
+// by default) or none; email can also be smtp, configured by SMTP_ADDR, SMTP_FROM, SMTP_USERNAME
+// and SMTP_PASSWORD. Returns an error for an unknown sender or an incomplete SMTP setup.
+func SendersFromEnv() ([]Sender, error) {
+	dir := os.Getenv("NOTIFY_FILE_DIR")
+	if dir == "" {
+		dir = "notifications"
+	}
+	var senders []Sender
+	for _, channel := range []string{ChannelEmail, ChannelSMS} {
+		variable := "NOTIFY_" + strings.ToUpper(channel) + "_SENDER"
+		switch kind := os.Getenv(variable); kind {
+		case "", "log":
+			senders = append(senders, LogSender{Kind: channel})
+		case "file":
+			senders = append(senders, FileSender{Kind: channel, Dir: filepath.Clean(dir)})
+		case "none":
+		case "smtp":
+			if channel != ChannelEmail {
+				return nil, fmt.Errorf("invalid %s %q: smtp only sends email", variable, kind)
+			}
+			sender := SMTPSender{Addr: os.Getenv("SMTP_ADDR"), From: os.Getenv("SMTP_FROM"), Username: os.Getenv("SMTP_USERNAME"), Password: os.Getenv("SMTP_PASSWORD")}
+			if sender.Addr == "" || sender.From == "" {
+				return nil, fmt.Errorf("SMTP_ADDR and SMTP_FROM are required with %s=smtp", variable)
+			}
+			senders = append(senders, sender)
+		default:
+			return nil, fmt.Errorf("invalid %s %q: must be log, file, smtp or none", variable, kind)
+		}
+	}
+	return senders, nil
+}
@@ -0,0 +1,54 @@
+package notify
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileSender_WritesOneFilePerMessage(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "outbox")
+	sender := FileSender{Kind: ChannelEmail, Dir: dir}
+	for _, to := range []string{"ada@example.com", "bob@example.com"} {
+		if err := sender.Send(context.Background(), Envelope{To: to, Subject: "Booking #7 received", Body: "Hello"}); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*-email-*.txt"))
+	if err != nil || len(files) != 2 {
+		t.Fatalf("expected 2 message files, got %v err=%v", files, err)
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if !strings.Contains(string(data), "Subject: Booking #7 received\n\nHello") {
+		t.Fatalf("unexpected message file:\n%s", data)
+	}
+}
+
+func TestSendersFromEnv(t *testing.T) {
+	t.Setenv("NOTIFY_EMAIL_SENDER", "file")
+	t.Setenv("NOTIFY_SMS_SENDER", "none")
+	t.Setenv("NOTIFY_FILE_DIR", "tmp/messages")
+	senders, err := SendersFromEnv()
+	if err != nil {
+		t.Fatalf("SendersFromEnv failed: %v", err)
+	}
+	if len(senders) != 1 || senders[0] != (FileSender{Kind: ChannelEmail, Dir: "tmp/messages"}) {
+		t.Fatalf("expected only a file email sender, got %+v", senders)
+	}
+
+	t.Setenv("NOTIFY_EMAIL_SENDER", "smtp")
+	if _, err := SendersFromEnv(); err == nil {
+		t.Fatal("expected an error for smtp without SMTP_ADDR")
+	}
+	t.Setenv("NOTIFY_EMAIL_SENDER", "")
+	t.Setenv("NOTIFY_SMS_SENDER", "pigeon")
+	if _, err := SendersFromEnv(); err == nil {
+		t.Fatal("expected an error for an unknown sender")
+	}
+}
@@ -0,0 +1,90 @@
+package render
+
+import (
+	"bytes"
+	"industry-api/internal/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// icalProductID identifies the application in generated calendars.
+const icalProductID = "-//industry-api//Calendar Feeds//EN"
+
+// icalLineLimit is the maximum length of a content line in octets, excluding the line break.
+const icalLineLimit = 75
+
+// ICalendar renders events as an RFC 5545 calendar named name. All-day events use dates;
+// other events use UTC date-times. Output only depends on the events, so identical feeds
+// produce identical bytes.
+func ICalendar(name string, events []models.CalendarEvent) []byte {
+	var buf bytes.Buffer
+	line := func(content string) { writeICalLine(&buf, content) }
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:" + icalProductID)
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:" + icalText(name))
+	for _, event := range events {
+		line("BEGIN:VEVENT")
+		line("UID:" + icalText(event.UID))
+		line("DTSTAMP:" + icalDateTime(event.LastModified))
+		line("LAST-MODIFIED:" + icalDateTime(event.LastModified))
+		if event.AllDay {
+			line("DTSTART;VALUE=DATE:" + event.Start.Format("20060102"))
+			line("DTEND;VALUE=DATE:" + event.End.Format("20060102"))
+		} else {
+			line("DTSTART:" + icalDateTime(event.Start))
+			line("DTEND:" + icalDateTime(event.End))
+		}
+		line("SUMMARY:" + icalText(event.Summary))
+		if event.Description != "" {
+			line("DESCRIPTION:" + icalText(event.Description))
+		}
+		if event.Status != "" {
+			line("STATUS:" + event.Status)
+		}
+		line("SEQUENCE:" + strconv.Itoa(event.Sequence))
+		line("TRANSP:OPAQUE")
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return buf.Bytes()
+}
+
+// writeICalLine writes a content line terminated by CRLF, folding it into continuation lines
+// that start with a space whenever it exceeds icalLineLimit octets. Lines are only folded
+// between UTF-8 characters.
+func writeICalLine(buf *bytes.Buffer, content string) {
+	limit := icalLineLimit
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(content[cut]) {
+			cut--
+		}
+		buf.WriteString(content[:cut])
+		buf.WriteString("\r\n ")
+		content = content[cut:]
+		// Continuation lines lose one octet to the leading space
+		limit = icalLineLimit - 1
+	}
+	buf.WriteString(content)
+	buf.WriteString("\r\n")
+}
+
+// isRuneStart reports whether b starts a UTF-8 encoded character.
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// icalText escapes a TEXT property value.
+func icalText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(s)
+}
+
+// icalDateTime formats t as a UTC date-time.
+func icalDateTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
@@ -0,0 +1,50 @@
+package render
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"industry-api/internal/models"
+)
+
+func TestICalendar(t *testing.T) {
+	modified := time.Date(2030, 5, 1, 9, 30, 0, 0, time.FixedZone("CEST", 2*3600))
+	events := []models.CalendarEvent{
+		{UID: "booking-1@industry-api", Summary: "Room 101: booking #1 (2 adults)", Status: "TENTATIVE", AllDay: true,
+			Start: time.Date(2030, 5, 3, 0, 0, 0, 0, time.UTC), End: time.Date(2030, 5, 5, 0, 0, 0, 0, time.UTC), Sequence: 3, LastModified: modified},
+		{UID: "maintenance-2@industry-api", Summary: "Room 102: maintenance - leak; bath, sink\nand " + strings.Repeat("é", 60), Status: "CONFIRMED",
+			Start: time.Date(2030, 5, 3, 8, 0, 0, 0, time.UTC), End: time.Date(2030, 5, 3, 12, 0, 0, 0, time.UTC), LastModified: modified},
+	}
+	feed := string(ICalendar("Room 101", events))
+
+	if !strings.HasPrefix(feed, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n") || !strings.HasSuffix(feed, "END:VCALENDAR\r\n") {
+		t.Fatalf("unexpected calendar envelope:\n%s", feed)
+	}
+	if strings.Contains(strings.ReplaceAll(feed, "\r\n", ""), "\n") {
+		t.Fatalf("expected every line to end with CRLF")
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(feed, "\r\n"), "\r\n") {
+		if len(line) > 75 || !utf8.ValidString(line) {
+			t.Fatalf("expected lines of at most 75 octets folded between characters, got %q", line)
+		}
+	}
+	for _, want := range []string{
+		"DTSTART;VALUE=DATE:20300503\r\n", "DTEND;VALUE=DATE:20300505\r\n", "STATUS:TENTATIVE\r\n", "SEQUENCE:3\r\n",
+		"DTSTART:20300503T080000Z\r\n", "LAST-MODIFIED:20300501T073000Z\r\n", `leak\; bath\, sink\nand`,
+	} {
+		if !strings.Contains(feed, want) {
+			t.Fatalf("expected %q in the calendar:\n%s", want, feed)
+		}
+	}
+
+	// Unfolding restores the escaped summary without splitting any character
+	unfolded := strings.ReplaceAll(feed, "\r\n ", "")
+	if !strings.Contains(unfolded, "and "+strings.Repeat("é", 60)+"\r\n") {
+		t.Fatalf("expected folded lines to unfold to the original text:\n%s", unfolded)
+	}
+	if feed != string(ICalendar("Room 101", events)) {
+		t.Fatalf("expected identical events to render identically")
+	}
+}
@@ -0,0 +1,148 @@
+// Package render turns issued documents such as invoices into HTML and PDF files for download.
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"industry-api/internal/models"
+	"strings"
+)
+
+// invoiceTitles are the document titles of each invoice kind.
+var invoiceTitles = map[string]string{
+	models.InvoiceKindInvoice:    "Invoice",
+	models.InvoiceKindCreditNote: "Credit note",
+}
+
+// invoiceTemplate lays out an invoice or credit note as a standalone HTML page.
+var invoiceTemplate = template.Must(template.New("invoice").Funcs(template.FuncMap{
+	"money": func(v float64) string { return fmt.Sprintf("%.2f", v) },
+	"date":  func(t interface{ Format(string) string }) string { return t.Format("2006-01-02") },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}} {{.Invoice.InvoiceNumber}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; width: 100%; margin-top: 1em; }
+th, td { padding: 4px 8px; border-bottom: 1px solid #ddd; text-align: left; }
+td.num, th.num { text-align: right; }
+.parties { display: flex; justify-content: space-between; }
+.totals td { border: none; }
+</style>
+</head>
+<body>
+<h1>{{.Title}} {{.Invoice.InvoiceNumber}}</h1>
+<p>Issued {{date .Invoice.IssuedAt}} for booking {{.Invoice.BookingID}}, stay {{date .Invoice.StayFrom}} to {{date .Invoice.StayTo}}</p>
+{{with .Invoice.Reason}}<p>Reason: {{.}}</p>{{end}}
+<div class="parties">
+<div><strong>{{.Invoice.PropertyName}}</strong><br>{{.Invoice.PropertyAddress}}</div>
+<div><strong>Bill to:</strong> {{.Invoice.BillToName}}<br>{{.Invoice.BillToAddress}}{{with .Invoice.BillToTaxID}}<br>Tax ID: {{.}}{{end}}</div>
+</div>
+<table>
+<tr><th>Description</th><th class="num">Qty</th><th class="num">Unit price</th><th class="num">Amount</th></tr>
+{{range .Invoice.Lines}}<tr><td>{{.Description}}</td><td class="num">{{.Quantity}}</td><td class="num">{{money .UnitPrice}}</td><td class="num">{{money .Amount}}</td></tr>
+{{end}}</table>
+{{if .Invoice.Taxes}}<table>
+<tr><th>Tax</th><th></th><th class="num">Amount</th></tr>
+{{range .Invoice.Taxes}}<tr><td>{{.Description}}</td><td>{{if .Inclusive}}included{{end}}</td><td class="num">{{money .Amount}}</td></tr>
+{{end}}</table>{{end}}
+<table class="totals">
+<tr><td>Subtotal</td><td class="num">{{money .Invoice.Subtotal}}</td></tr>
+<tr><td>Taxes</td><td class="num">{{money .Invoice.TaxTotal}}</td></tr>
+<tr><td><strong>Total</strong></td><td class="num"><strong>{{money .Invoice.Total}}</strong></td></tr>
+{{if .Invoice.IncludedTax}}<tr><td>Of which included taxes</td><td class="num">{{money .Invoice.IncludedTax}}</td></tr>{{end}}
+<tr><td>Paid</td><td class="num">{{money .Invoice.AmountPaid}}</td></tr>
+<tr><td><strong>Balance due</strong></td><td class="num"><strong>{{money .Invoice.BalanceDue}}</strong></td></tr>
+</table>
+</body>
+</html>
+`))
+
+// InvoiceHTML renders an invoice or credit note as an HTML page.
+func InvoiceHTML(invoice *models.Invoice) ([]byte, error) {
+	var buf bytes.Buffer
+	data := struct {
+		Title   string
+		Invoice *models.Invoice
+	}{invoiceTitles[invoice.Kind], invoice}
+	if err := invoiceTemplate.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render invoice: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// InvoicePDF renders an invoice or credit note as a PDF document.
+func InvoicePDF(invoice *models.Invoice) []byte {
+	return textPDF(invoiceText(invoice))
+}
+
+// invoiceText lays out an invoice as lines of fixed-width text for the PDF.
+func invoiceText(invoice *models.Invoice) []string {
+	const width = 88
+	money := func(v float64) string { return fmt.Sprintf("%.2f", v) }
+	row := func(label, amount string) string {
+		return fmt.Sprintf("%-*s%14s", width-14, truncate(label, width-15), amount)
+	}
+	rule := strings.Repeat("-", width)
+
+	lines := []string{
+		fmt.Sprintf("%s %s", strings.ToUpper(invoiceTitles[invoice.Kind]), invoice.InvoiceNumber),
+		"",
+		invoice.PropertyName,
+		invoice.PropertyAddress,
+		"",
+		"Bill to: " + invoice.BillToName,
+	}
+	if invoice.BillToAddress != "" {
+		lines = append(lines, "         "+invoice.BillToAddress)
+	}
+	if invoice.BillToTaxID != "" {
+		lines = append(lines, "Tax ID:  "+invoice.BillToTaxID)
+	}
+	lines = append(lines,
+		"",
+		fmt.Sprintf("Issued %s for booking %d, stay %s to %s", invoice.IssuedAt.Format("2006-01-02"), invoice.BookingID,
+			invoice.StayFrom.Format("2006-01-02"), invoice.StayTo.Format("2006-01-02")),
+	)
+	if invoice.Reason != "" {
+		lines = append(lines, "Reason: "+invoice.Reason)
+	}
+	lines = append(lines, "", fmt.Sprintf("%-50s%6s%16s%16s", "Description", "Qty", "Unit price", "Amount"), rule)
+	for _, line := range invoice.Lines {
+		lines = append(lines, fmt.Sprintf("%-50s%6d%16s%16s", truncate(line.Description, 49), line.Quantity, money(line.UnitPrice), money(line.Amount)))
+	}
+	if len(invoice.Taxes) > 0 {
+		lines = append(lines, "", row("Tax", "Amount"), rule)
+		for _, tax := range invoice.Taxes {
+			label := tax.Description
+			if tax.Inclusive {
+				label += " (included)"
+			}
+			lines = append(lines, row(label, money(tax.Amount)))
+		}
+	}
+	lines = append(lines, "", rule,
+		row("Subtotal", money(invoice.Subtotal)),
+		row("Taxes", money(invoice.TaxTotal)),
+		row("TOTAL", money(invoice.Total)),
+	)
+	if invoice.IncludedTax != 0 {
+		lines = append(lines, row("Of which included taxes", money(invoice.IncludedTax)))
+	}
+	lines = append(lines,
+		row("Paid", money(invoice.AmountPaid)),
+		row("BALANCE DUE", money(invoice.BalanceDue)),
+	)
+	return lines
+}
+
+// truncate shortens s to at most n characters.
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "~"
+	}
+	return s
+}
@@ -0,0 +1,69 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"industry-api/internal/models"
+)
+
+func testInvoice(lines int) *models.Invoice {
+	issued := time.Date(2030, 5, 3, 10, 0, 0, 0, time.UTC)
+	invoice := &models.Invoice{
+		ID: 1, BookingID: 3, Kind: models.InvoiceKindInvoice, InvoiceNumber: "INV-LON1-000007",
+		PropertyName: "Hotel <Central>", PropertyAddress: "1 Main St", BillToName: "Acme (UK) Ltd", BillToTaxID: "GB123",
+		StayFrom: issued.AddDate(0, 0, -2), StayTo: issued,
+		Taxes:    []models.InvoiceTax{{Code: "vat", Description: "VAT 5%", Amount: 11}},
+		Subtotal: 220, TaxTotal: 11, Total: 231, AmountPaid: 150, BalanceDue: 81, IssuedAt: issued,
+	}
+	for i := 0; i < lines; i++ {
+		invoice.Lines = append(invoice.Lines, models.InvoiceLine{Description: fmt.Sprintf("Night %d", i+1), Quantity: 1, UnitPrice: 110, Amount: 110})
+	}
+	return invoice
+}
+
+func TestInvoiceHTML(t *testing.T) {
+	page, err := InvoiceHTML(testInvoice(2))
+	if err != nil {
+		t.Fatalf("InvoiceHTML failed: %v", err)
+	}
+	html := string(page)
+	for _, want := range []string{"Invoice INV-LON1-000007", "Hotel &lt;Central&gt;", "Tax ID: GB123", "Night 2", "231.00", "81.00", "2030-05-01"} {
+		if !strings.Contains(html, want) {
+			t.Fatalf("expected %q in the rendered invoice:\n%s", want, html)
+		}
+	}
+}
+
+func TestInvoicePDF_ValidStructure(t *testing.T) {
+	pdf := InvoicePDF(testInvoice(120))
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Fatalf("missing PDF header or trailer")
+	}
+	if !bytes.Contains(pdf, []byte(`(Bill to: Acme \(UK\) Ltd) Tj`)) {
+		t.Fatalf("expected parentheses to be escaped")
+	}
+	// 120 lines do not fit on one page
+	if pages := bytes.Count(pdf, []byte("/Type /Page ")); pages < 2 {
+		t.Fatalf("expected several pages, got %d", pages)
+	}
+
+	// Every xref entry must point at the start of its object
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(pdf)
+	if startxref == nil {
+		t.Fatalf("missing startxref")
+	}
+	xref, _ := strconv.Atoi(string(startxref[1]))
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(pdf[xref:], -1)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		if want := fmt.Sprintf("%d 0 obj", i+1); !bytes.HasPrefix(pdf[offset:], []byte(want)) {
+			t.Fatalf("xref entry %d points at %q", i+1, pdf[offset:offset+10])
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"industry-api/internal/models"
+	"strings"
+	"text/template"
+)
+
+// notificationFuncs are the helpers available to notification templates.
+var notificationFuncs = template.FuncMap{
+	"money": func(v float64) string { return fmt.Sprintf("%.2f", v) },
+	"cents": func(v int64) string { return fmt.Sprintf("%.2f", float64(v)/100) },
+	"date":  func(t interface{ Format(string) string }) string { return t.Format("2006-01-02") },
+}
+
+// notificationTemplate holds the messages of one event: an email subject and body, and a short
+// SMS text.
+type notificationTemplate struct {
+	subject, email, sms *template.Template
+}
+
+// newNotificationTemplate parses the messages of an event.
+func newNotificationTemplate(event, subject, email, sms string) notificationTemplate {
+	parse := func(part, text string) *template.Template {
+		return template.Must(template.New(event + "." + part).Funcs(notificationFuncs).Parse(text))
+	}
+	return notificationTemplate{subject: parse("subject", subject), email: parse("email", email), sms: parse("sms", sms)}
+}
+
+// notificationTemplates are the guest messages of each outbox event. Templates are executed with
+// the models.OutboxEvent.
+var notificationTemplates = map[string]notificationTemplate{
+	models.EventBookingCreated: newNotificationTemplate(models.EventBookingCreated,
+		`Booking #{{.BookingID}} received`,
+		`Hello {{.Guest.Name}},
+
+Thank you for booking with {{.Guest.PropertyName}}. We have received booking #{{.BookingID}} for room {{.Guest.RoomNumber}} from {{date .Guest.CheckInDate}} to {{date .Guest.CheckOutDate}}, totalling {{money .Guest.TotalAmount}}.
+
+We will let you know as soon as it is confirmed.`,
+		`{{.Guest.PropertyName}}: booking #{{.BookingID}} received for {{date .Guest.CheckInDate}} to {{date .Guest.CheckOutDate}}.`),
+
+	models.EventBookingConfirmed: newNotificationTemplate(models.EventBookingConfirmed,
+		`Booking #{{.BookingID}} confirmed`,
+		`Hello {{.Guest.Name}},
+
+Your booking #{{.BookingID}} at {{.Guest.PropertyName}} is confirmed: room {{.Guest.RoomNumber}} from {{date .Guest.CheckInDate}} to {{date .Guest.CheckOutDate}}.
+
+We look forward to welcoming you.`,
+		`{{.Guest.PropertyName}}: booking #{{.BookingID}} is confirmed for {{date .Guest.CheckInDate}} to {{date .Guest.CheckOutDate}}.`),
+
+	models.EventBookingCancelled: newNotificationTemplate(models.EventBookingCancelled,
+		`Booking #{{.BookingID}} cancelled`,
+		`Hello {{.Guest.Name}},
+
+Your booking #{{.BookingID}} at {{.Guest.PropertyName}} from {{date .Guest.CheckInDate}} to {{date .Guest.CheckOutDate}} has been cancelled{{with .Payload.Reason}} ({{.}}){{end}}.
+{{- with .Guest.Cancellation}}
+{{if gt .Penalty 0.0}}
+A cancellation fee of {{money .Penalty}} applies.{{end}}{{if gt .Refund 0.0}}
+We will refund {{money .Refund}}.{{end}}
+{{- end}}`,
+		`{{.Guest.PropertyName}}: booking #{{.BookingID}} for {{date .Guest.CheckInDate}} has been cancelled.{{with .Guest.Cancellation}}{{if gt .Refund 0.0}} Refund: {{money .Refund}}.{{end}}{{end}}`),
+
+	models.EventPaymentSucceeded: newNotificationTemplate(models.EventPaymentSucceeded,
+		`Payment received for booking #{{.BookingID}}`,
+		`Hello {{.Guest.Name}},
+
+We have received your payment of {{cents .Payload.PaymentAmount}} for booking #{{.BookingID}} at {{.Guest.PropertyName}}, from {{date .Guest.CheckInDate}} to {{date .Guest.CheckOutDate}}.
+
+Thank you.`,
+		`{{.Guest.PropertyName}}: payment of {{cents .Payload.PaymentAmount}} received for booking #{{.BookingID}}.`),
+}
+
+// Notification renders the guest message of an outbox event for a channel ("email" or "sms").
+// The subject is empty for SMS.
+// Returns an error for an event type or channel without a template.
+func Notification(channel string, event models.OutboxEvent) (subject, body string, err error) {
+	tmpl, ok := notificationTemplates[event.EventType]
+	if !ok {
+		return "", "", fmt.Errorf("no notification template for %q", event.EventType)
+	}
+	execute := func(t *template.Template) (string, error) {
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, event); err != nil {
+			return "", fmt.Errorf("failed to render %s: %w", t.Name(), err)
+		}
+		return strings.TrimSpace(buf.String()), nil
+	}
+
+	switch channel {
+	case "email":
+		if subject, err = execute(tmpl.subject); err != nil {
+			return "", "", err
+		}
+		body, err = execute(tmpl.email)
+	case "sms":
+		body, err = execute(tmpl.sms)
+	default:
+		return "", "", fmt.Errorf("no notification template for channel %q", channel)
+	}
+	if err != nil {
+		return "", "", err
+	}
+	return subject, body, nil
+}
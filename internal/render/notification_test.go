@@ -0,0 +1,81 @@
+package render
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"industry-api/internal/models"
+)
+
+func testNotificationEvent(eventType string) models.OutboxEvent {
+	checkIn := time.Date(2030, 5, 10, 0, 0, 0, 0, time.UTC)
+	return models.OutboxEvent{
+		ID: 1, EventType: eventType, BookingID: 42,
+		Guest: models.NotificationGuest{
+			Name: "Ada", Email: "ada@example.com", Phone: "5550100100", PropertyName: "Main property", RoomNumber: "101",
+			CheckInDate: checkIn, CheckOutDate: checkIn.AddDate(0, 0, 2), TotalAmount: 250,
+		},
+	}
+}
+
+func TestNotification_RendersEveryEvent(t *testing.T) {
+	cancelled := testNotificationEvent(models.EventBookingCancelled)
+	cancelled.Payload.Reason = "hold expired before payment"
+	cancelled.Guest.Cancellation = &models.CancellationQuote{Penalty: 125, Refund: 25}
+	paid := testNotificationEvent(models.EventPaymentSucceeded)
+	paid.Payload.PaymentAmount = 15000
+
+	cases := []struct {
+		event   models.OutboxEvent
+		subject string
+		email   []string
+		sms     string
+	}{
+		{testNotificationEvent(models.EventBookingCreated), "Booking #42 received", []string{"Hello Ada", "room 101 from 2030-05-10 to 2030-05-12", "250.00"}, "booking #42 received"},
+		{testNotificationEvent(models.EventBookingConfirmed), "Booking #42 confirmed", []string{"is confirmed"}, "is confirmed for 2030-05-10"},
+		{cancelled, "Booking #42 cancelled", []string{"cancelled (hold expired before payment)", "fee of 125.00", "refund 25.00"}, "Refund: 25.00"},
+		{paid, "Payment received for booking #42", []string{"payment of 150.00"}, "payment of 150.00"},
+	}
+	for _, tc := range cases {
+		subject, body, err := Notification("email", tc.event)
+		if err != nil {
+			t.Fatalf("%s: email failed: %v", tc.event.EventType, err)
+		}
+		if subject != tc.subject {
+			t.Fatalf("%s: expected subject %q, got %q", tc.event.EventType, tc.subject, subject)
+		}
+		for _, want := range tc.email {
+			if !strings.Contains(body, want) {
+				t.Fatalf("%s: expected %q in the email:\n%s", tc.event.EventType, want, body)
+			}
+		}
+
+		subject, body, err = Notification("sms", tc.event)
+		if err != nil {
+			t.Fatalf("%s: sms failed: %v", tc.event.EventType, err)
+		}
+		if subject != "" || !strings.Contains(body, tc.sms) || len(body) > 160 {
+			t.Fatalf("%s: unexpected sms %q", tc.event.EventType, body)
+		}
+	}
+}
+
+func TestNotification_CancelledWithoutFees(t *testing.T) {
+	_, body, err := Notification("email", testNotificationEvent(models.EventBookingCancelled))
+	if err != nil {
+		t.Fatalf("Notification failed: %v", err)
+	}
+	if strings.Contains(body, "fee") || strings.Contains(body, "refund") || strings.Contains(body, "()") {
+		t.Fatalf("expected no fee, refund or empty reason:\n%s", body)
+	}
+}
+
+func TestNotification_UnknownTemplate(t *testing.T) {
+	if _, _, err := Notification("email", testNotificationEvent("booking.archived")); err == nil {
+		t.Fatal("expected an error for an unknown event")
+	}
+	if _, _, err := Notification("fax", testNotificationEvent(models.EventBookingCreated)); err == nil {
+		t.Fatal("expected an error for an unknown channel")
+	}
+}
@@ -4,8 +4,12 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"industry-api/internal/models"
+	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -17,25 +21,111 @@ type BookingRepository struct {
 // BookingRepo defines the methods used by services for booking operations.
 type BookingRepo interface {
 	AddBooking(ctx context.Context, booking *models.Booking) (*models.Booking, error)
+	GetRoomByID(ctx context.Context, roomID int) (*models.Room, error)
+	GetBookingByID(ctx context.Context, id int) (*models.Booking, error)
+	SetBookingStatus(ctx context.Context, id int, from, to string, changedBy *int, reason string) (*models.Booking, error)
+	ListBookingStatusHistory(ctx context.Context, id int) ([]models.BookingStatusChange, error)
+	GetPaidAmount(ctx context.Context, bookingID int) (int64, error)
+	CancelBooking(ctx context.Context, id int, from string, quote *models.CancellationQuote, changedBy *int, reason string) (*models.Booking, error)
+	ModifyBooking(ctx context.Context, booking *models.Booking, change *models.BookingChange) (*models.Booking, error)
+	ListBookingChanges(ctx context.Context, id int) ([]models.BookingChange, error)
+	ListBookings(ctx context.Context, filter models.BookingFilter) (*models.BookingListResponse, error)
+	CheckInBooking(ctx context.Context, booking *models.Booking, roomID int, changedBy *int, reason string) (*models.Booking, error)
+	CheckOutBooking(ctx context.Context, booking *models.Booking, changedBy *int, reason string) (*models.Booking, error)
+	ExpireHolds(ctx context.Context, now time.Time, limit int) ([]int, error)
+	ListNoShowCandidates(ctx context.Context, now time.Time, cutoff time.Duration, limit int) ([]models.Booking, error)
+	MarkNoShow(ctx context.Context, id int, quote *models.CancellationQuote, changedBy *int, reason string) (*models.Booking, error)
+	GetPromoCodes(ctx context.Context, codes []string) ([]models.PromoCode, error)
+	ListBookingPromoCodes(ctx context.Context, bookingID int) ([]models.PromoCode, error)
+	GetApplicableTaxRates(ctx context.Context, propertyID int, checkIn, checkOut time.Time) ([]models.TaxRate, error)
+	GetFolioTotal(ctx context.Context, bookingID int) (float64, error)
 }
 
+// bookingColumns is the column list scanned by scanBooking.
+const bookingColumns = `id, property_id, user_id, room_id, check_in_date, check_out_date, adults, children, total_amount, price_breakdown, status, payment_status, cancellation, special_requests, version, checked_in_at, checked_out_at, hold_expires_at, reservation_id, created_at, updated_at`
+
 // NewBookingRepository creates and returns a new instance of BookingRepository.
 // It accepts a database connection pool for executing database operations.
 func NewBookingRepository(db *pgxpool.Pool) *BookingRepository {
 	return &BookingRepository{db: db}
 }
 
+// BookingConflictError is returned when a booking would overlap an active booking of the same room
+// or a reservation imported from an external calendar.
+// It wraps ErrConflict and carries the conflicting stay so clients can show the taken dates.
+type BookingConflictError struct {
+	Conflict models.BookingConflict // Existing booking occupying some of the requested nights
+}
+
+// Error describes the conflicting stay.
+func (e *BookingConflictError) Error() string {
+	if e.Conflict.ExternalBlockID != nil {
+		return fmt.Sprintf("room %d is reserved on an external platform from %s to %s",
+			e.Conflict.RoomID, e.Conflict.CheckInDate.Format("2006-01-02"), e.Conflict.CheckOutDate.Format("2006-01-02"))
+	}
+	if e.Conflict.BookingID == 0 {
+		return fmt.Sprintf("room %d is already booked for the requested dates", e.Conflict.RoomID)
+	}
+	return fmt.Sprintf("room %d is already booked from %s to %s by booking %d",
+		e.Conflict.RoomID, e.Conflict.CheckInDate.Format("2006-01-02"), e.Conflict.CheckOutDate.Format("2006-01-02"), e.Conflict.BookingID)
+}
+
+// Unwrap lets errors.Is(err, ErrConflict) match booking conflicts.
+func (e *BookingConflictError) Unwrap() error { return ErrConflict }
+
 // AddBooking inserts a new booking record into the database.
-// It executes an INSERT query and returns the generated booking ID and creation timestamp.
+// The room row is locked and checked for overlapping active bookings inside a transaction, so
+// concurrent requests for the same room are serialised; the bookings_no_overlap exclusion
+// constraint is the final guard. An overlap is reported as a *BookingConflictError.
 // Returns the created booking with ID and CreatedAt fields populated, or an error if the operation fails.
 func (b *BookingRepository) AddBooking(ctx context.Context, booking *models.Booking) (*models.Booking, error) {
+	tx, err := b.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := insertBooking(ctx, tx, booking); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit booking: %w", err)
+	}
+	return booking, nil
+}
+
+// insertBooking locks the booking's room, checks it for overlapping active bookings and inserts
+// the booking with its initial status history entry and promo code redemptions as part of the
+// caller's transaction.
+// The insert runs in a savepoint so that an exclusion violation can still be explained with the
+// booking holding the nights.
+func insertBooking(ctx context.Context, tx dbExecutor, booking *models.Booking) error {
+	// Lock the room so overlapping requests for it are checked one at a time
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM rooms WHERE id = $1 FOR UPDATE`, booking.RoomID); err != nil {
+		return fmt.Errorf("failed to lock room: %w", err)
+	}
+	conflict, err := findBookingConflict(ctx, tx, booking.RoomID, booking.CheckInDate, booking.CheckOutDate, 0)
+	if err != nil {
+		return err
+	}
+	if conflict != nil {
+		return &BookingConflictError{Conflict: *conflict}
+	}
+
+	sp, err := tx.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin savepoint: %w", err)
+	}
+	defer sp.Rollback(ctx)
+
 	query := `
-	INSERT INTO bookings (user_id, room_id, check_in_date, check_out_date, adults, children, total_amount, status, payment_status, special_requests)
-	Values($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	INSERT INTO bookings (property_id, user_id, room_id, check_in_date, check_out_date, adults, children, total_amount, price_breakdown, status, payment_status, special_requests, hold_expires_at, reservation_id)
+	Values($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 	RETURNING id, created_at
 	`
 	// Execute the insert query and scan the returned ID and timestamp
-	err := b.db.QueryRow(ctx, query,
+	err = sp.QueryRow(ctx, query,
+		booking.PropertyID,
 		booking.UserID,
 		booking.RoomID,
 		booking.CheckInDate,
@@ -43,13 +133,724 @@ func (b *BookingRepository) AddBooking(ctx context.Context, booking *models.Book
 		booking.Adults,
 		booking.Children,
 		booking.TotalAmount,
+		booking.PriceBreakdown,
 		booking.Status,
 		booking.PaymentStatus,
 		booking.SpecialRequests,
+		booking.HoldExpiresAt,
+		booking.ReservationID,
 	).Scan(&booking.ID, &booking.CreatedAt)
 
 	if err != nil {
+		if hasPgCode(err, pgExclusionViolation) {
+			// A writer bypassed the room lock; report the booking that holds the nights
+			_ = sp.Rollback(ctx)
+			conflict, _ := findBookingConflict(ctx, tx, booking.RoomID, booking.CheckInDate, booking.CheckOutDate, 0)
+			if conflict == nil {
+				conflict = &models.BookingConflict{RoomID: booking.RoomID}
+			}
+			return &BookingConflictError{Conflict: *conflict}
+		}
+		return err
+	}
+	// Record the initial status so the history covers the booking's whole lifecycle
+	if err := recordBookingStatus(ctx, sp, booking.ID, nil, booking.Status, nil, "booking created"); err != nil {
+		return err
+	}
+	if err := redeemPromoCodes(ctx, sp, booking); err != nil {
+		return err
+	}
+	if err := saveBookingTaxes(ctx, sp, booking.ID, booking.PriceBreakdown); err != nil {
+		return err
+	}
+	if err := sp.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to release savepoint: %w", err)
+	}
+	return nil
+}
+
+// findBookingConflict returns the earliest active booking of a room whose nights overlap
+// [checkIn, checkOut), or nil if the nights are free. The booking excludeID (0 for none)
+// is ignored so a booking being modified does not conflict with itself. Without a booking
+// conflict, the earliest overlapping external block is returned instead.
+func findBookingConflict(ctx context.Context, db dbExecutor, roomID int, checkIn, checkOut time.Time, excludeID int) (*models.BookingConflict, error) {
+	query := `
+	SELECT id, room_id, check_in_date, check_out_date
+	FROM bookings
+	WHERE room_id = $1
+	  AND id <> $4
+	  AND status NOT IN ('cancelled', 'no_show')
+	  AND daterange(check_in_date::date, check_out_date::date, '[)') && daterange($2::date, $3::date, '[)')
+	ORDER BY check_in_date
+	LIMIT 1
+	`
+	var conflict models.BookingConflict
+	err := db.QueryRow(ctx, query, roomID, checkIn, checkOut, excludeID).Scan(&conflict.BookingID, &conflict.RoomID, &conflict.CheckInDate, &conflict.CheckOutDate)
+	if err == nil {
+		return &conflict, nil
+	}
+	if err != pgx.ErrNoRows {
+		return nil, fmt.Errorf("failed to check booking overlap: %w", err)
+	}
+
+	err = db.QueryRow(ctx, `
+	SELECT id, room_id, start_date, end_date
+	FROM external_blocks
+	WHERE room_id = $1
+	  AND daterange(start_date, end_date, '[)') && daterange($2::date, $3::date, '[)')
+	ORDER BY start_date
+	LIMIT 1
+	`, roomID, checkIn, checkOut).Scan(&conflict.ExternalBlockID, &conflict.RoomID, &conflict.CheckInDate, &conflict.CheckOutDate)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to check external blocks: %w", err)
+	}
+	return &conflict, nil
+}
+
+// GetRoomByID retrieves the room a booking refers to, including its rate and housekeeping status.
+// Returns an error wrapping ErrNotFound if the room does not exist.
+func (b *BookingRepository) GetRoomByID(ctx context.Context, roomID int) (*models.Room, error) {
+	query := `
+	SELECT id, property_id, room_number, room_type, description, price_per_night, capacity, floor, amenities, is_available, housekeeping_status, created_at, updated_at
+	FROM rooms
+	WHERE id = $1
+	`
+	var room models.Room
+	err := b.db.QueryRow(ctx, query, roomID).Scan(
+		&room.ID,
+		&room.PropertyID,
+		&room.RoomNumber,
+		&room.RoomType,
+		&room.Description,
+		&room.Price,
+		&room.Capacity,
+		&room.Floor,
+		&room.Amenities,
+		&room.IsAvailable,
+		&room.HousekeepingStatus,
+		&room.CreatedAt,
+		&room.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("room %w", ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get room: %w", err)
+	}
+	return &room, nil
+}
+
+// GetBookingByID retrieves a booking by its ID.
+// Returns an error wrapping ErrNotFound if the booking does not exist.
+func (b *BookingRepository) GetBookingByID(ctx context.Context, id int) (*models.Booking, error) {
+	query := `SELECT ` + bookingColumns + ` FROM bookings WHERE id = $1`
+	booking, err := scanBooking(b.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("booking %w", ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get booking: %w", err)
+	}
+	return booking, nil
+}
+
+// SetBookingStatus moves a booking from one status to another and records the change.
+// The update only applies while the booking is still in the from status, so a concurrent
+// change is reported as ErrConflict instead of being overwritten.
+func (b *BookingRepository) SetBookingStatus(ctx context.Context, id int, from, to string, changedBy *int, reason string) (*models.Booking, error) {
+	return setBookingStatus(ctx, b.db, id, from, to, changedBy, reason)
+}
+
+// ListBookingStatusHistory returns every status change of a booking, oldest first.
+func (b *BookingRepository) ListBookingStatusHistory(ctx context.Context, id int) ([]models.BookingStatusChange, error) {
+	query := `
+	SELECT id, booking_id, from_status, to_status, changed_by, reason, created_at
+	FROM booking_status_history
+	WHERE booking_id = $1
+	ORDER BY created_at, id
+	`
+	rows, err := b.db.Query(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list booking status history: %w", err)
+	}
+	defer rows.Close()
+
+	history := []models.BookingStatusChange{}
+	for rows.Next() {
+		var h models.BookingStatusChange
+		if err := rows.Scan(&h.ID, &h.BookingID, &h.FromStatus, &h.ToStatus, &h.ChangedBy, &h.Reason, &h.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan booking status change: %w", err)
+		}
+		history = append(history, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating booking status history: %w", err)
+	}
+	return history, nil
+}
+
+// bookingSortColumns maps the public sort names accepted by ListBookings to database columns.
+// Only whitelisted columns are ever interpolated into the ORDER BY clause.
+var bookingSortColumns = map[string]string{
+	"check_in_date":  "check_in_date",
+	"check_out_date": "check_out_date",
+	"created_at":     "created_at",
+	"total_amount":   "total_amount",
+	"status":         "status",
+}
+
+// buildBookingConditions translates a booking filter into WHERE clauses.
+func buildBookingConditions(filter models.BookingFilter) *queryConditions {
+	conds := &queryConditions{}
+	if filter.PropertyIDs != nil {
+		conds.add("property_id = ANY($%d::int[])", filter.PropertyIDs)
+	}
+	if filter.UserID != nil {
+		conds.add("user_id = $%d", *filter.UserID)
+	}
+	if filter.RoomID != nil {
+		conds.add("room_id = $%d", *filter.RoomID)
+	}
+	if len(filter.Statuses) > 0 {
+		conds.add("status = ANY($%d::text[])", filter.Statuses)
+	}
+	if filter.PaymentStatus != "" {
+		conds.add("payment_status = $%d", filter.PaymentStatus)
+	}
+	dateRanges := []struct {
+		column   string
+		from, to *time.Time
+	}{
+		{"check_in_date", filter.CheckInFrom, filter.CheckInTo},
+		{"check_out_date", filter.CheckOutFrom, filter.CheckOutTo},
+		{"created_at", filter.CreatedFrom, filter.CreatedTo},
+	}
+	for _, r := range dateRanges {
+		if r.from != nil {
+			conds.add(r.column+"::date >= $%d::date", *r.from)
+		}
+		if r.to != nil {
+			conds.add(r.column+"::date <= $%d::date", *r.to)
+		}
+	}
+	return conds
+}
+
+// ListBookings retrieves a filtered, sorted and paginated list of bookings.
+// A limit of 0 returns every matching booking.
+func (b *BookingRepository) ListBookings(ctx context.Context, filter models.BookingFilter) (*models.BookingListResponse, error) {
+	conds := buildBookingConditions(filter)
+
+	// Count all matching bookings for pagination
+	var total int
+	if err := b.db.QueryRow(ctx, "SELECT COUNT(*) FROM bookings"+conds.where(), conds.args...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count bookings: %w", err)
+	}
+
+	// Resolve the sort column from the whitelist, defaulting to newest first
+	column, ok := bookingSortColumns[filter.Sort]
+	if !ok {
+		column = "created_at"
+	}
+	direction := "DESC"
+	if strings.EqualFold(filter.Order, "asc") {
+		direction = "ASC"
+	}
+	// LIMIT NULL means no limit
+	var limit interface{}
+	if filter.Limit > 0 {
+		limit = filter.Limit
+	}
+
+	query := `SELECT ` + bookingColumns + ` FROM bookings` + conds.where() +
+		fmt.Sprintf(" ORDER BY %s %s, id ASC LIMIT $%d OFFSET $%d", column, direction, len(conds.args)+1, len(conds.args)+2)
+	args := append(append([]interface{}{}, conds.args...), limit, pageOffset(filter.Page, filter.Limit))
+	rows, err := b.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bookings: %w", err)
+	}
+	defer rows.Close()
+
+	bookings := []models.Booking{}
+	for rows.Next() {
+		booking, err := scanBooking(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan booking: %w", err)
+		}
+		bookings = append(bookings, *booking)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating bookings: %w", err)
+	}
+
+	return &models.BookingListResponse{
+		Page:       filter.Page,
+		Limit:      filter.Limit,
+		Total:      total,
+		TotalPages: pageCount(total, filter.Limit),
+		Bookings:   bookings,
+	}, nil
+}
+
+// GetPaidAmount returns the sum of a booking's paid payments in cents.
+func (b *BookingRepository) GetPaidAmount(ctx context.Context, bookingID int) (int64, error) {
+	var paid int64
+	err := b.db.QueryRow(ctx, `
+	SELECT COALESCE(SUM(amount), 0)::bigint
+	FROM payments
+	WHERE booking_id = $1 AND status = 'paid'
+	`, bookingID).Scan(&paid)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum booking payments: %w", err)
+	}
+	return paid, nil
+}
+
+// GetFolioTotal returns the net amount of the entries posted to a booking's folio that were not
+// voided: charges less credits, tax included.
+func (b *BookingRepository) GetFolioTotal(ctx context.Context, bookingID int) (float64, error) {
+	var total float64
+	err := b.db.QueryRow(ctx, `
+	SELECT COALESCE(SUM(CASE WHEN kind = 'credit' THEN -1 ELSE 1 END * (amount + tax_amount)), 0)::float8
+	FROM folio_entries
+	WHERE booking_id = $1 AND voided_at IS NULL
+	`, bookingID).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum folio entries: %w", err)
+	}
+	return total, nil
+}
+
+// GetPromoCodes returns the promo codes with the given codes, which must already be upper case.
+// Unknown codes are left out.
+func (b *BookingRepository) GetPromoCodes(ctx context.Context, codes []string) ([]models.PromoCode, error) {
+	return queryPromoCodes(ctx, b.db, `SELECT `+promoColumns+` FROM promo_codes WHERE code = ANY($1::text[])`, codes)
+}
+
+// ListBookingPromoCodes returns the promo codes a booking redeemed when it was created, in the
+// order they were applied.
+func (b *BookingRepository) ListBookingPromoCodes(ctx context.Context, bookingID int) ([]models.PromoCode, error) {
+	return queryPromoCodes(ctx, b.db, `
+	SELECT `+promoColumns+`
+	FROM promo_codes
+	JOIN (SELECT promo_code_id, id AS redemption_id FROM promo_code_redemptions WHERE booking_id = $1) r ON r.promo_code_id = promo_codes.id
+	ORDER BY r.redemption_id
+	`, bookingID)
+}
+
+// GetApplicableTaxRates returns the tax rates of a property, and those of every property, whose
+// validity dates overlap the nights from checkIn to checkOut, in the order they are applied.
+func (b *BookingRepository) GetApplicableTaxRates(ctx context.Context, propertyID int, checkIn, checkOut time.Time) ([]models.TaxRate, error) {
+	return queryTaxRates(ctx, b.db, `
+	SELECT `+taxRateColumns+`
+	FROM tax_rates
+	WHERE (property_id IS NULL OR property_id = $1)
+	  AND (valid_from IS NULL OR valid_from < $3::date)
+	  AND (valid_to IS NULL OR valid_to >= $2::date)
+	ORDER BY id
+	`, propertyID, checkIn, checkOut)
+}
+
+// CancelBooking moves a booking from its current status to cancelled and stores the penalty and
+// refund it was cancelled with in one transaction. The payment status becomes refund_due when
+// money has to be returned. Once cancelled the booking no longer holds its room's nights.
+func (b *BookingRepository) CancelBooking(ctx context.Context, id int, from string, quote *models.CancellationQuote, changedBy *int, reason string) (*models.Booking, error) {
+	return b.closeBooking(ctx, id, from, models.BookingStatusCancelled, quote, changedBy, reason)
+}
+
+// MarkNoShow moves a confirmed booking to no_show and stores its no-show penalty in one
+// transaction, like CancelBooking. The update only applies while the booking is still confirmed,
+// so a booking checked in or marked concurrently is reported as ErrConflict.
+// Once a no-show the booking no longer holds its room's nights.
+func (b *BookingRepository) MarkNoShow(ctx context.Context, id int, quote *models.CancellationQuote, changedBy *int, reason string) (*models.Booking, error) {
+	return b.closeBooking(ctx, id, models.BookingStatusConfirmed, models.BookingStatusNoShow, quote, changedBy, reason)
+}
+
+// closeBooking moves a booking from one status to a final one and stores the penalty quote it
+// was closed with in one transaction.
+func (b *BookingRepository) closeBooking(ctx context.Context, id int, from, to string, quote *models.CancellationQuote, changedBy *int, reason string) (*models.Booking, error) {
+	tx, err := b.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := setBookingStatus(ctx, tx, id, from, to, changedBy, reason); err != nil {
+		return nil, err
+	}
+	query := `
+	UPDATE bookings
+	SET cancellation = $1,
+	    payment_status = CASE WHEN $2 THEN $3 ELSE payment_status END
+	WHERE id = $4
+	RETURNING ` + bookingColumns
+	booking, err := scanBooking(tx.QueryRow(ctx, query, quote, quote.Refund > 0, models.PaymentStatusRefundDue, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to record %s penalty: %w", to, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit %s: %w", to, err)
+	}
+	return booking, nil
+}
+
+// ModifyBooking saves a changed stay and appends it to the booking's change log in one transaction.
+// The target room is locked and re-checked for overlapping bookings, and the update only applies
+// while the booking is still at change.Version-1, so concurrent modifications are reported as
+// ErrConflict. An overlap is reported as a *BookingConflictError.
+func (b *BookingRepository) ModifyBooking(ctx context.Context, booking *models.Booking, change *models.BookingChange) (*models.Booking, error) {
+	tx, err := b.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the target room so the availability check cannot race another booking
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM rooms WHERE id = $1 FOR UPDATE`, booking.RoomID); err != nil {
+		return nil, fmt.Errorf("failed to lock room: %w", err)
+	}
+	conflict, err := findBookingConflict(ctx, tx, booking.RoomID, booking.CheckInDate, booking.CheckOutDate, booking.ID)
+	if err != nil {
+		return nil, err
+	}
+	if conflict != nil {
+		return nil, &BookingConflictError{Conflict: *conflict}
+	}
+
+	query := `
+	UPDATE bookings
+	SET property_id = $1, room_id = $2, check_in_date = $3, check_out_date = $4, adults = $5, children = $6,
+	    total_amount = $7, price_breakdown = $8, special_requests = $9, version = version + 1, updated_at = NOW()
+	WHERE id = $10 AND version = $11 AND status IN ('pending', 'confirmed')
+	RETURNING ` + bookingColumns
+	updated, err := scanBooking(tx.QueryRow(ctx, query,
+		booking.PropertyID,
+		booking.RoomID,
+		booking.CheckInDate,
+		booking.CheckOutDate,
+		booking.Adults,
+		booking.Children,
+		booking.TotalAmount,
+		booking.PriceBreakdown,
+		booking.SpecialRequests,
+		booking.ID,
+		change.Version-1,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("booking was changed concurrently: %w", ErrConflict)
+		}
+		if hasPgCode(err, pgExclusionViolation) {
+			return nil, &BookingConflictError{Conflict: models.BookingConflict{RoomID: booking.RoomID}}
+		}
+		return nil, fmt.Errorf("failed to update booking: %w", err)
+	}
+	if err := saveBookingTaxes(ctx, tx, booking.ID, booking.PriceBreakdown); err != nil {
+		return nil, err
+	}
+	if err := updatePromoRedemptions(ctx, tx, booking); err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow(ctx, `
+	INSERT INTO booking_changes (booking_id, version, changed_by, reason, before, after, price_difference, balance_due, refund_due)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	RETURNING id, created_at
+	`, booking.ID, updated.Version, change.ChangedBy, change.Reason, change.Before, change.After,
+		change.PriceDifference, change.BalanceDue, change.RefundDue).Scan(&change.ID, &change.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record booking change: %w", err)
+	}
+	change.BookingID = booking.ID
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit booking change: %w", err)
+	}
+	return updated, nil
+}
+
+// ListBookingChanges returns the change log of a booking, oldest version first.
+func (b *BookingRepository) ListBookingChanges(ctx context.Context, id int) ([]models.BookingChange, error) {
+	query := `
+	SELECT id, booking_id, version, changed_by, reason, before, after, price_difference, balance_due, refund_due, created_at
+	FROM booking_changes
+	WHERE booking_id = $1
+	ORDER BY version
+	`
+	rows, err := b.db.Query(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list booking changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []models.BookingChange{}
+	for rows.Next() {
+		var ch models.BookingChange
+		err := rows.Scan(&ch.ID, &ch.BookingID, &ch.Version, &ch.ChangedBy, &ch.Reason, &ch.Before, &ch.After,
+			&ch.PriceDifference, &ch.BalanceDue, &ch.RefundDue, &ch.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan booking change: %w", err)
+		}
+		changes = append(changes, ch)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating booking changes: %w", err)
+	}
+	return changes, nil
+}
+
+// CheckInBooking moves a confirmed booking to checked_in, assigns roomID and records the
+// check-in time in one transaction. When the room changes it is locked and checked for
+// overlapping bookings first; an overlap is reported as a *BookingConflictError.
+func (b *BookingRepository) CheckInBooking(ctx context.Context, booking *models.Booking, roomID int, changedBy *int, reason string) (*models.Booking, error) {
+	tx, err := b.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if roomID != booking.RoomID {
+		if _, err := tx.Exec(ctx, `SELECT 1 FROM rooms WHERE id = $1 FOR UPDATE`, roomID); err != nil {
+			return nil, fmt.Errorf("failed to lock room: %w", err)
+		}
+		conflict, err := findBookingConflict(ctx, tx, roomID, booking.CheckInDate, booking.CheckOutDate, booking.ID)
+		if err != nil {
+			return nil, err
+		}
+		if conflict != nil {
+			return nil, &BookingConflictError{Conflict: *conflict}
+		}
+	}
+	if _, err := setBookingStatus(ctx, tx, booking.ID, booking.Status, models.BookingStatusCheckedIn, changedBy, reason); err != nil {
 		return nil, err
 	}
+	query := `
+	UPDATE bookings
+	SET room_id = $1, checked_in_at = NOW()
+	WHERE id = $2
+	RETURNING ` + bookingColumns
+	checkedIn, err := scanBooking(tx.QueryRow(ctx, query, roomID, booking.ID))
+	if err != nil {
+		if hasPgCode(err, pgExclusionViolation) {
+			return nil, &BookingConflictError{Conflict: models.BookingConflict{RoomID: roomID}}
+		}
+		return nil, fmt.Errorf("failed to record check-in: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit check-in: %w", err)
+	}
+	return checkedIn, nil
+}
+
+// CheckOutBooking moves a checked-in booking to checked_out, records the check-out time and
+// flags its room as dirty for housekeeping in one transaction. Rooms that are already dirty
+// or out of order keep their housekeeping status.
+func (b *BookingRepository) CheckOutBooking(ctx context.Context, booking *models.Booking, changedBy *int, reason string) (*models.Booking, error) {
+	tx, err := b.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := setBookingStatus(ctx, tx, booking.ID, booking.Status, models.BookingStatusCheckedOut, changedBy, reason); err != nil {
+		return nil, err
+	}
+	query := `
+	UPDATE bookings
+	SET checked_out_at = NOW()
+	WHERE id = $1
+	RETURNING ` + bookingColumns
+	checkedOut, err := scanBooking(tx.QueryRow(ctx, query, booking.ID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to record check-out: %w", err)
+	}
+
+	note := fmt.Sprintf("guest checked out (booking %d)", booking.ID)
+	if err := markRoomDirty(ctx, tx, checkedOut.RoomID, changedBy, note); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit check-out: %w", err)
+	}
+	return checkedOut, nil
+}
+
+// ExpireHolds cancels up to limit pending bookings whose hold expired before now without a paid
+// payment, releasing their rooms, and returns their IDs. now comes from the same clock that set
+// the holds. Rows are claimed with FOR UPDATE SKIP LOCKED so
+// several API instances can run the expiry at once without cancelling a booking twice or
+// waiting on each other.
+func (b *BookingRepository) ExpireHolds(ctx context.Context, now time.Time, limit int) ([]int, error) {
+	tx, err := b.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+	SELECT id
+	FROM bookings
+	WHERE status = 'pending'
+	  AND hold_expires_at < $1
+	  AND NOT EXISTS (SELECT 1 FROM payments p WHERE p.booking_id = bookings.id AND p.status = 'paid')
+	ORDER BY hold_expires_at
+	LIMIT $2
+	FOR UPDATE SKIP LOCKED
+	`, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find expired holds: %w", err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan expired holds: %w", err)
+	}
+
+	for _, id := range ids {
+		if _, err := setBookingStatus(ctx, tx, id, models.BookingStatusPending, models.BookingStatusCancelled, nil, "hold expired before payment"); err != nil {
+			return nil, err
+		}
+	}
+	if len(ids) > 0 {
+		if _, err := tx.Exec(ctx, `UPDATE bookings SET hold_expires_at = NULL WHERE id = ANY($1)`, ids); err != nil {
+			return nil, fmt.Errorf("failed to clear expired holds: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit expired holds: %w", err)
+	}
+	return ids, nil
+}
+
+// ListNoShowCandidates returns up to limit confirmed bookings whose arrival day has passed the
+// no-show cutoff by now, earliest arrival first. The cutoff is a time of day, given as the time
+// since midnight, in the timezone of the booking's property.
+func (b *BookingRepository) ListNoShowCandidates(ctx context.Context, now time.Time, cutoff time.Duration, limit int) ([]models.Booking, error) {
+	rows, err := b.db.Query(ctx, `
+	SELECT `+bookingColumns+`
+	FROM bookings
+	WHERE status = 'confirmed'
+	  AND (check_in_date::date + $2::int * interval '1 second')
+	      AT TIME ZONE COALESCE((SELECT p.timezone FROM properties p WHERE p.id = bookings.property_id), 'UTC') <= $1
+	ORDER BY check_in_date, id
+	LIMIT $3
+	`, now, int(cutoff.Seconds()), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find no-shows: %w", err)
+	}
+	defer rows.Close()
+
+	bookings := []models.Booking{}
+	for rows.Next() {
+		booking, err := scanBooking(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan booking: %w", err)
+		}
+		bookings = append(bookings, *booking)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to find no-shows: %w", err)
+	}
+	return bookings, nil
+}
+
+// setBookingStatus performs a conditional booking status change and records it in the history.
+// Bookings moving to cancelled or no_show release their promo code redemptions.
+// When db is a transaction, Begin starts a savepoint so the change joins the caller's transaction.
+func setBookingStatus(ctx context.Context, db dbExecutor, id int, from, to string, changedBy *int, reason string) (*models.Booking, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+	UPDATE bookings
+	SET status = $1, updated_at = NOW()
+	WHERE id = $2 AND status = $3
+	RETURNING ` + bookingColumns
+	booking, err := scanBooking(tx.QueryRow(ctx, query, to, id, from))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("booking status changed concurrently: %w", ErrConflict)
+		}
+		return nil, fmt.Errorf("failed to update booking status: %w", err)
+	}
+	if err := recordBookingStatus(ctx, tx, id, &from, to, changedBy, reason); err != nil {
+		return nil, err
+	}
+	// A booking that no longer holds its nights gives its promo code uses back
+	if to == models.BookingStatusCancelled || to == models.BookingStatusNoShow {
+		if err := releasePromoCodes(ctx, tx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit booking status: %w", err)
+	}
 	return booking, nil
 }
+
+// recordBookingStatus appends an entry to the booking status history and queues the guest
+// notification for it: creation, confirmation and cancellation are written to the outbox.
+// from is nil for the entry written when the booking is created.
+func recordBookingStatus(ctx context.Context, db dbExecutor, id int, from *string, to string, changedBy *int, reason string) error {
+	_, err := db.Exec(ctx, `
+	INSERT INTO booking_status_history (booking_id, from_status, to_status, changed_by, reason)
+	VALUES ($1, $2, $3, $4, $5)
+	`, id, from, to, changedBy, reason)
+	if err != nil {
+		return fmt.Errorf("failed to record booking status: %w", err)
+	}
+
+	event, notify := bookingStatusEvents[to]
+	payload := models.OutboxPayload{Reason: reason}
+	if from == nil {
+		event, notify = models.EventBookingCreated, true
+	} else {
+		payload.FromStatus = *from
+	}
+	if !notify {
+		return nil
+	}
+	return recordOutboxEvent(ctx, db, event, id, payload)
+}
+
+// scanBooking scans a row selected with bookingColumns.
+func scanBooking(row pgx.Row) (*models.Booking, error) {
+	var booking models.Booking
+	err := row.Scan(
+		&booking.ID,
+		&booking.PropertyID,
+		&booking.UserID,
+		&booking.RoomID,
+		&booking.CheckInDate,
+		&booking.CheckOutDate,
+		&booking.Adults,
+		&booking.Children,
+		&booking.TotalAmount,
+		&booking.PriceBreakdown,
+		&booking.Status,
+		&booking.PaymentStatus,
+		&booking.Cancellation,
+		&booking.SpecialRequests,
+		&booking.Version,
+		&booking.CheckedInAt,
+		&booking.CheckedOutAt,
+		&booking.HoldExpiresAt,
+		&booking.ReservationID,
+		&booking.CreatedAt,
+		&booking.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
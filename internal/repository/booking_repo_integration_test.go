@@ -2,7 +2,9 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
@@ -61,3 +63,438 @@ func TestBookingRepo_Create(t *testing.T) {
 		t.Logf("warning: cleanup failed: %v", err)
 	}
 }
+
+func TestBookingRepo_ParallelBookingsOnlyOneSucceeds(t *testing.T) {
+	_ = testsetup.LoadEnv()
+	dsn := os.Getenv("TEST_DB_URL")
+	if dsn == "" {
+		dsn = os.Getenv("DB_URL")
+	}
+	if dsn == "" {
+		t.Skip("DB_URL or TEST_DB_URL not set; skipping integration tests")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to db: %v", err)
+	}
+	defer pool.Close()
+
+	property := createTestProperty(t, ctx, pool, "K")
+	userID := createTestUser(t, ctx, pool, models.RoleGuest)
+	room := &models.Room{PropertyID: property.ID, RoomNumber: "DB-1", RoomType: "Test", Description: "Double booking test room", Price: 10, Capacity: 2, Floor: 1, Amenities: []string{"test"}}
+	if err := NewRoomRepository(pool).AddRoom(ctx, room); err != nil {
+		t.Fatalf("AddRoom failed: %v", err)
+	}
+	defer func() {
+		if _, err := pool.Exec(ctx, "DELETE FROM bookings WHERE room_id = $1", room.ID); err != nil {
+			t.Logf("warning: cleanup failed: %v", err)
+		}
+		if _, err := pool.Exec(ctx, "DELETE FROM rooms WHERE id = $1", room.ID); err != nil {
+			t.Logf("warning: cleanup failed: %v", err)
+		}
+	}()
+
+	repo := NewBookingRepository(pool)
+	checkIn := time.Now().AddDate(1, 0, 0).Truncate(24 * time.Hour)
+
+	// every request overlaps the others by at least one night
+	const parallel = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, parallel)
+	for i := 0; i < parallel; i++ {
+		wg.Add(1)
+		go func(offset int) {
+			defer wg.Done()
+			b := &models.Booking{
+				PropertyID:    property.ID,
+				UserID:        userID,
+				RoomID:        room.ID,
+				CheckInDate:   checkIn.AddDate(0, 0, offset%2),
+				CheckOutDate:  checkIn.AddDate(0, 0, 2+offset%2),
+				Adults:        1,
+				TotalAmount:   20,
+				Status:        "pending",
+				PaymentStatus: "pending",
+			}
+			_, err := repo.AddBooking(ctx, b)
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		var conflict *BookingConflictError
+		if !errors.As(err, &conflict) {
+			t.Fatalf("expected a booking conflict, got %v", err)
+		}
+		if conflict.Conflict.BookingID == 0 || conflict.Conflict.CheckInDate.IsZero() {
+			t.Fatalf("expected conflicting booking details, got %+v", conflict.Conflict)
+		}
+	}
+	if succeeded != 1 {
+		t.Fatalf("expected exactly one of %d parallel bookings to succeed, got %d", parallel, succeeded)
+	}
+
+	// back-to-back stays do not conflict
+	next := &models.Booking{PropertyID: property.ID, UserID: userID, RoomID: room.ID, CheckInDate: checkIn.AddDate(0, 0, 3), CheckOutDate: checkIn.AddDate(0, 0, 4), Adults: 1, TotalAmount: 10, Status: "pending", PaymentStatus: "pending"}
+	if _, err := repo.AddBooking(ctx, next); err != nil {
+		t.Fatalf("expected booking starting on the previous check-out day to succeed, got %v", err)
+	}
+}
+
+func TestBookingRepo_StatusChangesAreRecorded(t *testing.T) {
+	_ = testsetup.LoadEnv()
+	dsn := os.Getenv("TEST_DB_URL")
+	if dsn == "" {
+		dsn = os.Getenv("DB_URL")
+	}
+	if dsn == "" {
+		t.Skip("DB_URL or TEST_DB_URL not set; skipping integration tests")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to db: %v", err)
+	}
+	defer pool.Close()
+
+	property := createTestProperty(t, ctx, pool, "S")
+	userID := createTestUser(t, ctx, pool, models.RoleStaff)
+	room := &models.Room{PropertyID: property.ID, RoomNumber: "ST-1", RoomType: "Test", Description: "Status history test room", Price: 10, Capacity: 2, Floor: 1, Amenities: []string{"test"}}
+	if err := NewRoomRepository(pool).AddRoom(ctx, room); err != nil {
+		t.Fatalf("AddRoom failed: %v", err)
+	}
+	defer func() {
+		if _, err := pool.Exec(ctx, "DELETE FROM bookings WHERE room_id = $1", room.ID); err != nil {
+			t.Logf("warning: cleanup failed: %v", err)
+		}
+		if _, err := pool.Exec(ctx, "DELETE FROM rooms WHERE id = $1", room.ID); err != nil {
+			t.Logf("warning: cleanup failed: %v", err)
+		}
+	}()
+
+	repo := NewBookingRepository(pool)
+	checkIn := time.Now().AddDate(1, 1, 0).Truncate(24 * time.Hour)
+	b := &models.Booking{PropertyID: property.ID, UserID: userID, RoomID: room.ID, CheckInDate: checkIn, CheckOutDate: checkIn.AddDate(0, 0, 1), Adults: 1, TotalAmount: 10, Status: models.BookingStatusPending, PaymentStatus: models.PaymentStatusPending}
+	if _, err := repo.AddBooking(ctx, b); err != nil {
+		t.Fatalf("AddBooking failed: %v", err)
+	}
+
+	list, err := repo.ListBookings(ctx, models.BookingFilter{RoomID: &room.ID, Statuses: []string{models.BookingStatusPending}, CheckInFrom: &checkIn, CheckInTo: &checkIn, Sort: "check_in_date", Page: 1, Limit: 10})
+	if err != nil {
+		t.Fatalf("ListBookings failed: %v", err)
+	}
+	if list.Total != 1 || len(list.Bookings) != 1 || list.Bookings[0].ID != b.ID {
+		t.Fatalf("expected the new booking to be listed, got %+v", list)
+	}
+
+	updated, err := repo.SetBookingStatus(ctx, b.ID, models.BookingStatusPending, models.BookingStatusConfirmed, &userID, "deposit received")
+	if err != nil {
+		t.Fatalf("SetBookingStatus failed: %v", err)
+	}
+	if updated.Status != models.BookingStatusConfirmed {
+		t.Fatalf("expected confirmed, got %s", updated.Status)
+	}
+	// a stale from status is rejected
+	if _, err := repo.SetBookingStatus(ctx, b.ID, models.BookingStatusPending, models.BookingStatusCancelled, &userID, ""); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected conflict for stale status, got %v", err)
+	}
+
+	history, err := repo.ListBookingStatusHistory(ctx, b.ID)
+	if err != nil {
+		t.Fatalf("ListBookingStatusHistory failed: %v", err)
+	}
+	if len(history) != 2 || history[0].FromStatus != nil || history[1].ToStatus != models.BookingStatusConfirmed || history[1].ChangedBy == nil || *history[1].ChangedBy != userID {
+		t.Fatalf("unexpected history: %+v", history)
+	}
+
+	// modifying bumps the version and appends to the change log
+	changed := *updated
+	changed.CheckOutDate = checkIn.AddDate(0, 0, 2)
+	changed.TotalAmount = 20
+	change := &models.BookingChange{Version: updated.Version + 1, ChangedBy: &userID, Before: updated.Snapshot(), After: changed.Snapshot(), PriceDifference: 10, BalanceDue: 20}
+	modified, err := repo.ModifyBooking(ctx, &changed, change)
+	if err != nil {
+		t.Fatalf("ModifyBooking failed: %v", err)
+	}
+	if modified.Version != 2 || modified.TotalAmount != 20 {
+		t.Fatalf("unexpected modified booking: %+v", modified)
+	}
+	if _, err := repo.ModifyBooking(ctx, &changed, &models.BookingChange{Version: 2}); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected conflict for a stale version, got %v", err)
+	}
+	changes, err := repo.ListBookingChanges(ctx, b.ID)
+	if err != nil || len(changes) != 1 || changes[0].After.TotalAmount != 20 {
+		t.Fatalf("unexpected change log: %+v err=%v", changes, err)
+	}
+
+	// cancelling stores the quote and flags the refund
+	quote := &models.CancellationQuote{BookingID: b.ID, TotalAmount: 10, AmountPaid: 10, Penalty: 4, Refund: 6}
+	cancelled, err := repo.CancelBooking(ctx, b.ID, models.BookingStatusConfirmed, quote, &userID, "guest request")
+	if err != nil {
+		t.Fatalf("CancelBooking failed: %v", err)
+	}
+	if cancelled.Status != models.BookingStatusCancelled || cancelled.PaymentStatus != models.PaymentStatusRefundDue || cancelled.Cancellation == nil || cancelled.Cancellation.Refund != 6 {
+		t.Fatalf("unexpected cancelled booking: %+v", cancelled)
+	}
+}
+
+func TestBookingRepo_CheckInOutMarksRoomDirty(t *testing.T) {
+	_ = testsetup.LoadEnv()
+	dsn := os.Getenv("TEST_DB_URL")
+	if dsn == "" {
+		dsn = os.Getenv("DB_URL")
+	}
+	if dsn == "" {
+		t.Skip("DB_URL or TEST_DB_URL not set; skipping integration tests")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to db: %v", err)
+	}
+	defer pool.Close()
+
+	property := createTestProperty(t, ctx, pool, "F")
+	userID := createTestUser(t, ctx, pool, models.RoleStaff)
+	room := &models.Room{PropertyID: property.ID, RoomNumber: "FD-1", RoomType: "Test", Description: "Front desk test room", Price: 10, Capacity: 2, Floor: 1, Amenities: []string{"test"}}
+	if err := NewRoomRepository(pool).AddRoom(ctx, room); err != nil {
+		t.Fatalf("AddRoom failed: %v", err)
+	}
+	defer func() {
+		if _, err := pool.Exec(ctx, "DELETE FROM bookings WHERE room_id = $1", room.ID); err != nil {
+			t.Logf("warning: cleanup failed: %v", err)
+		}
+		if _, err := pool.Exec(ctx, "DELETE FROM housekeeping_events WHERE room_id = $1", room.ID); err != nil {
+			t.Logf("warning: cleanup failed: %v", err)
+		}
+		if _, err := pool.Exec(ctx, "DELETE FROM rooms WHERE id = $1", room.ID); err != nil {
+			t.Logf("warning: cleanup failed: %v", err)
+		}
+	}()
+	if _, err := pool.Exec(ctx, "UPDATE rooms SET housekeeping_status = $1 WHERE id = $2", models.HousekeepingInspected, room.ID); err != nil {
+		t.Fatalf("failed to prepare room: %v", err)
+	}
+
+	repo := NewBookingRepository(pool)
+	checkIn := time.Now().AddDate(1, 2, 0).Truncate(24 * time.Hour)
+	b := &models.Booking{PropertyID: property.ID, UserID: userID, RoomID: room.ID, CheckInDate: checkIn, CheckOutDate: checkIn.AddDate(0, 0, 1), Adults: 1, TotalAmount: 10, Status: models.BookingStatusPending, PaymentStatus: models.PaymentStatusPending}
+	if _, err := repo.AddBooking(ctx, b); err != nil {
+		t.Fatalf("AddBooking failed: %v", err)
+	}
+	confirmed, err := repo.SetBookingStatus(ctx, b.ID, models.BookingStatusPending, models.BookingStatusConfirmed, &userID, "")
+	if err != nil {
+		t.Fatalf("SetBookingStatus failed: %v", err)
+	}
+
+	checkedIn, err := repo.CheckInBooking(ctx, confirmed, room.ID, &userID, "")
+	if err != nil {
+		t.Fatalf("CheckInBooking failed: %v", err)
+	}
+	if checkedIn.Status != models.BookingStatusCheckedIn || checkedIn.CheckedInAt == nil {
+		t.Fatalf("unexpected checked-in booking: %+v", checkedIn)
+	}
+	checkedOut, err := repo.CheckOutBooking(ctx, checkedIn, &userID, "")
+	if err != nil {
+		t.Fatalf("CheckOutBooking failed: %v", err)
+	}
+	if checkedOut.Status != models.BookingStatusCheckedOut || checkedOut.CheckedOutAt == nil {
+		t.Fatalf("unexpected checked-out booking: %+v", checkedOut)
+	}
+
+	var status string
+	if err := pool.QueryRow(ctx, "SELECT housekeeping_status FROM rooms WHERE id = $1", room.ID).Scan(&status); err != nil {
+		t.Fatalf("failed to read housekeeping status: %v", err)
+	}
+	if status != models.HousekeepingDirty {
+		t.Fatalf("expected the room to be dirty after check-out, got %s", status)
+	}
+	history, err := repo.ListBookingStatusHistory(ctx, b.ID)
+	if err != nil || len(history) != 4 {
+		t.Fatalf("expected created, confirmed, checked in and checked out in the history, got %+v err=%v", history, err)
+	}
+}
+
+func TestBookingRepo_ExpireHoldsOnce(t *testing.T) {
+	_ = testsetup.LoadEnv()
+	dsn := os.Getenv("TEST_DB_URL")
+	if dsn == "" {
+		dsn = os.Getenv("DB_URL")
+	}
+	if dsn == "" {
+		t.Skip("DB_URL or TEST_DB_URL not set; skipping integration tests")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to db: %v", err)
+	}
+	defer pool.Close()
+
+	property := createTestProperty(t, ctx, pool, "H")
+	userID := createTestUser(t, ctx, pool, models.RoleGuest)
+	room := &models.Room{PropertyID: property.ID, RoomNumber: "HO-1", RoomType: "Test", Description: "Hold expiry test room", Price: 10, Capacity: 2, Floor: 1, Amenities: []string{"test"}}
+	if err := NewRoomRepository(pool).AddRoom(ctx, room); err != nil {
+		t.Fatalf("AddRoom failed: %v", err)
+	}
+	defer func() {
+		if _, err := pool.Exec(ctx, "DELETE FROM bookings WHERE room_id = $1", room.ID); err != nil {
+			t.Logf("warning: cleanup failed: %v", err)
+		}
+		if _, err := pool.Exec(ctx, "DELETE FROM rooms WHERE id = $1", room.ID); err != nil {
+			t.Logf("warning: cleanup failed: %v", err)
+		}
+	}()
+
+	repo := NewBookingRepository(pool)
+	now := time.Now()
+	expired := now.Add(-time.Minute)
+	checkIn := now.AddDate(1, 3, 0).Truncate(24 * time.Hour)
+	b := &models.Booking{PropertyID: property.ID, UserID: userID, RoomID: room.ID, CheckInDate: checkIn, CheckOutDate: checkIn.AddDate(0, 0, 1), Adults: 1, TotalAmount: 10, Status: models.BookingStatusPending, PaymentStatus: models.PaymentStatusPending, HoldExpiresAt: &expired}
+	if _, err := repo.AddBooking(ctx, b); err != nil {
+		t.Fatalf("AddBooking failed: %v", err)
+	}
+
+	// two instances sweeping at once cancel the booking exactly once
+	var wg sync.WaitGroup
+	results := make(chan []int, 2)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ids, err := repo.ExpireHolds(ctx, now, 100)
+			if err != nil {
+				t.Errorf("ExpireHolds failed: %v", err)
+			}
+			results <- ids
+		}()
+	}
+	wg.Wait()
+	close(results)
+	count := 0
+	for ids := range results {
+		for _, id := range ids {
+			if id == b.ID {
+				count++
+			}
+		}
+	}
+	if count != 1 {
+		t.Fatalf("expected the booking to be expired once, got %d", count)
+	}
+
+	expiredBooking, err := repo.GetBookingByID(ctx, b.ID)
+	if err != nil {
+		t.Fatalf("GetBookingByID failed: %v", err)
+	}
+	if expiredBooking.Status != models.BookingStatusCancelled || expiredBooking.HoldExpiresAt != nil {
+		t.Fatalf("expected a cancelled booking without hold, got %+v", expiredBooking)
+	}
+}
+
+func TestBookingRepo_MarkNoShowOnce(t *testing.T) {
+	_ = testsetup.LoadEnv()
+	dsn := os.Getenv("TEST_DB_URL")
+	if dsn == "" {
+		dsn = os.Getenv("DB_URL")
+	}
+	if dsn == "" {
+		t.Skip("DB_URL or TEST_DB_URL not set; skipping integration tests")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to db: %v", err)
+	}
+	defer pool.Close()
+
+	property := createTestProperty(t, ctx, pool, "N")
+	userID := createTestUser(t, ctx, pool, models.RoleGuest)
+	room := &models.Room{PropertyID: property.ID, RoomNumber: "NS-1", RoomType: "Test", Description: "No-show test room", Price: 10, Capacity: 2, Floor: 1, Amenities: []string{"test"}}
+	if err := NewRoomRepository(pool).AddRoom(ctx, room); err != nil {
+		t.Fatalf("AddRoom failed: %v", err)
+	}
+	defer func() {
+		if _, err := pool.Exec(ctx, "DELETE FROM bookings WHERE room_id = $1", room.ID); err != nil {
+			t.Logf("warning: cleanup failed: %v", err)
+		}
+		if _, err := pool.Exec(ctx, "DELETE FROM rooms WHERE id = $1", room.ID); err != nil {
+			t.Logf("warning: cleanup failed: %v", err)
+		}
+	}()
+
+	repo := NewBookingRepository(pool)
+	checkIn := time.Now().AddDate(2, 0, 0).Truncate(24 * time.Hour)
+	b := &models.Booking{PropertyID: property.ID, UserID: userID, RoomID: room.ID, CheckInDate: checkIn, CheckOutDate: checkIn.AddDate(0, 0, 2), Adults: 1, TotalAmount: 20, Status: models.BookingStatusConfirmed, PaymentStatus: models.PaymentStatusPending}
+	if _, err := repo.AddBooking(ctx, b); err != nil {
+		t.Fatalf("AddBooking failed: %v", err)
+	}
+
+	cutoff := 23 * time.Hour
+	before, err := repo.ListNoShowCandidates(ctx, checkIn.Add(22*time.Hour), cutoff, 1000)
+	if err != nil {
+		t.Fatalf("ListNoShowCandidates failed: %v", err)
+	}
+	for _, candidate := range before {
+		if candidate.ID == b.ID {
+			t.Fatal("expected the booking not to be a no-show before the cutoff")
+		}
+	}
+	after, err := repo.ListNoShowCandidates(ctx, checkIn.Add(24*time.Hour), cutoff, 1000)
+	if err != nil {
+		t.Fatalf("ListNoShowCandidates failed: %v", err)
+	}
+	found := false
+	for _, candidate := range after {
+		found = found || candidate.ID == b.ID
+	}
+	if !found {
+		t.Fatal("expected the booking to be a no-show after the cutoff")
+	}
+
+	quote := &models.CancellationQuote{BookingID: b.ID, Penalty: 10, PenaltyDue: 10}
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.MarkNoShow(ctx, b.ID, quote, nil, "guest did not arrive")
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	marked := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			marked++
+		case !errors.Is(err, ErrConflict):
+			t.Fatalf("MarkNoShow failed: %v", err)
+		}
+	}
+	if marked != 1 {
+		t.Fatalf("expected the booking to be marked once, got %d", marked)
+	}
+
+	noShow, err := repo.GetBookingByID(ctx, b.ID)
+	if err != nil {
+		t.Fatalf("GetBookingByID failed: %v", err)
+	}
+	if noShow.Status != models.BookingStatusNoShow || noShow.Cancellation == nil || noShow.Cancellation.PenaltyDue != 10 {
+		t.Fatalf("expected a no-show with its penalty, got %+v", noShow)
+	}
+}
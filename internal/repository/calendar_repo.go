@@ -0,0 +1,102 @@
+// Package repository provides database access layer implementations.
+// Repositories handle all direct database operations using SQL queries.
+package repository
+
+import (
+	"context"
+	"fmt"
+	"industry-api/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CalendarRepository provides database access for calendar feeds.
+type CalendarRepository struct {
+	db *pgxpool.Pool // Database connection pool
+}
+
+// CalendarRepo defines the methods used by services to build calendar feeds.
+type CalendarRepo interface {
+	ListCalendarEntries(ctx context.Context, filter models.CalendarFilter) ([]models.CalendarEntry, error)
+	GetFeedUser(ctx context.Context, userID int) (role string, propertyIDs []int, err error)
+	GetRoomPropertyID(ctx context.Context, roomID int) (int, error)
+}
+
+// NewCalendarRepository creates and returns a new instance of CalendarRepository.
+// It accepts a database connection pool for executing database operations.
+func NewCalendarRepository(db *pgxpool.Pool) *CalendarRepository {
+	return &CalendarRepository{db: db}
+}
+
+// ListCalendarEntries returns the bookings and maintenance windows of the filtered rooms that
+// overlap the filter's window, ordered by start. Cancelled bookings, no-shows and cancelled
+// maintenance are left out.
+func (r *CalendarRepository) ListCalendarEntries(ctx context.Context, filter models.CalendarFilter) ([]models.CalendarEntry, error) {
+	rows, err := r.db.Query(ctx, `
+	SELECT 'booking', b.id, b.property_id, b.room_id, rm.room_number, b.check_in_date, b.check_out_date, b.status,
+	       b.adults, b.children, '', b.version, b.updated_at
+	FROM bookings b
+	JOIN rooms rm ON rm.id = b.room_id
+	WHERE b.status NOT IN ('cancelled', 'no_show')
+	  AND b.check_in_date < $2 AND b.check_out_date > $1
+	  AND ($3::int IS NULL OR b.room_id = $3)
+	  AND ($4::int[] IS NULL OR b.property_id = ANY($4))
+	UNION ALL
+	SELECT 'maintenance', m.id, m.property_id, m.room_id, rm.room_number, m.start_date, m.end_date, m.status,
+	       0, 0, m.reason, 0, m.created_at
+	FROM room_maintenance m
+	JOIN rooms rm ON rm.id = m.room_id
+	WHERE m.status <> 'cancelled'
+	  AND m.start_date < $2 AND m.end_date >= $1
+	  AND ($3::int IS NULL OR m.room_id = $3)
+	  AND ($4::int[] IS NULL OR m.property_id = ANY($4))
+	ORDER BY 6, 1, 2
+	`, filter.From, filter.To, filter.RoomID, filter.PropertyIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list calendar entries: %w", err)
+	}
+	defer rows.Close()
+	entries := []models.CalendarEntry{}
+	for rows.Next() {
+		var e models.CalendarEntry
+		if err := rows.Scan(&e.Kind, &e.ID, &e.PropertyID, &e.RoomID, &e.RoomNumber, &e.Start, &e.End, &e.Status,
+			&e.Adults, &e.Children, &e.Reason, &e.Sequence, &e.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan calendar entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list calendar entries: %w", err)
+	}
+	return entries, nil
+}
+
+// GetFeedUser returns the role of a user and the properties they are assigned to.
+// Returns an error wrapping ErrNotFound if the user does not exist.
+func (r *CalendarRepository) GetFeedUser(ctx context.Context, userID int) (string, []int, error) {
+	var role string
+	var propertyIDs []int
+	err := r.db.QueryRow(ctx, `SELECT role, `+userPropertyIDsColumn+` FROM users WHERE id = $1`, userID).Scan(&role, &propertyIDs)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return "", nil, fmt.Errorf("user %w", ErrNotFound)
+		}
+		return "", nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return role, propertyIDs, nil
+}
+
+// GetRoomPropertyID returns the property a room belongs to.
+// Returns an error wrapping ErrNotFound if the room does not exist.
+func (r *CalendarRepository) GetRoomPropertyID(ctx context.Context, roomID int) (int, error) {
+	var propertyID int
+	err := r.db.QueryRow(ctx, `SELECT property_id FROM rooms WHERE id = $1`, roomID).Scan(&propertyID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, fmt.Errorf("room %w", ErrNotFound)
+		}
+		return 0, fmt.Errorf("failed to get room: %w", err)
+	}
+	return propertyID, nil
+}
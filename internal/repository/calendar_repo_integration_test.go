@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"industry-api/internal/models"
+	"industry-api/internal/testsetup"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestCalendarRepo_ListEntries(t *testing.T) {
+	_ = testsetup.LoadEnv()
+	dsn := os.Getenv("TEST_DB_URL")
+	if dsn == "" {
+		dsn = os.Getenv("DB_URL")
+	}
+	if dsn == "" {
+		t.Skip("DB_URL or TEST_DB_URL not set; skipping integration tests")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to db: %v", err)
+	}
+	defer pool.Close()
+
+	property := createTestProperty(t, ctx, pool, "C")
+	userID := createTestUser(t, ctx, pool, models.RoleGuest)
+	room := &models.Room{PropertyID: property.ID, RoomNumber: "CA-1", RoomType: "Test", Description: "Calendar test room", Price: 100, Capacity: 2, Floor: 1, Amenities: []string{"test"}}
+	if err := NewRoomRepository(pool).AddRoom(ctx, room); err != nil {
+		t.Fatalf("AddRoom failed: %v", err)
+	}
+	checkIn := time.Now().AddDate(1, 11, 0).Truncate(24 * time.Hour)
+	bookings := NewBookingRepository(pool)
+	stay := &models.Booking{PropertyID: property.ID, UserID: userID, RoomID: room.ID, CheckInDate: checkIn, CheckOutDate: checkIn.AddDate(0, 0, 2), Adults: 2, TotalAmount: 200, Status: models.BookingStatusConfirmed, PaymentStatus: models.PaymentStatusPending}
+	cancelled := &models.Booking{PropertyID: property.ID, UserID: userID, RoomID: room.ID, CheckInDate: checkIn.AddDate(0, 0, 5), CheckOutDate: checkIn.AddDate(0, 0, 6), Adults: 1, TotalAmount: 100, Status: models.BookingStatusCancelled, PaymentStatus: models.PaymentStatusPending}
+	for _, b := range []*models.Booking{stay, cancelled} {
+		if _, err := bookings.AddBooking(ctx, b); err != nil {
+			t.Fatalf("AddBooking failed: %v", err)
+		}
+	}
+	maintenance, err := NewRoomMaintenanceRepository(pool).AddRoomMaintenance(ctx, &models.RoomMaintenance{RoomID: room.ID, StartDate: checkIn.AddDate(0, 0, 3), EndDate: checkIn.AddDate(0, 0, 3).Add(4 * time.Hour), Reason: "Paint", Status: "scheduled", CreatedBy: userID})
+	if err != nil {
+		t.Fatalf("AddRoomMaintenance failed: %v", err)
+	}
+	defer func() {
+		_, _ = pool.Exec(ctx, "DELETE FROM room_maintenance WHERE id = $1", maintenance.ID)
+		_, _ = pool.Exec(ctx, "DELETE FROM bookings WHERE room_id = $1", room.ID)
+		_, _ = pool.Exec(ctx, "DELETE FROM rooms WHERE id = $1", room.ID)
+	}()
+
+	repo := NewCalendarRepository(pool)
+	entries, err := repo.ListCalendarEntries(ctx, models.CalendarFilter{RoomID: &room.ID, From: checkIn.AddDate(0, 0, -1), To: checkIn.AddDate(0, 0, 10)})
+	if err != nil {
+		t.Fatalf("ListCalendarEntries failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Kind != models.CalendarEntryBooking || entries[0].ID != stay.ID || entries[0].RoomNumber != "CA-1" ||
+		entries[1].Kind != models.CalendarEntryMaintenance || entries[1].Reason != "Paint" {
+		t.Fatalf("expected the stay then the maintenance window, got %+v", entries)
+	}
+	if entries, err := repo.ListCalendarEntries(ctx, models.CalendarFilter{PropertyIDs: []int{property.ID}, From: checkIn.AddDate(0, 0, 4), To: checkIn.AddDate(0, 0, 10)}); err != nil || len(entries) != 0 {
+		t.Fatalf("expected nothing outside the window, got %+v err=%v", entries, err)
+	}
+	if propertyID, err := repo.GetRoomPropertyID(ctx, room.ID); err != nil || propertyID != property.ID {
+		t.Fatalf("expected property %d, got %d err=%v", property.ID, propertyID, err)
+	}
+}
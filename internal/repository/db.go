@@ -0,0 +1,19 @@
+// Package repository provides database access layer implementations.
+// Repositories handle all direct database operations using SQL queries.
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// dbExecutor is implemented by both *pgxpool.Pool and pgx.Tx so helpers can run
+// either standalone or as part of a caller's transaction.
+type dbExecutor interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
@@ -0,0 +1,30 @@
+// Package repository provides database access layer implementations.
+// Repositories handle all direct database operations using SQL queries.
+package repository
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrNotFound is wrapped by errors returned when a requested record does not exist,
+// e.g. fmt.Errorf("media %w", ErrNotFound) yields "media not found".
+var ErrNotFound = errors.New("not found")
+
+// ErrConflict is wrapped by errors returned when a write loses a race with a concurrent
+// change or violates a uniqueness or exclusion rule.
+var ErrConflict = errors.New("conflict")
+
+// PostgreSQL error codes checked by the repositories.
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgExclusionViolation  = "23P01"
+)
+
+// hasPgCode reports whether err is a PostgreSQL error with the given SQLSTATE code.
+func hasPgCode(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code
+}
@@ -0,0 +1,254 @@
+// Package repository provides database access layer implementations.
+// Repositories handle all direct database operations using SQL queries.
+package repository
+
+import (
+	"context"
+	"fmt"
+	"industry-api/internal/models"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ExternalCalendarRepository provides database access for external calendars and their blocks.
+type ExternalCalendarRepository struct {
+	db *pgxpool.Pool // Database connection pool
+}
+
+// ExternalCalendarRepo defines the methods used by services for external calendar sync.
+type ExternalCalendarRepo interface {
+	CreateExternalCalendar(ctx context.Context, calendar *models.ExternalCalendar) error
+	GetExternalCalendar(ctx context.Context, id int) (*models.ExternalCalendar, error)
+	ListExternalCalendars(ctx context.Context, propertyIDs []int) ([]models.ExternalCalendar, error)
+	DeleteExternalCalendar(ctx context.Context, id int) error
+	ListExternalBlocks(ctx context.Context, calendarID int) ([]models.ExternalBlock, error)
+	ClaimDueExternalCalendars(ctx context.Context, syncedBefore time.Time, limit int) ([]models.ExternalCalendar, error)
+	ReplaceExternalBlocks(ctx context.Context, calendarID int, blocks []models.ExternalBlock, etag, lastModified string) error
+	MarkExternalCalendarSynced(ctx context.Context, calendarID int, etag, lastModified string) error
+	RecordExternalCalendarError(ctx context.Context, calendarID int, message string) error
+}
+
+// NewExternalCalendarRepository creates and returns a new instance of ExternalCalendarRepository.
+// It accepts a database connection pool for executing database operations.
+func NewExternalCalendarRepository(db *pgxpool.Pool) *ExternalCalendarRepository {
+	return &ExternalCalendarRepository{db: db}
+}
+
+// externalCalendarColumns is the column list scanned by scanExternalCalendar. Besides the stored
+// sync state it counts the calendar's blocks and the active bookings they overlap.
+const externalCalendarColumns = `c.id, c.property_id, c.room_id, c.name, c.url, c.active, c.etag, c.last_modified,
+	c.last_synced_at, c.last_success_at, c.last_error,
+	(SELECT COUNT(*) FROM external_blocks eb WHERE eb.calendar_id = c.id),
+	(SELECT COUNT(*) FROM external_blocks eb
+	 JOIN bookings b ON b.room_id = eb.room_id
+	  AND b.status NOT IN ('cancelled', 'no_show')
+	  AND daterange(b.check_in_date::date, b.check_out_date::date, '[)') && daterange(eb.start_date, eb.end_date, '[)')
+	 WHERE eb.calendar_id = c.id),
+	c.created_by, c.created_at`
+
+// scanExternalCalendar scans a row selected with externalCalendarColumns.
+func scanExternalCalendar(row pgx.Row) (*models.ExternalCalendar, error) {
+	var c models.ExternalCalendar
+	err := row.Scan(&c.ID, &c.PropertyID, &c.RoomID, &c.Name, &c.URL, &c.Active, &c.ETag, &c.LastModified,
+		&c.LastSyncedAt, &c.LastSuccessAt, &c.LastError, &c.BlockCount, &c.Conflicts, &c.CreatedBy, &c.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// queryExternalCalendars runs a query selecting externalCalendarColumns and collects the rows.
+func (r *ExternalCalendarRepository) queryExternalCalendars(ctx context.Context, query string, args ...interface{}) ([]models.ExternalCalendar, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list external calendars: %w", err)
+	}
+	defer rows.Close()
+	calendars := []models.ExternalCalendar{}
+	for rows.Next() {
+		c, err := scanExternalCalendar(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan external calendar: %w", err)
+		}
+		calendars = append(calendars, *c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list external calendars: %w", err)
+	}
+	return calendars, nil
+}
+
+// CreateExternalCalendar attaches a feed to the room in calendar.RoomID, under the room's property.
+// Returns an error wrapping ErrNotFound if the room does not exist, or ErrConflict if the feed
+// is already attached to the room.
+func (r *ExternalCalendarRepository) CreateExternalCalendar(ctx context.Context, calendar *models.ExternalCalendar) error {
+	err := r.db.QueryRow(ctx, `
+	INSERT INTO external_calendars (property_id, room_id, name, url, created_by)
+	SELECT property_id, id, $2, $3, $4 FROM rooms WHERE id = $1
+	RETURNING id, property_id, active, created_at
+	`, calendar.RoomID, calendar.Name, calendar.URL, calendar.CreatedBy).Scan(&calendar.ID, &calendar.PropertyID, &calendar.Active, &calendar.CreatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("room %w", ErrNotFound)
+		}
+		if hasPgCode(err, pgUniqueViolation) {
+			return fmt.Errorf("calendar is already attached to the room: %w", ErrConflict)
+		}
+		return fmt.Errorf("failed to create external calendar: %w", err)
+	}
+	return nil
+}
+
+// GetExternalCalendar returns an external calendar with its sync state.
+// Returns an error wrapping ErrNotFound if the calendar does not exist.
+func (r *ExternalCalendarRepository) GetExternalCalendar(ctx context.Context, id int) (*models.ExternalCalendar, error) {
+	calendar, err := scanExternalCalendar(r.db.QueryRow(ctx, `SELECT `+externalCalendarColumns+` FROM external_calendars c WHERE c.id = $1`, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("external calendar %w", ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get external calendar: %w", err)
+	}
+	return calendar, nil
+}
+
+// ListExternalCalendars returns the external calendars of the given properties (nil for all)
+// with their sync state, ordered by room and ID.
+func (r *ExternalCalendarRepository) ListExternalCalendars(ctx context.Context, propertyIDs []int) ([]models.ExternalCalendar, error) {
+	return r.queryExternalCalendars(ctx, `
+	SELECT `+externalCalendarColumns+`
+	FROM external_calendars c
+	WHERE ($1::int[] IS NULL OR c.property_id = ANY($1))
+	ORDER BY c.room_id, c.id
+	`, propertyIDs)
+}
+
+// DeleteExternalCalendar detaches a feed and removes its blocks, freeing their nights.
+// Returns an error wrapping ErrNotFound if the calendar does not exist.
+func (r *ExternalCalendarRepository) DeleteExternalCalendar(ctx context.Context, id int) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM external_calendars WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete external calendar: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("external calendar %w", ErrNotFound)
+	}
+	return nil
+}
+
+// ListExternalBlocks returns the blocks imported from a calendar, ordered by start.
+func (r *ExternalCalendarRepository) ListExternalBlocks(ctx context.Context, calendarID int) ([]models.ExternalBlock, error) {
+	rows, err := r.db.Query(ctx, `
+	SELECT id, calendar_id, property_id, room_id, uid, summary, start_date, end_date, updated_at
+	FROM external_blocks
+	WHERE calendar_id = $1
+	ORDER BY start_date, id
+	`, calendarID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list external blocks: %w", err)
+	}
+	defer rows.Close()
+	blocks := []models.ExternalBlock{}
+	for rows.Next() {
+		var b models.ExternalBlock
+		if err := rows.Scan(&b.ID, &b.CalendarID, &b.PropertyID, &b.RoomID, &b.UID, &b.Summary, &b.StartDate, &b.EndDate, &b.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan external block: %w", err)
+		}
+		blocks = append(blocks, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list external blocks: %w", err)
+	}
+	return blocks, nil
+}
+
+// ClaimDueExternalCalendars marks up to limit active calendars last synced before syncedBefore
+// (or never) as synced now and returns them, least recently synced first. Claimed rows are
+// skipped by concurrent callers, so every instance can run the sync job.
+func (r *ExternalCalendarRepository) ClaimDueExternalCalendars(ctx context.Context, syncedBefore time.Time, limit int) ([]models.ExternalCalendar, error) {
+	return r.queryExternalCalendars(ctx, `
+	WITH due AS (
+		SELECT id
+		FROM external_calendars
+		WHERE active AND (last_synced_at IS NULL OR last_synced_at < $1)
+		ORDER BY last_synced_at NULLS FIRST, id
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	), claimed AS (
+		UPDATE external_calendars SET last_synced_at = NOW()
+		WHERE id IN (SELECT id FROM due)
+		RETURNING id
+	)
+	SELECT `+externalCalendarColumns+`
+	FROM external_calendars c
+	WHERE c.id IN (SELECT id FROM claimed)
+	ORDER BY c.last_synced_at NULLS FIRST, c.id
+	`, syncedBefore, limit)
+}
+
+// ReplaceExternalBlocks makes blocks the complete set of blocks of a calendar in one transaction:
+// blocks are matched by UID, so existing ones are updated in place, new ones inserted and those
+// missing from blocks deleted. The calendar is marked as successfully synced with the download
+// validators etag and lastModified.
+func (r *ExternalCalendarRepository) ReplaceExternalBlocks(ctx context.Context, calendarID int, blocks []models.ExternalBlock, etag, lastModified string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	uids := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		_, err := tx.Exec(ctx, `
+		INSERT INTO external_blocks (calendar_id, property_id, room_id, uid, summary, start_date, end_date)
+		SELECT id, property_id, room_id, $2, $3, $4, $5 FROM external_calendars WHERE id = $1
+		ON CONFLICT (calendar_id, uid) DO UPDATE
+		SET summary = EXCLUDED.summary, start_date = EXCLUDED.start_date, end_date = EXCLUDED.end_date, updated_at = NOW()
+		WHERE (external_blocks.summary, external_blocks.start_date, external_blocks.end_date) IS DISTINCT FROM (EXCLUDED.summary, EXCLUDED.start_date, EXCLUDED.end_date)
+		`, calendarID, block.UID, block.Summary, block.StartDate, block.EndDate)
+		if err != nil {
+			return fmt.Errorf("failed to save external block: %w", err)
+		}
+		uids = append(uids, block.UID)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM external_blocks WHERE calendar_id = $1 AND uid <> ALL($2::text[])`, calendarID, uids); err != nil {
+		return fmt.Errorf("failed to remove external blocks: %w", err)
+	}
+	if err := markExternalCalendarSynced(ctx, tx, calendarID, etag, lastModified); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit external blocks: %w", err)
+	}
+	return nil
+}
+
+// MarkExternalCalendarSynced records a successful sync that left the blocks unchanged.
+func (r *ExternalCalendarRepository) MarkExternalCalendarSynced(ctx context.Context, calendarID int, etag, lastModified string) error {
+	return markExternalCalendarSynced(ctx, r.db, calendarID, etag, lastModified)
+}
+
+// markExternalCalendarSynced clears the error of a calendar and stores its download validators.
+func markExternalCalendarSynced(ctx context.Context, db dbExecutor, calendarID int, etag, lastModified string) error {
+	_, err := db.Exec(ctx, `
+	UPDATE external_calendars
+	SET last_synced_at = NOW(), last_success_at = NOW(), last_error = NULL, etag = NULLIF($2, ''), last_modified = NULLIF($3, '')
+	WHERE id = $1
+	`, calendarID, etag, lastModified)
+	if err != nil {
+		return fmt.Errorf("failed to record external calendar sync: %w", err)
+	}
+	return nil
+}
+
+// RecordExternalCalendarError records a failed sync. The calendar keeps its blocks, so the
+// nights stay blocked until a later sync succeeds.
+func (r *ExternalCalendarRepository) RecordExternalCalendarError(ctx context.Context, calendarID int, message string) error {
+	_, err := r.db.Exec(ctx, `UPDATE external_calendars SET last_synced_at = NOW(), last_error = $2 WHERE id = $1`, calendarID, message)
+	if err != nil {
+		return fmt.Errorf("failed to record external calendar error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"industry-api/internal/models"
+	"industry-api/internal/testsetup"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestExternalCalendarRepo_BlocksBookings(t *testing.T) {
+	_ = testsetup.LoadEnv()
+	dsn := os.Getenv("TEST_DB_URL")
+	if dsn == "" {
+		dsn = os.Getenv("DB_URL")
+	}
+	if dsn == "" {
+		t.Skip("DB_URL or TEST_DB_URL not set; skipping integration tests")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to db: %v", err)
+	}
+	defer pool.Close()
+
+	property := createTestProperty(t, ctx, pool, "X")
+	userID := createTestUser(t, ctx, pool, models.RoleGuest)
+	room := &models.Room{PropertyID: property.ID, RoomNumber: "EX-1", RoomType: "Test", Description: "External calendar test room", Price: 100, Capacity: 2, Floor: 1, Amenities: []string{"test"}}
+	if err := NewRoomRepository(pool).AddRoom(ctx, room); err != nil {
+		t.Fatalf("AddRoom failed: %v", err)
+	}
+	defer func() {
+		_, _ = pool.Exec(ctx, "DELETE FROM bookings WHERE room_id = $1", room.ID)
+		_, _ = pool.Exec(ctx, "DELETE FROM external_calendars WHERE room_id = $1", room.ID)
+		_, _ = pool.Exec(ctx, "DELETE FROM rooms WHERE id = $1", room.ID)
+	}()
+
+	repo := NewExternalCalendarRepository(pool)
+	calendar := &models.ExternalCalendar{RoomID: room.ID, Name: "Platform", URL: "https://platform.example/room.ics"}
+	if err := repo.CreateExternalCalendar(ctx, calendar); err != nil || calendar.PropertyID != property.ID {
+		t.Fatalf("CreateExternalCalendar failed: %+v err=%v", calendar, err)
+	}
+	if err := repo.CreateExternalCalendar(ctx, &models.ExternalCalendar{RoomID: room.ID, Name: "Again", URL: calendar.URL}); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected the same feed twice to conflict, got %v", err)
+	}
+
+	start := time.Now().AddDate(1, 9, 0).Truncate(24 * time.Hour)
+	blocks := []models.ExternalBlock{
+		{UID: "a", Summary: "Reserved", StartDate: start, EndDate: start.AddDate(0, 0, 3)},
+		{UID: "b", Summary: "Reserved", StartDate: start.AddDate(0, 0, 10), EndDate: start.AddDate(0, 0, 12)},
+	}
+	if err := repo.ReplaceExternalBlocks(ctx, calendar.ID, blocks, `"v1"`, ""); err != nil {
+		t.Fatalf("ReplaceExternalBlocks failed: %v", err)
+	}
+
+	bookings := NewBookingRepository(pool)
+	booking := &models.Booking{PropertyID: property.ID, UserID: userID, RoomID: room.ID, CheckInDate: start.AddDate(0, 0, 2), CheckOutDate: start.AddDate(0, 0, 4), Adults: 1, TotalAmount: 200, Status: models.BookingStatusConfirmed, PaymentStatus: models.PaymentStatusPending}
+	var conflict *BookingConflictError
+	if _, err := bookings.AddBooking(ctx, booking); !errors.As(err, &conflict) || conflict.Conflict.ExternalBlockID == nil {
+		t.Fatalf("expected the external block to conflict, got %v", err)
+	}
+
+	// The next sync drops block a, which frees its nights
+	if err := repo.ReplaceExternalBlocks(ctx, calendar.ID, blocks[1:], `"v2"`, ""); err != nil {
+		t.Fatalf("ReplaceExternalBlocks failed: %v", err)
+	}
+	if _, err := bookings.AddBooking(ctx, booking); err != nil {
+		t.Fatalf("expected the freed nights to be bookable, got %v", err)
+	}
+
+	synced, err := repo.GetExternalCalendar(ctx, calendar.ID)
+	if err != nil || synced.BlockCount != 1 || synced.ETag == nil || *synced.ETag != `"v2"` || synced.LastSuccessAt == nil || synced.LastError != nil {
+		t.Fatalf("unexpected sync state: %+v err=%v", synced, err)
+	}
+	if err := repo.RecordExternalCalendarError(ctx, calendar.ID, "status 500"); err != nil {
+		t.Fatalf("RecordExternalCalendarError failed: %v", err)
+	}
+	if failed, err := repo.GetExternalCalendar(ctx, calendar.ID); err != nil || failed.LastError == nil || failed.BlockCount != 1 {
+		t.Fatalf("expected the error to be recorded and the blocks kept, got %+v err=%v", failed, err)
+	}
+	if err := repo.DeleteExternalCalendar(ctx, calendar.ID); err != nil {
+		t.Fatalf("DeleteExternalCalendar failed: %v", err)
+	}
+	if left, err := repo.ListExternalBlocks(ctx, calendar.ID); err != nil || len(left) != 0 {
+		t.Fatalf("expected the blocks to be removed with the calendar, got %+v err=%v", left, err)
+	}
+}
@@ -0,0 +1,153 @@
+// Package repository provides database access layer implementations.
+// Repositories handle all direct database operations using SQL queries.
+package repository
+
+import (
+	"context"
+	"fmt"
+	"industry-api/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// FolioRepository provides database access for booking folios.
+type FolioRepository struct {
+	db *pgxpool.Pool // Database connection pool
+}
+
+// FolioRepo defines the methods used by services to manage booking folios.
+type FolioRepo interface {
+	PostFolioEntry(ctx context.Context, entry *models.FolioEntry) error
+	VoidFolioEntry(ctx context.Context, bookingID, entryID int, voidedBy *int, reason string) (*models.FolioEntry, error)
+	ListFolioEntries(ctx context.Context, bookingID int) ([]models.FolioEntry, error)
+	ListPaidPayments(ctx context.Context, bookingID int) ([]models.Payment, error)
+}
+
+// folioEntryColumns is the column list scanned by scanFolioEntry.
+const folioEntryColumns = `id, booking_id, kind, category, description, amount, tax_amount, posted_by, posted_at, voided_by, voided_at, void_reason`
+
+// NewFolioRepository creates and returns a new instance of FolioRepository.
+// It accepts a database connection pool for executing database operations.
+func NewFolioRepository(db *pgxpool.Pool) *FolioRepository {
+	return &FolioRepository{db: db}
+}
+
+// PostFolioEntry posts a charge or credit to the folio of a checked-in booking and fills in its
+// ID and posting time. The status is checked in the same statement, so nothing can be posted
+// once the booking has been checked out.
+// Returns an error wrapping ErrConflict if the booking is not checked in.
+func (r *FolioRepository) PostFolioEntry(ctx context.Context, entry *models.FolioEntry) error {
+	err := r.db.QueryRow(ctx, `
+	INSERT INTO folio_entries (booking_id, kind, category, description, amount, tax_amount, posted_by)
+	SELECT id, $2, $3, $4, $5, $6, $7
+	FROM bookings
+	WHERE id = $1 AND status = 'checked_in'
+	RETURNING id, posted_at
+	`, entry.BookingID, entry.Kind, entry.Category, entry.Description, entry.Amount, entry.TaxAmount, entry.PostedBy).
+		Scan(&entry.ID, &entry.PostedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("charges can only be posted to checked-in bookings: %w", ErrConflict)
+		}
+		return fmt.Errorf("failed to post folio entry: %w", err)
+	}
+	return nil
+}
+
+// VoidFolioEntry voids an entry of a booking's folio with the reason given, so it no longer
+// counts towards the balance.
+// Returns an error wrapping ErrNotFound if the booking has no such entry, or ErrConflict if it
+// was already voided.
+func (r *FolioRepository) VoidFolioEntry(ctx context.Context, bookingID, entryID int, voidedBy *int, reason string) (*models.FolioEntry, error) {
+	entry, err := scanFolioEntry(r.db.QueryRow(ctx, `
+	UPDATE folio_entries
+	SET voided_by = $3, voided_at = NOW(), void_reason = $4
+	WHERE id = $1 AND booking_id = $2 AND voided_at IS NULL
+	RETURNING `+folioEntryColumns, entryID, bookingID, voidedBy, reason))
+	if err == nil {
+		return entry, nil
+	}
+	if err != pgx.ErrNoRows {
+		return nil, fmt.Errorf("failed to void folio entry: %w", err)
+	}
+	// Tell a missing entry apart from one that was voided before
+	var exists bool
+	if err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM folio_entries WHERE id = $1 AND booking_id = $2)`, entryID, bookingID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to get folio entry: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("folio entry %w", ErrNotFound)
+	}
+	return nil, fmt.Errorf("folio entry was already voided: %w", ErrConflict)
+}
+
+// ListFolioEntries returns every entry of a booking's folio, voided ones included, in posting order.
+func (r *FolioRepository) ListFolioEntries(ctx context.Context, bookingID int) ([]models.FolioEntry, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+folioEntryColumns+` FROM folio_entries WHERE booking_id = $1 ORDER BY posted_at, id`, bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list folio entries: %w", err)
+	}
+	defer rows.Close()
+	entries := []models.FolioEntry{}
+	for rows.Next() {
+		entry, err := scanFolioEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan folio entry: %w", err)
+		}
+		entries = append(entries, *entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list folio entries: %w", err)
+	}
+	return entries, nil
+}
+
+// ListPaidPayments returns the paid payments of a booking in the order they were processed.
+func (r *FolioRepository) ListPaidPayments(ctx context.Context, bookingID int) ([]models.Payment, error) {
+	rows, err := r.db.Query(ctx, `
+	SELECT id, booking_id, amount, payment_method, status, COALESCE(processed_at, created_at), created_at
+	FROM payments
+	WHERE booking_id = $1 AND status = 'paid'
+	ORDER BY COALESCE(processed_at, created_at), id
+	`, bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payments: %w", err)
+	}
+	defer rows.Close()
+	payments := []models.Payment{}
+	for rows.Next() {
+		var payment models.Payment
+		if err := rows.Scan(&payment.ID, &payment.BookingID, &payment.Amount, &payment.PaymentMethod, &payment.Status, &payment.ProcessedAt, &payment.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan payment: %w", err)
+		}
+		payments = append(payments, payment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list payments: %w", err)
+	}
+	return payments, nil
+}
+
+// scanFolioEntry scans a row selected with folioEntryColumns.
+func scanFolioEntry(row pgx.Row) (*models.FolioEntry, error) {
+	var entry models.FolioEntry
+	err := row.Scan(
+		&entry.ID,
+		&entry.BookingID,
+		&entry.Kind,
+		&entry.Category,
+		&entry.Description,
+		&entry.Amount,
+		&entry.TaxAmount,
+		&entry.PostedBy,
+		&entry.PostedAt,
+		&entry.VoidedBy,
+		&entry.VoidedAt,
+		&entry.VoidReason,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"industry-api/internal/models"
+	"industry-api/internal/testsetup"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestFolioRepo_PostVoidAndTotal(t *testing.T) {
+	_ = testsetup.LoadEnv()
+	dsn := os.Getenv("TEST_DB_URL")
+	if dsn == "" {
+		dsn = os.Getenv("DB_URL")
+	}
+	if dsn == "" {
+		t.Skip("DB_URL or TEST_DB_URL not set; skipping integration tests")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to db: %v", err)
+	}
+	defer pool.Close()
+
+	property := createTestProperty(t, ctx, pool, "F")
+	userID := createTestUser(t, ctx, pool, models.RoleGuest)
+	room := &models.Room{PropertyID: property.ID, RoomNumber: "FO-1", RoomType: "Test", Description: "Folio test room", Price: 100, Capacity: 2, Floor: 1, Amenities: []string{"test"}}
+	if err := NewRoomRepository(pool).AddRoom(ctx, room); err != nil {
+		t.Fatalf("AddRoom failed: %v", err)
+	}
+	defer func() {
+		if _, err := pool.Exec(ctx, "DELETE FROM bookings WHERE room_id = $1", room.ID); err != nil {
+			t.Logf("warning: cleanup failed: %v", err)
+		}
+	}()
+
+	checkIn := time.Now().AddDate(1, 9, 0).Truncate(24 * time.Hour)
+	bookings := NewBookingRepository(pool)
+	booking := &models.Booking{PropertyID: property.ID, UserID: userID, RoomID: room.ID, CheckInDate: checkIn, CheckOutDate: checkIn.AddDate(0, 0, 2), Adults: 1, TotalAmount: 200, Status: models.BookingStatusConfirmed, PaymentStatus: models.PaymentStatusPending}
+	if _, err := bookings.AddBooking(ctx, booking); err != nil {
+		t.Fatalf("AddBooking failed: %v", err)
+	}
+
+	repo := NewFolioRepository(pool)
+	minibar := &models.FolioEntry{BookingID: booking.ID, Kind: models.FolioCharge, Category: "minibar", Amount: 10, TaxAmount: 2}
+	if err := repo.PostFolioEntry(ctx, minibar); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected conflict before check-in, got %v", err)
+	}
+	if _, err := pool.Exec(ctx, "UPDATE bookings SET status = 'checked_in' WHERE id = $1", booking.ID); err != nil {
+		t.Fatalf("failed to check in: %v", err)
+	}
+	parking := &models.FolioEntry{BookingID: booking.ID, Kind: models.FolioCharge, Category: "parking", Amount: 15}
+	credit := &models.FolioEntry{BookingID: booking.ID, Kind: models.FolioCredit, Category: "adjustment", Amount: 4}
+	for _, entry := range []*models.FolioEntry{minibar, parking, credit} {
+		if err := repo.PostFolioEntry(ctx, entry); err != nil {
+			t.Fatalf("PostFolioEntry failed: %v", err)
+		}
+	}
+
+	voided, err := repo.VoidFolioEntry(ctx, booking.ID, parking.ID, nil, "guest has no car")
+	if err != nil || voided.VoidedAt == nil || *voided.VoidReason != "guest has no car" {
+		t.Fatalf("unexpected voided entry: %+v err=%v", voided, err)
+	}
+	if _, err := repo.VoidFolioEntry(ctx, booking.ID, parking.ID, nil, "again"); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected conflict voiding twice, got %v", err)
+	}
+	if _, err := repo.VoidFolioEntry(ctx, booking.ID+1000000, parking.ID, nil, "wrong booking"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected not found for another booking's entry, got %v", err)
+	}
+
+	total, err := bookings.GetFolioTotal(ctx, booking.ID)
+	if err != nil || total != 8 {
+		t.Fatalf("expected a folio total of 8, got %v err=%v", total, err)
+	}
+	entries, err := repo.ListFolioEntries(ctx, booking.ID)
+	if err != nil || len(entries) != 3 {
+		t.Fatalf("expected 3 entries including the voided one, got %+v err=%v", entries, err)
+	}
+}
@@ -0,0 +1,125 @@
+// Package repository provides database access layer implementations.
+// Repositories handle all direct database operations using SQL queries.
+package repository
+
+import (
+	"context"
+	"fmt"
+	"industry-api/internal/models"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// GuestRepository provides database access for booking guest manifests.
+type GuestRepository struct {
+	db *pgxpool.Pool // Database connection pool
+}
+
+// GuestRepo defines the methods used by services for booking guest manifests.
+type GuestRepo interface {
+	ReplaceBookingGuests(ctx context.Context, bookingID int, guests []models.BookingGuest) error
+	ListBookingGuests(ctx context.Context, bookingID int) ([]models.BookingGuest, error)
+	ListGuestRegistrations(ctx context.Context, date time.Time, propertyIDs []int) ([]models.GuestRegistration, error)
+}
+
+// NewGuestRepository creates and returns a new instance of GuestRepository.
+// It accepts a database connection pool for executing database operations.
+func NewGuestRepository(db *pgxpool.Pool) *GuestRepository {
+	return &GuestRepository{db: db}
+}
+
+// ReplaceBookingGuests replaces the guest manifest of a booking in one transaction and fills in
+// the IDs and creation times of guests. Document numbers are stored as given in DocumentCipher.
+func (r *GuestRepository) ReplaceBookingGuests(ctx context.Context, bookingID int, guests []models.BookingGuest) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM booking_guests WHERE booking_id = $1`, bookingID); err != nil {
+		return fmt.Errorf("failed to clear booking guests: %w", err)
+	}
+	for i := range guests {
+		guest := &guests[i]
+		guest.BookingID = bookingID
+		err := tx.QueryRow(ctx, `
+		INSERT INTO booking_guests (booking_id, first_name, last_name, nationality, date_of_birth, is_child, document_type, document_cipher)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at
+		`, bookingID, guest.FirstName, guest.LastName, guest.Nationality, guest.DateOfBirth, guest.IsChild, guest.DocumentType, guest.DocumentCipher).
+			Scan(&guest.ID, &guest.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to add booking guest: %w", err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit booking guests: %w", err)
+	}
+	return nil
+}
+
+// ListBookingGuests returns the guest manifest of a booking, adults first, in the order recorded.
+// Document numbers are returned encrypted in DocumentCipher.
+func (r *GuestRepository) ListBookingGuests(ctx context.Context, bookingID int) ([]models.BookingGuest, error) {
+	rows, err := r.db.Query(ctx, `
+	SELECT id, booking_id, first_name, last_name, nationality, date_of_birth, is_child, document_type, document_cipher, created_at
+	FROM booking_guests
+	WHERE booking_id = $1
+	ORDER BY is_child, id
+	`, bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list booking guests: %w", err)
+	}
+	defer rows.Close()
+
+	guests := []models.BookingGuest{}
+	for rows.Next() {
+		var g models.BookingGuest
+		if err := rows.Scan(&g.ID, &g.BookingID, &g.FirstName, &g.LastName, &g.Nationality, &g.DateOfBirth, &g.IsChild, &g.DocumentType, &g.DocumentCipher, &g.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan booking guest: %w", err)
+		}
+		guests = append(guests, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list booking guests: %w", err)
+	}
+	return guests, nil
+}
+
+// ListGuestRegistrations returns every recorded guest of the confirmed, checked-in or checked-out
+// bookings staying the night of date, ordered by property, room and booking. A nil propertyIDs
+// covers every property. Document numbers are returned encrypted in DocumentCipher.
+func (r *GuestRepository) ListGuestRegistrations(ctx context.Context, date time.Time, propertyIDs []int) ([]models.GuestRegistration, error) {
+	rows, err := r.db.Query(ctx, `
+	SELECT b.id, b.property_id, r.room_number, b.check_in_date, b.check_out_date,
+	       g.first_name, g.last_name, g.nationality, g.date_of_birth, g.document_type, g.document_cipher
+	FROM booking_guests g
+	JOIN bookings b ON b.id = g.booking_id
+	JOIN rooms r ON r.id = b.room_id
+	WHERE b.status IN ('confirmed', 'checked_in', 'checked_out')
+	  AND b.check_in_date::date <= $1::date
+	  AND b.check_out_date::date > $1::date
+	  AND ($2::int[] IS NULL OR b.property_id = ANY($2::int[]))
+	ORDER BY b.property_id, r.room_number, b.id, g.is_child, g.id
+	`, date, propertyIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list guest registrations: %w", err)
+	}
+	defer rows.Close()
+
+	registrations := []models.GuestRegistration{}
+	for rows.Next() {
+		var g models.GuestRegistration
+		if err := rows.Scan(&g.BookingID, &g.PropertyID, &g.RoomNumber, &g.CheckInDate, &g.CheckOutDate,
+			&g.FirstName, &g.LastName, &g.Nationality, &g.DateOfBirth, &g.DocumentType, &g.DocumentCipher); err != nil {
+			return nil, fmt.Errorf("failed to scan guest registration: %w", err)
+		}
+		registrations = append(registrations, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list guest registrations: %w", err)
+	}
+	return registrations, nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"industry-api/internal/models"
+	"industry-api/internal/testsetup"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestGuestRepo_ReplaceAndRegistrations(t *testing.T) {
+	_ = testsetup.LoadEnv()
+	dsn := os.Getenv("TEST_DB_URL")
+	if dsn == "" {
+		dsn = os.Getenv("DB_URL")
+	}
+	if dsn == "" {
+		t.Skip("DB_URL or TEST_DB_URL not set; skipping integration tests")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to db: %v", err)
+	}
+	defer pool.Close()
+
+	property := createTestProperty(t, ctx, pool, "M")
+	userID := createTestUser(t, ctx, pool, models.RoleGuest)
+	room := &models.Room{PropertyID: property.ID, RoomNumber: "GM-1", RoomType: "Test", Description: "Guest manifest test room", Price: 10, Capacity: 2, Floor: 1, Amenities: []string{"test"}}
+	if err := NewRoomRepository(pool).AddRoom(ctx, room); err != nil {
+		t.Fatalf("AddRoom failed: %v", err)
+	}
+	checkIn := time.Now().AddDate(1, 6, 0).Truncate(24 * time.Hour)
+	booking, err := NewBookingRepository(pool).AddBooking(ctx, &models.Booking{PropertyID: property.ID, UserID: userID, RoomID: room.ID, CheckInDate: checkIn, CheckOutDate: checkIn.AddDate(0, 0, 2), Adults: 1, TotalAmount: 20, Status: models.BookingStatusConfirmed, PaymentStatus: models.PaymentStatusPaid})
+	if err != nil {
+		t.Fatalf("AddBooking failed: %v", err)
+	}
+	defer func() {
+		if _, err := pool.Exec(ctx, "DELETE FROM bookings WHERE id = $1", booking.ID); err != nil {
+			t.Logf("warning: cleanup failed: %v", err)
+		}
+		if _, err := pool.Exec(ctx, "DELETE FROM rooms WHERE id = $1", room.ID); err != nil {
+			t.Logf("warning: cleanup failed: %v", err)
+		}
+	}()
+
+	repo := NewGuestRepository(pool)
+	guest := func(first string) models.BookingGuest {
+		return models.BookingGuest{FirstName: first, LastName: "Test", Nationality: "IN", DateOfBirth: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC), DocumentType: models.DocumentPassport, DocumentCipher: []byte{1, 2, 3}}
+	}
+	if err := repo.ReplaceBookingGuests(ctx, booking.ID, []models.BookingGuest{guest("First")}); err != nil {
+		t.Fatalf("ReplaceBookingGuests failed: %v", err)
+	}
+	// replacing drops the earlier manifest
+	if err := repo.ReplaceBookingGuests(ctx, booking.ID, []models.BookingGuest{guest("Second")}); err != nil {
+		t.Fatalf("ReplaceBookingGuests failed: %v", err)
+	}
+	guests, err := repo.ListBookingGuests(ctx, booking.ID)
+	if err != nil {
+		t.Fatalf("ListBookingGuests failed: %v", err)
+	}
+	if len(guests) != 1 || guests[0].FirstName != "Second" || len(guests[0].DocumentCipher) != 3 {
+		t.Fatalf("unexpected guests: %+v", guests)
+	}
+
+	// the guest is reported for the nights of the stay but not on the departure date
+	for date, want := range map[time.Time]int{checkIn.AddDate(0, 0, 1): 1, checkIn.AddDate(0, 0, 2): 0} {
+		registrations, err := repo.ListGuestRegistrations(ctx, date, []int{property.ID})
+		if err != nil {
+			t.Fatalf("ListGuestRegistrations failed: %v", err)
+		}
+		if len(registrations) != want {
+			t.Fatalf("expected %d registration(s) on %s, got %+v", want, date.Format("2006-01-02"), registrations)
+		}
+	}
+}
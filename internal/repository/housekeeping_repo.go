@@ -0,0 +1,186 @@
+// Package repository provides database access layer implementations.
+// Repositories handle all direct database operations using SQL queries.
+package repository
+
+import (
+	"context"
+	"fmt"
+	"industry-api/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// HousekeepingRepository provides database access for room housekeeping state.
+type HousekeepingRepository struct {
+	db *pgxpool.Pool // Database connection pool
+}
+
+// HousekeepingRepo defines the methods used by services for housekeeping operations.
+type HousekeepingRepo interface {
+	GetHousekeepingRoom(ctx context.Context, roomID int) (*models.HousekeepingRoom, error)
+	SetHousekeepingStatus(ctx context.Context, roomID int, from, to string, changedBy *int, note string) (*models.HousekeepingRoom, error)
+	ListHousekeepingRooms(ctx context.Context, status string, propertyIDs []int) ([]models.HousekeepingRoom, error)
+	ListHousekeepingEvents(ctx context.Context, roomID int, limit int) ([]models.HousekeepingEvent, error)
+}
+
+// NewHousekeepingRepository creates and returns a new instance of HousekeepingRepository.
+// It accepts a database connection pool for executing database operations.
+func NewHousekeepingRepository(db *pgxpool.Pool) *HousekeepingRepository {
+	return &HousekeepingRepository{db: db}
+}
+
+// housekeepingColumns lists the room columns selected for housekeeping queries, in scan order.
+const housekeepingColumns = `id, property_id, room_number, room_type, floor, housekeeping_status, housekeeping_updated_at, housekeeping_updated_by`
+
+// GetHousekeepingRoom retrieves the housekeeping state of a room.
+// Returns an error wrapping ErrNotFound if the room does not exist.
+func (r *HousekeepingRepository) GetHousekeepingRoom(ctx context.Context, roomID int) (*models.HousekeepingRoom, error) {
+	query := `SELECT ` + housekeepingColumns + ` FROM rooms WHERE id = $1`
+	room, err := scanHousekeepingRoom(r.db.QueryRow(ctx, query, roomID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("room %w", ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get room housekeeping status: %w", err)
+	}
+	return room, nil
+}
+
+// SetHousekeepingStatus moves a room from one housekeeping status to another and records the event.
+// The update only applies if the room is still in the from status, so two staff members changing
+// the same room at once cannot both succeed; the loser gets an error wrapping ErrConflict.
+// The status change and the event are written in a single transaction.
+func (r *HousekeepingRepository) SetHousekeepingStatus(ctx context.Context, roomID int, from, to string, changedBy *int, note string) (*models.HousekeepingRoom, error) {
+	return setHousekeepingStatus(ctx, r.db, roomID, from, to, changedBy, note)
+}
+
+// ListHousekeepingRooms retrieves the housekeeping state of every room ordered by property, floor and room number.
+// If status is not empty, only rooms in that status are returned; if propertyIDs is not nil,
+// only rooms of those properties are returned.
+func (r *HousekeepingRepository) ListHousekeepingRooms(ctx context.Context, status string, propertyIDs []int) ([]models.HousekeepingRoom, error) {
+	query := `
+	SELECT ` + housekeepingColumns + `
+	FROM rooms
+	WHERE (NULLIF($1, '') IS NULL OR housekeeping_status = $1)
+	  AND ($2::int[] IS NULL OR property_id = ANY($2))
+	ORDER BY property_id, floor, room_number
+	`
+	rows, err := r.db.Query(ctx, query, status, propertyIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list housekeeping rooms: %w", err)
+	}
+	defer rows.Close()
+
+	rooms := []models.HousekeepingRoom{}
+	for rows.Next() {
+		room, err := scanHousekeepingRoom(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan housekeeping room: %w", err)
+		}
+		rooms = append(rooms, *room)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating housekeeping rooms: %w", err)
+	}
+	return rooms, nil
+}
+
+// ListHousekeepingEvents retrieves the most recent housekeeping events of a room, newest first.
+func (r *HousekeepingRepository) ListHousekeepingEvents(ctx context.Context, roomID int, limit int) ([]models.HousekeepingEvent, error) {
+	query := `
+	SELECT id, room_id, from_status, to_status, changed_by, note, created_at
+	FROM housekeeping_events
+	WHERE room_id = $1
+	ORDER BY created_at DESC, id DESC
+	LIMIT $2
+	`
+	rows, err := r.db.Query(ctx, query, roomID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list housekeeping events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []models.HousekeepingEvent{}
+	for rows.Next() {
+		var e models.HousekeepingEvent
+		if err := rows.Scan(&e.ID, &e.RoomID, &e.FromStatus, &e.ToStatus, &e.ChangedBy, &e.Note, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan housekeeping event: %w", err)
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating housekeeping events: %w", err)
+	}
+	return events, nil
+}
+
+// setHousekeepingStatus performs a conditional housekeeping status change and records the event.
+// When db is a transaction, Begin starts a savepoint so the change joins the caller's transaction.
+func setHousekeepingStatus(ctx context.Context, db dbExecutor, roomID int, from, to string, changedBy *int, note string) (*models.HousekeepingRoom, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+	UPDATE rooms
+	SET housekeeping_status = $1, housekeeping_updated_at = NOW(), housekeeping_updated_by = $2
+	WHERE id = $3 AND housekeeping_status = $4
+	RETURNING ` + housekeepingColumns
+	room, err := scanHousekeepingRoom(tx.QueryRow(ctx, query, to, changedBy, roomID, from))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("room housekeeping status changed concurrently: %w", ErrConflict)
+		}
+		return nil, fmt.Errorf("failed to update housekeeping status: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+	INSERT INTO housekeeping_events (room_id, from_status, to_status, changed_by, note)
+	VALUES ($1, $2, $3, $4, $5)
+	`, roomID, from, to, changedBy, note)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record housekeeping event: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit housekeeping status: %w", err)
+	}
+	return room, nil
+}
+
+// markRoomDirty flags a room for cleaning through setHousekeepingStatus, as part of the caller's
+// transaction. Rooms that are already dirty or out of order keep their housekeeping status.
+func markRoomDirty(ctx context.Context, db dbExecutor, roomID int, changedBy *int, note string) error {
+	var status string
+	err := db.QueryRow(ctx, `SELECT housekeeping_status FROM rooms WHERE id = $1 FOR UPDATE`, roomID).Scan(&status)
+	if err != nil {
+		return fmt.Errorf("failed to get housekeeping status: %w", err)
+	}
+	if status == models.HousekeepingDirty || status == models.HousekeepingOutOfOrder {
+		return nil
+	}
+	_, err = setHousekeepingStatus(ctx, db, roomID, status, models.HousekeepingDirty, changedBy, note)
+	return err
+}
+
+// scanHousekeepingRoom scans a row selected with housekeepingColumns.
+func scanHousekeepingRoom(row pgx.Row) (*models.HousekeepingRoom, error) {
+	var room models.HousekeepingRoom
+	err := row.Scan(
+		&room.RoomID,
+		&room.PropertyID,
+		&room.RoomNumber,
+		&room.RoomType,
+		&room.Floor,
+		&room.Status,
+		&room.UpdatedAt,
+		&room.UpdatedBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &room, nil
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"industry-api/internal/models"
+	"industry-api/internal/testsetup"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestHousekeepingRepo_SetStatus(t *testing.T) {
+	_ = testsetup.LoadEnv()
+	dsn := os.Getenv("TEST_DB_URL")
+	if dsn == "" {
+		dsn = os.Getenv("DB_URL")
+	}
+	if dsn == "" {
+		t.Skip("DB_URL or TEST_DB_URL not set; skipping integration tests")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to db: %v", err)
+	}
+	defer pool.Close()
+
+	property := createTestProperty(t, ctx, pool, "H")
+	room := &models.Room{
+		PropertyID:  property.ID,
+		RoomNumber:  "HK-" + time.Now().Format("150405"),
+		RoomType:    "Test",
+		Description: "Housekeeping integration test room",
+		Price:       10,
+		Capacity:    1,
+		Floor:       1,
+		Amenities:   []string{"test"},
+	}
+	if err := NewRoomRepository(pool).AddRoom(ctx, room); err != nil {
+		t.Fatalf("AddRoom failed: %v", err)
+	}
+	defer func() {
+		if _, err := pool.Exec(ctx, "DELETE FROM rooms WHERE id = $1", room.ID); err != nil {
+			t.Logf("warning: cleanup failed: %v", err)
+		}
+	}()
+
+	repo := NewHousekeepingRepository(pool)
+	updated, err := repo.SetHousekeepingStatus(ctx, room.ID, models.HousekeepingInspected, models.HousekeepingDirty, nil, "check-out")
+	if err != nil {
+		t.Fatalf("SetHousekeepingStatus failed: %v", err)
+	}
+	if updated.Status != models.HousekeepingDirty {
+		t.Fatalf("expected dirty, got %s", updated.Status)
+	}
+
+	// A second change from the stale status must lose
+	if _, err := repo.SetHousekeepingStatus(ctx, room.ID, models.HousekeepingInspected, models.HousekeepingDirty, nil, ""); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict for stale status, got %v", err)
+	}
+
+	events, err := repo.ListHousekeepingEvents(ctx, room.ID, 10)
+	if err != nil {
+		t.Fatalf("ListHousekeepingEvents failed: %v", err)
+	}
+	if len(events) != 1 || events[0].Note != "check-out" {
+		t.Fatalf("expected one recorded event, got %+v", events)
+	}
+
+	// Check-out leaves dirty and out of order rooms alone and flags other rooms with an event
+	if err := markRoomDirty(ctx, pool, room.ID, nil, "guest checked out"); err != nil {
+		t.Fatalf("markRoomDirty failed: %v", err)
+	}
+	if _, err := repo.SetHousekeepingStatus(ctx, room.ID, models.HousekeepingDirty, models.HousekeepingOutOfOrder, nil, ""); err != nil {
+		t.Fatalf("SetHousekeepingStatus failed: %v", err)
+	}
+	if err := markRoomDirty(ctx, pool, room.ID, nil, "guest checked out"); err != nil {
+		t.Fatalf("markRoomDirty failed: %v", err)
+	}
+	if _, err := repo.SetHousekeepingStatus(ctx, room.ID, models.HousekeepingOutOfOrder, models.HousekeepingClean, nil, ""); err != nil {
+		t.Fatalf("SetHousekeepingStatus failed: %v", err)
+	}
+	if err := markRoomDirty(ctx, pool, room.ID, nil, "guest checked out"); err != nil {
+		t.Fatalf("markRoomDirty failed: %v", err)
+	}
+	events, err = repo.ListHousekeepingEvents(ctx, room.ID, 10)
+	if err != nil {
+		t.Fatalf("ListHousekeepingEvents failed: %v", err)
+	}
+	if len(events) != 4 || events[0].ToStatus != models.HousekeepingDirty || events[0].Note != "guest checked out" {
+		t.Fatalf("expected the clean room to be flagged dirty once, got %+v", events)
+	}
+}
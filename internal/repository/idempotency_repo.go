@@ -0,0 +1,111 @@
+// Package repository provides database access layer implementations.
+// Repositories handle all direct database operations using SQL queries.
+package repository
+
+import (
+	"context"
+	"fmt"
+	"industry-api/internal/models"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// IdempotencyRepository provides database access for stored idempotent responses.
+type IdempotencyRepository struct {
+	db *pgxpool.Pool // Database connection pool
+}
+
+// IdempotencyRepo defines the methods used by services for idempotency keys.
+type IdempotencyRepo interface {
+	ClaimIdempotencyKey(ctx context.Context, record *models.IdempotencyRecord, staleBefore, expiredBefore time.Time) (*models.IdempotencyRecord, error)
+	CompleteIdempotencyKey(ctx context.Context, caller, key string, statusCode int, body []byte) error
+	ReleaseIdempotencyKey(ctx context.Context, caller, key string) error
+	DeleteIdempotencyKeys(ctx context.Context, before time.Time) (int, error)
+}
+
+// NewIdempotencyRepository creates and returns a new instance of IdempotencyRepository.
+// It accepts a database connection pool for executing database operations.
+func NewIdempotencyRepository(db *pgxpool.Pool) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// ClaimIdempotencyKey marks record's key as in flight for its caller, first used at record.CreatedAt.
+// A key that is still in flight since before staleBefore, or that completed before expiredBefore,
+// is taken over as if it had never been used.
+// Returns nil when the key was claimed, otherwise the record already stored for the key.
+func (r *IdempotencyRepository) ClaimIdempotencyKey(ctx context.Context, record *models.IdempotencyRecord, staleBefore, expiredBefore time.Time) (*models.IdempotencyRecord, error) {
+	// The key may be released between the two statements, so claiming is retried once
+	for attempt := 0; attempt < 2; attempt++ {
+		tag, err := r.db.Exec(ctx, `
+		INSERT INTO idempotency_keys (caller, key, request_hash, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (caller, key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash, status_code = NULL, response_body = NULL,
+		    created_at = EXCLUDED.created_at, completed_at = NULL
+		WHERE (idempotency_keys.status_code IS NULL AND idempotency_keys.created_at < $5)
+		   OR (idempotency_keys.status_code IS NOT NULL AND idempotency_keys.created_at < $6)
+		`, record.Caller, record.Key, record.RequestHash, record.CreatedAt, staleBefore, expiredBefore)
+		if err != nil {
+			return nil, fmt.Errorf("failed to claim idempotency key: %w", err)
+		}
+		if tag.RowsAffected() > 0 {
+			return nil, nil
+		}
+
+		existing := &models.IdempotencyRecord{Caller: record.Caller, Key: record.Key}
+		err = r.db.QueryRow(ctx, `
+		SELECT request_hash, status_code, response_body, created_at, completed_at
+		FROM idempotency_keys
+		WHERE caller = $1 AND key = $2
+		`, record.Caller, record.Key).Scan(&existing.RequestHash, &existing.StatusCode, &existing.ResponseBody, &existing.CreatedAt, &existing.CompletedAt)
+		if err == nil {
+			return existing, nil
+		}
+		if err != pgx.ErrNoRows {
+			return nil, fmt.Errorf("failed to fetch idempotency key: %w", err)
+		}
+	}
+	return nil, fmt.Errorf("idempotency key is being released: %w", ErrConflict)
+}
+
+// CompleteIdempotencyKey stores the response of the request that claimed a key.
+// Returns an error wrapping ErrNotFound if the key is no longer claimed.
+func (r *IdempotencyRepository) CompleteIdempotencyKey(ctx context.Context, caller, key string, statusCode int, body []byte) error {
+	tag, err := r.db.Exec(ctx, `
+	UPDATE idempotency_keys
+	SET status_code = $3, response_body = $4, completed_at = NOW()
+	WHERE caller = $1 AND key = $2 AND status_code IS NULL
+	`, caller, key, statusCode, body)
+	if err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("idempotency key %w", ErrNotFound)
+	}
+	return nil
+}
+
+// ReleaseIdempotencyKey forgets an in-flight key so the request can be retried.
+// Keys that already hold a response are left unchanged.
+func (r *IdempotencyRepository) ReleaseIdempotencyKey(ctx context.Context, caller, key string) error {
+	_, err := r.db.Exec(ctx, `
+	DELETE FROM idempotency_keys
+	WHERE caller = $1 AND key = $2 AND status_code IS NULL
+	`, caller, key)
+	if err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// DeleteIdempotencyKeys removes keys first used before the given time.
+// Returns the number of keys removed.
+func (r *IdempotencyRepository) DeleteIdempotencyKeys(ctx context.Context, before time.Time) (int, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM idempotency_keys WHERE created_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete idempotency keys: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
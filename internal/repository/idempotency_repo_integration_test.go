@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"industry-api/internal/models"
+	"industry-api/internal/testsetup"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestIdempotencyRepo_ClaimCompleteAndReplay(t *testing.T) {
+	_ = testsetup.LoadEnv()
+	dsn := os.Getenv("TEST_DB_URL")
+	if dsn == "" {
+		dsn = os.Getenv("DB_URL")
+	}
+	if dsn == "" {
+		t.Skip("DB_URL or TEST_DB_URL not set; skipping integration tests")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to db: %v", err)
+	}
+	defer pool.Close()
+
+	repo := NewIdempotencyRepository(pool)
+	caller := "test:" + time.Now().Format("150405.000000")
+	defer func() {
+		if _, err := pool.Exec(ctx, "DELETE FROM idempotency_keys WHERE caller = $1", caller); err != nil {
+			t.Logf("warning: cleanup failed: %v", err)
+		}
+	}()
+
+	now := time.Now()
+	claim := func(at time.Time) (*models.IdempotencyRecord, error) {
+		record := &models.IdempotencyRecord{Caller: caller, Key: "k1", RequestHash: "hash", CreatedAt: at}
+		return repo.ClaimIdempotencyKey(ctx, record, at.Add(-time.Minute), at.Add(-24*time.Hour))
+	}
+
+	if existing, err := claim(now); err != nil || existing != nil {
+		t.Fatalf("expected the first claim to succeed, got %+v %v", existing, err)
+	}
+	existing, err := claim(now)
+	if err != nil || existing == nil || existing.StatusCode != nil {
+		t.Fatalf("expected an in-flight record, got %+v %v", existing, err)
+	}
+
+	if err := repo.CompleteIdempotencyKey(ctx, caller, "k1", 201, []byte(`{"id":1}`)); err != nil {
+		t.Fatalf("CompleteIdempotencyKey failed: %v", err)
+	}
+	existing, err = claim(now)
+	if err != nil || existing == nil || existing.StatusCode == nil || *existing.StatusCode != 201 || string(existing.ResponseBody) != `{"id":1}` {
+		t.Fatalf("expected the stored response, got %+v %v", existing, err)
+	}
+
+	// a day later the key may be used again
+	if existing, err := claim(now.Add(25 * time.Hour)); err != nil || existing != nil {
+		t.Fatalf("expected an expired key to be claimed again, got %+v %v", existing, err)
+	}
+}
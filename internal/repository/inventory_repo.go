@@ -0,0 +1,117 @@
+// Package repository provides database access layer implementations.
+// Repositories handle all direct database operations using SQL queries.
+package repository
+
+import (
+	"context"
+	"fmt"
+	"industry-api/internal/models"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// InventoryRepository provides read access to room inventory across rooms, bookings and maintenance.
+type InventoryRepository struct {
+	db *pgxpool.Pool // Database connection pool
+}
+
+// InventoryRepo defines the methods used by services for inventory queries.
+type InventoryRepo interface {
+	GetCalendarCells(ctx context.Context, filter models.InventoryFilter) ([]models.InventoryCell, error)
+}
+
+// NewInventoryRepository creates and returns a new instance of InventoryRepository.
+// It accepts a database connection pool for executing database operations.
+func NewInventoryRepository(db *pgxpool.Pool) *InventoryRepository {
+	return &InventoryRepository{db: db}
+}
+
+// GetCalendarCells returns one cell per room and night in the requested range.
+// The grid is produced by a single query: the matching rooms are cross joined with a
+// generated series of nights, and each cell looks up the booking, maintenance window and
+// external block covering that night. A booking takes precedence over maintenance, which takes
+// precedence over a room that has been taken off sale, is out of order or is reserved on an
+// external platform.
+// Only rooms of the properties in filter.PropertyIDs are included unless it is nil.
+// Cells are ordered by property, floor, room number and date.
+func (r *InventoryRepository) GetCalendarCells(ctx context.Context, filter models.InventoryFilter) ([]models.InventoryCell, error) {
+	query := `
+	WITH nights AS (
+		SELECT d::date AS night
+		FROM generate_series($1::date, $2::date, interval '1 day') AS d
+	),
+	selected_rooms AS (
+		SELECT id, property_id, room_number, room_type, floor, is_available, housekeeping_status
+		FROM rooms
+		WHERE ($3::int IS NULL OR floor = $3)
+		  AND (NULLIF($4, '') IS NULL OR room_type = $4)
+		  AND ($5::int[] IS NULL OR property_id = ANY($5))
+	)
+	SELECT sr.id, sr.property_id, sr.room_number, sr.room_type, sr.floor, n.night,
+		CASE
+			WHEN b.id IS NOT NULL THEN 'booked'
+			WHEN m.id IS NOT NULL THEN 'maintenance'
+			WHEN NOT sr.is_available OR sr.housekeeping_status = 'out_of_order' OR x.id IS NOT NULL THEN 'blocked'
+			ELSE 'free'
+		END AS status,
+		b.id
+	FROM selected_rooms sr
+	CROSS JOIN nights n
+	LEFT JOIN LATERAL (
+		SELECT bk.id
+		FROM bookings bk
+		WHERE bk.room_id = sr.id
+		  AND bk.status NOT IN ('cancelled', 'no_show')
+		  AND bk.check_in_date::date <= n.night
+		  AND bk.check_out_date::date > n.night
+		ORDER BY bk.id
+		LIMIT 1
+	) b ON TRUE
+	LEFT JOIN LATERAL (
+		SELECT rm.id
+		FROM room_maintenance rm
+		WHERE rm.room_id = sr.id
+		  AND rm.status NOT IN ('completed', 'cancelled')
+		  AND rm.start_date::date <= n.night
+		  AND rm.end_date::date >= n.night
+		LIMIT 1
+	) m ON TRUE
+	LEFT JOIN LATERAL (
+		SELECT eb.id
+		FROM external_blocks eb
+		WHERE eb.room_id = sr.id
+		  AND eb.start_date <= n.night
+		  AND eb.end_date > n.night
+		LIMIT 1
+	) x ON TRUE
+	ORDER BY sr.property_id, sr.floor, sr.room_number, n.night
+	`
+	rows, err := r.db.Query(ctx, query, filter.From, filter.To, filter.Floor, filter.RoomType, filter.PropertyIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query inventory calendar: %w", err)
+	}
+	defer rows.Close()
+
+	var cells []models.InventoryCell
+	for rows.Next() {
+		var cell models.InventoryCell
+		err := rows.Scan(
+			&cell.RoomID,
+			&cell.PropertyID,
+			&cell.RoomNumber,
+			&cell.RoomType,
+			&cell.Floor,
+			&cell.Date,
+			&cell.Status,
+			&cell.BookingID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan inventory cell: %w", err)
+		}
+		cells = append(cells, cell)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating inventory cells: %w", err)
+	}
+	return cells, nil
+}
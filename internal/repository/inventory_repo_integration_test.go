@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"industry-api/internal/models"
+	"industry-api/internal/testsetup"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestInventoryRepo_CalendarCells(t *testing.T) {
+	_ = testsetup.LoadEnv()
+	dsn := os.Getenv("TEST_DB_URL")
+	if dsn == "" {
+		dsn = os.Getenv("DB_URL")
+	}
+	if dsn == "" {
+		t.Skip("DB_URL or TEST_DB_URL not set; skipping integration tests")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to db: %v", err)
+	}
+	defer pool.Close()
+
+	roomRepo := NewRoomRepository(pool)
+	repo := NewInventoryRepository(pool)
+
+	// create a room on a floor no other data uses so the grid is predictable
+	roomNum := "INV-" + time.Now().Format("150405")
+	property := createTestProperty(t, ctx, pool, "I")
+	room := &models.Room{
+		PropertyID:  property.ID,
+		RoomNumber:  roomNum,
+		RoomType:    "InventoryTest",
+		Description: "Inventory integration test room",
+		Price:       10,
+		Capacity:    1,
+		Floor:       999,
+		Amenities:   []string{"test"},
+	}
+	if err := roomRepo.AddRoom(ctx, room); err != nil {
+		t.Fatalf("AddRoom failed: %v", err)
+	}
+	defer func() {
+		if _, err := pool.Exec(ctx, "DELETE FROM rooms WHERE id = $1", room.ID); err != nil {
+			t.Logf("warning: cleanup failed: %v", err)
+		}
+	}()
+
+	from := time.Now().AddDate(1, 0, 0).Truncate(24 * time.Hour)
+	floor := 999
+	cells, err := repo.GetCalendarCells(ctx, models.InventoryFilter{From: from, To: from.AddDate(0, 0, 2), Floor: &floor, RoomType: "InventoryTest", PropertyIDs: []int{property.ID}})
+	if err != nil {
+		t.Fatalf("GetCalendarCells failed: %v", err)
+	}
+	if len(cells) != 3 {
+		t.Fatalf("expected 3 cells for one room over 3 nights, got %d", len(cells))
+	}
+	for _, cell := range cells {
+		if cell.Status != models.CellStatusFree {
+			t.Fatalf("expected free cell, got %+v", cell)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+// Package repository provides database access layer implementations.
+// Repositories handle all direct database operations using SQL queries.
+package repository
+
+import (
+	"context"
+	"fmt"
+	"industry-api/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// InvoiceRepository provides database access for invoices and credit notes.
+type InvoiceRepository struct {
+	db *pgxpool.Pool // Database connection pool
+}
+
+// InvoiceRepo defines the methods used by services to issue and read invoices.
+type InvoiceRepo interface {
+	IssueInvoice(ctx context.Context, invoice *models.Invoice) error
+	GetInvoice(ctx context.Context, id int) (*models.Invoice, error)
+	ListBookingInvoices(ctx context.Context, bookingID int) ([]models.Invoice, error)
+}
+
+// invoiceColumns is the column list scanned by scanInvoice; credit_note_id is derived from the
+// credit note correcting the invoice.
+const invoiceColumns = `id, property_id, booking_id, kind, number, invoice_number, corrects_invoice_id,
+	(SELECT c.id FROM invoices c WHERE c.corrects_invoice_id = invoices.id) AS credit_note_id,
+	reason, property_name, property_address, bill_to_name, bill_to_address, bill_to_tax_id, stay_from, stay_to,
+	lines, taxes, subtotal, tax_total, included_tax, total, amount_paid, balance_due, issued_by, issued_at`
+
+// invoicePrefixes are the number prefixes of each document kind.
+var invoicePrefixes = map[string]string{
+	models.InvoiceKindInvoice:    "INV",
+	models.InvoiceKindCreditNote: "CN",
+}
+
+// NewInvoiceRepository creates and returns a new instance of InvoiceRepository.
+// It accepts a database connection pool for executing database operations.
+func NewInvoiceRepository(db *pgxpool.Pool) *InvoiceRepository {
+	return &InvoiceRepository{db: db}
+}
+
+// IssueInvoice stores an invoice or credit note for its booking under the next number of the
+// booking's property and fills in its number, issuer details and ID. The booking row is locked,
+// so documents of one booking are issued one at a time, and the number is taken from a counter
+// row held until commit, so numbers are gap-free even when issuing fails. The guest is billed
+// when no name is given.
+// Returns an error wrapping ErrConflict if an invoice of the booking has not been credited, or
+// if the invoice a credit note corrects was already credited.
+func (r *InvoiceRepository) IssueInvoice(ctx context.Context, invoice *models.Invoice) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var propertyCode, guestName string
+	err = tx.QueryRow(ctx, `
+	SELECT b.property_id, p.code, p.name, p.address, u.name
+	FROM bookings b
+	JOIN properties p ON p.id = b.property_id
+	JOIN users u ON u.id = b.user_id
+	WHERE b.id = $1
+	FOR UPDATE OF b
+	`, invoice.BookingID).Scan(&invoice.PropertyID, &propertyCode, &invoice.PropertyName, &invoice.PropertyAddress, &guestName)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("booking %w", ErrNotFound)
+		}
+		return fmt.Errorf("failed to lock booking: %w", err)
+	}
+	if invoice.BillToName == "" {
+		invoice.BillToName = guestName
+	}
+
+	if invoice.Kind == models.InvoiceKindInvoice {
+		var open bool
+		if err := tx.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM invoices i
+			WHERE i.booking_id = $1 AND i.kind = 'invoice'
+			  AND NOT EXISTS (SELECT 1 FROM invoices c WHERE c.corrects_invoice_id = i.id)
+		)`, invoice.BookingID).Scan(&open); err != nil {
+			return fmt.Errorf("failed to check booking invoices: %w", err)
+		}
+		if open {
+			return fmt.Errorf("booking already has an invoice; credit it before issuing another: %w", ErrConflict)
+		}
+	}
+
+	err = tx.QueryRow(ctx, `
+	INSERT INTO invoice_sequences (property_id, kind, last_number)
+	VALUES ($1, $2, 1)
+	ON CONFLICT (property_id, kind) DO UPDATE SET last_number = invoice_sequences.last_number + 1
+	RETURNING last_number
+	`, invoice.PropertyID, invoice.Kind).Scan(&invoice.Number)
+	if err != nil {
+		return fmt.Errorf("failed to allocate invoice number: %w", err)
+	}
+	invoice.InvoiceNumber = fmt.Sprintf("%s-%s-%06d", invoicePrefixes[invoice.Kind], propertyCode, invoice.Number)
+
+	err = tx.QueryRow(ctx, `
+	INSERT INTO invoices (property_id, booking_id, kind, number, invoice_number, corrects_invoice_id, reason, property_name, property_address,
+	    bill_to_name, bill_to_address, bill_to_tax_id, stay_from, stay_to, lines, taxes, subtotal, tax_total, included_tax, total,
+	    amount_paid, balance_due, issued_by)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23)
+	RETURNING id, issued_at
+	`, invoice.PropertyID, invoice.BookingID, invoice.Kind, invoice.Number, invoice.InvoiceNumber, invoice.CorrectsInvoiceID, invoice.Reason,
+		invoice.PropertyName, invoice.PropertyAddress, invoice.BillToName, invoice.BillToAddress, invoice.BillToTaxID, invoice.StayFrom, invoice.StayTo,
+		invoice.Lines, invoice.Taxes, invoice.Subtotal, invoice.TaxTotal, invoice.IncludedTax, invoice.Total, invoice.AmountPaid, invoice.BalanceDue,
+		invoice.IssuedBy).Scan(&invoice.ID, &invoice.IssuedAt)
+	if err != nil {
+		if hasPgCode(err, pgUniqueViolation) {
+			return fmt.Errorf("invoice was already credited: %w", ErrConflict)
+		}
+		return fmt.Errorf("failed to issue invoice: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit invoice: %w", err)
+	}
+	return nil
+}
+
+// GetInvoice returns an invoice or credit note by its ID.
+// Returns an error wrapping ErrNotFound if it does not exist.
+func (r *InvoiceRepository) GetInvoice(ctx context.Context, id int) (*models.Invoice, error) {
+	invoice, err := scanInvoice(r.db.QueryRow(ctx, `SELECT `+invoiceColumns+` FROM invoices WHERE id = $1`, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("invoice %w", ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get invoice: %w", err)
+	}
+	return invoice, nil
+}
+
+// ListBookingInvoices returns the invoices and credit notes of a booking in the order they were issued.
+func (r *InvoiceRepository) ListBookingInvoices(ctx context.Context, bookingID int) ([]models.Invoice, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+invoiceColumns+` FROM invoices WHERE booking_id = $1 ORDER BY id`, bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invoices: %w", err)
+	}
+	defer rows.Close()
+	invoices := []models.Invoice{}
+	for rows.Next() {
+		invoice, err := scanInvoice(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan invoice: %w", err)
+		}
+		invoices = append(invoices, *invoice)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list invoices: %w", err)
+	}
+	return invoices, nil
+}
+
+// scanInvoice scans a row selected with invoiceColumns.
+func scanInvoice(row pgx.Row) (*models.Invoice, error) {
+	var invoice models.Invoice
+	err := row.Scan(
+		&invoice.ID,
+		&invoice.PropertyID,
+		&invoice.BookingID,
+		&invoice.Kind,
+		&invoice.Number,
+		&invoice.InvoiceNumber,
+		&invoice.CorrectsInvoiceID,
+		&invoice.CreditNoteID,
+		&invoice.Reason,
+		&invoice.PropertyName,
+		&invoice.PropertyAddress,
+		&invoice.BillToName,
+		&invoice.BillToAddress,
+		&invoice.BillToTaxID,
+		&invoice.StayFrom,
+		&invoice.StayTo,
+		&invoice.Lines,
+		&invoice.Taxes,
+		&invoice.Subtotal,
+		&invoice.TaxTotal,
+		&invoice.IncludedTax,
+		&invoice.Total,
+		&invoice.AmountPaid,
+		&invoice.BalanceDue,
+		&invoice.IssuedBy,
+		&invoice.IssuedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
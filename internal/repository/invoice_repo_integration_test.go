@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"industry-api/internal/models"
+	"industry-api/internal/testsetup"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestInvoiceRepo_SequentialNumbersAndImmutability(t *testing.T) {
+	_ = testsetup.LoadEnv()
+	dsn := os.Getenv("TEST_DB_URL")
+	if dsn == "" {
+		dsn = os.Getenv("DB_URL")
+	}
+	if dsn == "" {
+		t.Skip("DB_URL or TEST_DB_URL not set; skipping integration tests")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to db: %v", err)
+	}
+	defer pool.Close()
+
+	// Issued invoices cannot be deleted, so the test data is left behind under a fresh property
+	property := createTestProperty(t, ctx, pool, "I")
+	userID := createTestUser(t, ctx, pool, models.RoleGuest)
+	room := &models.Room{PropertyID: property.ID, RoomNumber: "IN-1", RoomType: "Test", Description: "Invoice test room", Price: 100, Capacity: 2, Floor: 1, Amenities: []string{"test"}}
+	if err := NewRoomRepository(pool).AddRoom(ctx, room); err != nil {
+		t.Fatalf("AddRoom failed: %v", err)
+	}
+	checkIn := time.Now().AddDate(1, 10, 0).Truncate(24 * time.Hour)
+	booking := &models.Booking{PropertyID: property.ID, UserID: userID, RoomID: room.ID, CheckInDate: checkIn, CheckOutDate: checkIn.AddDate(0, 0, 1), Adults: 1, TotalAmount: 100, Status: models.BookingStatusConfirmed, PaymentStatus: models.PaymentStatusPending}
+	if _, err := NewBookingRepository(pool).AddBooking(ctx, booking); err != nil {
+		t.Fatalf("AddBooking failed: %v", err)
+	}
+
+	repo := NewInvoiceRepository(pool)
+	newInvoice := func() *models.Invoice {
+		return &models.Invoice{BookingID: booking.ID, Kind: models.InvoiceKindInvoice, StayFrom: checkIn, StayTo: checkIn.AddDate(0, 0, 1),
+			Lines: []models.InvoiceLine{{Description: "Room", Quantity: 1, UnitPrice: 100, Amount: 100}}, Taxes: []models.InvoiceTax{}, Subtotal: 100, Total: 100, BalanceDue: 100}
+	}
+	first := newInvoice()
+	if err := repo.IssueInvoice(ctx, first); err != nil {
+		t.Fatalf("IssueInvoice failed: %v", err)
+	}
+	if first.Number != 1 || first.InvoiceNumber != "INV-"+property.Code+"-000001" || first.BillToName == "" {
+		t.Fatalf("unexpected first invoice: %+v", first)
+	}
+	if err := repo.IssueInvoice(ctx, newInvoice()); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected conflict for a second open invoice, got %v", err)
+	}
+
+	note := &models.Invoice{BookingID: booking.ID, Kind: models.InvoiceKindCreditNote, CorrectsInvoiceID: &first.ID, Reason: "wrong name", StayFrom: first.StayFrom, StayTo: first.StayTo,
+		Lines: []models.InvoiceLine{}, Taxes: []models.InvoiceTax{}, Subtotal: -100, Total: -100, BalanceDue: -100}
+	if err := repo.IssueInvoice(ctx, note); err != nil || note.InvoiceNumber != "CN-"+property.Code+"-000001" {
+		t.Fatalf("unexpected credit note %+v err=%v", note, err)
+	}
+
+	// The failed attempt above did not use up a number
+	second := newInvoice()
+	if err := repo.IssueInvoice(ctx, second); err != nil || second.Number != 2 {
+		t.Fatalf("expected the next invoice to be number 2, got %+v err=%v", second, err)
+	}
+	credited, err := repo.GetInvoice(ctx, first.ID)
+	if err != nil || credited.CreditNoteID == nil || *credited.CreditNoteID != note.ID || len(credited.Lines) != 1 {
+		t.Fatalf("unexpected credited invoice: %+v err=%v", credited, err)
+	}
+
+	if _, err := pool.Exec(ctx, "UPDATE invoices SET total = 0 WHERE id = $1", first.ID); err == nil {
+		t.Fatalf("expected issued invoices to be immutable")
+	}
+	invoices, err := repo.ListBookingInvoices(ctx, booking.ID)
+	if err != nil || len(invoices) != 3 {
+		t.Fatalf("expected 3 documents for the booking, got %d err=%v", len(invoices), err)
+	}
+}
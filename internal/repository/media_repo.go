@@ -0,0 +1,220 @@
+// Package repository provides database access layer implementations.
+// Repositories handle all direct database operations using SQL queries.
+package repository
+
+import (
+	"context"
+	"fmt"
+	"industry-api/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// MediaRepository provides database access for room and room type photos.
+type MediaRepository struct {
+	db *pgxpool.Pool // Database connection pool
+}
+
+// MediaRepo defines the methods used by services for photo metadata.
+type MediaRepo interface {
+	AddMedia(ctx context.Context, media *models.RoomMedia) error
+	GetMediaByID(ctx context.Context, id int) (*models.RoomMedia, error)
+	ListMedia(ctx context.Context, owner models.MediaOwner) ([]models.RoomMedia, error)
+	ListMediaForRooms(ctx context.Context, roomIDs []int, roomTypes []string) ([]models.RoomMedia, error)
+	UpdateMediaCaption(ctx context.Context, id int, caption string) (*models.RoomMedia, error)
+	ReorderMedia(ctx context.Context, owner models.MediaOwner, ids []int) error
+	DeleteMedia(ctx context.Context, id int) error
+	GetRoomPropertyID(ctx context.Context, roomID int) (int, error)
+}
+
+// NewMediaRepository creates and returns a new instance of MediaRepository.
+// It accepts a database connection pool for executing database operations.
+func NewMediaRepository(db *pgxpool.Pool) *MediaRepository {
+	return &MediaRepository{db: db}
+}
+
+// mediaColumns lists the columns selected for every room_media query, in scan order.
+const mediaColumns = `id, room_id, room_type, storage_key, thumbnail_key, content_type, size_bytes, caption, position, created_at, updated_at`
+
+// ownerCondition returns the WHERE clause and argument that select the photos of owner.
+func ownerCondition(owner models.MediaOwner) (string, interface{}) {
+	if owner.RoomID != nil {
+		return "room_id = $1", *owner.RoomID
+	}
+	return "room_type = $1", owner.RoomType
+}
+
+// AddMedia inserts a new photo and appends it to the end of its owner's display order.
+// Returns nil on success with ID, position and timestamps populated, or an error if the insert fails.
+func (r *MediaRepository) AddMedia(ctx context.Context, media *models.RoomMedia) error {
+	query := `
+	INSERT INTO room_media (room_id, room_type, storage_key, thumbnail_key, content_type, size_bytes, caption, position)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, (
+		SELECT COALESCE(MAX(position) + 1, 0)
+		FROM room_media
+		WHERE room_id IS NOT DISTINCT FROM $1 AND room_type IS NOT DISTINCT FROM $2
+	))
+	RETURNING id, position, created_at, updated_at
+	`
+	err := r.db.QueryRow(ctx, query,
+		media.RoomID,
+		media.RoomType,
+		media.StorageKey,
+		media.ThumbnailKey,
+		media.ContentType,
+		media.SizeBytes,
+		media.Caption,
+	).Scan(&media.ID, &media.Position, &media.CreatedAt, &media.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add media: %w", err)
+	}
+	return nil
+}
+
+// GetMediaByID retrieves a single photo by its ID.
+// Returns an error with message "media not found" if no photo has the given ID.
+func (r *MediaRepository) GetMediaByID(ctx context.Context, id int) (*models.RoomMedia, error) {
+	query := `SELECT ` + mediaColumns + ` FROM room_media WHERE id = $1`
+	rows, err := r.db.Query(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get media: %w", err)
+	}
+	media, err := scanMedia(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(media) == 0 {
+		return nil, fmt.Errorf("media %w", ErrNotFound)
+	}
+	return &media[0], nil
+}
+
+// ListMedia retrieves the photos of a room or room type in display order.
+func (r *MediaRepository) ListMedia(ctx context.Context, owner models.MediaOwner) ([]models.RoomMedia, error) {
+	cond, arg := ownerCondition(owner)
+	query := `SELECT ` + mediaColumns + ` FROM room_media WHERE ` + cond + ` ORDER BY position, id`
+	rows, err := r.db.Query(ctx, query, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list media: %w", err)
+	}
+	return scanMedia(rows)
+}
+
+// ListMediaForRooms retrieves, in a single query, the photos of the given rooms and room types.
+// Results are ordered by owner and display position so callers can group them in one pass.
+func (r *MediaRepository) ListMediaForRooms(ctx context.Context, roomIDs []int, roomTypes []string) ([]models.RoomMedia, error) {
+	query := `
+	SELECT ` + mediaColumns + `
+	FROM room_media
+	WHERE room_id = ANY($1::int[]) OR room_type = ANY($2::text[])
+	ORDER BY room_id NULLS LAST, room_type, position, id
+	`
+	rows, err := r.db.Query(ctx, query, roomIDs, roomTypes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list media: %w", err)
+	}
+	return scanMedia(rows)
+}
+
+// UpdateMediaCaption changes the caption of a photo.
+// Returns the updated photo or an error with message "media not found" if it does not exist.
+func (r *MediaRepository) UpdateMediaCaption(ctx context.Context, id int, caption string) (*models.RoomMedia, error) {
+	query := `UPDATE room_media SET caption = $1, updated_at = NOW() WHERE id = $2 RETURNING ` + mediaColumns
+	rows, err := r.db.Query(ctx, query, caption, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update media: %w", err)
+	}
+	media, err := scanMedia(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(media) == 0 {
+		return nil, fmt.Errorf("media %w", ErrNotFound)
+	}
+	return &media[0], nil
+}
+
+// ReorderMedia sets the display order of an owner's photos to the order of ids.
+// All positions are updated in one statement inside a transaction; the update is rolled back
+// if any ID does not belong to the owner.
+func (r *MediaRepository) ReorderMedia(ctx context.Context, owner models.MediaOwner, ids []int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	cond, arg := ownerCondition(owner)
+	query := `
+	UPDATE room_media m
+	SET position = o.pos - 1, updated_at = NOW()
+	FROM unnest($2::int[]) WITH ORDINALITY AS o(id, pos)
+	WHERE m.id = o.id AND m.` + cond
+	tag, err := tx.Exec(ctx, query, arg, ids)
+	if err != nil {
+		return fmt.Errorf("failed to reorder media: %w", err)
+	}
+	if int(tag.RowsAffected()) != len(ids) {
+		return fmt.Errorf("media does not belong to owner")
+	}
+	return tx.Commit(ctx)
+}
+
+// DeleteMedia removes a photo record.
+// Returns an error with message "media not found" if no photo has the given ID.
+func (r *MediaRepository) DeleteMedia(ctx context.Context, id int) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM room_media WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete media: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("media %w", ErrNotFound)
+	}
+	return nil
+}
+
+// scanMedia scans room_media rows selected with mediaColumns and closes the result set.
+func scanMedia(rows pgx.Rows) ([]models.RoomMedia, error) {
+	defer rows.Close()
+
+	media := []models.RoomMedia{}
+	for rows.Next() {
+		var m models.RoomMedia
+		err := rows.Scan(
+			&m.ID,
+			&m.RoomID,
+			&m.RoomType,
+			&m.StorageKey,
+			&m.ThumbnailKey,
+			&m.ContentType,
+			&m.SizeBytes,
+			&m.Caption,
+			&m.Position,
+			&m.CreatedAt,
+			&m.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan media: %w", err)
+		}
+		media = append(media, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating media: %w", err)
+	}
+	return media, nil
+}
+
+// GetRoomPropertyID returns the ID of the property a room belongs to.
+// Returns an error wrapping ErrNotFound if the room does not exist.
+func (r *MediaRepository) GetRoomPropertyID(ctx context.Context, roomID int) (int, error) {
+	var propertyID int
+	err := r.db.QueryRow(ctx, `SELECT property_id FROM rooms WHERE id = $1`, roomID).Scan(&propertyID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, fmt.Errorf("room %w", ErrNotFound)
+		}
+		return 0, fmt.Errorf("failed to get room: %w", err)
+	}
+	return propertyID, nil
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"industry-api/internal/models"
+	"industry-api/internal/testsetup"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestMediaRepo_AddReorderDelete(t *testing.T) {
+	_ = testsetup.LoadEnv()
+	dsn := os.Getenv("TEST_DB_URL")
+	if dsn == "" {
+		dsn = os.Getenv("DB_URL")
+	}
+	if dsn == "" {
+		t.Skip("DB_URL or TEST_DB_URL not set; skipping integration tests")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to db: %v", err)
+	}
+	defer pool.Close()
+
+	repo := NewMediaRepository(pool)
+
+	roomType := "MediaTest-" + time.Now().Format("150405")
+	owner := models.MediaOwner{RoomType: roomType}
+	defer func() {
+		if _, err := pool.Exec(ctx, "DELETE FROM room_media WHERE room_type = $1", roomType); err != nil {
+			t.Logf("warning: cleanup failed: %v", err)
+		}
+	}()
+
+	var ids []int
+	for i := 0; i < 2; i++ {
+		m := &models.RoomMedia{RoomType: &roomType, StorageKey: "k", ThumbnailKey: "t", ContentType: "image/png", SizeBytes: 1}
+		if err := repo.AddMedia(ctx, m); err != nil {
+			t.Fatalf("AddMedia failed: %v", err)
+		}
+		if m.Position != i {
+			t.Fatalf("expected position %d, got %d", i, m.Position)
+		}
+		ids = append(ids, m.ID)
+	}
+
+	if err := repo.ReorderMedia(ctx, owner, []int{ids[1], ids[0]}); err != nil {
+		t.Fatalf("ReorderMedia failed: %v", err)
+	}
+	media, err := repo.ListMedia(ctx, owner)
+	if err != nil {
+		t.Fatalf("ListMedia failed: %v", err)
+	}
+	if len(media) != 2 || media[0].ID != ids[1] {
+		t.Fatalf("expected reordered media, got %+v", media)
+	}
+
+	if err := repo.DeleteMedia(ctx, ids[0]); err != nil {
+		t.Fatalf("DeleteMedia failed: %v", err)
+	}
+}
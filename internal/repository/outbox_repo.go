@@ -0,0 +1,204 @@
+// Package repository provides database access layer implementations.
+// Repositories handle all direct database operations using SQL queries.
+package repository
+
+import (
+	"context"
+	"fmt"
+	"industry-api/internal/models"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// OutboxRepository provides database access for the notification outbox.
+type OutboxRepository struct {
+	db *pgxpool.Pool // Database connection pool
+}
+
+// OutboxRepo defines the methods used by services to deliver outbox events.
+type OutboxRepo interface {
+	ClaimOutboxEvents(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.OutboxEvent, error)
+	MarkOutboxEventSent(ctx context.Context, id int64, delivered []string) error
+	RecordOutboxEventFailure(ctx context.Context, id int64, delivered []string, message string, retryAt time.Time, dead bool) error
+	ListOutboxEvents(ctx context.Context, status string, limit int) ([]models.OutboxEvent, error)
+	RetryOutboxEvent(ctx context.Context, id int64) (*models.OutboxEvent, error)
+}
+
+// NewOutboxRepository creates and returns a new instance of OutboxRepository.
+// It accepts a database connection pool for executing database operations.
+func NewOutboxRepository(db *pgxpool.Pool) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// bookingStatusEvents maps the booking statuses guests are notified about to their event.
+var bookingStatusEvents = map[string]string{
+	models.BookingStatusConfirmed: models.EventBookingConfirmed,
+	models.BookingStatusCancelled: models.EventBookingCancelled,
+}
+
+// recordOutboxEvent queues an event about a booking as part of the caller's transaction, so the
+// event is delivered exactly when the change it describes is committed.
+func recordOutboxEvent(ctx context.Context, db dbExecutor, eventType string, bookingID int, payload models.OutboxPayload) error {
+	_, err := db.Exec(ctx, `
+	INSERT INTO outbox_events (event_type, booking_id, payload)
+	VALUES ($1, $2, $3)
+	`, eventType, bookingID, payload)
+	if err != nil {
+		return fmt.Errorf("failed to record %s event: %w", eventType, err)
+	}
+	return nil
+}
+
+// outboxEventColumns is the column list scanned by scanOutboxEvent.
+const outboxEventColumns = `o.id, o.event_type, o.booking_id, o.payload, o.status, o.attempts, o.next_attempt_at,
+	o.delivered_channels, o.last_error, o.created_at, o.sent_at`
+
+// scanOutboxEvent scans a row selected with outboxEventColumns.
+func scanOutboxEvent(row pgx.Row) (*models.OutboxEvent, error) {
+	var e models.OutboxEvent
+	err := row.Scan(&e.ID, &e.EventType, &e.BookingID, &e.Payload, &e.Status, &e.Attempts, &e.NextAttemptAt,
+		&e.DeliveredChannels, &e.LastError, &e.CreatedAt, &e.SentAt)
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// ClaimOutboxEvents returns up to limit pending events due by now, oldest first, with the guest
+// and stay of their booking as they are now. Claimed events are not due again until leaseUntil,
+// so concurrent dispatchers skip them and an event whose dispatcher died is retried afterwards.
+func (r *OutboxRepository) ClaimOutboxEvents(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.OutboxEvent, error) {
+	rows, err := r.db.Query(ctx, `
+	WITH due AS (
+		SELECT id
+		FROM outbox_events
+		WHERE status = 'pending' AND next_attempt_at <= $1
+		ORDER BY next_attempt_at, id
+		LIMIT $3
+		FOR UPDATE SKIP LOCKED
+	), claimed AS (
+		UPDATE outbox_events SET next_attempt_at = $2
+		WHERE id IN (SELECT id FROM due)
+		RETURNING id
+	)
+	SELECT `+outboxEventColumns+`,
+	       u.name, COALESCE(u.email, ''), COALESCE(u.phone, ''), p.name, r.room_number,
+	       b.check_in_date, b.check_out_date, b.total_amount, b.cancellation
+	FROM outbox_events o
+	JOIN bookings b ON b.id = o.booking_id
+	JOIN users u ON u.id = b.user_id
+	JOIN rooms r ON r.id = b.room_id
+	JOIN properties p ON p.id = b.property_id
+	WHERE o.id IN (SELECT id FROM claimed)
+	ORDER BY o.id
+	`, now, leaseUntil, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []models.OutboxEvent{}
+	for rows.Next() {
+		var e models.OutboxEvent
+		g := &e.Guest
+		err := rows.Scan(&e.ID, &e.EventType, &e.BookingID, &e.Payload, &e.Status, &e.Attempts, &e.NextAttemptAt,
+			&e.DeliveredChannels, &e.LastError, &e.CreatedAt, &e.SentAt,
+			&g.Name, &g.Email, &g.Phone, &g.PropertyName, &g.RoomNumber, &g.CheckInDate, &g.CheckOutDate, &g.TotalAmount, &g.Cancellation)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+	return events, nil
+}
+
+// MarkOutboxEventSent records that an event was delivered on every channel in delivered.
+func (r *OutboxRepository) MarkOutboxEventSent(ctx context.Context, id int64, delivered []string) error {
+	_, err := r.db.Exec(ctx, `
+	UPDATE outbox_events
+	SET status = 'sent', delivered_channels = $2, last_error = NULL, sent_at = NOW()
+	WHERE id = $1
+	`, id, delivered)
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox event sent: %w", err)
+	}
+	return nil
+}
+
+// RecordOutboxEventFailure records a failed delivery attempt of an event. The channels in
+// delivered are not attempted again; the others are retried at retryAt, unless dead is set and
+// the event becomes a dead letter.
+func (r *OutboxRepository) RecordOutboxEventFailure(ctx context.Context, id int64, delivered []string, message string, retryAt time.Time, dead bool) error {
+	_, err := r.db.Exec(ctx, `
+	UPDATE outbox_events
+	SET attempts = attempts + 1,
+	    delivered_channels = $2,
+	    last_error = $3,
+	    next_attempt_at = $4,
+	    status = CASE WHEN $5 THEN 'dead' ELSE status END
+	WHERE id = $1
+	`, id, delivered, message, retryAt, dead)
+	if err != nil {
+		return fmt.Errorf("failed to record outbox event failure: %w", err)
+	}
+	return nil
+}
+
+// ListOutboxEvents returns up to limit events, newest first, optionally only those with status.
+func (r *OutboxRepository) ListOutboxEvents(ctx context.Context, status string, limit int) ([]models.OutboxEvent, error) {
+	rows, err := r.db.Query(ctx, `
+	SELECT `+outboxEventColumns+`
+	FROM outbox_events o
+	WHERE $1 = '' OR o.status = $1
+	ORDER BY o.id DESC
+	LIMIT $2
+	`, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []models.OutboxEvent{}
+	for rows.Next() {
+		e, err := scanOutboxEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+		events = append(events, *e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list outbox events: %w", err)
+	}
+	return events, nil
+}
+
+// RetryOutboxEvent puts a dead event back in the queue with a fresh set of attempts. Channels that
+// already delivered it are still skipped.
+// Returns an error wrapping ErrNotFound if the event does not exist, or ErrConflict if it is not dead.
+func (r *OutboxRepository) RetryOutboxEvent(ctx context.Context, id int64) (*models.OutboxEvent, error) {
+	event, err := scanOutboxEvent(r.db.QueryRow(ctx, `
+	UPDATE outbox_events o
+	SET status = 'pending', attempts = 0, next_attempt_at = NOW()
+	WHERE o.id = $1 AND o.status = 'dead'
+	RETURNING `+outboxEventColumns, id))
+	if err == nil {
+		return event, nil
+	}
+	if err != pgx.ErrNoRows {
+		return nil, fmt.Errorf("failed to retry outbox event: %w", err)
+	}
+
+	var status string
+	if err := r.db.QueryRow(ctx, `SELECT status FROM outbox_events WHERE id = $1`, id).Scan(&status); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("outbox event %w", ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to retry outbox event: %w", err)
+	}
+	return nil, fmt.Errorf("outbox event is %s, only dead events can be retried: %w", status, ErrConflict)
+}
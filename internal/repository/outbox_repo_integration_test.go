@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"industry-api/internal/models"
+	"industry-api/internal/testsetup"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestOutboxRepo_EventsWrittenWithChangesAndClaimedOnce(t *testing.T) {
+	_ = testsetup.LoadEnv()
+	dsn := os.Getenv("TEST_DB_URL")
+	if dsn == "" {
+		dsn = os.Getenv("DB_URL")
+	}
+	if dsn == "" {
+		t.Skip("DB_URL or TEST_DB_URL not set; skipping integration tests")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to db: %v", err)
+	}
+	defer pool.Close()
+
+	property := createTestProperty(t, ctx, pool, "O")
+	userID := createTestUser(t, ctx, pool, models.RoleGuest)
+	room := &models.Room{PropertyID: property.ID, RoomNumber: "OB-1", RoomType: "Test", Description: "Outbox test room", Price: 10, Capacity: 2, Floor: 1, Amenities: []string{"test"}}
+	if err := NewRoomRepository(pool).AddRoom(ctx, room); err != nil {
+		t.Fatalf("AddRoom failed: %v", err)
+	}
+	defer func() {
+		if _, err := pool.Exec(ctx, "DELETE FROM bookings WHERE room_id = $1", room.ID); err != nil {
+			t.Logf("warning: cleanup failed: %v", err)
+		}
+		if _, err := pool.Exec(ctx, "DELETE FROM rooms WHERE id = $1", room.ID); err != nil {
+			t.Logf("warning: cleanup failed: %v", err)
+		}
+	}()
+
+	bookings := NewBookingRepository(pool)
+	checkIn := time.Now().AddDate(2, 1, 0).Truncate(24 * time.Hour)
+	b := &models.Booking{PropertyID: property.ID, UserID: userID, RoomID: room.ID, CheckInDate: checkIn, CheckOutDate: checkIn.AddDate(0, 0, 2), Adults: 1, TotalAmount: 20, Status: models.BookingStatusPending, PaymentStatus: models.PaymentStatusPending}
+	if _, err := bookings.AddBooking(ctx, b); err != nil {
+		t.Fatalf("AddBooking failed: %v", err)
+	}
+	if _, err := bookings.SetBookingStatus(ctx, b.ID, models.BookingStatusPending, models.BookingStatusConfirmed, nil, "paid"); err != nil {
+		t.Fatalf("SetBookingStatus failed: %v", err)
+	}
+	if _, err := bookings.SetBookingStatus(ctx, b.ID, models.BookingStatusConfirmed, models.BookingStatusCheckedIn, nil, ""); err != nil {
+		t.Fatalf("SetBookingStatus failed: %v", err)
+	}
+
+	// Claim concurrently; each event of the booking must go to exactly one caller
+	repo := NewOutboxRepository(pool)
+	now := time.Now().Add(time.Minute)
+	var mu sync.Mutex
+	claimed := map[int64]int{}
+	var claimedEvents []models.OutboxEvent
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			events, err := repo.ClaimOutboxEvents(ctx, now, now.Add(time.Hour), 1000)
+			if err != nil {
+				t.Errorf("ClaimOutboxEvents failed: %v", err)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for _, e := range events {
+				if e.BookingID == b.ID {
+					claimed[e.ID]++
+					claimedEvents = append(claimedEvents, e)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(claimedEvents) != 2 {
+		t.Fatalf("expected created and confirmed events only, got %+v", claimedEvents)
+	}
+	for id, n := range claimed {
+		if n != 1 {
+			t.Fatalf("expected event %d to be claimed once, got %d", id, n)
+		}
+	}
+	types := map[string]bool{}
+	for _, e := range claimedEvents {
+		types[e.EventType] = true
+		if e.Guest.Email == "" || e.Guest.RoomNumber != "OB-1" || e.Guest.PropertyName != property.Name {
+			t.Fatalf("expected the guest and stay with the event, got %+v", e.Guest)
+		}
+	}
+	if !types[models.EventBookingCreated] || !types[models.EventBookingConfirmed] {
+		t.Fatalf("expected created and confirmed events, got %v", types)
+	}
+
+	// Leased events are not claimed again before the lease ends
+	again, err := repo.ClaimOutboxEvents(ctx, now, now.Add(time.Hour), 1000)
+	if err != nil {
+		t.Fatalf("ClaimOutboxEvents failed: %v", err)
+	}
+	for _, e := range again {
+		if e.BookingID == b.ID {
+			t.Fatalf("expected leased event %d not to be claimed again", e.ID)
+		}
+	}
+
+	sent, dead := claimedEvents[0], claimedEvents[1]
+	if err := repo.MarkOutboxEventSent(ctx, sent.ID, []string{"email", "sms"}); err != nil {
+		t.Fatalf("MarkOutboxEventSent failed: %v", err)
+	}
+	if err := repo.RecordOutboxEventFailure(ctx, dead.ID, []string{"email"}, "sms: gateway unavailable", now, true); err != nil {
+		t.Fatalf("RecordOutboxEventFailure failed: %v", err)
+	}
+	if _, err := repo.RetryOutboxEvent(ctx, sent.ID); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected conflict retrying a sent event, got %v", err)
+	}
+	retried, err := repo.RetryOutboxEvent(ctx, dead.ID)
+	if err != nil {
+		t.Fatalf("RetryOutboxEvent failed: %v", err)
+	}
+	if retried.Status != models.OutboxStatusPending || retried.Attempts != 0 || len(retried.DeliveredChannels) != 1 {
+		t.Fatalf("expected a pending event keeping its delivered channel, got %+v", retried)
+	}
+	if _, err := repo.RetryOutboxEvent(ctx, -1); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
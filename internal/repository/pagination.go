@@ -0,0 +1,25 @@
+// Package repository provides database access layer implementations.
+// Repositories handle all direct database operations using SQL queries.
+package repository
+
+// pageCount calculates the number of pages needed to show total records with the given limit.
+// A limit of 0 means "no pagination" and always yields a single page.
+func pageCount(total, limit int) int {
+	if limit == 0 || total == 0 {
+		return 1
+	}
+	pages := total / limit
+	if total%limit > 0 {
+		pages++
+	}
+	return pages
+}
+
+// pageOffset converts a 1-based page number into a row offset.
+func pageOffset(page, limit int) int {
+	offset := (page - 1) * limit
+	if offset < 0 {
+		return 0
+	}
+	return offset
+}
@@ -4,8 +4,11 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"industry-api/internal/models"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -16,7 +19,7 @@ type PaymentRepository struct {
 
 // PaymentRepo defines the methods used by services for payment operations.
 type PaymentRepo interface {
-	InitiatePayment(ctx context.Context, payment *models.Payment) (*models.Payment, error)
+	InitiatePayment(ctx context.Context, payment *models.Payment, holdUntil *time.Time) (*models.Payment, error)
 	UpdatePayment(ctx context.Context, payment *models.Payment, id int) (*models.Payment, error)
 }
 
@@ -28,27 +31,60 @@ func NewPaymentRepository(db *pgxpool.Pool) *PaymentRepository {
 
 // InitiatePayment inserts a new payment record into the database.
 // It creates a new payment entry with the initial status and returns the payment with ID and creation timestamp.
+// When holdUntil is set, the booking's hold is extended to it in the same transaction, so a
+// booking whose hold already expired gets no payment.
 // Returns the created payment with ID and CreatedAt fields populated, or an error if the operation fails.
-func (r *PaymentRepository) InitiatePayment(ctx context.Context, payment *models.Payment) (*models.Payment, error) {
+func (r *PaymentRepository) InitiatePayment(ctx context.Context, payment *models.Payment, holdUntil *time.Time) (*models.Payment, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if holdUntil != nil {
+		if err := extendBookingHold(ctx, tx, payment.BookingID, *holdUntil); err != nil {
+			return nil, err
+		}
+	}
+
 	query := `
 		INSERT INTO payments (booking_id, amount, payment_method, transaction_id, status)
 		VALUES ($1, $2, $3, $4, $5)
 		RETURNING id, created_at
 	`
 	// Execute the insert query and scan the returned ID and timestamp
-	err := r.db.QueryRow(ctx, query, payment.BookingID, payment.Amount, payment.PaymentMethod, payment.TransactionID, payment.Status).Scan(&payment.ID, &payment.CreatedAt)
-
+	err = tx.QueryRow(ctx, query, payment.BookingID, payment.Amount, payment.PaymentMethod, payment.TransactionID, payment.Status).Scan(&payment.ID, &payment.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit payment: %w", err)
+	}
 	return payment, nil
 
 }
 
 // UpdatePayment updates an existing payment record with card details and payment status.
-// It updates the card information and marks the payment as paid.
+// It updates the card information and marks the payment as paid. A payment that becomes paid
+// queues a payment.succeeded notification in the same transaction.
 // Returns the updated payment with all transaction details, or an error if the operation fails.
 func (r *PaymentRepository) UpdatePayment(ctx context.Context, payment *models.Payment, id int) (*models.Payment, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the payment so only the update that marks it paid notifies the guest
+	var previous string
+	if err := tx.QueryRow(ctx, `SELECT status FROM payments WHERE id = $1 FOR UPDATE`, id).Scan(&previous); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("payment %w", ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get payment: %w", err)
+	}
+
 	query := `
 	UPDATE payments
 	SET card_last4 = $1, card_brand=$2, receipt_url=$3, status=$4
@@ -56,11 +92,48 @@ func (r *PaymentRepository) UpdatePayment(ctx context.Context, payment *models.P
 	RETURNING id, booking_id, payment_method, amount, status, processed_at
 	`
 	// Execute the update query and scan the returned payment details
-	pmt := r.db.QueryRow(ctx, query, payment.CardLastFour, payment.CardBrand, payment.ReceitURL, payment.Status, id)
-	err := pmt.Scan(&payment.ID, &payment.BookingID, &payment.PaymentMethod, &payment.Amount, &payment.Status, &payment.ProcessedAt)
+	pmt := tx.QueryRow(ctx, query, payment.CardLastFour, payment.CardBrand, payment.ReceitURL, payment.Status, id)
+	err = pmt.Scan(&payment.ID, &payment.BookingID, &payment.PaymentMethod, &payment.Amount, &payment.Status, &payment.ProcessedAt)
 	if err != nil {
 		return nil, err
 	}
+	if payment.Status == models.PaymentStatusPaid && previous != models.PaymentStatusPaid {
+		payload := models.OutboxPayload{PaymentID: payment.ID, PaymentAmount: payment.Amount}
+		if err := recordOutboxEvent(ctx, tx, models.EventPaymentSucceeded, payment.BookingID, payload); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit payment: %w", err)
+	}
 	return payment, nil
 
 }
+
+// extendBookingHold pushes the hold of a pending booking out to until while its guest pays.
+// Holds are never shortened, and bookings without a hold are left unchanged.
+// Returns an error wrapping ErrNotFound if the booking does not exist, or ErrConflict if it has
+// been cancelled (for example because its hold already expired).
+func extendBookingHold(ctx context.Context, db dbExecutor, bookingID int, until time.Time) error {
+	var status string
+	err := db.QueryRow(ctx, `
+	UPDATE bookings
+	SET hold_expires_at = CASE
+	        WHEN status = 'pending' AND hold_expires_at IS NOT NULL THEN GREATEST(hold_expires_at, $2)
+	        ELSE hold_expires_at
+	    END
+	WHERE id = $1
+	RETURNING status
+	`, bookingID, until).Scan(&status)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("booking %w", ErrNotFound)
+		}
+		return fmt.Errorf("failed to extend booking hold: %w", err)
+	}
+	if status == models.BookingStatusCancelled || status == models.BookingStatusNoShow {
+		return fmt.Errorf("booking is %s: %w", status, ErrConflict)
+	}
+	return nil
+}
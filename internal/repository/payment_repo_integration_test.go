@@ -40,7 +40,7 @@ func TestPaymentRepository_InitiateAndCleanup(t *testing.T) {
 		Status:        "pending",
 	}
 
-	created, err := repo.InitiatePayment(ctx, p)
+	created, err := repo.InitiatePayment(ctx, p, nil)
 	if err != nil {
 		t.Logf("InitiatePayment failed (likely FK constraints): %v", err)
 		t.Skip("InitiatePayment requires valid booking FK; skipping in this environment")
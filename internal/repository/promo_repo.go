@@ -0,0 +1,220 @@
+// Package repository provides database access layer implementations.
+// Repositories handle all direct database operations using SQL queries.
+package repository
+
+import (
+	"context"
+	"fmt"
+	"industry-api/internal/models"
+	"sort"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PromoRepository provides database access for promo codes.
+type PromoRepository struct {
+	db *pgxpool.Pool // Database connection pool
+}
+
+// PromoRepo defines the methods used by services to manage promo codes.
+type PromoRepo interface {
+	CreatePromoCode(ctx context.Context, promo *models.PromoCode) error
+	ListPromoCodes(ctx context.Context) ([]models.PromoCode, error)
+	DeactivatePromoCode(ctx context.Context, id int) (*models.PromoCode, error)
+}
+
+// promoColumns is the column list scanned by scanPromoCode.
+const promoColumns = `id, code, description, discount_type, discount_value, stay_from, stay_to, book_from, book_to, min_nights, room_types, max_uses, max_uses_per_user, times_used, stackable, is_active, created_at, updated_at`
+
+// NewPromoRepository creates and returns a new instance of PromoRepository.
+// It accepts a database connection pool for executing database operations.
+func NewPromoRepository(db *pgxpool.Pool) *PromoRepository {
+	return &PromoRepository{db: db}
+}
+
+// CreatePromoCode inserts a promo code and fills in its ID, usage count and timestamps.
+// Returns an error wrapping ErrConflict if the code already exists.
+func (r *PromoRepository) CreatePromoCode(ctx context.Context, promo *models.PromoCode) error {
+	err := r.db.QueryRow(ctx, `
+	INSERT INTO promo_codes (code, description, discount_type, discount_value, stay_from, stay_to, book_from, book_to, min_nights, room_types, max_uses, max_uses_per_user, stackable)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	RETURNING id, times_used, is_active, created_at, updated_at
+	`, promo.Code, promo.Description, promo.DiscountType, promo.DiscountValue, promo.StayFrom, promo.StayTo, promo.BookFrom, promo.BookTo,
+		promo.MinNights, promo.RoomTypes, promo.MaxUses, promo.MaxUsesPerUser, promo.Stackable).
+		Scan(&promo.ID, &promo.TimesUsed, &promo.IsActive, &promo.CreatedAt, &promo.UpdatedAt)
+	if err != nil {
+		if hasPgCode(err, pgUniqueViolation) {
+			return fmt.Errorf("promo code %q already exists: %w", promo.Code, ErrConflict)
+		}
+		return fmt.Errorf("failed to create promo code: %w", err)
+	}
+	return nil
+}
+
+// ListPromoCodes returns every promo code, newest first.
+func (r *PromoRepository) ListPromoCodes(ctx context.Context) ([]models.PromoCode, error) {
+	return queryPromoCodes(ctx, r.db, `SELECT `+promoColumns+` FROM promo_codes ORDER BY created_at DESC, id DESC`)
+}
+
+// DeactivatePromoCode stops a promo code from being redeemed; existing bookings keep their discount.
+// Returns an error wrapping ErrNotFound if the code does not exist.
+func (r *PromoRepository) DeactivatePromoCode(ctx context.Context, id int) (*models.PromoCode, error) {
+	promo, err := scanPromoCode(r.db.QueryRow(ctx, `
+	UPDATE promo_codes SET is_active = FALSE, updated_at = NOW()
+	WHERE id = $1
+	RETURNING `+promoColumns, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("promo code %w", ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to deactivate promo code: %w", err)
+	}
+	return promo, nil
+}
+
+// redeemPromoCodes records the booking's redemptions as part of the caller's transaction.
+// Each code is locked before its usage is counted, so concurrent bookings cannot exceed the
+// total or per-user limits; codes are locked in ID order to avoid deadlocks. A code that was
+// deactivated or used up in the meantime is reported with an error wrapping ErrConflict.
+func redeemPromoCodes(ctx context.Context, tx dbExecutor, booking *models.Booking) error {
+	redemptions := append([]models.PromoRedemption(nil), booking.Redemptions...)
+	sort.Slice(redemptions, func(i, j int) bool { return redemptions[i].PromoCodeID < redemptions[j].PromoCodeID })
+
+	for _, redemption := range redemptions {
+		var active bool
+		var used int
+		var maxUses, maxPerUser *int
+		err := tx.QueryRow(ctx, `
+		SELECT is_active, times_used, max_uses, max_uses_per_user
+		FROM promo_codes
+		WHERE id = $1
+		FOR UPDATE
+		`, redemption.PromoCodeID).Scan(&active, &used, &maxUses, &maxPerUser)
+		if err != nil {
+			return fmt.Errorf("failed to lock promo code: %w", err)
+		}
+		if !active {
+			return fmt.Errorf("promo code %s is no longer active: %w", redemption.Code, ErrConflict)
+		}
+		if maxUses != nil && used >= *maxUses {
+			return fmt.Errorf("promo code %s has been used up: %w", redemption.Code, ErrConflict)
+		}
+		// Counted after the lock, so redemptions committed by concurrent bookings are seen
+		if maxPerUser != nil {
+			var userUses int
+			if err := tx.QueryRow(ctx, `
+			SELECT COUNT(*) FROM promo_code_redemptions WHERE promo_code_id = $1 AND user_id = $2 AND released_at IS NULL
+			`, redemption.PromoCodeID, booking.UserID).Scan(&userUses); err != nil {
+				return fmt.Errorf("failed to count promo code uses: %w", err)
+			}
+			if userUses >= *maxPerUser {
+				return fmt.Errorf("promo code %s has already been used the maximum number of times by this guest: %w", redemption.Code, ErrConflict)
+			}
+		}
+
+		if _, err := tx.Exec(ctx, `UPDATE promo_codes SET times_used = times_used + 1, updated_at = NOW() WHERE id = $1`, redemption.PromoCodeID); err != nil {
+			return fmt.Errorf("failed to update promo code usage: %w", err)
+		}
+		if _, err := tx.Exec(ctx, `
+		INSERT INTO promo_code_redemptions (promo_code_id, booking_id, user_id, discount_amount)
+		VALUES ($1, $2, $3, $4)
+		`, redemption.PromoCodeID, booking.ID, booking.UserID, redemption.DiscountAmount); err != nil {
+			return fmt.Errorf("failed to record promo code redemption: %w", err)
+		}
+	}
+	return nil
+}
+
+// updatePromoRedemptions stores the discount each of the booking's redemptions gives on its
+// repriced stay, as part of the caller's transaction. Usage counts are left unchanged.
+func updatePromoRedemptions(ctx context.Context, tx dbExecutor, booking *models.Booking) error {
+	for _, redemption := range booking.Redemptions {
+		if _, err := tx.Exec(ctx, `
+		UPDATE promo_code_redemptions SET discount_amount = $3
+		WHERE booking_id = $1 AND promo_code_id = $2
+		`, booking.ID, redemption.PromoCodeID, redemption.DiscountAmount); err != nil {
+			return fmt.Errorf("failed to update promo code redemption: %w", err)
+		}
+	}
+	return nil
+}
+
+// releasePromoCodes gives back the uses of the promo codes a booking redeemed, as part of the
+// caller's transaction, once the booking no longer holds its nights. The redemptions are kept
+// but stop counting towards the total and per-user limits. Codes are locked in ID order, like
+// redeemPromoCodes, to avoid deadlocks; releasing a booking twice has no further effect.
+func releasePromoCodes(ctx context.Context, tx dbExecutor, bookingID int) error {
+	if _, err := tx.Exec(ctx, `
+	SELECT id FROM promo_codes
+	WHERE id IN (SELECT promo_code_id FROM promo_code_redemptions WHERE booking_id = $1 AND released_at IS NULL)
+	ORDER BY id
+	FOR UPDATE
+	`, bookingID); err != nil {
+		return fmt.Errorf("failed to lock promo codes: %w", err)
+	}
+	_, err := tx.Exec(ctx, `
+	WITH released AS (
+		UPDATE promo_code_redemptions SET released_at = NOW()
+		WHERE booking_id = $1 AND released_at IS NULL
+		RETURNING promo_code_id
+	)
+	UPDATE promo_codes
+	SET times_used = GREATEST(times_used - 1, 0), updated_at = NOW()
+	WHERE id IN (SELECT promo_code_id FROM released)
+	`, bookingID)
+	if err != nil {
+		return fmt.Errorf("failed to release promo codes: %w", err)
+	}
+	return nil
+}
+
+// queryPromoCodes runs a query selecting promoColumns and scans every row.
+func queryPromoCodes(ctx context.Context, db dbExecutor, query string, args ...interface{}) ([]models.PromoCode, error) {
+	rows, err := db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list promo codes: %w", err)
+	}
+	defer rows.Close()
+	promos := []models.PromoCode{}
+	for rows.Next() {
+		promo, err := scanPromoCode(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan promo code: %w", err)
+		}
+		promos = append(promos, *promo)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list promo codes: %w", err)
+	}
+	return promos, nil
+}
+
+// scanPromoCode scans a row selected with promoColumns.
+func scanPromoCode(row pgx.Row) (*models.PromoCode, error) {
+	var promo models.PromoCode
+	err := row.Scan(
+		&promo.ID,
+		&promo.Code,
+		&promo.Description,
+		&promo.DiscountType,
+		&promo.DiscountValue,
+		&promo.StayFrom,
+		&promo.StayTo,
+		&promo.BookFrom,
+		&promo.BookTo,
+		&promo.MinNights,
+		&promo.RoomTypes,
+		&promo.MaxUses,
+		&promo.MaxUsesPerUser,
+		&promo.TimesUsed,
+		&promo.Stackable,
+		&promo.IsActive,
+		&promo.CreatedAt,
+		&promo.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &promo, nil
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"industry-api/internal/models"
+	"industry-api/internal/testsetup"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestPromoRepo_UsageLimitHoldsUnderConcurrency(t *testing.T) {
+	_ = testsetup.LoadEnv()
+	dsn := os.Getenv("TEST_DB_URL")
+	if dsn == "" {
+		dsn = os.Getenv("DB_URL")
+	}
+	if dsn == "" {
+		t.Skip("DB_URL or TEST_DB_URL not set; skipping integration tests")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to db: %v", err)
+	}
+	defer pool.Close()
+
+	property := createTestProperty(t, ctx, pool, "P")
+	userID := createTestUser(t, ctx, pool, models.RoleGuest)
+	var rooms []*models.Room
+	for _, number := range []string{"PR-1", "PR-2", "PR-3"} {
+		room := &models.Room{PropertyID: property.ID, RoomNumber: number, RoomType: "Test", Description: "Promo code test room", Price: 10, Capacity: 2, Floor: 1, Amenities: []string{"test"}}
+		if err := NewRoomRepository(pool).AddRoom(ctx, room); err != nil {
+			t.Fatalf("AddRoom failed: %v", err)
+		}
+		rooms = append(rooms, room)
+	}
+	two := 2
+	promo := &models.PromoCode{Code: "T" + time.Now().Format("150405.000000"), DiscountType: models.DiscountFixed, DiscountValue: 5, MinNights: 1, RoomTypes: []string{}, MaxUses: &two}
+	if err := NewPromoRepository(pool).CreatePromoCode(ctx, promo); err != nil {
+		t.Fatalf("CreatePromoCode failed: %v", err)
+	}
+	defer func() {
+		for _, room := range rooms {
+			if _, err := pool.Exec(ctx, "DELETE FROM bookings WHERE room_id = $1", room.ID); err != nil {
+				t.Logf("warning: cleanup failed: %v", err)
+			}
+		}
+		if _, err := pool.Exec(ctx, "DELETE FROM promo_codes WHERE id = $1", promo.ID); err != nil {
+			t.Logf("warning: cleanup failed: %v", err)
+		}
+	}()
+
+	// three guests race for a code that may be used twice
+	checkIn := time.Now().AddDate(1, 7, 0).Truncate(24 * time.Hour)
+	repo := NewBookingRepository(pool)
+	newBooking := func(room *models.Room) *models.Booking {
+		return &models.Booking{PropertyID: property.ID, UserID: userID, RoomID: room.ID, CheckInDate: checkIn, CheckOutDate: checkIn.AddDate(0, 0, 1), Adults: 1, TotalAmount: 5, Status: models.BookingStatusPending, PaymentStatus: models.PaymentStatusPending,
+			Redemptions: []models.PromoRedemption{{PromoCodeID: promo.ID, Code: promo.Code, DiscountAmount: 5}}}
+	}
+	bookings := make([]*models.Booking, len(rooms))
+	errs := make([]error, len(rooms))
+	var wg sync.WaitGroup
+	for i, room := range rooms {
+		wg.Add(1)
+		go func(i int, room *models.Room) {
+			defer wg.Done()
+			bookings[i] = newBooking(room)
+			_, errs[i] = repo.AddBooking(ctx, bookings[i])
+		}(i, room)
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+		} else if !errors.Is(err, ErrConflict) {
+			t.Fatalf("expected a conflict for the losing booking, got %v", err)
+		}
+	}
+	var used, redemptions int
+	if err := pool.QueryRow(ctx, "SELECT times_used, (SELECT COUNT(*) FROM promo_code_redemptions WHERE promo_code_id = $1) FROM promo_codes WHERE id = $1", promo.ID).Scan(&used, &redemptions); err != nil {
+		t.Fatalf("failed to read usage: %v", err)
+	}
+	if succeeded != 2 || used != 2 || redemptions != 2 {
+		t.Fatalf("expected 2 redemptions, got %d bookings, times_used=%d, %d redemptions", succeeded, used, redemptions)
+	}
+
+	redeemed, err := repo.ListBookingPromoCodes(ctx, 0)
+	if err != nil || len(redeemed) != 0 {
+		t.Fatalf("expected no promo codes for an unknown booking, got %+v err=%v", redeemed, err)
+	}
+
+	// Cancelling a booking gives its use back, so the losing guest can now redeem the code
+	cancelled, loser := -1, -1
+	for i, err := range errs {
+		if err == nil && cancelled < 0 {
+			cancelled = i
+		} else if err != nil {
+			loser = i
+		}
+	}
+	quote := &models.CancellationQuote{BookingID: bookings[cancelled].ID}
+	if _, err := repo.CancelBooking(ctx, bookings[cancelled].ID, models.BookingStatusPending, quote, nil, "changed plans"); err != nil {
+		t.Fatalf("CancelBooking failed: %v", err)
+	}
+	if err := pool.QueryRow(ctx, "SELECT times_used FROM promo_codes WHERE id = $1", promo.ID).Scan(&used); err != nil || used != 1 {
+		t.Fatalf("expected the cancelled booking to release its use, times_used=%d err=%v", used, err)
+	}
+	if _, err := repo.AddBooking(ctx, newBooking(rooms[loser])); err != nil {
+		t.Fatalf("expected the released use to be redeemable, got %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+// Package repository provides database access layer implementations.
+// Repositories handle all direct database operations using SQL queries.
+package repository
+
+import (
+	"context"
+	"fmt"
+	"industry-api/internal/models"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PropertyRepository provides database access for properties and staff assignments.
+type PropertyRepository struct {
+	db *pgxpool.Pool // Database connection pool
+}
+
+// PropertyRepo defines the methods used by services for property data access.
+type PropertyRepo interface {
+	CreateProperty(ctx context.Context, property *models.Property) error
+	ListProperties(ctx context.Context, propertyIDs []int) ([]models.Property, error)
+	GetUserPropertyIDs(ctx context.Context, userID int) ([]int, error)
+	SetUserProperties(ctx context.Context, userID int, propertyIDs []int) error
+}
+
+// NewPropertyRepository creates and returns a new instance of PropertyRepository.
+// It accepts a database connection pool for executing database operations.
+func NewPropertyRepository(db *pgxpool.Pool) *PropertyRepository {
+	return &PropertyRepository{db: db}
+}
+
+// CreateProperty inserts a new property and fills in its generated ID and timestamps.
+// Returns an error wrapping ErrConflict if the code is already in use.
+func (r *PropertyRepository) CreateProperty(ctx context.Context, property *models.Property) error {
+	query := `
+	INSERT INTO properties (code, name, address, timezone)
+	VALUES ($1, $2, $3, $4)
+	RETURNING id, created_at, updated_at
+	`
+	err := r.db.QueryRow(ctx, query, property.Code, property.Name, property.Address, property.Timezone).
+		Scan(&property.ID, &property.CreatedAt, &property.UpdatedAt)
+	if err != nil {
+		if hasPgCode(err, pgUniqueViolation) {
+			return fmt.Errorf("property code %q already exists: %w", property.Code, ErrConflict)
+		}
+		return fmt.Errorf("failed to create property: %w", err)
+	}
+	return nil
+}
+
+// ListProperties retrieves properties ordered by name.
+// A nil propertyIDs returns every property; otherwise only the listed ones are returned.
+func (r *PropertyRepository) ListProperties(ctx context.Context, propertyIDs []int) ([]models.Property, error) {
+	query := `
+	SELECT id, code, name, address, timezone, created_at, updated_at
+	FROM properties
+	WHERE ($1::int[] IS NULL OR id = ANY($1))
+	ORDER BY name, id
+	`
+	rows, err := r.db.Query(ctx, query, propertyIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query properties: %w", err)
+	}
+	defer rows.Close()
+
+	properties := []models.Property{}
+	for rows.Next() {
+		var p models.Property
+		if err := rows.Scan(&p.ID, &p.Code, &p.Name, &p.Address, &p.Timezone, &p.CreatedAt, &p.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan property: %w", err)
+		}
+		properties = append(properties, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating properties: %w", err)
+	}
+	return properties, nil
+}
+
+// GetUserPropertyIDs retrieves the IDs of the properties a user is assigned to, in ascending order.
+func (r *PropertyRepository) GetUserPropertyIDs(ctx context.Context, userID int) ([]int, error) {
+	rows, err := r.db.Query(ctx, `SELECT property_id FROM user_properties WHERE user_id = $1 ORDER BY property_id`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user properties: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan user property: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating user properties: %w", err)
+	}
+	return ids, nil
+}
+
+// SetUserProperties replaces a user's property assignments in a single transaction.
+// Returns an error wrapping ErrNotFound if the user or any of the properties does not exist.
+func (r *PropertyRepository) SetUserProperties(ctx context.Context, userID int, propertyIDs []int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var exists bool
+	if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE id = $1)`, userID).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check user: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("user %w", ErrNotFound)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM user_properties WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to clear user properties: %w", err)
+	}
+	if len(propertyIDs) > 0 {
+		_, err := tx.Exec(ctx, `
+		INSERT INTO user_properties (user_id, property_id)
+		SELECT $1, p FROM unnest($2::int[]) AS p
+		`, userID, propertyIDs)
+		if err != nil {
+			if hasPgCode(err, pgForeignKeyViolation) {
+				return fmt.Errorf("property %w", ErrNotFound)
+			}
+			return fmt.Errorf("failed to assign user properties: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit user properties: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"industry-api/internal/models"
+	"industry-api/internal/testsetup"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// createTestProperty inserts a property with a unique code and deletes it when the test finishes.
+// Rooms created on it must be deleted by the test before then.
+func createTestProperty(t *testing.T, ctx context.Context, pool *pgxpool.Pool, suffix string) *models.Property {
+	t.Helper()
+	property := &models.Property{
+		Code:     "T" + suffix + time.Now().Format("150405.000000"),
+		Name:     "Integration test property " + suffix,
+		Timezone: "UTC",
+	}
+	if err := NewPropertyRepository(pool).CreateProperty(ctx, property); err != nil {
+		t.Fatalf("CreateProperty failed: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := pool.Exec(context.Background(), "DELETE FROM properties WHERE id = $1", property.ID); err != nil {
+			t.Logf("warning: property cleanup failed: %v", err)
+		}
+	})
+	return property
+}
+
+// createTestUser inserts a user with a unique email, deletes it when the test finishes and returns its ID.
+func createTestUser(t *testing.T, ctx context.Context, pool *pgxpool.Pool, role string) int {
+	t.Helper()
+	user := &models.User{Name: "Integration " + role, Email: role + "-" + time.Now().Format("150405.000000") + "@example.com", Password: "x", Phone: "1234567890", Role: role}
+	if err := NewUserRepository(pool).CreateUser(ctx, user); err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	var userID int
+	if err := pool.QueryRow(ctx, "SELECT id FROM users WHERE email = $1", user.Email).Scan(&userID); err != nil {
+		t.Fatalf("failed to look up user: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := pool.Exec(context.Background(), "DELETE FROM users WHERE id = $1", userID); err != nil {
+			t.Logf("warning: user cleanup failed: %v", err)
+		}
+	})
+	return userID
+}
+
+func TestPropertyRepo_RoomNumbersScopedToProperty(t *testing.T) {
+	_ = testsetup.LoadEnv()
+	dsn := os.Getenv("TEST_DB_URL")
+	if dsn == "" {
+		dsn = os.Getenv("DB_URL")
+	}
+	if dsn == "" {
+		t.Skip("DB_URL or TEST_DB_URL not set; skipping integration tests")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to db: %v", err)
+	}
+	defer pool.Close()
+
+	first := createTestProperty(t, ctx, pool, "A")
+	second := createTestProperty(t, ctx, pool, "B")
+	roomRepo := NewRoomRepository(pool)
+	defer func() {
+		if _, err := pool.Exec(ctx, "DELETE FROM rooms WHERE property_id = ANY($1)", []int{first.ID, second.ID}); err != nil {
+			t.Logf("warning: cleanup failed: %v", err)
+		}
+	}()
+
+	// the same room number may exist once per property
+	for _, p := range []*models.Property{first, second} {
+		room := &models.Room{PropertyID: p.ID, RoomNumber: "101", RoomType: "PropertyTest", Description: "d", Price: 10, Capacity: 1, Floor: 1, Amenities: []string{"test"}}
+		if err := roomRepo.AddRoom(ctx, room); err != nil {
+			t.Fatalf("AddRoom failed: %v", err)
+		}
+	}
+	dup := &models.Room{PropertyID: first.ID, RoomNumber: "101", RoomType: "PropertyTest", Description: "d", Price: 10, Capacity: 1, Floor: 1, Amenities: []string{"test"}}
+	if err := roomRepo.AddRoom(ctx, dup); err == nil {
+		t.Fatalf("expected duplicate room number within a property to fail")
+	}
+
+	rooms, err := roomRepo.GetRoomsList(ctx, []int{first.ID})
+	if err != nil {
+		t.Fatalf("GetRoomsList failed: %v", err)
+	}
+	if len(rooms) != 1 || rooms[0].PropertyID != first.ID {
+		t.Fatalf("expected exactly the first property's room, got %+v", rooms)
+	}
+
+	// staff assignment round trip
+	userID := createTestUser(t, ctx, pool, models.RoleStaff)
+
+	repo := NewPropertyRepository(pool)
+	if err := repo.SetUserProperties(ctx, userID, []int{second.ID}); err != nil {
+		t.Fatalf("SetUserProperties failed: %v", err)
+	}
+	ids, err := repo.GetUserPropertyIDs(ctx, userID)
+	if err != nil {
+		t.Fatalf("GetUserPropertyIDs failed: %v", err)
+	}
+	if len(ids) != 1 || ids[0] != second.ID {
+		t.Fatalf("unexpected assignment: %v", ids)
+	}
+}
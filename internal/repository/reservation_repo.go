@@ -0,0 +1,206 @@
+// Package repository provides database access layer implementations.
+// Repositories handle all direct database operations using SQL queries.
+package repository
+
+import (
+	"context"
+	"fmt"
+	"industry-api/internal/models"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ReservationRepository provides database access for group reservations.
+type ReservationRepository struct {
+	db *pgxpool.Pool // Database connection pool
+}
+
+// ReservationRepo defines the methods used by services for group reservations.
+type ReservationRepo interface {
+	CreateReservation(ctx context.Context, reservation *models.Reservation) error
+	GetReservation(ctx context.Context, id int) (*models.Reservation, error)
+	GetReservationPaidAmounts(ctx context.Context, id int) (map[int]int64, error)
+	GetReservationPendingAmounts(ctx context.Context, id int) (map[int]int64, error)
+	AddReservationPayments(ctx context.Context, payments []*models.Payment, committed map[int]int64, holdUntil *time.Time) error
+}
+
+// NewReservationRepository creates and returns a new instance of ReservationRepository.
+// It accepts a database connection pool for executing database operations.
+func NewReservationRepository(db *pgxpool.Pool) *ReservationRepository {
+	return &ReservationRepository{db: db}
+}
+
+// CreateReservation inserts a reservation together with all of its bookings in one transaction,
+// so either every room is booked or none is. Rooms are locked in ID order to avoid deadlocks
+// with other group reservations; an overlap is reported as a *BookingConflictError and a taken
+// confirmation code as an error wrapping ErrConflict.
+func (r *ReservationRepository) CreateReservation(ctx context.Context, reservation *models.Reservation) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	roomIDs := make([]int, 0, len(reservation.Bookings))
+	for _, booking := range reservation.Bookings {
+		roomIDs = append(roomIDs, booking.RoomID)
+	}
+	sort.Ints(roomIDs)
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM rooms WHERE id = ANY($1) ORDER BY id FOR UPDATE`, roomIDs); err != nil {
+		return fmt.Errorf("failed to lock rooms: %w", err)
+	}
+
+	err = tx.QueryRow(ctx, `
+	INSERT INTO reservations (confirmation_code, property_id, lead_user_id, special_requests)
+	VALUES ($1, $2, $3, $4)
+	RETURNING id, created_at, updated_at
+	`, reservation.ConfirmationCode, reservation.PropertyID, reservation.LeadUserID, reservation.SpecialRequests).
+		Scan(&reservation.ID, &reservation.CreatedAt, &reservation.UpdatedAt)
+	if err != nil {
+		if hasPgCode(err, pgUniqueViolation) {
+			return fmt.Errorf("confirmation code %s is already in use: %w", reservation.ConfirmationCode, ErrConflict)
+		}
+		return fmt.Errorf("failed to create reservation: %w", err)
+	}
+
+	for i := range reservation.Bookings {
+		booking := &reservation.Bookings[i]
+		booking.ReservationID = &reservation.ID
+		if err := insertBooking(ctx, tx, booking); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit reservation: %w", err)
+	}
+	return nil
+}
+
+// GetReservation retrieves a reservation and its bookings ordered by arrival.
+// Totals are left for the service to compute.
+// Returns an error wrapping ErrNotFound if the reservation does not exist.
+func (r *ReservationRepository) GetReservation(ctx context.Context, id int) (*models.Reservation, error) {
+	reservation := &models.Reservation{ID: id}
+	err := r.db.QueryRow(ctx, `
+	SELECT confirmation_code, property_id, lead_user_id, special_requests, created_at, updated_at
+	FROM reservations
+	WHERE id = $1
+	`, id).Scan(&reservation.ConfirmationCode, &reservation.PropertyID, &reservation.LeadUserID, &reservation.SpecialRequests, &reservation.CreatedAt, &reservation.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("reservation %w", ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get reservation: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, `SELECT `+bookingColumns+` FROM bookings WHERE reservation_id = $1 ORDER BY check_in_date, id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reservation bookings: %w", err)
+	}
+	defer rows.Close()
+	reservation.Bookings = []models.Booking{}
+	for rows.Next() {
+		booking, err := scanBooking(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan booking: %w", err)
+		}
+		reservation.Bookings = append(reservation.Bookings, *booking)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list reservation bookings: %w", err)
+	}
+	return reservation, nil
+}
+
+// GetReservationPaidAmounts returns the paid amount in cents of each booking of a reservation,
+// keyed by booking ID. Bookings without paid payments are omitted.
+func (r *ReservationRepository) GetReservationPaidAmounts(ctx context.Context, id int) (map[int]int64, error) {
+	return r.sumReservationPayments(ctx, id, models.PaymentStatusPaid)
+}
+
+// GetReservationPendingAmounts returns the amount in cents of the payments still pending for
+// each booking of a reservation, keyed by booking ID. Bookings without pending payments are omitted.
+func (r *ReservationRepository) GetReservationPendingAmounts(ctx context.Context, id int) (map[int]int64, error) {
+	return r.sumReservationPayments(ctx, id, models.PaymentStatusPending)
+}
+
+// sumReservationPayments returns the amount in cents of the payments with status of each
+// booking of a reservation, keyed by booking ID.
+func (r *ReservationRepository) sumReservationPayments(ctx context.Context, id int, status string) (map[int]int64, error) {
+	rows, err := r.db.Query(ctx, `
+	SELECT p.booking_id, SUM(p.amount)
+	FROM payments p
+	JOIN bookings b ON b.id = p.booking_id
+	WHERE b.reservation_id = $1 AND p.status = $2
+	GROUP BY p.booking_id
+	`, id, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum reservation payments: %w", err)
+	}
+	defer rows.Close()
+	amounts := map[int]int64{}
+	for rows.Next() {
+		var bookingID int
+		var amount int64
+		if err := rows.Scan(&bookingID, &amount); err != nil {
+			return nil, fmt.Errorf("failed to scan reservation payment: %w", err)
+		}
+		amounts[bookingID] = amount
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to sum reservation payments: %w", err)
+	}
+	return amounts, nil
+}
+
+// AddReservationPayments inserts the per-booking payments of a reservation payment in one
+// transaction. committed holds the paid and pending amounts in cents the payments were split
+// against, keyed by booking ID; a booking paid for concurrently fails the whole payment with an
+// error wrapping ErrConflict. When holdUntil is set, each booking's hold is extended to it first,
+// so a booking whose hold already expired fails the whole payment the same way.
+func (r *ReservationRepository) AddReservationPayments(ctx context.Context, payments []*models.Payment, committed map[int]int64, holdUntil *time.Time) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, payment := range payments {
+		// Lock the booking so its payments cannot change before this one is added
+		var current int64
+		err := tx.QueryRow(ctx, `
+		SELECT COALESCE(SUM(p.amount), 0)
+		FROM (SELECT id FROM bookings WHERE id = $1 FOR UPDATE) b
+		LEFT JOIN payments p ON p.booking_id = b.id AND p.status IN ('paid', 'pending')
+		`, payment.BookingID).Scan(&current)
+		if err != nil {
+			return fmt.Errorf("failed to sum booking payments: %w", err)
+		}
+		if current != committed[payment.BookingID] {
+			return fmt.Errorf("booking %d was paid for concurrently: %w", payment.BookingID, ErrConflict)
+		}
+		if holdUntil != nil {
+			if err := extendBookingHold(ctx, tx, payment.BookingID, *holdUntil); err != nil {
+				return err
+			}
+		}
+		err = tx.QueryRow(ctx, `
+		INSERT INTO payments (booking_id, reservation_id, amount, payment_method, transaction_id, status)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+		`, payment.BookingID, payment.ReservationID, payment.Amount, payment.PaymentMethod, payment.TransactionID, payment.Status).
+			Scan(&payment.ID, &payment.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to add reservation payment: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit reservation payment: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"industry-api/internal/models"
+	"industry-api/internal/testsetup"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestReservationRepo_AllOrNothing(t *testing.T) {
+	_ = testsetup.LoadEnv()
+	dsn := os.Getenv("TEST_DB_URL")
+	if dsn == "" {
+		dsn = os.Getenv("DB_URL")
+	}
+	if dsn == "" {
+		t.Skip("DB_URL or TEST_DB_URL not set; skipping integration tests")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to db: %v", err)
+	}
+	defer pool.Close()
+
+	property := createTestProperty(t, ctx, pool, "G")
+	userID := createTestUser(t, ctx, pool, models.RoleGuest)
+	var rooms []*models.Room
+	for _, number := range []string{"GR-1", "GR-2"} {
+		room := &models.Room{PropertyID: property.ID, RoomNumber: number, RoomType: "Test", Description: "Group reservation test room", Price: 10, Capacity: 2, Floor: 1, Amenities: []string{"test"}}
+		if err := NewRoomRepository(pool).AddRoom(ctx, room); err != nil {
+			t.Fatalf("AddRoom failed: %v", err)
+		}
+		rooms = append(rooms, room)
+	}
+	code := "T" + time.Now().Format("150405.000")
+	defer func() {
+		if _, err := pool.Exec(ctx, "DELETE FROM bookings WHERE room_id IN ($1, $2)", rooms[0].ID, rooms[1].ID); err != nil {
+			t.Logf("warning: cleanup failed: %v", err)
+		}
+		if _, err := pool.Exec(ctx, "DELETE FROM reservations WHERE property_id = $1", property.ID); err != nil {
+			t.Logf("warning: cleanup failed: %v", err)
+		}
+		if _, err := pool.Exec(ctx, "DELETE FROM rooms WHERE id IN ($1, $2)", rooms[0].ID, rooms[1].ID); err != nil {
+			t.Logf("warning: cleanup failed: %v", err)
+		}
+	}()
+
+	checkIn := time.Now().AddDate(1, 4, 0).Truncate(24 * time.Hour)
+	booking := func(room *models.Room) models.Booking {
+		return models.Booking{PropertyID: property.ID, UserID: userID, RoomID: room.ID, CheckInDate: checkIn, CheckOutDate: checkIn.AddDate(0, 0, 1), Adults: 1, TotalAmount: 10, Status: models.BookingStatusPending, PaymentStatus: models.PaymentStatusPending}
+	}
+
+	// the second room is taken, so the first must not be booked either
+	taken := booking(rooms[1])
+	if _, err := NewBookingRepository(pool).AddBooking(ctx, &taken); err != nil {
+		t.Fatalf("AddBooking failed: %v", err)
+	}
+	repo := NewReservationRepository(pool)
+	failed := &models.Reservation{ConfirmationCode: code + "A", PropertyID: property.ID, LeadUserID: userID, Bookings: []models.Booking{booking(rooms[0]), booking(rooms[1])}}
+	var conflict *BookingConflictError
+	if err := repo.CreateReservation(ctx, failed); !errors.As(err, &conflict) || conflict.Conflict.BookingID != taken.ID {
+		t.Fatalf("expected a conflict with booking %d, got %v", taken.ID, err)
+	}
+	var count int
+	if err := pool.QueryRow(ctx, "SELECT COUNT(*) FROM bookings WHERE room_id = $1", rooms[0].ID).Scan(&count); err != nil || count != 0 {
+		t.Fatalf("expected no booking of the free room, got %d (%v)", count, err)
+	}
+
+	if _, err := pool.Exec(ctx, "DELETE FROM bookings WHERE id = $1", taken.ID); err != nil {
+		t.Fatalf("failed to free the room: %v", err)
+	}
+	reservation := &models.Reservation{ConfirmationCode: code + "B", PropertyID: property.ID, LeadUserID: userID, Bookings: []models.Booking{booking(rooms[0]), booking(rooms[1])}}
+	if err := repo.CreateReservation(ctx, reservation); err != nil {
+		t.Fatalf("CreateReservation failed: %v", err)
+	}
+	loaded, err := repo.GetReservation(ctx, reservation.ID)
+	if err != nil {
+		t.Fatalf("GetReservation failed: %v", err)
+	}
+	if loaded.ConfirmationCode != reservation.ConfirmationCode || len(loaded.Bookings) != 2 || *loaded.Bookings[0].ReservationID != reservation.ID {
+		t.Fatalf("unexpected reservation: %+v", loaded)
+	}
+
+	holdUntil := time.Now().Add(time.Hour)
+	payment := &models.Payment{BookingID: loaded.Bookings[0].ID, ReservationID: &reservation.ID, Amount: 1000, PaymentMethod: "card", Status: models.PaymentStatusPending}
+	if err := repo.AddReservationPayments(ctx, []*models.Payment{payment}, map[int]int64{}, &holdUntil); err != nil {
+		t.Fatalf("AddReservationPayments failed: %v", err)
+	}
+	pending, err := repo.GetReservationPendingAmounts(ctx, reservation.ID)
+	if err != nil || pending[payment.BookingID] != 1000 {
+		t.Fatalf("expected 1000 pending, got %v (err %v)", pending, err)
+	}
+	// A payment split against amounts that no longer hold is refused
+	stale := &models.Payment{BookingID: payment.BookingID, ReservationID: &reservation.ID, Amount: 1000, PaymentMethod: "card", Status: models.PaymentStatusPending}
+	if err := repo.AddReservationPayments(ctx, []*models.Payment{stale}, map[int]int64{}, nil); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected a conflict for a concurrent payment, got %v", err)
+	}
+	if _, err := pool.Exec(ctx, "DELETE FROM payments WHERE id = $1", payment.ID); err != nil {
+		t.Logf("warning: cleanup failed: %v", err)
+	}
+}
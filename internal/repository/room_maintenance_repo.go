@@ -27,12 +27,13 @@ func NewRoomMaintenanceRepository(db *pgxpool.Pool) *RoomMaintenanceRepository {
 
 // AddRoomMaintenance inserts a new room maintenance record into the database.
 // It schedules maintenance for a room with start date, end date, reason, and status.
+// The record is scoped to the property of the room.
 // Returns the created maintenance record with ID and creation timestamp, or an error if the operation fails.
 func (r *RoomMaintenanceRepository) AddRoomMaintenance(ctx context.Context, roomMaintenance *models.RoomMaintenance) (*models.RoomMaintenance, error) {
 	query := `
-    INSERT INTO room_maintenance (room_id, start_date, end_date, reason, status, created_by) 
-    VALUES ($1, $2, $3, $4, $5, $6) 
-    RETURNING id, created_at
+    INSERT INTO room_maintenance (property_id, room_id, start_date, end_date, reason, status, created_by) 
+    VALUES ((SELECT property_id FROM rooms WHERE id = $1), $1, $2, $3, $4, $5, $6) 
+    RETURNING id, property_id, created_at
     `
 
 	// Execute the insert query and scan the returned ID and timestamp
@@ -43,7 +44,7 @@ func (r *RoomMaintenanceRepository) AddRoomMaintenance(ctx context.Context, room
 		roomMaintenance.Reason,
 		roomMaintenance.Status,
 		roomMaintenance.CreatedBy,
-	).Scan(&roomMaintenance.ID, &roomMaintenance.CreatedAt)
+	).Scan(&roomMaintenance.ID, &roomMaintenance.PropertyID, &roomMaintenance.CreatedAt)
 
 	if err != nil {
 		return nil, err
@@ -6,7 +6,9 @@ import (
 	"context"
 	"fmt"
 	"industry-api/internal/models"
+	"strings"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -18,8 +20,9 @@ type RoomRepository struct {
 // RoomRepo defines the methods used by services for room data access.
 type RoomRepo interface {
 	AddRoom(ctx context.Context, room *models.Room) error
-	GetRoomsList(ctx context.Context) ([]*models.Room, error)
-	GetAvailableRooms(ctx context.Context) ([]*models.Room, error)
+	GetRoomsList(ctx context.Context, propertyIDs []int) ([]*models.Room, error)
+	GetAvailableRooms(ctx context.Context, propertyIDs []int) ([]*models.Room, error)
+	SearchRooms(ctx context.Context, filter models.RoomSearchFilter) (*models.RoomSearchResponse, error)
 }
 
 // NewRoomRepository creates and returns a new instance of RoomRepository.
@@ -31,15 +34,16 @@ func NewRoomRepository(db *pgxpool.Pool) *RoomRepository {
 // AddRoom inserts a new room record into the database.
 // It executes an INSERT query with room details and returns the generated room ID and timestamps.
 // The room is created with is_available set to TRUE by default.
-// Returns nil on success or an error if the database operation fails.
+// Returns an error wrapping ErrConflict if the room number is already used at the property,
+// ErrNotFound if the property does not exist, or another error if the database operation fails.
 func (r *RoomRepository) AddRoom(ctx context.Context, room *models.Room) error {
 	// SQL query to insert a new room record
 	query := `
     INSERT INTO rooms 
-        (room_number, room_type, description, price_per_night, capacity, floor, amenities, is_available)
+        (property_id, room_number, room_type, description, price_per_night, capacity, floor, amenities, is_available)
     VALUES 
-        ($1, $2, $3, $4, $5, $6, $7, TRUE)
-    RETURNING id, created_at, updated_at;
+        ($1, $2, $3, $4, $5, $6, $7, $8, TRUE)
+    RETURNING id, housekeeping_status, created_at, updated_at;
     `
 
 	fmt.Printf("Repository: Executing query with amenities: %v\n", room.Amenities)
@@ -49,6 +53,7 @@ func (r *RoomRepository) AddRoom(ctx context.Context, room *models.Room) error {
 	err := r.db.QueryRow(
 		ctx,
 		query,
+		room.PropertyID,  // Property the room belongs to
 		room.RoomNumber,  // Room number identifier
 		room.RoomType,    // Type of room
 		room.Description, // Room description
@@ -56,10 +61,16 @@ func (r *RoomRepository) AddRoom(ctx context.Context, room *models.Room) error {
 		room.Capacity,    // Guest capacity
 		room.Floor,       // Floor number
 		room.Amenities,   // List of amenities
-	).Scan(&room.ID, &room.CreatedAt, &room.UpdatedAt)
+	).Scan(&room.ID, &room.HousekeepingStatus, &room.CreatedAt, &room.UpdatedAt)
 
 	if err != nil {
 		fmt.Printf("Repository: Database error - %v\n", err)
+		if hasPgCode(err, pgUniqueViolation) {
+			return fmt.Errorf("room %s already exists at property %d: %w", room.RoomNumber, room.PropertyID, ErrConflict)
+		}
+		if hasPgCode(err, pgForeignKeyViolation) {
+			return fmt.Errorf("property %w", ErrNotFound)
+		}
 		return err
 	}
 
@@ -68,14 +79,16 @@ func (r *RoomRepository) AddRoom(ctx context.Context, room *models.Room) error {
 }
 
 // GetRoomsList retrieves all rooms from the database.
-// It returns a list of all rooms with their complete details.
+// It returns a list of all rooms with their complete details, limited to the given
+// properties unless propertyIDs is nil.
 // Returns a slice of room pointers or an error if the database query fails.
-func (r *RoomRepository) GetRoomsList(ctx context.Context) ([]*models.Room, error) {
+func (r *RoomRepository) GetRoomsList(ctx context.Context, propertyIDs []int) ([]*models.Room, error) {
 	query := `
-    SELECT id, room_number, room_type, description, price_per_night, capacity, floor, amenities, is_available, created_at, updated_at
+    SELECT id, property_id, room_number, room_type, description, price_per_night, capacity, floor, amenities, is_available, housekeeping_status, created_at, updated_at
     FROM rooms
+    WHERE ($1::int[] IS NULL OR property_id = ANY($1))
     `
-	rows, err := r.db.Query(ctx, query)
+	rows, err := r.db.Query(ctx, query, propertyIDs)
 	if err != nil {
 		return nil, err
 	}
@@ -86,6 +99,7 @@ func (r *RoomRepository) GetRoomsList(ctx context.Context) ([]*models.Room, erro
 		var room models.Room
 		err := rows.Scan(
 			&room.ID,
+			&room.PropertyID,
 			&room.RoomNumber,
 			&room.RoomType,
 			&room.Description,
@@ -94,6 +108,7 @@ func (r *RoomRepository) GetRoomsList(ctx context.Context) ([]*models.Room, erro
 			&room.Floor,
 			&room.Amenities, // This should now work with []string
 			&room.IsAvailable,
+			&room.HousekeepingStatus,
 			&room.CreatedAt,
 			&room.UpdatedAt,
 		)
@@ -107,15 +122,17 @@ func (r *RoomRepository) GetRoomsList(ctx context.Context) ([]*models.Room, erro
 }
 
 // GetAvailableRooms retrieves all rooms that are currently available for booking.
-// It filters for rooms where is_available is true.
+// It filters for rooms where is_available is true, limited to the given properties unless
+// propertyIDs is nil.
 // Returns a slice of available room pointers or an error if the database query fails.
-func (r *RoomRepository) GetAvailableRooms(ctx context.Context) ([]*models.Room, error) {
+func (r *RoomRepository) GetAvailableRooms(ctx context.Context, propertyIDs []int) ([]*models.Room, error) {
 	query := `
-	SELECT id, room_number, room_type, description, price_per_night, capacity, floor, amenities, is_available
+	SELECT id, property_id, room_number, room_type, description, price_per_night, capacity, floor, amenities, is_available, housekeeping_status
 	FROM rooms
 	WHERE is_available = true
+	  AND ($1::int[] IS NULL OR property_id = ANY($1))
 	`
-	rooms, err := r.db.Query(ctx, query)
+	rooms, err := r.db.Query(ctx, query, propertyIDs)
 	if err != nil {
 		return nil, err
 	}
@@ -124,7 +141,7 @@ func (r *RoomRepository) GetAvailableRooms(ctx context.Context) ([]*models.Room,
 	var roomsList []*models.Room
 	for rooms.Next() {
 		var room models.Room
-		err := rooms.Scan(&room.ID, &room.RoomNumber, &room.RoomType, &room.Description, &room.Price, &room.Capacity, &room.Floor, &room.Amenities, &room.IsAvailable)
+		err := rooms.Scan(&room.ID, &room.PropertyID, &room.RoomNumber, &room.RoomType, &room.Description, &room.Price, &room.Capacity, &room.Floor, &room.Amenities, &room.IsAvailable, &room.HousekeepingStatus)
 		if err != nil {
 			return nil, err
 		}
@@ -132,3 +149,195 @@ func (r *RoomRepository) GetAvailableRooms(ctx context.Context) ([]*models.Room,
 	}
 	return roomsList, nil
 }
+
+// roomSortColumns maps the public sort names accepted by SearchRooms to database columns.
+// Only whitelisted columns are ever interpolated into the ORDER BY clause.
+var roomSortColumns = map[string]string{
+	"price":       "price_per_night",
+	"capacity":    "capacity",
+	"floor":       "floor",
+	"room_number": "room_number",
+	"created_at":  "created_at",
+}
+
+// queryConditions accumulates WHERE clauses and their positional arguments for list queries.
+type queryConditions struct {
+	clauses []string
+	args    []interface{}
+}
+
+// add appends a clause containing a single %d placeholder for the argument position.
+func (c *queryConditions) add(clause string, arg interface{}) {
+	c.args = append(c.args, arg)
+	c.clauses = append(c.clauses, fmt.Sprintf(clause, len(c.args)))
+}
+
+// where renders the accumulated clauses as a WHERE clause (empty if there are none).
+func (c *queryConditions) where() string {
+	if len(c.clauses) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(c.clauses, " AND ")
+}
+
+// buildRoomConditions translates a search filter into WHERE clauses.
+// The amenity or room type filters can be skipped so that facet counts for a field
+// are computed against every other active filter (disjunctive faceting).
+func buildRoomConditions(filter models.RoomSearchFilter, skipAmenities, skipRoomType bool) *queryConditions {
+	conds := &queryConditions{}
+	if filter.PropertyIDs != nil {
+		conds.add("property_id = ANY($%d::int[])", filter.PropertyIDs)
+	}
+	if !skipAmenities && len(filter.AmenitiesAll) > 0 {
+		conds.add("amenities @> $%d::text[]", filter.AmenitiesAll)
+	}
+	if !skipAmenities && len(filter.AmenitiesAny) > 0 {
+		conds.add("amenities && $%d::text[]", filter.AmenitiesAny)
+	}
+	if !skipRoomType && filter.RoomType != "" {
+		conds.add("room_type = $%d", filter.RoomType)
+	}
+	if filter.MinPrice != nil {
+		conds.add("price_per_night >= $%d", *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		conds.add("price_per_night <= $%d", *filter.MaxPrice)
+	}
+	if filter.MinCapacity != nil {
+		conds.add("capacity >= $%d", *filter.MinCapacity)
+	}
+	if filter.Floor != nil {
+		conds.add("floor = $%d", *filter.Floor)
+	}
+	return conds
+}
+
+// SearchRooms retrieves a filtered, sorted and paginated list of rooms together with facet counts.
+// Amenity filters use the array containment (@>) and overlap (&&) operators so they can be
+// served by the GIN index on rooms.amenities.
+// Facet counts for amenities ignore the amenity filters and counts for room types ignore the
+// room type filter, so the client can show how many rooms each additional chip would match.
+func (r *RoomRepository) SearchRooms(ctx context.Context, filter models.RoomSearchFilter) (*models.RoomSearchResponse, error) {
+	conds := buildRoomConditions(filter, false, false)
+
+	// Count all matching rooms for pagination
+	var total int
+	countQuery := "SELECT COUNT(*) FROM rooms" + conds.where()
+	if err := r.db.QueryRow(ctx, countQuery, conds.args...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count rooms: %w", err)
+	}
+
+	// Resolve the sort column from the whitelist, defaulting to room number
+	column, ok := roomSortColumns[filter.Sort]
+	if !ok {
+		column = "room_number"
+	}
+	direction := "ASC"
+	if strings.EqualFold(filter.Order, "desc") {
+		direction = "DESC"
+	}
+
+	query := `
+	SELECT id, property_id, room_number, room_type, description, price_per_night, capacity, floor, amenities, is_available, housekeeping_status, created_at, updated_at
+	FROM rooms` + conds.where() +
+		fmt.Sprintf(" ORDER BY %s %s, id ASC LIMIT $%d OFFSET $%d", column, direction, len(conds.args)+1, len(conds.args)+2)
+	args := append(append([]interface{}{}, conds.args...), filter.Limit, pageOffset(filter.Page, filter.Limit))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rooms: %w", err)
+	}
+	rooms, err := r.scanRooms(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// Amenity facets: one row per amenity among rooms matching every non-amenity filter
+	amenityConds := buildRoomConditions(filter, true, false)
+	amenityFacets, err := r.facetCounts(ctx, `
+	SELECT a.amenity, COUNT(*)
+	FROM rooms CROSS JOIN LATERAL unnest(rooms.amenities) AS a(amenity)`+amenityConds.where()+`
+	GROUP BY a.amenity
+	ORDER BY COUNT(*) DESC, a.amenity`, amenityConds.args)
+	if err != nil {
+		return nil, err
+	}
+
+	// Room type facets: one row per type among rooms matching every non-type filter
+	typeConds := buildRoomConditions(filter, false, true)
+	typeFacets, err := r.facetCounts(ctx, `
+	SELECT room_type, COUNT(*)
+	FROM rooms`+typeConds.where()+`
+	GROUP BY room_type
+	ORDER BY room_type`, typeConds.args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.RoomSearchResponse{
+		Page:       filter.Page,
+		Limit:      filter.Limit,
+		Total:      total,
+		TotalPages: pageCount(total, filter.Limit),
+		Rooms:      rooms,
+		Facets: models.RoomFacets{
+			Amenities: amenityFacets,
+			RoomTypes: typeFacets,
+		},
+	}, nil
+}
+
+// facetCounts runs a "value, count" aggregation query and collects the results.
+func (r *RoomRepository) facetCounts(ctx context.Context, query string, args []interface{}) ([]models.FacetCount, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query room facets: %w", err)
+	}
+	defer rows.Close()
+
+	facets := []models.FacetCount{}
+	for rows.Next() {
+		var facet models.FacetCount
+		if err := rows.Scan(&facet.Value, &facet.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan room facet: %w", err)
+		}
+		facets = append(facets, facet)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating room facets: %w", err)
+	}
+	return facets, nil
+}
+
+// scanRooms scans full room rows and closes the result set.
+func (r *RoomRepository) scanRooms(rows pgx.Rows) ([]models.Room, error) {
+	defer rows.Close()
+
+	rooms := []models.Room{}
+	for rows.Next() {
+		var room models.Room
+		err := rows.Scan(
+			&room.ID,
+			&room.PropertyID,
+			&room.RoomNumber,
+			&room.RoomType,
+			&room.Description,
+			&room.Price,
+			&room.Capacity,
+			&room.Floor,
+			&room.Amenities,
+			&room.IsAvailable,
+			&room.HousekeepingStatus,
+			&room.CreatedAt,
+			&room.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan room: %w", err)
+		}
+		rooms = append(rooms, room)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rooms: %w", err)
+	}
+	return rooms, nil
+}
@@ -30,10 +30,12 @@ func TestRoomsRepo_CreateAndList(t *testing.T) {
 	defer pool.Close()
 
 	repo := NewRoomRepository(pool)
+	property := createTestProperty(t, ctx, pool, "R")
 
 	ts := time.Now().UnixNano()
 	roomNum := "T-" + time.Unix(0, ts).Format("150405")
 	room := &models.Room{
+		PropertyID:  property.ID,
 		RoomNumber:  roomNum,
 		RoomType:    "Test",
 		Description: "Integration test room",
@@ -50,7 +52,7 @@ func TestRoomsRepo_CreateAndList(t *testing.T) {
 		t.Fatalf("expected room ID to be set")
 	}
 
-	rooms, err := repo.GetRoomsList(ctx)
+	rooms, err := repo.GetRoomsList(ctx, []int{property.ID})
 	if err != nil {
 		t.Fatalf("GetRoomsList failed: %v", err)
 	}
@@ -70,3 +72,66 @@ func TestRoomsRepo_CreateAndList(t *testing.T) {
 		t.Logf("warning: cleanup failed: %v", err)
 	}
 }
+
+func TestRoomsRepo_SearchRooms(t *testing.T) {
+	_ = testsetup.LoadEnv()
+	dsn := os.Getenv("TEST_DB_URL")
+	if dsn == "" {
+		dsn = os.Getenv("DB_URL")
+	}
+	if dsn == "" {
+		t.Skip("DB_URL or TEST_DB_URL not set; skipping integration tests")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to db: %v", err)
+	}
+	defer pool.Close()
+
+	repo := NewRoomRepository(pool)
+	property := createTestProperty(t, ctx, pool, "R")
+
+	roomType := "SearchTest-" + time.Now().Format("150405")
+	room := &models.Room{
+		PropertyID:  property.ID,
+		RoomNumber:  roomType + "-1",
+		RoomType:    roomType,
+		Description: "Search integration test room",
+		Price:       10,
+		Capacity:    2,
+		Floor:       1,
+		Amenities:   []string{"wifi", "search-test-amenity"},
+	}
+	if err := repo.AddRoom(ctx, room); err != nil {
+		t.Fatalf("AddRoom failed: %v", err)
+	}
+	defer func() {
+		if _, err := pool.Exec(ctx, "DELETE FROM rooms WHERE room_type = $1", roomType); err != nil {
+			t.Logf("warning: cleanup failed: %v", err)
+		}
+	}()
+
+	result, err := repo.SearchRooms(ctx, models.RoomSearchFilter{
+		AmenitiesAll: []string{"search-test-amenity"},
+		RoomType:     roomType,
+		Page:         1,
+		Limit:        10,
+	})
+	if err != nil {
+		t.Fatalf("SearchRooms failed: %v", err)
+	}
+	if result.Total != 1 || len(result.Rooms) != 1 {
+		t.Fatalf("expected exactly one matching room, got %+v", result)
+	}
+	found := false
+	for _, facet := range result.Facets.Amenities {
+		if facet.Value == "search-test-amenity" && facet.Count == 1 {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected amenity facet for search-test-amenity, got %+v", result.Facets.Amenities)
+	}
+}
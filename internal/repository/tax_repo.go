@@ -0,0 +1,173 @@
+// Package repository provides database access layer implementations.
+// Repositories handle all direct database operations using SQL queries.
+package repository
+
+import (
+	"context"
+	"fmt"
+	"industry-api/internal/models"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TaxRepository provides database access for tax rates and the tax report.
+type TaxRepository struct {
+	db *pgxpool.Pool // Database connection pool
+}
+
+// TaxRepo defines the methods used by services to manage tax rates.
+type TaxRepo interface {
+	CreateTaxRate(ctx context.Context, rate *models.TaxRate) error
+	ListTaxRates(ctx context.Context, propertyIDs []int) ([]models.TaxRate, error)
+	DeleteTaxRate(ctx context.Context, id int) error
+	TaxReport(ctx context.Context, from, to time.Time, propertyIDs []int) ([]models.TaxReportLine, error)
+}
+
+// taxRateColumns is the column list scanned by scanTaxRate.
+const taxRateColumns = `id, property_id, code, name, basis, period, rate, inclusive, children_exempt, valid_from, valid_to, created_at`
+
+// NewTaxRepository creates and returns a new instance of TaxRepository.
+// It accepts a database connection pool for executing database operations.
+func NewTaxRepository(db *pgxpool.Pool) *TaxRepository {
+	return &TaxRepository{db: db}
+}
+
+// CreateTaxRate inserts a tax rate and fills in its ID and creation time.
+// Returns an error wrapping ErrNotFound if the property does not exist.
+func (r *TaxRepository) CreateTaxRate(ctx context.Context, rate *models.TaxRate) error {
+	err := r.db.QueryRow(ctx, `
+	INSERT INTO tax_rates (property_id, code, name, basis, period, rate, inclusive, children_exempt, valid_from, valid_to)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	RETURNING id, created_at
+	`, rate.PropertyID, rate.Code, rate.Name, rate.Basis, rate.Period, rate.Rate, rate.Inclusive, rate.ChildrenExempt, rate.ValidFrom, rate.ValidTo).
+		Scan(&rate.ID, &rate.CreatedAt)
+	if err != nil {
+		if hasPgCode(err, pgForeignKeyViolation) {
+			return fmt.Errorf("property %w", ErrNotFound)
+		}
+		return fmt.Errorf("failed to create tax rate: %w", err)
+	}
+	return nil
+}
+
+// ListTaxRates returns the tax rates of the given properties (nil for all) together with the
+// rates that apply to every property, in the order they are applied.
+func (r *TaxRepository) ListTaxRates(ctx context.Context, propertyIDs []int) ([]models.TaxRate, error) {
+	return queryTaxRates(ctx, r.db, `
+	SELECT `+taxRateColumns+`
+	FROM tax_rates
+	WHERE property_id IS NULL OR $1::int[] IS NULL OR property_id = ANY($1)
+	ORDER BY id
+	`, propertyIDs)
+}
+
+// DeleteTaxRate removes a tax rate. Tax lines already charged with it are kept.
+// Returns an error wrapping ErrNotFound if the rate does not exist.
+func (r *TaxRepository) DeleteTaxRate(ctx context.Context, id int) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM tax_rates WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete tax rate: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("tax rate %w", ErrNotFound)
+	}
+	return nil
+}
+
+// TaxReport sums the tax lines of the bookings of the given properties (nil for all) arriving
+// between from and to (inclusive), per property, code and inclusiveness. Cancelled bookings and
+// no-shows are left out.
+func (r *TaxRepository) TaxReport(ctx context.Context, from, to time.Time, propertyIDs []int) ([]models.TaxReportLine, error) {
+	rows, err := r.db.Query(ctx, `
+	SELECT b.property_id, t.code, t.inclusive, COUNT(DISTINCT b.id), SUM(t.amount)
+	FROM booking_taxes t
+	JOIN bookings b ON b.id = t.booking_id
+	WHERE b.check_in_date BETWEEN $1 AND $2
+	  AND b.status NOT IN ('cancelled', 'no_show')
+	  AND ($3::int[] IS NULL OR b.property_id = ANY($3))
+	GROUP BY b.property_id, t.code, t.inclusive
+	ORDER BY b.property_id, t.code, t.inclusive
+	`, from, to, propertyIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build tax report: %w", err)
+	}
+	defer rows.Close()
+	lines := []models.TaxReportLine{}
+	for rows.Next() {
+		var line models.TaxReportLine
+		if err := rows.Scan(&line.PropertyID, &line.Code, &line.Inclusive, &line.Bookings, &line.Amount); err != nil {
+			return nil, fmt.Errorf("failed to scan tax report line: %w", err)
+		}
+		lines = append(lines, line)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to build tax report: %w", err)
+	}
+	return lines, nil
+}
+
+// saveBookingTaxes replaces the tax lines of a booking with those of its price breakdown as part
+// of the caller's transaction.
+func saveBookingTaxes(ctx context.Context, tx dbExecutor, bookingID int, breakdown *models.PriceBreakdown) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM booking_taxes WHERE booking_id = $1`, bookingID); err != nil {
+		return fmt.Errorf("failed to clear booking taxes: %w", err)
+	}
+	if breakdown == nil {
+		return nil
+	}
+	for _, line := range breakdown.Taxes {
+		if _, err := tx.Exec(ctx, `
+		INSERT INTO booking_taxes (booking_id, tax_rate_id, code, description, inclusive, amount)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		`, bookingID, line.TaxRateID, line.Code, line.Description, line.Inclusive, line.Amount); err != nil {
+			return fmt.Errorf("failed to record booking tax: %w", err)
+		}
+	}
+	return nil
+}
+
+// queryTaxRates runs a query selecting taxRateColumns and scans every row.
+func queryTaxRates(ctx context.Context, db dbExecutor, query string, args ...interface{}) ([]models.TaxRate, error) {
+	rows, err := db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tax rates: %w", err)
+	}
+	defer rows.Close()
+	rates := []models.TaxRate{}
+	for rows.Next() {
+		rate, err := scanTaxRate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tax rate: %w", err)
+		}
+		rates = append(rates, *rate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list tax rates: %w", err)
+	}
+	return rates, nil
+}
+
+// scanTaxRate scans a row selected with taxRateColumns.
+func scanTaxRate(row pgx.Row) (*models.TaxRate, error) {
+	var rate models.TaxRate
+	err := row.Scan(
+		&rate.ID,
+		&rate.PropertyID,
+		&rate.Code,
+		&rate.Name,
+		&rate.Basis,
+		&rate.Period,
+		&rate.Rate,
+		&rate.Inclusive,
+		&rate.ChildrenExempt,
+		&rate.ValidFrom,
+		&rate.ValidTo,
+		&rate.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"industry-api/internal/models"
+	"industry-api/internal/testsetup"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestTaxRepo_StoresBookingTaxesForReport(t *testing.T) {
+	_ = testsetup.LoadEnv()
+	dsn := os.Getenv("TEST_DB_URL")
+	if dsn == "" {
+		dsn = os.Getenv("DB_URL")
+	}
+	if dsn == "" {
+		t.Skip("DB_URL or TEST_DB_URL not set; skipping integration tests")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to db: %v", err)
+	}
+	defer pool.Close()
+
+	property := createTestProperty(t, ctx, pool, "X")
+	userID := createTestUser(t, ctx, pool, models.RoleGuest)
+	room := &models.Room{PropertyID: property.ID, RoomNumber: "TX-1", RoomType: "Test", Description: "Tax test room", Price: 100, Capacity: 2, Floor: 1, Amenities: []string{"test"}}
+	if err := NewRoomRepository(pool).AddRoom(ctx, room); err != nil {
+		t.Fatalf("AddRoom failed: %v", err)
+	}
+	checkIn := time.Now().AddDate(1, 8, 0).Truncate(24 * time.Hour)
+	validTo := checkIn.AddDate(0, 0, -1)
+	taxes := NewTaxRepository(pool)
+	current := &models.TaxRate{PropertyID: &property.ID, Code: "vat", Name: "VAT", Basis: models.TaxPercentage, Period: models.TaxPerNight, Rate: 10}
+	expired := &models.TaxRate{PropertyID: &property.ID, Code: "old", Name: "Old fee", Basis: models.TaxPerRoom, Period: models.TaxPerStay, Rate: 5, ValidTo: &validTo}
+	for _, rate := range []*models.TaxRate{current, expired} {
+		if err := taxes.CreateTaxRate(ctx, rate); err != nil {
+			t.Fatalf("CreateTaxRate failed: %v", err)
+		}
+	}
+	defer func() {
+		if _, err := pool.Exec(ctx, "DELETE FROM bookings WHERE room_id = $1", room.ID); err != nil {
+			t.Logf("warning: cleanup failed: %v", err)
+		}
+		if _, err := pool.Exec(ctx, "DELETE FROM tax_rates WHERE property_id = $1", property.ID); err != nil {
+			t.Logf("warning: cleanup failed: %v", err)
+		}
+	}()
+
+	repo := NewBookingRepository(pool)
+	rates, err := repo.GetApplicableTaxRates(ctx, property.ID, checkIn, checkIn.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("GetApplicableTaxRates failed: %v", err)
+	}
+	for _, rate := range rates {
+		if rate.ID == expired.ID {
+			t.Fatalf("expected the expired rate to be left out, got %+v", rates)
+		}
+	}
+
+	breakdown := &models.PriceBreakdown{Nights: 1, Lines: []models.PriceLine{{Code: "room", Quantity: 1, UnitPrice: 100, Amount: 100}},
+		Taxes: []models.TaxLine{{TaxRateID: current.ID, Code: "vat", Description: "VAT 10%", Quantity: 1, Rate: 10, Amount: 10}}, TaxTotal: 10, Total: 110}
+	booking := &models.Booking{PropertyID: property.ID, UserID: userID, RoomID: room.ID, CheckInDate: checkIn, CheckOutDate: checkIn.AddDate(0, 0, 1), Adults: 1, TotalAmount: 110, PriceBreakdown: breakdown, Status: models.BookingStatusConfirmed, PaymentStatus: models.PaymentStatusPending}
+	if _, err := repo.AddBooking(ctx, booking); err != nil {
+		t.Fatalf("AddBooking failed: %v", err)
+	}
+
+	report, err := taxes.TaxReport(ctx, checkIn, checkIn, []int{property.ID})
+	if err != nil {
+		t.Fatalf("TaxReport failed: %v", err)
+	}
+	if len(report) != 1 || report[0].Code != "vat" || report[0].Bookings != 1 || report[0].Amount != 10 {
+		t.Fatalf("unexpected tax report: %+v", report)
+	}
+}
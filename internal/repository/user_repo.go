@@ -19,7 +19,7 @@ type UserRepo interface {
 	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
 	GetUserByID(ctx context.Context, id int) (*models.User, error)
 	UpdateUserStatus(ctx context.Context, id int, isActive bool) (*models.User, error)
-	GetUserList(ctx context.Context, role string, isActive *bool, search string, page, limit int) (*models.UserListResponse, error)
+	GetUserList(ctx context.Context, role string, isActive *bool, search string, propertyIDs []int, page, limit int) (*models.UserListResponse, error)
 }
 
 // UserRepository provides database access for user operations.
@@ -74,13 +74,17 @@ func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*mod
 
 }
 
+// userPropertyIDsColumn selects the sorted property assignments of a user as an array.
+const userPropertyIDsColumn = `ARRAY(SELECT up.property_id FROM user_properties up WHERE up.user_id = users.id ORDER BY up.property_id)`
+
 // GetUserList retrieves a paginated list of users with optional filtering.
 // It supports filtering by role, active status, and search term (name/email).
+// If propertyIDs is not nil, only users assigned to at least one of those properties are returned.
 // It also supports pagination - if limit is 0, returns all records without pagination.
-func (r *UserRepository) GetUserList(ctx context.Context, role string, isActive *bool, search string, page, limit int) (*models.UserListResponse, error) {
+func (r *UserRepository) GetUserList(ctx context.Context, role string, isActive *bool, search string, propertyIDs []int, page, limit int) (*models.UserListResponse, error) {
 	// Build dynamic query
 	baseQuery := `
-		SELECT id, name, email, phone, role, is_active, created_at, updated_at 
+		SELECT id, name, email, phone, role, is_active, ` + userPropertyIDsColumn + `, created_at, updated_at 
 		FROM users 
 		WHERE 1=1
 	`
@@ -112,6 +116,13 @@ func (r *UserRepository) GetUserList(ctx context.Context, role string, isActive
 		argPos++
 	}
 
+	// Add property condition if the caller is limited to some properties
+	if propertyIDs != nil {
+		conditions = append(conditions, fmt.Sprintf("id IN (SELECT user_id FROM user_properties WHERE property_id = ANY($%d::int[]))", argPos))
+		args = append(args, propertyIDs)
+		argPos++
+	}
+
 	// Add WHERE conditions if any
 	if len(conditions) > 0 {
 		whereClause := " AND " + strings.Join(conditions, " AND ")
@@ -179,14 +190,7 @@ func (r *UserRepository) GetUserList(ctx context.Context, role string, isActive
 
 // Helper function to calculate total pages
 func (r *UserRepository) calculateTotalPages(total, limit int) int {
-	if limit == 0 || total == 0 {
-		return 1
-	}
-	pages := total / limit
-	if total%limit > 0 {
-		pages++
-	}
-	return pages
+	return pageCount(total, limit)
 }
 
 // Enhanced helper function to scan users from rows
@@ -201,6 +205,7 @@ func (r *UserRepository) scanUsers(rows pgx.Rows) ([]models.User, error) {
 			&user.Phone,
 			&user.Role,
 			&user.IsActive,
+			&user.PropertyIDs,
 			&user.CreatedAt,
 			&user.UpdatedAt,
 		)
@@ -217,7 +222,7 @@ func (r *UserRepository) scanUsers(rows pgx.Rows) ([]models.User, error) {
 }
 
 func (r *UserRepository) GetUserByID(ctx context.Context, id int) (*models.User, error) {
-	query := `SELECT id, name, email, password_hash, phone, role, created_at
+	query := `SELECT id, name, email, password_hash, phone, role, ` + userPropertyIDsColumn + `, created_at
 		FROM users
 		WHERE id = $1`
 	var user models.User
@@ -228,6 +233,7 @@ func (r *UserRepository) GetUserByID(ctx context.Context, id int) (*models.User,
 		&user.Password,
 		&user.Phone,
 		&user.Role,
+		&user.PropertyIDs,
 		&user.CreatedAt,
 	)
 	if err != nil {
@@ -0,0 +1,298 @@
+// Package repository provides database access layer implementations.
+// Repositories handle all direct database operations using SQL queries.
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"industry-api/internal/models"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// WaitlistRepository provides database access for the waitlist.
+type WaitlistRepository struct {
+	db *pgxpool.Pool // Database connection pool
+}
+
+// WaitlistRepo defines the methods used by services for the waitlist.
+type WaitlistRepo interface {
+	AddWaitlistEntry(ctx context.Context, entry *models.WaitlistEntry) error
+	GetWaitlistEntry(ctx context.Context, id int) (*models.WaitlistEntry, error)
+	ListWaitlistEntries(ctx context.Context, userID int) ([]models.WaitlistEntry, error)
+	CancelWaitlistEntry(ctx context.Context, id int) (*models.WaitlistEntry, error)
+	ExpireWaitlistOffers(ctx context.Context, now time.Time) (int, error)
+	OfferWaitlistRooms(ctx context.Context, expiresAt time.Time, limit int) ([]models.WaitlistEntry, error)
+	ClaimWaitlistOffer(ctx context.Context, id int, booking *models.Booking, now time.Time) error
+}
+
+// waitlistColumns is the column list scanned by scanWaitlistEntry.
+const waitlistColumns = `id, property_id, user_id, room_type, check_in_date, check_out_date, adults, children, status, offered_room_id, offer_expires_at, booking_id, created_at, updated_at`
+
+// NewWaitlistRepository creates and returns a new instance of WaitlistRepository.
+// It accepts a database connection pool for executing database operations.
+func NewWaitlistRepository(db *pgxpool.Pool) *WaitlistRepository {
+	return &WaitlistRepository{db: db}
+}
+
+// AddWaitlistEntry inserts a waiting entry and fills in its ID, status and timestamps.
+func (r *WaitlistRepository) AddWaitlistEntry(ctx context.Context, entry *models.WaitlistEntry) error {
+	err := r.db.QueryRow(ctx, `
+	INSERT INTO waitlist_entries (property_id, user_id, room_type, check_in_date, check_out_date, adults, children)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	RETURNING id, status, created_at, updated_at
+	`, entry.PropertyID, entry.UserID, entry.RoomType, entry.CheckInDate, entry.CheckOutDate, entry.Adults, entry.Children).
+		Scan(&entry.ID, &entry.Status, &entry.CreatedAt, &entry.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add waitlist entry: %w", err)
+	}
+	return nil
+}
+
+// GetWaitlistEntry retrieves a waitlist entry by its ID.
+// Returns an error wrapping ErrNotFound if the entry does not exist.
+func (r *WaitlistRepository) GetWaitlistEntry(ctx context.Context, id int) (*models.WaitlistEntry, error) {
+	entry, err := scanWaitlistEntry(r.db.QueryRow(ctx, `SELECT `+waitlistColumns+` FROM waitlist_entries WHERE id = $1`, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("waitlist entry %w", ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get waitlist entry: %w", err)
+	}
+	return entry, nil
+}
+
+// ListWaitlistEntries returns a user's waitlist entries, newest first.
+func (r *WaitlistRepository) ListWaitlistEntries(ctx context.Context, userID int) ([]models.WaitlistEntry, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+waitlistColumns+` FROM waitlist_entries WHERE user_id = $1 ORDER BY created_at DESC, id DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list waitlist entries: %w", err)
+	}
+	defer rows.Close()
+	entries := []models.WaitlistEntry{}
+	for rows.Next() {
+		entry, err := scanWaitlistEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan waitlist entry: %w", err)
+		}
+		entries = append(entries, *entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list waitlist entries: %w", err)
+	}
+	return entries, nil
+}
+
+// CancelWaitlistEntry takes a waiting or offered entry off the waitlist, releasing any offered room.
+// Returns an error wrapping ErrConflict if the entry was already booked, expired or cancelled.
+func (r *WaitlistRepository) CancelWaitlistEntry(ctx context.Context, id int) (*models.WaitlistEntry, error) {
+	entry, err := scanWaitlistEntry(r.db.QueryRow(ctx, `
+	UPDATE waitlist_entries
+	SET status = 'cancelled', offered_room_id = NULL, offer_expires_at = NULL, updated_at = NOW()
+	WHERE id = $1 AND status IN ('waiting', 'offered')
+	RETURNING `+waitlistColumns, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("waitlist entry can no longer be cancelled: %w", ErrConflict)
+		}
+		return nil, fmt.Errorf("failed to cancel waitlist entry: %w", err)
+	}
+	return entry, nil
+}
+
+// ExpireWaitlistOffers marks offers that lapsed before now as expired, which frees their rooms
+// for the next guests in line. Returns the number of offers expired.
+func (r *WaitlistRepository) ExpireWaitlistOffers(ctx context.Context, now time.Time) (int, error) {
+	tag, err := r.db.Exec(ctx, `
+	UPDATE waitlist_entries
+	SET status = 'expired', offered_room_id = NULL, updated_at = NOW()
+	WHERE status = 'offered' AND offer_expires_at < $1
+	`, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire waitlist offers: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// OfferWaitlistRooms walks up to limit waiting entries in the order they joined and reserves a
+// free room for each entry that has one until expiresAt. A room is free when it is on sale, not
+// out of order, big enough and neither booked, under maintenance nor offered to another entry
+// for any of the nights. Entries and rooms are claimed with FOR UPDATE SKIP LOCKED so several
+// API instances can make offers at once without offering the same room twice.
+// Returns the entries that received an offer.
+func (r *WaitlistRepository) OfferWaitlistRooms(ctx context.Context, expiresAt time.Time, limit int) ([]models.WaitlistEntry, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+	SELECT `+waitlistColumns+`
+	FROM waitlist_entries
+	WHERE status = 'waiting' AND check_in_date >= CURRENT_DATE
+	ORDER BY created_at, id
+	LIMIT $1
+	FOR UPDATE SKIP LOCKED
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find waiting entries: %w", err)
+	}
+	var waiting []models.WaitlistEntry
+	for rows.Next() {
+		entry, err := scanWaitlistEntry(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan waitlist entry: %w", err)
+		}
+		waiting = append(waiting, *entry)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to find waiting entries: %w", err)
+	}
+
+	offered := []models.WaitlistEntry{}
+	for _, entry := range waiting {
+		var roomID int
+		err := tx.QueryRow(ctx, `
+		SELECT r.id
+		FROM rooms r
+		WHERE r.property_id = $1
+		  AND r.room_type = $2
+		  AND r.capacity >= $3
+		  AND r.is_available
+		  AND r.housekeeping_status <> 'out_of_order'
+		  AND NOT EXISTS (
+		      SELECT 1 FROM bookings b
+		      WHERE b.room_id = r.id
+		        AND b.status NOT IN ('cancelled', 'no_show')
+		        AND daterange(b.check_in_date::date, b.check_out_date::date, '[)') && daterange($4::date, $5::date, '[)'))
+		  AND NOT EXISTS (
+		      SELECT 1 FROM room_maintenance m
+		      WHERE m.room_id = r.id
+		        AND m.status NOT IN ('completed', 'cancelled')
+		        AND daterange(m.start_date::date, m.end_date::date, '[]') && daterange($4::date, $5::date, '[)'))
+		  AND NOT EXISTS (
+		      SELECT 1 FROM external_blocks x
+		      WHERE x.room_id = r.id
+		        AND daterange(x.start_date, x.end_date, '[)') && daterange($4::date, $5::date, '[)'))
+		  AND NOT EXISTS (
+		      SELECT 1 FROM waitlist_entries w
+		      WHERE w.offered_room_id = r.id
+		        AND w.status = 'offered'
+		        AND daterange(w.check_in_date, w.check_out_date, '[)') && daterange($4::date, $5::date, '[)'))
+		ORDER BY r.room_number
+		LIMIT 1
+		FOR UPDATE OF r SKIP LOCKED
+		`, entry.PropertyID, entry.RoomType, entry.Adults+entry.Children, entry.CheckInDate, entry.CheckOutDate).Scan(&roomID)
+		if err == pgx.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to find a free room: %w", err)
+		}
+
+		updated, err := scanWaitlistEntry(tx.QueryRow(ctx, `
+		UPDATE waitlist_entries
+		SET status = 'offered', offered_room_id = $2, offer_expires_at = $3, updated_at = NOW()
+		WHERE id = $1
+		RETURNING `+waitlistColumns, entry.ID, roomID, expiresAt))
+		if err != nil {
+			return nil, fmt.Errorf("failed to offer room: %w", err)
+		}
+		offered = append(offered, *updated)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit waitlist offers: %w", err)
+	}
+	return offered, nil
+}
+
+// ClaimWaitlistOffer books the room offered to a waitlist entry and marks the entry booked.
+// The offer must still be open at now; otherwise an error wrapping ErrConflict is returned.
+// If the room was booked by someone else in the meantime, the entry goes back to waiting in its
+// original place in line and a *BookingConflictError is returned.
+func (r *WaitlistRepository) ClaimWaitlistOffer(ctx context.Context, id int, booking *models.Booking, now time.Time) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var open bool
+	err = tx.QueryRow(ctx, `
+	SELECT status = 'offered' AND offer_expires_at >= $2
+	FROM waitlist_entries
+	WHERE id = $1
+	FOR UPDATE
+	`, id, now).Scan(&open)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("waitlist entry %w", ErrNotFound)
+		}
+		return fmt.Errorf("failed to lock waitlist entry: %w", err)
+	}
+	if !open {
+		return fmt.Errorf("waitlist offer is no longer open: %w", ErrConflict)
+	}
+
+	if err := insertBooking(ctx, tx, booking); err != nil {
+		var conflict *BookingConflictError
+		if !errors.As(err, &conflict) {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `
+		UPDATE waitlist_entries
+		SET status = 'waiting', offered_room_id = NULL, offer_expires_at = NULL, updated_at = NOW()
+		WHERE id = $1
+		`, id); err != nil {
+			return fmt.Errorf("failed to return entry to the waitlist: %w", err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return fmt.Errorf("failed to commit waitlist entry: %w", err)
+		}
+		return conflict
+	}
+
+	if _, err := tx.Exec(ctx, `
+	UPDATE waitlist_entries
+	SET status = 'booked', booking_id = $2, offer_expires_at = NULL, updated_at = NOW()
+	WHERE id = $1
+	`, id, booking.ID); err != nil {
+		return fmt.Errorf("failed to mark waitlist entry booked: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit waitlist claim: %w", err)
+	}
+	return nil
+}
+
+// scanWaitlistEntry scans a row selected with waitlistColumns.
+func scanWaitlistEntry(row pgx.Row) (*models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	err := row.Scan(
+		&entry.ID,
+		&entry.PropertyID,
+		&entry.UserID,
+		&entry.RoomType,
+		&entry.CheckInDate,
+		&entry.CheckOutDate,
+		&entry.Adults,
+		&entry.Children,
+		&entry.Status,
+		&entry.OfferedRoomID,
+		&entry.OfferExpiresAt,
+		&entry.BookingID,
+		&entry.CreatedAt,
+		&entry.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"industry-api/internal/models"
+	"industry-api/internal/testsetup"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestWaitlistRepo_OffersFreedRoomFirstComeFirstServed(t *testing.T) {
+	_ = testsetup.LoadEnv()
+	dsn := os.Getenv("TEST_DB_URL")
+	if dsn == "" {
+		dsn = os.Getenv("DB_URL")
+	}
+	if dsn == "" {
+		t.Skip("DB_URL or TEST_DB_URL not set; skipping integration tests")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to db: %v", err)
+	}
+	defer pool.Close()
+
+	property := createTestProperty(t, ctx, pool, "W")
+	userID := createTestUser(t, ctx, pool, models.RoleGuest)
+	roomType := "Waitlist " + time.Now().Format("150405.000000")
+	room := &models.Room{PropertyID: property.ID, RoomNumber: "WL-1", RoomType: roomType, Description: "Waitlist test room", Price: 10, Capacity: 2, Floor: 1, Amenities: []string{"test"}}
+	if err := NewRoomRepository(pool).AddRoom(ctx, room); err != nil {
+		t.Fatalf("AddRoom failed: %v", err)
+	}
+	defer func() {
+		if _, err := pool.Exec(ctx, "DELETE FROM waitlist_entries WHERE property_id = $1", property.ID); err != nil {
+			t.Logf("warning: cleanup failed: %v", err)
+		}
+		if _, err := pool.Exec(ctx, "DELETE FROM bookings WHERE room_id = $1", room.ID); err != nil {
+			t.Logf("warning: cleanup failed: %v", err)
+		}
+		if _, err := pool.Exec(ctx, "DELETE FROM rooms WHERE id = $1", room.ID); err != nil {
+			t.Logf("warning: cleanup failed: %v", err)
+		}
+	}()
+
+	checkIn := time.Now().AddDate(1, 5, 0).Truncate(24 * time.Hour)
+	booking := func() *models.Booking {
+		return &models.Booking{PropertyID: property.ID, UserID: userID, RoomID: room.ID, CheckInDate: checkIn, CheckOutDate: checkIn.AddDate(0, 0, 2), Adults: 1, TotalAmount: 20, Status: models.BookingStatusPending, PaymentStatus: models.PaymentStatusPending}
+	}
+	taken, err := NewBookingRepository(pool).AddBooking(ctx, booking())
+	if err != nil {
+		t.Fatalf("AddBooking failed: %v", err)
+	}
+
+	repo := NewWaitlistRepository(pool)
+	var entries []*models.WaitlistEntry
+	for i := 0; i < 2; i++ {
+		entry := &models.WaitlistEntry{PropertyID: property.ID, UserID: userID, RoomType: roomType, CheckInDate: checkIn, CheckOutDate: checkIn.AddDate(0, 0, 2), Adults: 1}
+		if err := repo.AddWaitlistEntry(ctx, entry); err != nil {
+			t.Fatalf("AddWaitlistEntry failed: %v", err)
+		}
+		entries = append(entries, entry)
+	}
+	expiresAt := time.Now().Add(time.Hour)
+	offeredTo := func() []int {
+		offers, err := repo.OfferWaitlistRooms(ctx, expiresAt, 1000)
+		if err != nil {
+			t.Fatalf("OfferWaitlistRooms failed: %v", err)
+		}
+		var ids []int
+		for _, offer := range offers {
+			if offer.PropertyID == property.ID {
+				ids = append(ids, offer.ID)
+			}
+		}
+		return ids
+	}
+
+	// the only room is booked, so nobody gets an offer
+	if ids := offeredTo(); len(ids) != 0 {
+		t.Fatalf("expected no offers while the room is booked, got %v", ids)
+	}
+	// once the booking is cancelled the room goes to the first guest in line only
+	if _, err := pool.Exec(ctx, "UPDATE bookings SET status = 'cancelled' WHERE id = $1", taken.ID); err != nil {
+		t.Fatalf("failed to cancel booking: %v", err)
+	}
+	if ids := offeredTo(); len(ids) != 1 || ids[0] != entries[0].ID {
+		t.Fatalf("expected an offer for entry %d only, got %v", entries[0].ID, ids)
+	}
+
+	claim := booking()
+	if err := repo.ClaimWaitlistOffer(ctx, entries[0].ID, claim, time.Now()); err != nil {
+		t.Fatalf("ClaimWaitlistOffer failed: %v", err)
+	}
+	claimed, err := repo.GetWaitlistEntry(ctx, entries[0].ID)
+	if err != nil {
+		t.Fatalf("GetWaitlistEntry failed: %v", err)
+	}
+	if claimed.Status != models.WaitlistBooked || claimed.BookingID == nil || *claimed.BookingID != claim.ID {
+		t.Fatalf("expected the entry booked with booking %d, got %+v", claim.ID, claimed)
+	}
+	// a second claim of the same offer is refused
+	if err := repo.ClaimWaitlistOffer(ctx, entries[0].ID, booking(), time.Now()); err == nil {
+		t.Fatal("expected a second claim to fail")
+	}
+}
@@ -0,0 +1,79 @@
+// Package service provides business logic layer implementations.
+// Services contain validation logic and business rules before delegating to repositories.
+package service
+
+import (
+	"fmt"
+	"industry-api/internal/models"
+	"os"
+	"strconv"
+	"time"
+)
+
+// BookingConfig holds the configurable rules of the booking service.
+type BookingConfig struct {
+	CancellationPolicy models.CancellationPolicy // Penalties applied by CancelBooking
+	HoldTTL            time.Duration             // How long an unpaid pending booking holds its room
+	NoShowCutoff       time.Duration             // Time of day, after midnight in the property's timezone, when unarrived guests become no-shows
+	GuestPricing       GuestPricing              // Guests covered by room rates and the charges for further guests
+}
+
+// DefaultBookingConfig is used when nothing is configured: the default cancellation policy,
+// 15-minute holds, a no-show cutoff at 23:00 on the arrival day and the default guest pricing.
+var DefaultBookingConfig = BookingConfig{
+	CancellationPolicy: DefaultCancellationPolicy,
+	HoldTTL:            15 * time.Minute,
+	NoShowCutoff:       23 * time.Hour,
+	GuestPricing:       DefaultGuestPricing,
+}
+
+// BookingConfigFromEnv builds the booking configuration from the CANCELLATION_* variables,
+// BOOKING_HOLD_TTL (a Go duration such as "20m"), NO_SHOW_CUTOFF (a time of day such as
+// "18:00"), INCLUDED_GUESTS, EXTRA_ADULT_RATE and EXTRA_CHILD_RATE, falling back to
+// DefaultBookingConfig.
+// Returns an error if a variable is set to an invalid value.
+func BookingConfigFromEnv() (BookingConfig, error) {
+	config := DefaultBookingConfig
+	policy, err := CancellationPolicyFromEnv()
+	if err != nil {
+		return config, err
+	}
+	config.CancellationPolicy = policy
+	if v := os.Getenv("BOOKING_HOLD_TTL"); v != "" {
+		ttl, err := time.ParseDuration(v)
+		if err != nil || ttl <= 0 {
+			return config, fmt.Errorf("invalid BOOKING_HOLD_TTL %q: must be a positive duration", v)
+		}
+		config.HoldTTL = ttl
+	}
+	if v := os.Getenv("NO_SHOW_CUTOFF"); v != "" {
+		cutoff, err := time.Parse("15:04", v)
+		if err != nil {
+			return config, fmt.Errorf("invalid NO_SHOW_CUTOFF %q: must be a time of day such as 18:00", v)
+		}
+		config.NoShowCutoff = time.Duration(cutoff.Hour())*time.Hour + time.Duration(cutoff.Minute())*time.Minute
+	}
+	if v := os.Getenv("INCLUDED_GUESTS"); v != "" {
+		guests, err := strconv.Atoi(v)
+		if err != nil || guests < 0 {
+			return config, fmt.Errorf("invalid INCLUDED_GUESTS %q: must be a non-negative number", v)
+		}
+		config.GuestPricing.IncludedGuests = guests
+	}
+	for _, rate := range []struct {
+		name  string
+		value *float64
+	}{
+		{"EXTRA_ADULT_RATE", &config.GuestPricing.ExtraAdultRate},
+		{"EXTRA_CHILD_RATE", &config.GuestPricing.ExtraChildRate},
+	} {
+		if v := os.Getenv(rate.name); v != "" {
+			amount, err := strconv.ParseFloat(v, 64)
+			if err != nil || amount < 0 {
+				return config, fmt.Errorf("invalid %s %q: must be a non-negative amount", rate.name, v)
+			}
+			*rate.value = amount
+		}
+	}
+	return config, nil
+}
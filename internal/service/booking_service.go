@@ -4,24 +4,73 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"industry-api/internal/models"
 	"industry-api/internal/repository"
+	"log"
+	"math"
+	"slices"
+	"strings"
+	"time"
 )
 
+// bookingTransitions lists the statuses each booking status may move to.
+// Checked-out, cancelled and no-show bookings are final.
+var bookingTransitions = map[string][]string{
+	models.BookingStatusPending:    {models.BookingStatusConfirmed, models.BookingStatusCancelled},
+	models.BookingStatusConfirmed:  {models.BookingStatusCheckedIn, models.BookingStatusCancelled, models.BookingStatusNoShow},
+	models.BookingStatusCheckedIn:  {models.BookingStatusCheckedOut},
+	models.BookingStatusCheckedOut: {},
+	models.BookingStatusCancelled:  {},
+	models.BookingStatusNoShow:     {},
+}
+
+// expireHoldsBatchSize is the number of expired holds released per repository call.
+const expireHoldsBatchSize = 100
+
+// processNoShowsBatchSize is the number of no-show candidates loaded per repository call.
+const processNoShowsBatchSize = 100
+
+// Booking list pagination defaults.
+const (
+	defaultBookingListLimit = 20
+	maxBookingListLimit     = 100
+)
+
+// bookingSortFields lists the sort fields accepted by ListBookings.
+var bookingSortFields = map[string]struct{}{
+	"check_in_date":  {},
+	"check_out_date": {},
+	"created_at":     {},
+	"total_amount":   {},
+	"status":         {},
+}
+
 // BookingService handles all booking-related business logic operations.
 // It acts as an intermediary between the handler and repository layers.
 type BookingService struct {
-	repo repository.BookingRepo // interface for booking data access (mockable)
+	repo   repository.BookingRepo // interface for booking data access (mockable)
+	config BookingConfig          // cancellation policy and hold duration
+	now    func() time.Time       // clock used for date rules (time.Now when nil)
 }
 
 // NewBookingService creates and returns a new instance of BookingService.
-// It accepts a BookingRepo interface for data access operations.
-func NewBookingService(repo repository.BookingRepo) *BookingService {
-	return &BookingService{repo: repo}
+// It accepts a BookingRepo interface for data access operations and the booking configuration.
+func NewBookingService(repo repository.BookingRepo, config BookingConfig) *BookingService {
+	return &BookingService{repo: repo, config: config, now: time.Now}
+}
+
+// clock returns the current time of the service's clock.
+func (s *BookingService) clock() time.Time {
+	if s.now == nil {
+		return time.Now()
+	}
+	return s.now()
 }
 
 // AddBooking creates a new booking after validating all required fields.
-// It validates the booking data before delegating to the repository for persistence.
+// It validates the booking data, prepares it with prepareBooking and delegates to the
+// repository for persistence.
 // Returns the created booking or an error if validation fails or database operation fails.
 func (s *BookingService) AddBooking(ctx context.Context, booking *models.Booking) (*models.Booking, error) {
 	// Validate UserID is provided
@@ -40,27 +89,22 @@ func (s *BookingService) AddBooking(ctx context.Context, booking *models.Booking
 	if booking.CheckOutDate.IsZero() {
 		return nil, errors.New("check out date is required")
 	}
+	// Validate the stay covers at least one night
+	if !dateOnly(booking.CheckOutDate).After(dateOnly(booking.CheckInDate)) {
+		return nil, validationError("check out date must be after check in date")
+	}
 	// Validate Adults count is provided
 	if booking.Adults == 0 {
 		return nil, errors.New("adults is required")
 	}
-	// Validate Children count is provided
-	if booking.Children == 0 {
-		return nil, errors.New("children is required")
+	if booking.Children < 0 {
+		return nil, validationError("children cannot be negative")
 	}
-	// Validate TotalAmount is provided
-	if booking.TotalAmount == 0 {
-		return nil, errors.New("total amount is required")
-	}
-	// Validate Status is provided
-	if booking.Status == "" {
-		return nil, errors.New("status is required")
-	}
-	// Validate PaymentStatus is provided
-	if booking.PaymentStatus == "" {
-		return nil, errors.New("payment status is required")
+	if err := s.prepareBooking(ctx, booking); err != nil {
+		return nil, err
 	}
-	// Persist the booking to the database through the repository
+
+	// Persist the booking; overlapping stays are rejected with a *BookingConflictError
 	booking, err := s.repo.AddBooking(ctx, booking)
 	if err != nil {
 		return nil, err
@@ -68,3 +112,521 @@ func (s *BookingService) AddBooking(ctx context.Context, booking *models.Booking
 	return booking, nil
 
 }
+
+// prepareBooking readies a validated new booking for insertion: it starts the booking pending
+// with a hold, checks the room can take a same-day arrival, and prices the stay from the room's
+// rate less the discounts of its promo codes. A client-supplied total must match the computed price.
+func (s *BookingService) prepareBooking(ctx context.Context, booking *models.Booking) error {
+	// New bookings always start pending and unpaid; later statuses are reached through ChangeStatus
+	booking.Status = models.BookingStatusPending
+	booking.PaymentStatus = models.PaymentStatusPending
+	// The room is only held for a while unless the guest pays
+	if s.config.HoldTTL > 0 {
+		holdExpiresAt := s.clock().Add(s.config.HoldTTL)
+		booking.HoldExpiresAt = &holdExpiresAt
+	}
+	// A room assigned to a guest arriving today must already be inspected
+	room, err := s.repo.GetRoomByID(ctx, booking.RoomID)
+	if err != nil {
+		return err
+	}
+	if sameDate(booking.CheckInDate, s.clock()) && !readyForSameDayArrival(room.HousekeepingStatus) {
+		return conflictError("room %s is %s and cannot be assigned for a same-day arrival", room.RoomNumber, room.HousekeepingStatus)
+	}
+	// The booking belongs to the property of its room
+	booking.PropertyID = room.PropertyID
+
+	// Price the stay from the room's rate less any promo codes plus taxes; a client-supplied total must match it
+	breakdown, err := calculatePrice(room, s.config.GuestPricing, booking.CheckInDate, booking.CheckOutDate, booking.Adults, booking.Children)
+	if err != nil {
+		return err
+	}
+	expected := booking.TotalAmount
+	booking.TotalAmount = breakdown.Total
+	booking.PriceBreakdown = breakdown
+	if err := s.applyPromoCodes(ctx, booking, room); err != nil {
+		return err
+	}
+	if err := s.addTaxes(ctx, booking); err != nil {
+		return err
+	}
+	if expected != 0 && math.Abs(expected-booking.TotalAmount) > priceMismatchTolerance {
+		return validationError("total amount %.2f does not match the computed price %.2f", expected, booking.TotalAmount)
+	}
+	return nil
+}
+
+// applyPromoCodes checks the booking's promo codes against the priced stay in room and adds
+// their discounts to its price breakdown. Codes are matched case-insensitively and a code that
+// does not stack must be the only one. The usage limits are enforced when the redemptions are
+// recorded with the booking. Returns a validation error naming the first code that does not apply.
+func (s *BookingService) applyPromoCodes(ctx context.Context, booking *models.Booking, room *models.Room) error {
+	var codes []string
+	for _, code := range booking.PromoCodes {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if code != "" && !slices.Contains(codes, code) {
+			codes = append(codes, code)
+		}
+	}
+	booking.Redemptions = nil
+	if len(codes) == 0 {
+		return nil
+	}
+	if len(codes) > maxPromoCodesPerBooking {
+		return validationError("at most %d promo codes can be used on a booking", maxPromoCodesPerBooking)
+	}
+
+	found, err := s.repo.GetPromoCodes(ctx, codes)
+	if err != nil {
+		return err
+	}
+	promos := make([]models.PromoCode, 0, len(codes))
+	for _, code := range codes {
+		i := slices.IndexFunc(found, func(p models.PromoCode) bool { return p.Code == code })
+		if i < 0 {
+			return validationError("promo code %s does not exist", code)
+		}
+		if err := checkPromoCode(&found[i], booking, room, s.clock()); err != nil {
+			return err
+		}
+		if !found[i].Stackable && len(codes) > 1 {
+			return validationError("promo code %s cannot be combined with other codes", code)
+		}
+		promos = append(promos, found[i])
+	}
+	booking.Redemptions = addDiscounts(booking.PriceBreakdown, promos)
+	booking.TotalAmount = booking.PriceBreakdown.Total
+	return nil
+}
+
+// addTaxes applies the tax rates of the booking's property in force during its stay to its
+// price breakdown and updates the total.
+func (s *BookingService) addTaxes(ctx context.Context, booking *models.Booking) error {
+	rates, err := s.repo.GetApplicableTaxRates(ctx, booking.PropertyID, booking.CheckInDate, booking.CheckOutDate)
+	if err != nil {
+		return err
+	}
+	applyTaxRates(booking.PriceBreakdown, rates, booking.CheckInDate, booking.CheckOutDate, booking.Adults, booking.Children)
+	booking.TotalAmount = booking.PriceBreakdown.Total
+	return nil
+}
+
+// GetBooking returns a booking by its ID.
+func (s *BookingService) GetBooking(ctx context.Context, id int) (*models.Booking, error) {
+	if id <= 0 {
+		return nil, validationError("booking id is required")
+	}
+	return s.repo.GetBookingByID(ctx, id)
+}
+
+// canTransitionBooking reports whether a booking may move from one status to another.
+func canTransitionBooking(from, to string) bool {
+	for _, allowed := range bookingTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ChangeStatus moves a booking to a new lifecycle status and records who did it and why.
+// The transition must be allowed from the booking's current status; changedBy may be nil
+// when the change is made automatically.
+// Returns the updated booking or an error if the transition is not allowed.
+func (s *BookingService) ChangeStatus(ctx context.Context, id int, to string, changedBy *int, reason string) (*models.Booking, error) {
+	if _, ok := bookingTransitions[to]; !ok {
+		return nil, validationError("unknown booking status %q", to)
+	}
+	booking, err := s.GetBooking(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !canTransitionBooking(booking.Status, to) {
+		return nil, conflictError("booking cannot move from %s to %s", booking.Status, to)
+	}
+	if to == models.BookingStatusNoShow {
+		if dateOnly(s.clock().In(booking.CheckInDate.Location())).Before(dateOnly(booking.CheckInDate)) {
+			return nil, conflictError("booking cannot be marked as a no-show before its arrival day")
+		}
+		return s.markNoShow(ctx, booking, changedBy, reason)
+	}
+	return s.repo.SetBookingStatus(ctx, id, booking.Status, to, changedBy, reason)
+}
+
+// CancelBooking cancels a pending or confirmed booking under the service's cancellation policy.
+// The penalty and refund are computed against the booking's paid payments; with preview set
+// the quote is returned and nothing is changed.
+// Returns the booking and the quote, or a conflict error if the booking can no longer be cancelled.
+func (s *BookingService) CancelBooking(ctx context.Context, id int, changedBy *int, reason string, preview bool) (*models.CancellationResult, error) {
+	booking, err := s.GetBooking(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !canTransitionBooking(booking.Status, models.BookingStatusCancelled) {
+		return nil, conflictError("booking cannot move from %s to %s", booking.Status, models.BookingStatusCancelled)
+	}
+	paid, err := s.repo.GetPaidAmount(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	quote := quoteCancellation(s.config.CancellationPolicy, booking, paid, s.clock())
+	if preview {
+		return &models.CancellationResult{Preview: true, Booking: booking, Quote: quote}, nil
+	}
+
+	cancelled, err := s.repo.CancelBooking(ctx, id, booking.Status, &quote, changedBy, reason)
+	if err != nil {
+		return nil, err
+	}
+	return &models.CancellationResult{Booking: cancelled, Quote: quote}, nil
+}
+
+// ModifyBooking changes the room, dates, guest counts or special requests of a pending or
+// confirmed booking. The new stay is repriced, keeping the discounts of the promo codes redeemed
+// at booking, and, against the booking's paid payments, the balance owed or refund due is
+// recorded with both versions in the booking's change log.
+// propertyIDs limits the rooms the booking may move to (nil means every property).
+// Returns the updated booking and change, a conflict error if the booking can no longer be
+// changed or was changed since req.Version, or a *BookingConflictError if the new room is taken.
+func (s *BookingService) ModifyBooking(ctx context.Context, id int, req *models.BookingUpdateRequest, changedBy *int, propertyIDs []int) (*models.BookingModification, error) {
+	booking, err := s.GetBooking(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if booking.Status != models.BookingStatusPending && booking.Status != models.BookingStatusConfirmed {
+		return nil, conflictError("a %s booking cannot be modified", booking.Status)
+	}
+	if req.Version != 0 && req.Version != booking.Version {
+		return nil, conflictError("booking is at version %d, not %d", booking.Version, req.Version)
+	}
+
+	// Apply the requested changes to a copy of the booking
+	updated := *booking
+	if req.RoomID != nil {
+		updated.RoomID = *req.RoomID
+	}
+	if req.CheckInDate != nil {
+		updated.CheckInDate = *req.CheckInDate
+	}
+	if req.CheckOutDate != nil {
+		updated.CheckOutDate = *req.CheckOutDate
+	}
+	if req.Adults != nil {
+		updated.Adults = *req.Adults
+	}
+	if req.Children != nil {
+		updated.Children = *req.Children
+	}
+	if req.SpecialRequests != nil {
+		updated.SpecialRequests = *req.SpecialRequests
+	}
+	if sameStay(updated.Snapshot(), booking.Snapshot()) && updated.SpecialRequests == booking.SpecialRequests {
+		return nil, validationError("no changes requested")
+	}
+	if updated.Adults < 1 {
+		return nil, validationError("at least one adult is required")
+	}
+	if updated.Children < 0 {
+		return nil, validationError("children cannot be negative")
+	}
+
+	room, err := s.repo.GetRoomByID(ctx, updated.RoomID)
+	if err != nil {
+		return nil, err
+	}
+	if propertyIDs != nil && !slices.Contains(propertyIDs, room.PropertyID) {
+		return nil, fmt.Errorf("room %w", ErrNotFound)
+	}
+	if booking.ReservationID != nil && room.PropertyID != booking.PropertyID {
+		return nil, validationError("a booking of a group reservation cannot move to another property")
+	}
+	moved := updated.RoomID != booking.RoomID || !sameDate(updated.CheckInDate, booking.CheckInDate)
+	if moved && sameDate(updated.CheckInDate, s.clock()) && !readyForSameDayArrival(room.HousekeepingStatus) {
+		return nil, conflictError("room %s is %s and cannot be assigned for a same-day arrival", room.RoomNumber, room.HousekeepingStatus)
+	}
+	updated.PropertyID = room.PropertyID
+
+	// Reprice the new stay with the taxes in force, keeping the promo codes redeemed at booking, and settle it against what has been paid.
+	// The redeemed codes must still fit the new stay; their usage was counted when the booking was made.
+	breakdown, err := calculatePrice(room, s.config.GuestPricing, updated.CheckInDate, updated.CheckOutDate, updated.Adults, updated.Children)
+	if err != nil {
+		return nil, err
+	}
+	promos, err := s.repo.ListBookingPromoCodes(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for i := range promos {
+		if err := checkPromoStay(&promos[i], &updated, room); err != nil {
+			return nil, err
+		}
+	}
+	updated.Redemptions = addDiscounts(breakdown, promos)
+	updated.PriceBreakdown = breakdown
+	if err := s.addTaxes(ctx, &updated); err != nil {
+		return nil, err
+	}
+	paidCents, err := s.repo.GetPaidAmount(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	paid := roundMoney(float64(paidCents) / 100)
+
+	change := &models.BookingChange{
+		Version:         booking.Version + 1,
+		ChangedBy:       changedBy,
+		Reason:          req.Reason,
+		Before:          booking.Snapshot(),
+		After:           updated.Snapshot(),
+		PriceDifference: roundMoney(updated.TotalAmount - booking.TotalAmount),
+		BalanceDue:      roundMoney(math.Max(0, updated.TotalAmount-paid)),
+		RefundDue:       roundMoney(math.Max(0, paid-updated.TotalAmount)),
+	}
+	saved, err := s.repo.ModifyBooking(ctx, &updated, change)
+	if err != nil {
+		return nil, err
+	}
+	return &models.BookingModification{Booking: saved, Change: change, AmountPaid: paid}, nil
+}
+
+// ListBookings returns a filtered, sorted and paginated list of bookings.
+// Returns a validation error for unknown statuses, sort fields or inverted date ranges.
+func (s *BookingService) ListBookings(ctx context.Context, filter models.BookingFilter) (*models.BookingListResponse, error) {
+	for _, status := range filter.Statuses {
+		if _, ok := bookingTransitions[status]; !ok {
+			return nil, validationError("unknown booking status %q", status)
+		}
+	}
+	if filter.Sort != "" {
+		if _, ok := bookingSortFields[filter.Sort]; !ok {
+			return nil, validationError("unsupported sort field %q", filter.Sort)
+		}
+	}
+	if filter.Order != "" && !strings.EqualFold(filter.Order, "asc") && !strings.EqualFold(filter.Order, "desc") {
+		return nil, validationError("order must be asc or desc")
+	}
+	ranges := []struct {
+		name     string
+		from, to *time.Time
+	}{
+		{"check_in", filter.CheckInFrom, filter.CheckInTo},
+		{"check_out", filter.CheckOutFrom, filter.CheckOutTo},
+		{"created", filter.CreatedFrom, filter.CreatedTo},
+	}
+	for _, r := range ranges {
+		if r.from != nil && r.to != nil && r.to.Before(*r.from) {
+			return nil, validationError("%s_to must not be before %s_from", r.name, r.name)
+		}
+	}
+
+	// Apply pagination defaults
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultBookingListLimit
+	}
+	if filter.Limit > maxBookingListLimit {
+		filter.Limit = maxBookingListLimit
+	}
+	return s.repo.ListBookings(ctx, filter)
+}
+
+// Arrivals returns every booking due to arrive on date that has not been cancelled or marked
+// as a no-show, including guests who already checked in.
+func (s *BookingService) Arrivals(ctx context.Context, date time.Time, propertyIDs []int) ([]models.Booking, error) {
+	result, err := s.repo.ListBookings(ctx, models.BookingFilter{
+		PropertyIDs: propertyIDs,
+		Statuses:    []string{models.BookingStatusPending, models.BookingStatusConfirmed, models.BookingStatusCheckedIn},
+		CheckInFrom: &date,
+		CheckInTo:   &date,
+		Sort:        "check_in_date",
+		Order:       "asc",
+		Page:        1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.Bookings, nil
+}
+
+// Departures returns every in-house or checked-out booking due to leave on date.
+func (s *BookingService) Departures(ctx context.Context, date time.Time, propertyIDs []int) ([]models.Booking, error) {
+	result, err := s.repo.ListBookings(ctx, models.BookingFilter{
+		PropertyIDs:  propertyIDs,
+		Statuses:     []string{models.BookingStatusCheckedIn, models.BookingStatusCheckedOut},
+		CheckOutFrom: &date,
+		CheckOutTo:   &date,
+		Sort:         "check_out_date",
+		Order:        "asc",
+		Page:         1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.Bookings, nil
+}
+
+// sameStay reports whether two snapshots describe the same room, dates and guests.
+func sameStay(a, b models.BookingSnapshot) bool {
+	return a.RoomID == b.RoomID && a.CheckInDate.Equal(b.CheckInDate) && a.CheckOutDate.Equal(b.CheckOutDate) &&
+		a.Adults == b.Adults && a.Children == b.Children
+}
+
+// Changes returns the versioned change log of a booking, oldest version first.
+func (s *BookingService) Changes(ctx context.Context, id int) ([]models.BookingChange, error) {
+	if _, err := s.GetBooking(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListBookingChanges(ctx, id)
+}
+
+// CheckIn registers the arrival of a confirmed booking's guest and records the assigned room
+// and time. The arrival date must have been reached unless req.EarlyCheckIn is set, the stay
+// must not be over, and the assigned room must belong to the booking's property, fit the
+// guests and be inspected. propertyIDs limits the rooms that may be assigned (nil means every property).
+// Returns the checked-in booking, or a *BookingConflictError if a newly assigned room is taken.
+func (s *BookingService) CheckIn(ctx context.Context, id int, req *models.CheckInRequest, changedBy *int, propertyIDs []int) (*models.Booking, error) {
+	booking, err := s.GetBooking(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if booking.Status != models.BookingStatusConfirmed {
+		return nil, conflictError("only confirmed bookings can be checked in, booking is %s", booking.Status)
+	}
+
+	// The arrival day must have come, unless the front desk allows an early check-in
+	today := dateOnly(s.clock().In(booking.CheckInDate.Location()))
+	if today.Before(dateOnly(booking.CheckInDate)) && !req.EarlyCheckIn {
+		return nil, conflictError("booking arrives on %s; set early_check_in to check in before", booking.CheckInDate.Format("2006-01-02"))
+	}
+	if !today.Before(dateOnly(booking.CheckOutDate)) {
+		return nil, conflictError("booking departed on %s and can no longer be checked in", booking.CheckOutDate.Format("2006-01-02"))
+	}
+
+	// Validate the room the guest is given
+	roomID := booking.RoomID
+	if req.RoomID != nil {
+		roomID = *req.RoomID
+	}
+	room, err := s.repo.GetRoomByID(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if propertyIDs != nil && !slices.Contains(propertyIDs, room.PropertyID) {
+		return nil, fmt.Errorf("room %w", ErrNotFound)
+	}
+	if room.PropertyID != booking.PropertyID {
+		return nil, validationError("room %s belongs to another property", room.RoomNumber)
+	}
+	if booking.Adults+booking.Children > room.Capacity {
+		return nil, validationError("room %s sleeps at most %d guests", room.RoomNumber, room.Capacity)
+	}
+	if !readyForSameDayArrival(room.HousekeepingStatus) {
+		return nil, conflictError("room %s is %s and cannot be assigned yet", room.RoomNumber, room.HousekeepingStatus)
+	}
+
+	return s.repo.CheckInBooking(ctx, booking, room.ID, changedBy, req.Reason)
+}
+
+// CheckOut registers the departure of a checked-in booking's guest, records the time and flags
+// the room for housekeeping. An outstanding folio balance, the stay plus incidentals less
+// payments, blocks the check-out unless req.OverrideBalance is set.
+// Returns the checked-out booking or a conflict error.
+func (s *BookingService) CheckOut(ctx context.Context, id int, req *models.CheckOutRequest, changedBy *int) (*models.Booking, error) {
+	booking, err := s.GetBooking(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if booking.Status != models.BookingStatusCheckedIn {
+		return nil, conflictError("only checked-in bookings can be checked out, booking is %s", booking.Status)
+	}
+	paid, err := s.repo.GetPaidAmount(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// The balance covers the incidental charges and credits on the folio as well as the stay
+	incidentals, err := s.repo.GetFolioTotal(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	balance := roundMoney(booking.TotalAmount + incidentals - float64(paid)/100)
+	if balance > priceMismatchTolerance && !req.OverrideBalance {
+		return nil, conflictError("folio has an outstanding balance of %.2f; set override_balance to check out anyway", balance)
+	}
+	return s.repo.CheckOutBooking(ctx, booking, changedBy, req.Reason)
+}
+
+// ProcessNoShows marks the confirmed bookings whose guests had not arrived by the no-show cutoff
+// of their arrival day as no-shows, charging the no-show penalty of the cancellation terms and
+// freeing their nights, and returns how many were marked. It is safe to run from several
+// instances at once: a booking marked or checked in by another caller in the meantime is skipped.
+func (s *BookingService) ProcessNoShows(ctx context.Context) (int, error) {
+	marked := 0
+	for {
+		bookings, err := s.repo.ListNoShowCandidates(ctx, s.clock(), s.config.NoShowCutoff, processNoShowsBatchSize)
+		if err != nil {
+			return marked, err
+		}
+		progressed := false
+		for i := range bookings {
+			booking, err := s.markNoShow(ctx, &bookings[i], nil, "guest did not arrive by the no-show cutoff")
+			if errors.Is(err, ErrConflict) {
+				continue
+			}
+			if err != nil {
+				return marked, err
+			}
+			quote := booking.Cancellation
+			log.Printf("booking %d marked as no-show: penalty %.2f, paid %.2f, refund %.2f, due %.2f",
+				booking.ID, quote.Penalty, quote.AmountPaid, quote.Refund, quote.PenaltyDue)
+			marked++
+			progressed = true
+		}
+		if len(bookings) < processNoShowsBatchSize || !progressed {
+			return marked, nil
+		}
+	}
+}
+
+// markNoShow prices the no-show penalty of a confirmed booking and marks it as a no-show.
+func (s *BookingService) markNoShow(ctx context.Context, booking *models.Booking, changedBy *int, reason string) (*models.Booking, error) {
+	paid, err := s.repo.GetPaidAmount(ctx, booking.ID)
+	if err != nil {
+		return nil, err
+	}
+	quote := quoteNoShow(s.config.CancellationPolicy, booking, paid, s.clock())
+	return s.repo.MarkNoShow(ctx, booking.ID, &quote, changedBy, reason)
+}
+
+// ExpireHolds cancels the pending bookings whose hold expired without payment, releasing their
+// rooms, and returns how many were cancelled. It is safe to run from several instances at once.
+func (s *BookingService) ExpireHolds(ctx context.Context) (int, error) {
+	expired := 0
+	for {
+		ids, err := s.repo.ExpireHolds(ctx, s.clock(), expireHoldsBatchSize)
+		expired += len(ids)
+		if err != nil || len(ids) < expireHoldsBatchSize {
+			return expired, err
+		}
+	}
+}
+
+// History returns every status change of a booking, oldest first.
+func (s *BookingService) History(ctx context.Context, id int) ([]models.BookingStatusChange, error) {
+	if _, err := s.GetBooking(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListBookingStatusHistory(ctx, id)
+}
+
+// dateOnly truncates t to midnight of its calendar day in t's location.
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// sameDate reports whether a and b fall on the same calendar day in a's location.
+func sameDate(a, b time.Time) bool {
+	b = b.In(a.Location())
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
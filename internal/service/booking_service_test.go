@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -9,13 +11,135 @@ import (
 )
 
 type mockBookingRepo struct {
-	add func(ctx context.Context, b *models.Booking) (*models.Booking, error)
+	add    func(ctx context.Context, b *models.Booking) (*models.Booking, error)
+	room   func(ctx context.Context, roomID int) (*models.Room, error)
+	get    func(ctx context.Context, id int) (*models.Booking, error)
+	set    func(ctx context.Context, id int, from, to string, changedBy *int, reason string) (*models.Booking, error)
+	paid   int64
+	cancel func(ctx context.Context, id int, from string, quote *models.CancellationQuote) (*models.Booking, error)
+	modify func(ctx context.Context, b *models.Booking, change *models.BookingChange) (*models.Booking, error)
+	list   func(ctx context.Context, filter models.BookingFilter) (*models.BookingListResponse, error)
+	// checkedInRoom and checkedOut record the front desk calls
+	checkedInRoom int
+	checkedOut    bool
+	// expireBatches are the results returned by successive ExpireHolds calls
+	expireBatches [][]int
+	// noShowBatches are the results returned by successive ListNoShowCandidates calls and
+	// noShowQuotes the quotes bookings were marked as no-shows with
+	noShowBatches [][]models.Booking
+	noShowQuotes  map[int]models.CancellationQuote
+	// promos are the promo codes known to the repository and redeemed are those of the booking
+	promos   []models.PromoCode
+	redeemed []models.PromoCode
+	// taxRates are the tax rates returned for every stay
+	taxRates []models.TaxRate
+	// folio is the net amount of the incidental charges on the folio
+	folio float64
 }
 
 func (m *mockBookingRepo) AddBooking(ctx context.Context, b *models.Booking) (*models.Booking, error) {
 	return m.add(ctx, b)
 }
 
+// GetRoomByID returns an inspected room unless the test overrides it.
+func (m *mockBookingRepo) GetRoomByID(ctx context.Context, roomID int) (*models.Room, error) {
+	if m.room == nil {
+		return &models.Room{ID: roomID, RoomNumber: "101", Price: 100, Capacity: 4, HousekeepingStatus: models.HousekeepingInspected}, nil
+	}
+	return m.room(ctx, roomID)
+}
+
+func (m *mockBookingRepo) GetBookingByID(ctx context.Context, id int) (*models.Booking, error) {
+	return m.get(ctx, id)
+}
+
+func (m *mockBookingRepo) SetBookingStatus(ctx context.Context, id int, from, to string, changedBy *int, reason string) (*models.Booking, error) {
+	return m.set(ctx, id, from, to, changedBy, reason)
+}
+
+func (m *mockBookingRepo) ListBookingStatusHistory(ctx context.Context, id int) ([]models.BookingStatusChange, error) {
+	return nil, nil
+}
+
+func (m *mockBookingRepo) GetPaidAmount(ctx context.Context, bookingID int) (int64, error) {
+	return m.paid, nil
+}
+
+func (m *mockBookingRepo) CancelBooking(ctx context.Context, id int, from string, quote *models.CancellationQuote, changedBy *int, reason string) (*models.Booking, error) {
+	return m.cancel(ctx, id, from, quote)
+}
+
+func (m *mockBookingRepo) ModifyBooking(ctx context.Context, b *models.Booking, change *models.BookingChange) (*models.Booking, error) {
+	return m.modify(ctx, b, change)
+}
+
+func (m *mockBookingRepo) ListBookingChanges(ctx context.Context, id int) ([]models.BookingChange, error) {
+	return nil, nil
+}
+
+func (m *mockBookingRepo) ListBookings(ctx context.Context, filter models.BookingFilter) (*models.BookingListResponse, error) {
+	return m.list(ctx, filter)
+}
+
+func (m *mockBookingRepo) CheckInBooking(ctx context.Context, b *models.Booking, roomID int, changedBy *int, reason string) (*models.Booking, error) {
+	m.checkedInRoom = roomID
+	b.Status = models.BookingStatusCheckedIn
+	b.RoomID = roomID
+	return b, nil
+}
+
+func (m *mockBookingRepo) CheckOutBooking(ctx context.Context, b *models.Booking, changedBy *int, reason string) (*models.Booking, error) {
+	m.checkedOut = true
+	b.Status = models.BookingStatusCheckedOut
+	return b, nil
+}
+
+func (m *mockBookingRepo) ExpireHolds(ctx context.Context, now time.Time, limit int) ([]int, error) {
+	if len(m.expireBatches) == 0 {
+		return nil, nil
+	}
+	ids := m.expireBatches[0]
+	m.expireBatches = m.expireBatches[1:]
+	return ids, nil
+}
+
+func (m *mockBookingRepo) ListNoShowCandidates(ctx context.Context, now time.Time, cutoff time.Duration, limit int) ([]models.Booking, error) {
+	if len(m.noShowBatches) == 0 {
+		return nil, nil
+	}
+	bookings := m.noShowBatches[0]
+	m.noShowBatches = m.noShowBatches[1:]
+	return bookings, nil
+}
+
+// MarkNoShow records the quote and marks the booking through set.
+func (m *mockBookingRepo) MarkNoShow(ctx context.Context, id int, quote *models.CancellationQuote, changedBy *int, reason string) (*models.Booking, error) {
+	booking, err := m.set(ctx, id, models.BookingStatusConfirmed, models.BookingStatusNoShow, changedBy, reason)
+	if err != nil {
+		return nil, err
+	}
+	if m.noShowQuotes == nil {
+		m.noShowQuotes = map[int]models.CancellationQuote{}
+	}
+	m.noShowQuotes[id] = *quote
+	booking.Cancellation = quote
+	return booking, nil
+}
+
+func (m *mockBookingRepo) GetPromoCodes(ctx context.Context, codes []string) ([]models.PromoCode, error) {
+	return m.promos, nil
+}
+
+func (m *mockBookingRepo) ListBookingPromoCodes(ctx context.Context, bookingID int) ([]models.PromoCode, error) {
+	return m.redeemed, nil
+}
+func (m *mockBookingRepo) GetFolioTotal(ctx context.Context, bookingID int) (float64, error) {
+	return m.folio, nil
+}
+func (m *mockBookingRepo) GetApplicableTaxRates(ctx context.Context, propertyID int, checkIn, checkOut time.Time) ([]models.TaxRate, error) {
+	return m.taxRates, nil
+}
+
 func TestAddBooking_ValidationErrors(t *testing.T) {
 	svc := &BookingService{repo: &mockBookingRepo{add: func(ctx context.Context, b *models.Booking) (*models.Booking, error) { return b, nil }}}
 
@@ -24,7 +148,7 @@ func TestAddBooking_ValidationErrors(t *testing.T) {
 		t.Fatalf("expected validation error for missing fields")
 	}
 
-	b = &models.Booking{UserID: 1, RoomID: 1, CheckInDate: time.Now(), CheckOutDate: time.Now().Add(24 * time.Hour), Adults: 1, Children: 1, TotalAmount: 100, Status: "pending", PaymentStatus: "pending"}
+	b = &models.Booking{UserID: 1, RoomID: 1, CheckInDate: time.Now(), CheckOutDate: time.Now().Add(24 * time.Hour), Adults: 1, Children: 1, TotalAmount: 100}
 	got, err := svc.AddBooking(context.Background(), b)
 	if err != nil {
 		t.Fatalf("expected success, got %v", err)
@@ -32,4 +156,564 @@ func TestAddBooking_ValidationErrors(t *testing.T) {
 	if got == nil || got.UserID != 1 {
 		t.Fatalf("unexpected result: %+v", got)
 	}
+	if got.Status != models.BookingStatusPending || got.PaymentStatus != models.PaymentStatusPending {
+		t.Fatalf("expected a new booking to start pending, got %s/%s", got.Status, got.PaymentStatus)
+	}
+
+	// adults travelling without children are fine, negative counts are not
+	b = &models.Booking{UserID: 1, RoomID: 1, CheckInDate: time.Now(), CheckOutDate: time.Now().Add(24 * time.Hour), Adults: 2}
+	if _, err := svc.AddBooking(context.Background(), b); err != nil {
+		t.Fatalf("expected a booking without children to succeed, got %v", err)
+	}
+	b = &models.Booking{UserID: 1, RoomID: 1, CheckInDate: time.Now(), CheckOutDate: time.Now().Add(24 * time.Hour), Adults: 2, Children: -1}
+	if _, err := svc.AddBooking(context.Background(), b); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error for negative children, got %v", err)
+	}
+}
+
+func TestAddBooking_SameDayRequiresInspectedRoom(t *testing.T) {
+	repo := &mockBookingRepo{
+		add: func(ctx context.Context, b *models.Booking) (*models.Booking, error) { return b, nil },
+		room: func(ctx context.Context, roomID int) (*models.Room, error) {
+			return &models.Room{ID: roomID, RoomNumber: "101", Price: 100, Capacity: 4, HousekeepingStatus: models.HousekeepingDirty}, nil
+		},
+	}
+	// "today" is the service clock's day, not the wall clock's
+	now := time.Date(2030, 5, 1, 9, 0, 0, 0, time.UTC)
+	svc := &BookingService{repo: repo, now: func() time.Time { return now }}
+
+	today := &models.Booking{UserID: 1, RoomID: 1, CheckInDate: now, CheckOutDate: now.Add(24 * time.Hour), Adults: 1, Children: 1, TotalAmount: 100}
+	if _, err := svc.AddBooking(context.Background(), today); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected conflict for dirty room on same-day arrival, got %v", err)
+	}
+
+	later := &models.Booking{UserID: 1, RoomID: 1, CheckInDate: now.AddDate(0, 0, 3), CheckOutDate: now.AddDate(0, 0, 4), Adults: 1, Children: 1, TotalAmount: 100}
+	if _, err := svc.AddBooking(context.Background(), later); err != nil {
+		t.Fatalf("expected future booking of dirty room to succeed, got %v", err)
+	}
+}
+
+func TestAddBooking_RejectsEmptyStay(t *testing.T) {
+	svc := &BookingService{repo: &mockBookingRepo{add: func(ctx context.Context, b *models.Booking) (*models.Booking, error) { return b, nil }}}
+	in := time.Now().AddDate(0, 0, 5)
+
+	b := &models.Booking{UserID: 1, RoomID: 1, CheckInDate: in, CheckOutDate: in.Add(time.Hour), Adults: 1, Children: 1, TotalAmount: 100}
+	if _, err := svc.AddBooking(context.Background(), b); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error for a stay without nights, got %v", err)
+	}
+}
+
+func TestAddBooking_ReturnsTypedConflict(t *testing.T) {
+	taken := models.BookingConflict{BookingID: 9, RoomID: 1, CheckInDate: time.Now().AddDate(0, 0, 4), CheckOutDate: time.Now().AddDate(0, 0, 6)}
+	repo := &mockBookingRepo{add: func(ctx context.Context, b *models.Booking) (*models.Booking, error) {
+		return nil, &BookingConflictError{Conflict: taken}
+	}}
+	svc := &BookingService{repo: repo}
+
+	b := &models.Booking{UserID: 1, RoomID: 1, CheckInDate: time.Now().AddDate(0, 0, 5), CheckOutDate: time.Now().AddDate(0, 0, 7), Adults: 1, Children: 1}
+	_, err := svc.AddBooking(context.Background(), b)
+	var conflict *BookingConflictError
+	if !errors.As(err, &conflict) || !errors.Is(err, ErrConflict) || conflict.Conflict.BookingID != 9 {
+		t.Fatalf("expected typed booking conflict, got %v", err)
+	}
+}
+
+func TestCalculatePrice_ItemisesExtraGuests(t *testing.T) {
+	room := &models.Room{RoomNumber: "201", RoomType: "suite", Price: 150, Capacity: 5}
+	in := time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC)
+	out := time.Date(2025, 3, 13, 11, 0, 0, 0, time.UTC)
+
+	// 3 adults and 1 child: one extra adult and one extra child for 3 nights
+	got, err := calculatePrice(room, DefaultGuestPricing, in, out, 3, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Nights != 3 || len(got.Lines) != 3 {
+		t.Fatalf("unexpected breakdown: %+v", got)
+	}
+	want := 150*3 + DefaultGuestPricing.ExtraAdultRate*3 + DefaultGuestPricing.ExtraChildRate*3
+	if got.Total != want {
+		t.Fatalf("expected total %.2f, got %.2f", want, got.Total)
+	}
+
+	// 1 adult and 1 child fit in the included guests
+	got, _ = calculatePrice(room, DefaultGuestPricing, in, out, 1, 1)
+	if len(got.Lines) != 1 || got.Total != 450 {
+		t.Fatalf("expected room line only, got %+v", got)
+	}
+
+	if _, err := calculatePrice(room, DefaultGuestPricing, in, out, 4, 2); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected capacity validation error, got %v", err)
+	}
+
+	// a rate covering one guest charges the child of 1 adult and 1 child
+	got, _ = calculatePrice(room, GuestPricing{IncludedGuests: 1, ExtraAdultRate: 40, ExtraChildRate: 15}, in, out, 1, 1)
+	if len(got.Lines) != 2 || got.Total != 495 || got.FirstNight != 165 {
+		t.Fatalf("expected the configured child rate, got %+v", got)
+	}
+}
+
+func TestAddBooking_ComputesAndChecksTotal(t *testing.T) {
+	svc := &BookingService{repo: &mockBookingRepo{add: func(ctx context.Context, b *models.Booking) (*models.Booking, error) { return b, nil }}}
+	in := time.Now().AddDate(0, 0, 3)
+
+	// a suite for 1.00 is rejected
+	cheap := &models.Booking{UserID: 1, RoomID: 1, CheckInDate: in, CheckOutDate: in.AddDate(0, 0, 2), Adults: 1, Children: 1, TotalAmount: 1}
+	if _, err := svc.AddBooking(context.Background(), cheap); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error for mismatching total, got %v", err)
+	}
+
+	// without a client total the computed price is stored with its breakdown
+	b := &models.Booking{UserID: 1, RoomID: 1, CheckInDate: in, CheckOutDate: in.AddDate(0, 0, 2), Adults: 1, Children: 1}
+	got, err := svc.AddBooking(context.Background(), b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.TotalAmount != 200 || got.PriceBreakdown == nil || got.PriceBreakdown.Nights != 2 {
+		t.Fatalf("unexpected price: %.2f %+v", got.TotalAmount, got.PriceBreakdown)
+	}
+}
+
+func TestBookingChangeStatus_EnforcesTransitions(t *testing.T) {
+	cases := []struct {
+		from, to string
+		allowed  bool
+	}{
+		{models.BookingStatusPending, models.BookingStatusConfirmed, true},
+		{models.BookingStatusPending, models.BookingStatusCancelled, true},
+		{models.BookingStatusPending, models.BookingStatusCheckedIn, false},
+		{models.BookingStatusConfirmed, models.BookingStatusCheckedIn, true},
+		{models.BookingStatusConfirmed, models.BookingStatusNoShow, true},
+		{models.BookingStatusCheckedIn, models.BookingStatusCancelled, false},
+		{models.BookingStatusCheckedIn, models.BookingStatusCheckedOut, true},
+		{models.BookingStatusCancelled, models.BookingStatusConfirmed, false},
+		{models.BookingStatusCheckedOut, models.BookingStatusCheckedIn, false},
+	}
+	for _, tc := range cases {
+		var recorded string
+		repo := &mockBookingRepo{
+			get: func(ctx context.Context, id int) (*models.Booking, error) {
+				return &models.Booking{ID: id, Status: tc.from}, nil
+			},
+			set: func(ctx context.Context, id int, from, to string, changedBy *int, reason string) (*models.Booking, error) {
+				recorded = from + "->" + to
+				return &models.Booking{ID: id, Status: to}, nil
+			},
+		}
+		svc := &BookingService{repo: repo}
+		actor := 7
+		_, err := svc.ChangeStatus(context.Background(), 1, tc.to, &actor, "")
+		if tc.allowed {
+			if err != nil || recorded != tc.from+"->"+tc.to {
+				t.Fatalf("%s -> %s: expected success, got err=%v recorded=%q", tc.from, tc.to, err, recorded)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrConflict) || recorded != "" {
+			t.Fatalf("%s -> %s: expected conflict, got err=%v recorded=%q", tc.from, tc.to, err, recorded)
+		}
+	}
+
+	svc := &BookingService{repo: &mockBookingRepo{}}
+	if _, err := svc.ChangeStatus(context.Background(), 1, "archived", nil, ""); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error for unknown status, got %v", err)
+	}
+}
+
+func TestQuoteCancellation_AppliesPolicy(t *testing.T) {
+	checkIn := time.Date(2030, 5, 10, 14, 0, 0, 0, time.UTC)
+	booking := &models.Booking{ID: 1, CheckInDate: checkIn, CheckOutDate: checkIn.AddDate(0, 0, 4), TotalAmount: 400}
+	firstNight := models.CancellationPolicy{FreeCancellationHours: 48, PenaltyType: models.PenaltyFirstNight}
+	percentage := models.CancellationPolicy{FreeCancellationHours: 48, PenaltyType: models.PenaltyPercentage, PenaltyPercent: 25}
+
+	cases := []struct {
+		name            string
+		policy          models.CancellationPolicy
+		paidCents       int64
+		now             time.Time
+		penalty, refund float64
+	}{
+		{"free period refunds everything", firstNight, 40000, checkIn.Add(-72 * time.Hour), 0, 400},
+		{"first night after the free period", firstNight, 40000, checkIn.Add(-24 * time.Hour), 100, 300},
+		{"percentage after the free period", percentage, 40000, checkIn.Add(-time.Hour), 100, 300},
+		{"partial payment covers part of the penalty", firstNight, 5000, checkIn.Add(-time.Hour), 100, 0},
+		{"unpaid booking refunds nothing", percentage, 0, checkIn.Add(-72 * time.Hour), 0, 0},
+	}
+	for _, tc := range cases {
+		quote := quoteCancellation(tc.policy, booking, tc.paidCents, tc.now)
+		if quote.Penalty != tc.penalty || quote.Refund != tc.refund {
+			t.Fatalf("%s: expected penalty %.2f refund %.2f, got %+v", tc.name, tc.penalty, tc.refund, quote)
+		}
+	}
+}
+
+func TestQuoteCancellation_FirstNightLeavesOutPerStayAmounts(t *testing.T) {
+	checkIn := time.Date(2030, 5, 10, 14, 0, 0, 0, time.UTC)
+	checkOut := checkIn.AddDate(0, 0, 3)
+	room := &models.Room{RoomNumber: "101", Price: 100, Capacity: 2}
+	breakdown, err := calculatePrice(room, DefaultGuestPricing, checkIn, checkOut, 2, 0)
+	if err != nil {
+		t.Fatalf("calculatePrice: %v", err)
+	}
+	addDiscounts(breakdown, []models.PromoCode{{Code: "WELCOME", DiscountType: models.DiscountFixed, DiscountValue: 60}})
+	applyTaxRates(breakdown, []models.TaxRate{
+		{Code: "vat", Name: "VAT", Basis: models.TaxPercentage, Period: models.TaxPerNight, Rate: 10},
+		{Code: "city_tax", Name: "City tax", Basis: models.TaxPerPerson, Period: models.TaxPerNight, Rate: 2},
+		{Code: "cleaning", Name: "Cleaning fee", Basis: models.TaxPerRoom, Period: models.TaxPerStay, Rate: 30},
+	}, checkIn, checkOut, 2, 0)
+	// 300 - 60 off the stay, + 24 VAT, + 12 city tax, + 30 cleaning
+	if breakdown.Total != 306 {
+		t.Fatalf("expected total 306, got %+v", breakdown)
+	}
+
+	booking := &models.Booking{ID: 1, CheckInDate: checkIn, CheckOutDate: checkOut, TotalAmount: breakdown.Total, PriceBreakdown: breakdown}
+	policy := models.CancellationPolicy{FreeCancellationHours: 48, PenaltyType: models.PenaltyFirstNight}
+	quote := quoteCancellation(policy, booking, 30600, checkIn.Add(-time.Hour))
+	// One night of 100 with 10 VAT and 4 city tax; the discount and the cleaning fee are per stay
+	if quote.Penalty != 114 || quote.Refund != 192 {
+		t.Fatalf("expected penalty 114 refund 192, got %+v", quote)
+	}
+}
+
+func TestCancelBooking_PreviewChangesNothing(t *testing.T) {
+	cancelled := false
+	repo := &mockBookingRepo{
+		get: func(ctx context.Context, id int) (*models.Booking, error) {
+			in := time.Now().AddDate(0, 0, 10)
+			return &models.Booking{ID: id, Status: models.BookingStatusConfirmed, CheckInDate: in, CheckOutDate: in.AddDate(0, 0, 2), TotalAmount: 200}, nil
+		},
+		paid: 20000,
+		cancel: func(ctx context.Context, id int, from string, quote *models.CancellationQuote) (*models.Booking, error) {
+			cancelled = true
+			return &models.Booking{ID: id, Status: models.BookingStatusCancelled, Cancellation: quote}, nil
+		},
+	}
+	svc := &BookingService{repo: repo, config: DefaultBookingConfig}
+
+	result, err := svc.CancelBooking(context.Background(), 1, nil, "", true)
+	if err != nil || !result.Preview || cancelled || result.Quote.Refund != 200 {
+		t.Fatalf("expected a full-refund preview without cancelling, got %+v err=%v", result, err)
+	}
+	result, err = svc.CancelBooking(context.Background(), 1, nil, "", false)
+	if err != nil || !cancelled || result.Booking.Status != models.BookingStatusCancelled {
+		t.Fatalf("expected the booking to be cancelled, got %+v err=%v", result, err)
+	}
+}
+
+func TestCancellationPolicyFromEnv_Validates(t *testing.T) {
+	t.Setenv("CANCELLATION_PENALTY_TYPE", models.PenaltyPercentage)
+	t.Setenv("CANCELLATION_PENALTY_PERCENT", "150")
+	if _, err := CancellationPolicyFromEnv(); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error for a penalty above 100%%, got %v", err)
+	}
+	t.Setenv("CANCELLATION_PENALTY_PERCENT", "30")
+	t.Setenv("CANCELLATION_FREE_HOURS", "72")
+	policy, err := CancellationPolicyFromEnv()
+	if err != nil || policy.FreeCancellationHours != 72 || policy.PenaltyPercent != 30 {
+		t.Fatalf("unexpected policy %+v err=%v", policy, err)
+	}
+}
+
+func TestBookingConfigFromEnv_GuestPricing(t *testing.T) {
+	t.Setenv("INCLUDED_GUESTS", "3")
+	t.Setenv("EXTRA_CHILD_RATE", "12.5")
+	config, err := BookingConfigFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := GuestPricing{IncludedGuests: 3, ExtraAdultRate: DefaultGuestPricing.ExtraAdultRate, ExtraChildRate: 12.5}
+	if config.GuestPricing != want {
+		t.Fatalf("expected %+v, got %+v", want, config.GuestPricing)
+	}
+	t.Setenv("EXTRA_ADULT_RATE", "-5")
+	if _, err := BookingConfigFromEnv(); err == nil {
+		t.Fatalf("expected an error for a negative rate")
+	}
+}
+
+func TestModifyBooking_RepricesAndSettlesAgainstPayments(t *testing.T) {
+	in := time.Now().AddDate(0, 1, 0)
+	current := &models.Booking{ID: 3, RoomID: 1, Status: models.BookingStatusConfirmed, CheckInDate: in, CheckOutDate: in.AddDate(0, 0, 2), Adults: 2, TotalAmount: 200, Version: 1}
+	var saved *models.BookingChange
+	repo := &mockBookingRepo{
+		get:  func(ctx context.Context, id int) (*models.Booking, error) { copied := *current; return &copied, nil },
+		paid: 20000,
+		modify: func(ctx context.Context, b *models.Booking, change *models.BookingChange) (*models.Booking, error) {
+			saved = change
+			b.Version = change.Version
+			return b, nil
+		},
+	}
+	svc := &BookingService{repo: repo}
+
+	// one night shorter: 100 of the 200 paid is refundable
+	out := in.AddDate(0, 0, 1)
+	result, err := svc.ModifyBooking(context.Background(), 3, &models.BookingUpdateRequest{CheckOutDate: &out, Version: 1}, nil, nil)
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if result.Booking.TotalAmount != 100 || saved.PriceDifference != -100 || saved.RefundDue != 100 || saved.BalanceDue != 0 || saved.Version != 2 {
+		t.Fatalf("unexpected modification: booking=%+v change=%+v", result.Booking, saved)
+	}
+	if saved.Before.TotalAmount != 200 || saved.After.TotalAmount != 100 {
+		t.Fatalf("expected both versions in the change log, got %+v", saved)
+	}
+
+	// one night longer: 100 still owed
+	out = in.AddDate(0, 0, 3)
+	if _, err := svc.ModifyBooking(context.Background(), 3, &models.BookingUpdateRequest{CheckOutDate: &out}, nil, nil); err != nil || saved.BalanceDue != 100 {
+		t.Fatalf("expected a balance of 100, got change=%+v err=%v", saved, err)
+	}
+
+	// a stale version is rejected
+	if _, err := svc.ModifyBooking(context.Background(), 3, &models.BookingUpdateRequest{CheckOutDate: &out, Version: 4}, nil, nil); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected conflict for a stale version, got %v", err)
+	}
+	// the room must be in the caller's properties
+	room := 9
+	if _, err := svc.ModifyBooking(context.Background(), 3, &models.BookingUpdateRequest{RoomID: &room}, nil, []int{5}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected not found for a room outside the scope, got %v", err)
+	}
+}
+
+func TestModifyBooking_RejectsClosedBookings(t *testing.T) {
+	for _, status := range []string{models.BookingStatusCheckedIn, models.BookingStatusCancelled, models.BookingStatusCheckedOut, models.BookingStatusNoShow} {
+		repo := &mockBookingRepo{get: func(ctx context.Context, id int) (*models.Booking, error) {
+			return &models.Booking{ID: id, Status: status, Adults: 1}, nil
+		}}
+		svc := &BookingService{repo: repo}
+		adults := 2
+		if _, err := svc.ModifyBooking(context.Background(), 1, &models.BookingUpdateRequest{Adults: &adults}, nil, nil); !errors.Is(err, ErrConflict) {
+			t.Fatalf("%s: expected conflict, got %v", status, err)
+		}
+	}
+}
+
+func TestListBookings_ValidatesAndDefaults(t *testing.T) {
+	var got models.BookingFilter
+	repo := &mockBookingRepo{list: func(ctx context.Context, filter models.BookingFilter) (*models.BookingListResponse, error) {
+		got = filter
+		return &models.BookingListResponse{Bookings: []models.Booking{}}, nil
+	}}
+	svc := &BookingService{repo: repo}
+
+	from := time.Date(2030, 1, 10, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, -1)
+	invalid := []models.BookingFilter{
+		{Statuses: []string{"archived"}},
+		{Sort: "guest_name"},
+		{Order: "sideways"},
+		{CheckInFrom: &from, CheckInTo: &to},
+	}
+	for _, filter := range invalid {
+		if _, err := svc.ListBookings(context.Background(), filter); !errors.Is(err, ErrValidation) {
+			t.Fatalf("expected validation error for %+v, got %v", filter, err)
+		}
+	}
+
+	if _, err := svc.ListBookings(context.Background(), models.BookingFilter{Limit: 500}); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if got.Page != 1 || got.Limit != maxBookingListLimit {
+		t.Fatalf("expected page 1 and limit capped at %d, got %+v", maxBookingListLimit, got)
+	}
+
+	// arrivals cover every booking still expected or already in the house on that date
+	if _, err := svc.Arrivals(context.Background(), from, []int{2}); err != nil {
+		t.Fatalf("Arrivals failed: %v", err)
+	}
+	if got.CheckInFrom == nil || !got.CheckInFrom.Equal(from) || !got.CheckInTo.Equal(from) || len(got.Statuses) != 3 || got.PropertyIDs[0] != 2 || got.Limit != 0 {
+		t.Fatalf("unexpected arrivals filter: %+v", got)
+	}
+}
+
+func TestCheckIn_EnforcesDateRoomAndStatus(t *testing.T) {
+	arrival := time.Date(2030, 6, 10, 0, 0, 0, 0, time.UTC)
+	rooms := map[int]*models.Room{
+		1: {ID: 1, PropertyID: 1, RoomNumber: "101", Capacity: 2, HousekeepingStatus: models.HousekeepingInspected},
+		2: {ID: 2, PropertyID: 1, RoomNumber: "102", Capacity: 2, HousekeepingStatus: models.HousekeepingDirty},
+		3: {ID: 3, PropertyID: 2, RoomNumber: "201", Capacity: 2, HousekeepingStatus: models.HousekeepingInspected},
+	}
+	status := models.BookingStatusConfirmed
+	repo := &mockBookingRepo{
+		get: func(ctx context.Context, id int) (*models.Booking, error) {
+			return &models.Booking{ID: id, PropertyID: 1, RoomID: 1, Status: status, CheckInDate: arrival, CheckOutDate: arrival.AddDate(0, 0, 2), Adults: 2}, nil
+		},
+		room: func(ctx context.Context, roomID int) (*models.Room, error) { return rooms[roomID], nil },
+	}
+	svc := &BookingService{repo: repo}
+	room := func(id int) *int { return &id }
+	check := func(now time.Time, req models.CheckInRequest) error {
+		svc.now = func() time.Time { return now }
+		_, err := svc.CheckIn(context.Background(), 1, &req, nil, nil)
+		return err
+	}
+
+	if err := check(arrival.Add(-20*time.Hour), models.CheckInRequest{}); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected conflict for an early arrival without override, got %v", err)
+	}
+	if err := check(arrival.Add(-20*time.Hour), models.CheckInRequest{EarlyCheckIn: true}); err != nil {
+		t.Fatalf("expected early check-in with override, got %v", err)
+	}
+	if err := check(arrival.AddDate(0, 0, 2).Add(time.Hour), models.CheckInRequest{}); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected conflict after the departure date, got %v", err)
+	}
+	if err := check(arrival.Add(15*time.Hour), models.CheckInRequest{RoomID: room(2)}); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected conflict for a dirty room, got %v", err)
+	}
+	if err := check(arrival.Add(15*time.Hour), models.CheckInRequest{RoomID: room(3)}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error for a room of another property, got %v", err)
+	}
+	if err := check(arrival.Add(15*time.Hour), models.CheckInRequest{}); err != nil || repo.checkedInRoom != 1 {
+		t.Fatalf("expected check-in into room 1, got room %d err=%v", repo.checkedInRoom, err)
+	}
+
+	status = models.BookingStatusPending
+	if err := check(arrival.Add(15*time.Hour), models.CheckInRequest{}); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected conflict for a pending booking, got %v", err)
+	}
+}
+
+func TestCheckOut_RequiresSettledBalance(t *testing.T) {
+	repo := &mockBookingRepo{
+		get: func(ctx context.Context, id int) (*models.Booking, error) {
+			return &models.Booking{ID: id, RoomID: 1, Status: models.BookingStatusCheckedIn, TotalAmount: 250}, nil
+		},
+		paid: 20000,
+	}
+	svc := &BookingService{repo: repo}
+
+	if _, err := svc.CheckOut(context.Background(), 1, &models.CheckOutRequest{}, nil); !errors.Is(err, ErrConflict) || repo.checkedOut {
+		t.Fatalf("expected conflict for an outstanding balance, got %v", err)
+	}
+	if _, err := svc.CheckOut(context.Background(), 1, &models.CheckOutRequest{OverrideBalance: true, Reason: "invoiced to company"}, nil); err != nil || !repo.checkedOut {
+		t.Fatalf("expected check-out with override, got %v", err)
+	}
+
+	repo.checkedOut = false
+	repo.paid = 25000
+	if _, err := svc.CheckOut(context.Background(), 1, &models.CheckOutRequest{}, nil); err != nil || !repo.checkedOut {
+		t.Fatalf("expected check-out of a settled booking, got %v", err)
+	}
+
+	// Unpaid incidentals on the folio block the check-out as well
+	repo.checkedOut = false
+	repo.folio = 12.5
+	if _, err := svc.CheckOut(context.Background(), 1, &models.CheckOutRequest{}, nil); !errors.Is(err, ErrConflict) || repo.checkedOut {
+		t.Fatalf("expected conflict for unpaid folio charges, got %v", err)
+	}
+}
+
+func TestAddBooking_HoldsRoomForTTL(t *testing.T) {
+	now := time.Date(2030, 3, 1, 9, 0, 0, 0, time.UTC)
+	svc := &BookingService{
+		repo:   &mockBookingRepo{add: func(ctx context.Context, b *models.Booking) (*models.Booking, error) { return b, nil }},
+		config: BookingConfig{HoldTTL: 30 * time.Minute},
+		now:    func() time.Time { return now },
+	}
+	in := now.AddDate(0, 0, 7)
+	got, err := svc.AddBooking(context.Background(), &models.Booking{UserID: 1, RoomID: 1, CheckInDate: in, CheckOutDate: in.AddDate(0, 0, 1), Adults: 1, Children: 1})
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if got.HoldExpiresAt == nil || !got.HoldExpiresAt.Equal(now.Add(30*time.Minute)) {
+		t.Fatalf("expected a 30 minute hold, got %v", got.HoldExpiresAt)
+	}
+}
+
+func TestExpireHolds_DrainsBatches(t *testing.T) {
+	full := make([]int, expireHoldsBatchSize)
+	repo := &mockBookingRepo{expireBatches: [][]int{full, {1, 2, 3}, {4}}}
+	svc := &BookingService{repo: repo}
+
+	expired, err := svc.ExpireHolds(context.Background())
+	if err != nil || expired != expireHoldsBatchSize+3 {
+		t.Fatalf("expected %d expired holds, got %d err=%v", expireHoldsBatchSize+3, expired, err)
+	}
+	if len(repo.expireBatches) != 1 {
+		t.Fatalf("expected to stop after a partial batch, %d batches left", len(repo.expireBatches))
+	}
+}
+
+func TestQuoteNoShow_ChargesPenaltyAtArrival(t *testing.T) {
+	checkIn := time.Date(2030, 5, 10, 0, 0, 0, 0, time.UTC)
+	booking := &models.Booking{ID: 1, CheckInDate: checkIn, CheckOutDate: checkIn.AddDate(0, 0, 4), TotalAmount: 400}
+	now := checkIn.Add(23 * time.Hour)
+
+	cases := []struct {
+		name                 string
+		policy               models.CancellationPolicy
+		paidCents            int64
+		penalty, refund, due float64
+	}{
+		{"first night of a fully paid booking", models.CancellationPolicy{FreeCancellationHours: 24, PenaltyType: models.PenaltyFirstNight}, 40000, 100, 300, 0},
+		{"unpaid penalty stays due", models.CancellationPolicy{FreeCancellationHours: 24, PenaltyType: models.PenaltyFirstNight}, 2500, 100, 0, 75},
+		{"policy free until arrival still charges", models.CancellationPolicy{FreeCancellationHours: 0, PenaltyType: models.PenaltyPercentage, PenaltyPercent: 50}, 0, 200, 0, 200},
+	}
+	for _, tc := range cases {
+		quote := quoteNoShow(tc.policy, booking, tc.paidCents, now)
+		if quote.Penalty != tc.penalty || quote.Refund != tc.refund || quote.PenaltyDue != tc.due {
+			t.Fatalf("%s: expected penalty %.2f refund %.2f due %.2f, got %+v", tc.name, tc.penalty, tc.refund, tc.due, quote)
+		}
+		if !quote.QuotedAt.Equal(now) || quote.HoursBeforeCheckIn != -23 {
+			t.Fatalf("%s: expected the quote to be dated when detected, got %+v", tc.name, quote)
+		}
+	}
+}
+
+func TestBookingChangeStatus_NoShowBeforeArrivalDay(t *testing.T) {
+	checkIn := time.Date(2030, 5, 10, 0, 0, 0, 0, time.UTC)
+	now := checkIn.Add(-2 * time.Hour)
+	repo := &mockBookingRepo{
+		get: func(ctx context.Context, id int) (*models.Booking, error) {
+			return &models.Booking{ID: id, Status: models.BookingStatusConfirmed, CheckInDate: checkIn, CheckOutDate: checkIn.AddDate(0, 0, 2), TotalAmount: 200}, nil
+		},
+		set: func(ctx context.Context, id int, from, to string, changedBy *int, reason string) (*models.Booking, error) {
+			return &models.Booking{ID: id, Status: to}, nil
+		},
+	}
+	svc := &BookingService{repo: repo, config: DefaultBookingConfig, now: func() time.Time { return now }}
+	if _, err := svc.ChangeStatus(context.Background(), 1, models.BookingStatusNoShow, nil, ""); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected conflict before the arrival day, got %v", err)
+	}
+
+	now = now.Add(3 * time.Hour)
+	if _, err := svc.ChangeStatus(context.Background(), 1, models.BookingStatusNoShow, nil, ""); err != nil {
+		t.Fatalf("expected success on the arrival day, got %v", err)
+	}
+	if quote, ok := repo.noShowQuotes[1]; !ok || quote.Penalty != 100 {
+		t.Fatalf("expected the first night as no-show penalty, got %+v", repo.noShowQuotes)
+	}
+}
+
+func TestProcessNoShows_SkipsBookingsChangedConcurrently(t *testing.T) {
+	checkIn := time.Date(2030, 5, 10, 0, 0, 0, 0, time.UTC)
+	candidate := func(id int) models.Booking {
+		return models.Booking{ID: id, Status: models.BookingStatusConfirmed, CheckInDate: checkIn, CheckOutDate: checkIn.AddDate(0, 0, 2), TotalAmount: 200}
+	}
+	full := make([]models.Booking, processNoShowsBatchSize)
+	for i := range full {
+		full[i] = candidate(100 + i)
+	}
+	repo := &mockBookingRepo{
+		noShowBatches: [][]models.Booking{full, {candidate(1), candidate(2)}, {candidate(3)}},
+		set: func(ctx context.Context, id int, from, to string, changedBy *int, reason string) (*models.Booking, error) {
+			if id == 2 {
+				return nil, fmt.Errorf("booking status changed concurrently: %w", ErrConflict)
+			}
+			return &models.Booking{ID: id, Status: to}, nil
+		},
+	}
+	svc := &BookingService{repo: repo, config: DefaultBookingConfig, now: func() time.Time { return checkIn.Add(23 * time.Hour) }}
+
+	marked, err := svc.ProcessNoShows(context.Background())
+	if err != nil || marked != processNoShowsBatchSize+1 {
+		t.Fatalf("expected %d no-shows, got %d err=%v", processNoShowsBatchSize+1, marked, err)
+	}
+	if _, ok := repo.noShowQuotes[2]; ok {
+		t.Fatal("expected the concurrently changed booking to be skipped")
+	}
+	if len(repo.noShowBatches) != 1 {
+		t.Fatalf("expected to stop after a partial batch, %d batches left", len(repo.noShowBatches))
+	}
 }
@@ -0,0 +1,210 @@
+// Package service provides business logic layer implementations.
+// Services contain validation logic and business rules before delegating to repositories.
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"industry-api/internal/models"
+	"industry-api/internal/render"
+	"industry-api/internal/repository"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Calendar feeds cover a window around today, so clients keep recent history without the feed
+// growing forever.
+const (
+	calendarFeedPast   = 30 * 24 * time.Hour
+	calendarFeedFuture = 366 * 24 * time.Hour
+)
+
+// calendarFeedScopes lists the scopes a feed can have.
+var calendarFeedScopes = []string{models.CalendarFeedRoom, models.CalendarFeedProperty, models.CalendarFeedStaff}
+
+// CalendarConfig holds the configurable settings of calendar feeds.
+type CalendarConfig struct {
+	Secret  []byte // Key signing the feed tokens
+	FeedURL string // URL the feeds are served under; the token and .ics are appended
+}
+
+// CalendarConfigFromEnv builds the calendar feed configuration from CALENDAR_SECRET (JWT_SECRET
+// when unset) and CALENDAR_FEED_URL (the feed endpoint by default).
+func CalendarConfigFromEnv() CalendarConfig {
+	config := CalendarConfig{Secret: []byte(os.Getenv("CALENDAR_SECRET")), FeedURL: os.Getenv("CALENDAR_FEED_URL")}
+	if len(config.Secret) == 0 {
+		config.Secret = []byte(os.Getenv("JWT_SECRET"))
+	}
+	if config.FeedURL == "" {
+		config.FeedURL = "/api/v1/calendar/feeds"
+	}
+	config.FeedURL = strings.TrimRight(config.FeedURL, "/")
+	return config
+}
+
+// CalendarService publishes the bookings and maintenance windows of rooms as iCalendar feeds.
+// Feeds are read by calendar apps without logging in, so they are authorised by a signed token
+// in the feed URL.
+type CalendarService struct {
+	repo   repository.CalendarRepo // Repository interface for data access (allows mocking in tests)
+	config CalendarConfig          // Token key and feed URL
+	now    func() time.Time        // Clock, replaceable in tests
+}
+
+// NewCalendarService creates and returns a new instance of CalendarService.
+// It accepts a CalendarRepo interface for data access operations and the configuration.
+func NewCalendarService(repo repository.CalendarRepo, config CalendarConfig) *CalendarService {
+	return &CalendarService{repo: repo, config: config, now: time.Now}
+}
+
+// FeedLink returns the signed feed link of a room, property or staff member. Rooms and
+// properties outside propertyIDs (nil for all) are reported as not found.
+// Returns a validation error for an unknown scope or when no signing key is configured.
+func (s *CalendarService) FeedLink(ctx context.Context, scope string, id int, propertyIDs []int) (*models.CalendarFeedLink, error) {
+	if !slices.Contains(calendarFeedScopes, scope) {
+		return nil, validationError("unknown calendar feed %q", scope)
+	}
+	if id <= 0 {
+		return nil, validationError("%s id is required", scope)
+	}
+	if len(s.config.Secret) == 0 {
+		return nil, validationError("calendar feeds are not configured")
+	}
+	switch scope {
+	case models.CalendarFeedRoom:
+		propertyID, err := s.repo.GetRoomPropertyID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if propertyIDs != nil && !slices.Contains(propertyIDs, propertyID) {
+			return nil, fmt.Errorf("room %w", ErrNotFound)
+		}
+	case models.CalendarFeedProperty:
+		if propertyIDs != nil && !slices.Contains(propertyIDs, id) {
+			return nil, fmt.Errorf("property %w", ErrNotFound)
+		}
+	}
+	token := s.signFeed(scope, id)
+	return &models.CalendarFeedLink{Scope: scope, ID: id, Token: token, URL: s.config.FeedURL + "/" + token + ".ics"}, nil
+}
+
+// Feed renders the calendar a feed token grants access to. A staff member's feed covers the
+// properties they are assigned to when it is read (every property for admins).
+// Returns a validation error for an invalid token, or ErrNotFound once the user of a staff feed
+// no longer works at the hotel.
+func (s *CalendarService) Feed(ctx context.Context, token string) ([]byte, error) {
+	scope, id, err := s.verifyFeedToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock()
+	filter := models.CalendarFilter{From: now.Add(-calendarFeedPast), To: now.Add(calendarFeedFuture)}
+	var name string
+	switch scope {
+	case models.CalendarFeedRoom:
+		filter.RoomID = &id
+		name = fmt.Sprintf("Room %d", id)
+	case models.CalendarFeedProperty:
+		filter.PropertyIDs = []int{id}
+		name = fmt.Sprintf("Property %d", id)
+	case models.CalendarFeedStaff:
+		role, propertyIDs, err := s.repo.GetFeedUser(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		switch role {
+		case models.RoleAdmin:
+		case models.RoleStaff:
+			filter.PropertyIDs = propertyIDs
+		default:
+			return nil, fmt.Errorf("calendar feed %w", ErrNotFound)
+		}
+		name = "My properties"
+	}
+
+	entries, err := s.repo.ListCalendarEntries(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]models.CalendarEvent, 0, len(entries))
+	for _, entry := range entries {
+		events = append(events, calendarEvent(entry))
+	}
+	return render.ICalendar(name, events), nil
+}
+
+// calendarEvent turns a booking or maintenance window into a calendar event. Stays are all-day
+// events from the arrival to the departure date; maintenance windows keep their times.
+func calendarEvent(entry models.CalendarEntry) models.CalendarEvent {
+	event := models.CalendarEvent{
+		UID:          fmt.Sprintf("%s-%d@industry-api", entry.Kind, entry.ID),
+		Description:  "Status: " + entry.Status,
+		Status:       "CONFIRMED",
+		Sequence:     entry.Sequence,
+		LastModified: entry.UpdatedAt,
+	}
+	if entry.Kind == models.CalendarEntryMaintenance {
+		event.Summary = fmt.Sprintf("Room %s: maintenance", entry.RoomNumber)
+		if entry.Reason != "" {
+			event.Summary += " - " + entry.Reason
+		}
+		event.Start, event.End = entry.Start, entry.End
+		return event
+	}
+
+	guests := fmt.Sprintf("%d adult", entry.Adults)
+	if entry.Adults != 1 {
+		guests += "s"
+	}
+	if entry.Children > 0 {
+		guests += fmt.Sprintf(", %d child", entry.Children)
+		if entry.Children != 1 {
+			guests += "ren"
+		}
+	}
+	event.Summary = fmt.Sprintf("Room %s: booking #%d (%s)", entry.RoomNumber, entry.ID, guests)
+	event.Start, event.End, event.AllDay = dateOnly(entry.Start), dateOnly(entry.End), true
+	if entry.Status == models.BookingStatusPending {
+		event.Status = "TENTATIVE"
+	}
+	return event
+}
+
+// signFeed returns the token of a feed: its scope and ID followed by an HMAC-SHA256 of both.
+func (s *CalendarService) signFeed(scope string, id int) string {
+	payload := scope + "." + strconv.Itoa(id)
+	mac := hmac.New(sha256.New, s.config.Secret)
+	mac.Write([]byte(payload))
+	return payload + "." + hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifyFeedToken checks a feed token's signature and returns the scope and ID it names.
+func (s *CalendarService) verifyFeedToken(token string) (string, int, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || len(s.config.Secret) == 0 {
+		return "", 0, validationError("invalid calendar feed token")
+	}
+	id, err := strconv.Atoi(parts[1])
+	if err != nil || !slices.Contains(calendarFeedScopes, parts[0]) {
+		return "", 0, validationError("invalid calendar feed token")
+	}
+	if !hmac.Equal([]byte(s.signFeed(parts[0], id)), []byte(token)) {
+		return "", 0, validationError("invalid calendar feed token")
+	}
+	return parts[0], id, nil
+}
+
+// clock returns the current time from the configured clock.
+func (s *CalendarService) clock() time.Time {
+	if s.now == nil {
+		return time.Now()
+	}
+	return s.now()
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"industry-api/internal/models"
+)
+
+type mockCalendarRepo struct {
+	entries     []models.CalendarEntry
+	role        string
+	propertyIDs []int
+	filter      models.CalendarFilter
+}
+
+func (m *mockCalendarRepo) ListCalendarEntries(ctx context.Context, filter models.CalendarFilter) ([]models.CalendarEntry, error) {
+	m.filter = filter
+	return m.entries, nil
+}
+func (m *mockCalendarRepo) GetFeedUser(ctx context.Context, userID int) (string, []int, error) {
+	return m.role, m.propertyIDs, nil
+}
+func (m *mockCalendarRepo) GetRoomPropertyID(ctx context.Context, roomID int) (int, error) {
+	return 2, nil
+}
+
+func TestCalendarFeedLink_SignedAndScoped(t *testing.T) {
+	repo := &mockCalendarRepo{}
+	svc := NewCalendarService(repo, CalendarConfig{Secret: []byte("secret"), FeedURL: "https://hotel.example/api/v1/calendar/feeds"})
+
+	link, err := svc.FeedLink(context.Background(), models.CalendarFeedRoom, 7, []int{2})
+	if err != nil {
+		t.Fatalf("FeedLink failed: %v", err)
+	}
+	if !strings.HasPrefix(link.Token, "room.7.") || link.URL != "https://hotel.example/api/v1/calendar/feeds/"+link.Token+".ics" {
+		t.Fatalf("unexpected feed link: %+v", link)
+	}
+	if _, err := svc.FeedLink(context.Background(), models.CalendarFeedRoom, 7, []int{1}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected a room of another property to be not found, got %v", err)
+	}
+	if _, err := svc.FeedLink(context.Background(), models.CalendarFeedProperty, 3, []int{1, 2}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected a property out of scope to be not found, got %v", err)
+	}
+	if _, err := svc.FeedLink(context.Background(), "guest", 1, nil); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected an unknown scope to be rejected, got %v", err)
+	}
+
+	if _, err := svc.Feed(context.Background(), link.Token); err != nil || repo.filter.RoomID == nil || *repo.filter.RoomID != 7 {
+		t.Fatalf("expected the room feed, got filter %+v err=%v", repo.filter, err)
+	}
+	for _, token := range []string{"room.8." + link.Token[len("room.7."):], link.Token + "0", "room.7", ""} {
+		if _, err := svc.Feed(context.Background(), token); !errors.Is(err, ErrValidation) {
+			t.Fatalf("expected token %q to be rejected, got %v", token, err)
+		}
+	}
+	other := NewCalendarService(repo, CalendarConfig{Secret: []byte("other")})
+	if _, err := other.Feed(context.Background(), link.Token); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected a token signed with another key to be rejected, got %v", err)
+	}
+}
+
+func TestCalendarFeed_StaffEvents(t *testing.T) {
+	updated := time.Date(2030, 4, 20, 10, 0, 0, 0, time.UTC)
+	repo := &mockCalendarRepo{role: models.RoleStaff, propertyIDs: []int{1, 3}, entries: []models.CalendarEntry{
+		{Kind: models.CalendarEntryBooking, ID: 12, RoomNumber: "101", Start: time.Date(2030, 5, 3, 14, 0, 0, 0, time.UTC), End: time.Date(2030, 5, 5, 11, 0, 0, 0, time.UTC),
+			Status: models.BookingStatusPending, Adults: 2, Children: 1, Sequence: 4, UpdatedAt: updated},
+		{Kind: models.CalendarEntryMaintenance, ID: 5, RoomNumber: "102", Start: time.Date(2030, 5, 4, 8, 0, 0, 0, time.UTC), End: time.Date(2030, 5, 4, 12, 0, 0, 0, time.UTC),
+			Status: "scheduled", Reason: "Leak", UpdatedAt: updated},
+	}}
+	svc := NewCalendarService(repo, CalendarConfig{Secret: []byte("secret")})
+	svc.now = func() time.Time { return time.Date(2030, 5, 1, 0, 0, 0, 0, time.UTC) }
+	link, err := svc.FeedLink(context.Background(), models.CalendarFeedStaff, 9, nil)
+	if err != nil {
+		t.Fatalf("FeedLink failed: %v", err)
+	}
+
+	body, err := svc.Feed(context.Background(), link.Token)
+	if err != nil {
+		t.Fatalf("Feed failed: %v", err)
+	}
+	if len(repo.filter.PropertyIDs) != 2 || !repo.filter.From.Equal(time.Date(2030, 4, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected the staff member's properties around today, got %+v", repo.filter)
+	}
+	feed := string(body)
+	for _, want := range []string{
+		"UID:booking-12@industry-api", "Room 101: booking #12 (2 adults\\, 1 child)", "DTSTART;VALUE=DATE:20300503", "DTEND;VALUE=DATE:20300505",
+		"STATUS:TENTATIVE", "SEQUENCE:4", "UID:maintenance-5@industry-api", "Room 102: maintenance - Leak", "DTSTART:20300504T080000Z",
+	} {
+		if !strings.Contains(feed, want) {
+			t.Fatalf("expected %q in the feed:\n%s", want, feed)
+		}
+	}
+
+	repo.role = models.RoleAdmin
+	if _, err := svc.Feed(context.Background(), link.Token); err != nil || repo.filter.PropertyIDs != nil {
+		t.Fatalf("expected an admin's feed to cover every property, got %+v err=%v", repo.filter, err)
+	}
+	repo.role = models.RoleGuest
+	if _, err := svc.Feed(context.Background(), link.Token); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected the feed of a user who is no longer staff to be gone, got %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+// Package service provides business logic layer implementations.
+// Services contain validation logic and business rules before delegating to repositories.
+package service
+
+import (
+	"fmt"
+	"industry-api/internal/models"
+	"math"
+	"os"
+	"strconv"
+	"time"
+)
+
+// DefaultCancellationPolicy is used when no policy is configured: cancelling is free until
+// 24 hours before check-in, after which the first night is charged.
+var DefaultCancellationPolicy = models.CancellationPolicy{
+	FreeCancellationHours: 24,
+	PenaltyType:           models.PenaltyFirstNight,
+}
+
+// CancellationPolicyFromEnv builds the cancellation policy from CANCELLATION_FREE_HOURS,
+// CANCELLATION_PENALTY_TYPE and CANCELLATION_PENALTY_PERCENT, falling back to
+// DefaultCancellationPolicy for unset variables.
+// Returns an error if a variable is set to an invalid value.
+func CancellationPolicyFromEnv() (models.CancellationPolicy, error) {
+	policy := DefaultCancellationPolicy
+	if v := os.Getenv("CANCELLATION_FREE_HOURS"); v != "" {
+		hours, err := strconv.Atoi(v)
+		if err != nil {
+			return policy, fmt.Errorf("invalid CANCELLATION_FREE_HOURS %q: %w", v, err)
+		}
+		policy.FreeCancellationHours = hours
+	}
+	if v := os.Getenv("CANCELLATION_PENALTY_TYPE"); v != "" {
+		policy.PenaltyType = v
+	}
+	if v := os.Getenv("CANCELLATION_PENALTY_PERCENT"); v != "" {
+		percent, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return policy, fmt.Errorf("invalid CANCELLATION_PENALTY_PERCENT %q: %w", v, err)
+		}
+		policy.PenaltyPercent = percent
+	}
+	return policy, validateCancellationPolicy(policy)
+}
+
+// validateCancellationPolicy checks that a policy can be applied.
+func validateCancellationPolicy(policy models.CancellationPolicy) error {
+	if policy.FreeCancellationHours < 0 {
+		return validationError("free cancellation hours cannot be negative")
+	}
+	switch policy.PenaltyType {
+	case models.PenaltyFirstNight:
+	case models.PenaltyPercentage:
+		if policy.PenaltyPercent < 0 || policy.PenaltyPercent > 100 {
+			return validationError("penalty percent must be between 0 and 100")
+		}
+	default:
+		return validationError("unknown penalty type %q", policy.PenaltyType)
+	}
+	return nil
+}
+
+// quoteCancellation prices cancelling a booking at now under a policy.
+// The penalty is never more than the booking total, and the refund is whatever was paid
+// beyond the penalty; an unpaid penalty is not charged.
+func quoteCancellation(policy models.CancellationPolicy, booking *models.Booking, paidCents int64, now time.Time) models.CancellationQuote {
+	freeUntil := booking.CheckInDate.Add(-time.Duration(policy.FreeCancellationHours) * time.Hour)
+	quote := models.CancellationQuote{
+		BookingID:          booking.ID,
+		Policy:             policy,
+		QuotedAt:           now,
+		FreeUntil:          freeUntil,
+		HoursBeforeCheckIn: math.Round(booking.CheckInDate.Sub(now).Hours()*100) / 100,
+		TotalAmount:        booking.TotalAmount,
+		AmountPaid:         roundMoney(float64(paidCents) / 100),
+	}
+
+	if now.After(freeUntil) {
+		switch policy.PenaltyType {
+		case models.PenaltyPercentage:
+			quote.Penalty = roundMoney(booking.TotalAmount * policy.PenaltyPercent / 100)
+		case models.PenaltyFirstNight:
+			quote.Penalty = firstNightPrice(booking)
+		}
+		quote.Penalty = math.Min(quote.Penalty, booking.TotalAmount)
+	}
+	quote.Refund = roundMoney(math.Max(0, quote.AmountPaid-quote.Penalty))
+	return quote
+}
+
+// firstNightPrice returns what the first night of a booking costs. Per-stay fees and fixed
+// discounts are not part of it. Bookings priced before the first night was recorded fall back
+// to an even share of the total.
+func firstNightPrice(booking *models.Booking) float64 {
+	if booking.PriceBreakdown != nil && booking.PriceBreakdown.FirstNight > 0 {
+		return booking.PriceBreakdown.FirstNight
+	}
+	nights := nightsBetween(booking.CheckInDate, booking.CheckOutDate)
+	if nights <= 0 {
+		return 0
+	}
+	return roundMoney(booking.TotalAmount / float64(nights))
+}
+
+// quoteNoShow prices a no-show detected at now: the booking's cancellation terms applied as if
+// it were cancelled at arrival, so the penalty is charged even for a policy whose free window
+// would have ended later. Unlike a cancellation, the unpaid part of the penalty stays owed.
+func quoteNoShow(policy models.CancellationPolicy, booking *models.Booking, paidCents int64, now time.Time) models.CancellationQuote {
+	at := now
+	if at.Before(booking.CheckInDate) {
+		at = booking.CheckInDate
+	}
+	if policy.FreeCancellationHours == 0 {
+		// A policy free until arrival would still waive the penalty exactly at check-in
+		at = at.Add(time.Second)
+	}
+	quote := quoteCancellation(policy, booking, paidCents, at)
+	quote.QuotedAt = now
+	quote.HoursBeforeCheckIn = math.Round(booking.CheckInDate.Sub(now).Hours()*100) / 100
+	quote.PenaltyDue = roundMoney(math.Max(0, quote.Penalty-quote.AmountPaid))
+	return quote
+}
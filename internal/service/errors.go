@@ -0,0 +1,36 @@
+// Package service provides business logic layer implementations.
+// Services contain validation logic and business rules before delegating to repositories.
+package service
+
+import (
+	"errors"
+	"fmt"
+	"industry-api/internal/repository"
+)
+
+// ErrValidation is wrapped by errors caused by invalid client input.
+// Handlers use errors.Is(err, ErrValidation) to answer with 400 Bad Request.
+var ErrValidation = errors.New("validation failed")
+
+// validationError builds an error that wraps ErrValidation with a descriptive message.
+func validationError(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrValidation, fmt.Sprintf(format, args...))
+}
+
+// ErrNotFound is wrapped by errors returned when a requested record does not exist.
+// Handlers use errors.Is(err, ErrNotFound) to answer with 404 Not Found.
+var ErrNotFound = repository.ErrNotFound
+
+// ErrConflict is wrapped by errors returned when a request conflicts with the current state.
+// Handlers use errors.Is(err, ErrConflict) to answer with 409 Conflict.
+var ErrConflict = repository.ErrConflict
+
+// conflictError builds an error that wraps ErrConflict with a descriptive message.
+func conflictError(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrConflict, fmt.Sprintf(format, args...))
+}
+
+// BookingConflictError is returned when a booking overlaps an active booking of the same room or
+// an external reservation.
+// It wraps ErrConflict; handlers use errors.As to include the conflicting dates in the response.
+type BookingConflictError = repository.BookingConflictError
@@ -0,0 +1,190 @@
+// Package service provides business logic layer implementations.
+// Services contain validation logic and business rules before delegating to repositories.
+package service
+
+import (
+	"context"
+	"fmt"
+	"industry-api/internal/calsync"
+	"industry-api/internal/models"
+	"industry-api/internal/repository"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// syncExternalCalendarBatchSize caps how many feeds one pass of the sync job downloads.
+const syncExternalCalendarBatchSize = 20
+
+// externalCalendarFetchTimeout bounds the download of a single feed.
+const externalCalendarFetchTimeout = 30 * time.Second
+
+// ExternalCalendarConfig holds the configurable settings of external calendar sync.
+type ExternalCalendarConfig struct {
+	SyncInterval time.Duration // How often each feed is downloaded
+}
+
+// ExternalCalendarConfigFromEnv builds the external calendar configuration from
+// EXTERNAL_CALENDAR_SYNC_INTERVAL (a Go duration, 15 minutes by default).
+// Returns an error if the variable is set to an invalid value.
+func ExternalCalendarConfigFromEnv() (ExternalCalendarConfig, error) {
+	config := ExternalCalendarConfig{SyncInterval: 15 * time.Minute}
+	if v := os.Getenv("EXTERNAL_CALENDAR_SYNC_INTERVAL"); v != "" {
+		interval, err := time.ParseDuration(v)
+		if err != nil || interval <= 0 {
+			return config, fmt.Errorf("invalid EXTERNAL_CALENDAR_SYNC_INTERVAL %q: must be a positive duration", v)
+		}
+		config.SyncInterval = interval
+	}
+	return config, nil
+}
+
+// ExternalCalendarService imports the iCal feeds of external booking platforms. Each reservation
+// in a room's feed becomes an external block that keeps the room's nights from being booked here.
+type ExternalCalendarService struct {
+	repo   repository.ExternalCalendarRepo // Repository interface for data access (allows mocking in tests)
+	client *calsync.Client                 // Downloads and parses the feeds
+	config ExternalCalendarConfig          // Sync interval
+	now    func() time.Time                // Clock, replaceable in tests
+}
+
+// NewExternalCalendarService creates and returns a new instance of ExternalCalendarService.
+// It accepts an ExternalCalendarRepo interface for data access operations, the client used to
+// download feeds and the configuration.
+func NewExternalCalendarService(repo repository.ExternalCalendarRepo, client *calsync.Client, config ExternalCalendarConfig) *ExternalCalendarService {
+	return &ExternalCalendarService{repo: repo, client: client, config: config, now: time.Now}
+}
+
+// AddCalendar attaches an external feed to a room and syncs it right away, so its reservations
+// block the room immediately. The name defaults to the feed's host.
+// Returns a validation error for a URL that is not http, https or webcal, an error wrapping
+// ErrNotFound for an unknown room, or ErrConflict if the feed is already attached to the room.
+// A failed first sync does not fail the request; it is reported in the calendar's sync state.
+func (s *ExternalCalendarService) AddCalendar(ctx context.Context, calendar *models.ExternalCalendar) (*models.ExternalCalendar, error) {
+	calendar.URL = strings.TrimSpace(calendar.URL)
+	calendar.Name = strings.TrimSpace(calendar.Name)
+	parsed, err := url.Parse(calendar.URL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https" && parsed.Scheme != "webcal") {
+		return nil, validationError("url must be an http, https or webcal URL")
+	}
+	if calendar.Name == "" {
+		calendar.Name = parsed.Hostname()
+	}
+	if err := s.repo.CreateExternalCalendar(ctx, calendar); err != nil {
+		return nil, err
+	}
+	return s.SyncCalendar(ctx, calendar.ID)
+}
+
+// ListCalendars returns the external calendars of the given properties (nil for all) with their
+// sync state.
+func (s *ExternalCalendarService) ListCalendars(ctx context.Context, propertyIDs []int) ([]models.ExternalCalendar, error) {
+	return s.repo.ListExternalCalendars(ctx, propertyIDs)
+}
+
+// GetCalendar returns an external calendar with its sync state.
+// Returns an error wrapping ErrNotFound if the calendar does not exist.
+func (s *ExternalCalendarService) GetCalendar(ctx context.Context, id int) (*models.ExternalCalendar, error) {
+	return s.repo.GetExternalCalendar(ctx, id)
+}
+
+// ListBlocks returns the blocks currently imported from an external calendar.
+func (s *ExternalCalendarService) ListBlocks(ctx context.Context, calendarID int) ([]models.ExternalBlock, error) {
+	return s.repo.ListExternalBlocks(ctx, calendarID)
+}
+
+// DeleteCalendar detaches an external calendar; its blocks are removed and their nights freed.
+func (s *ExternalCalendarService) DeleteCalendar(ctx context.Context, id int) error {
+	return s.repo.DeleteExternalCalendar(ctx, id)
+}
+
+// SyncCalendar downloads an external calendar now and returns it with its new sync state.
+// Download and parse failures are recorded on the calendar rather than returned.
+func (s *ExternalCalendarService) SyncCalendar(ctx context.Context, id int) (*models.ExternalCalendar, error) {
+	calendar, err := s.repo.GetExternalCalendar(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.sync(ctx, calendar); err != nil {
+		return nil, err
+	}
+	return s.repo.GetExternalCalendar(ctx, id)
+}
+
+// SyncDue downloads the external calendars not synced within the sync interval and updates
+// their blocks. It is run periodically by a background job and returns how many calendars
+// synced successfully. Failed downloads are recorded on their calendar and retried after the
+// next interval; their blocks are kept meanwhile.
+func (s *ExternalCalendarService) SyncDue(ctx context.Context) (int, error) {
+	calendars, err := s.repo.ClaimDueExternalCalendars(ctx, s.clock().Add(-s.config.SyncInterval), syncExternalCalendarBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	synced := 0
+	for i := range calendars {
+		ok, err := s.sync(ctx, &calendars[i])
+		if err != nil {
+			return synced, err
+		}
+		if ok {
+			synced++
+		}
+	}
+	return synced, nil
+}
+
+// sync downloads one calendar and stores the outcome. It reports whether the download succeeded;
+// only failures to store the outcome are returned as errors.
+func (s *ExternalCalendarService) sync(ctx context.Context, calendar *models.ExternalCalendar) (bool, error) {
+	fetchCtx, cancel := context.WithTimeout(ctx, externalCalendarFetchTimeout)
+	defer cancel()
+	var etag, lastModified string
+	if calendar.ETag != nil {
+		etag = *calendar.ETag
+	}
+	if calendar.LastModified != nil {
+		lastModified = *calendar.LastModified
+	}
+
+	feed, err := s.client.Fetch(fetchCtx, calendar.URL, etag, lastModified)
+	if err != nil {
+		return false, s.repo.RecordExternalCalendarError(ctx, calendar.ID, err.Error())
+	}
+	if feed.NotModified {
+		return true, s.repo.MarkExternalCalendarSynced(ctx, calendar.ID, feed.ETag, feed.LastModified)
+	}
+	blocks := externalBlocks(feed.Events, dateOnly(s.clock()))
+	return true, s.repo.ReplaceExternalBlocks(ctx, calendar.ID, blocks, feed.ETag, feed.LastModified)
+}
+
+// externalBlocks turns the events of a feed into blocked nights. An event blocks the nights
+// from its start date up to its end date, and at least the night it starts. Cancelled events,
+// events that ended before today and repeated UIDs are skipped.
+func externalBlocks(events []models.CalendarEvent, today time.Time) []models.ExternalBlock {
+	blocks := []models.ExternalBlock{}
+	seen := map[string]bool{}
+	for _, event := range events {
+		if event.Status == "CANCELLED" || seen[event.UID] {
+			continue
+		}
+		start, end := dateOnly(event.Start), dateOnly(event.End)
+		if !end.After(start) {
+			end = start.AddDate(0, 0, 1)
+		}
+		if !end.After(today) {
+			continue
+		}
+		seen[event.UID] = true
+		blocks = append(blocks, models.ExternalBlock{UID: event.UID, Summary: event.Summary, StartDate: start, EndDate: end})
+	}
+	return blocks
+}
+
+// clock returns the current time from the configured clock.
+func (s *ExternalCalendarService) clock() time.Time {
+	if s.now == nil {
+		return time.Now()
+	}
+	return s.now()
+}
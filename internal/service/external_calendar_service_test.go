@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"industry-api/internal/calsync"
+	"industry-api/internal/models"
+)
+
+type mockExternalCalendarRepo struct {
+	calendars map[int]*models.ExternalCalendar
+	blocks    map[int][]models.ExternalBlock
+	claimedBy time.Time
+	errors    map[int]string
+	synced    map[int]int
+}
+
+func newMockExternalCalendarRepo(calendars ...models.ExternalCalendar) *mockExternalCalendarRepo {
+	m := &mockExternalCalendarRepo{calendars: map[int]*models.ExternalCalendar{}, blocks: map[int][]models.ExternalBlock{}, errors: map[int]string{}, synced: map[int]int{}}
+	for i := range calendars {
+		m.calendars[calendars[i].ID] = &calendars[i]
+	}
+	return m
+}
+
+func (m *mockExternalCalendarRepo) CreateExternalCalendar(ctx context.Context, calendar *models.ExternalCalendar) error {
+	calendar.ID = len(m.calendars) + 1
+	m.calendars[calendar.ID] = calendar
+	return nil
+}
+func (m *mockExternalCalendarRepo) GetExternalCalendar(ctx context.Context, id int) (*models.ExternalCalendar, error) {
+	calendar, ok := m.calendars[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	copied := *calendar
+	return &copied, nil
+}
+func (m *mockExternalCalendarRepo) ListExternalCalendars(ctx context.Context, propertyIDs []int) ([]models.ExternalCalendar, error) {
+	return nil, nil
+}
+func (m *mockExternalCalendarRepo) DeleteExternalCalendar(ctx context.Context, id int) error {
+	return nil
+}
+func (m *mockExternalCalendarRepo) ListExternalBlocks(ctx context.Context, calendarID int) ([]models.ExternalBlock, error) {
+	return m.blocks[calendarID], nil
+}
+func (m *mockExternalCalendarRepo) ClaimDueExternalCalendars(ctx context.Context, syncedBefore time.Time, limit int) ([]models.ExternalCalendar, error) {
+	m.claimedBy = syncedBefore
+	due := []models.ExternalCalendar{}
+	for id := 1; id <= len(m.calendars); id++ {
+		due = append(due, *m.calendars[id])
+	}
+	return due, nil
+}
+func (m *mockExternalCalendarRepo) ReplaceExternalBlocks(ctx context.Context, calendarID int, blocks []models.ExternalBlock, etag, lastModified string) error {
+	m.blocks[calendarID] = blocks
+	return m.MarkExternalCalendarSynced(ctx, calendarID, etag, lastModified)
+}
+func (m *mockExternalCalendarRepo) MarkExternalCalendarSynced(ctx context.Context, calendarID int, etag, lastModified string) error {
+	m.synced[calendarID]++
+	m.calendars[calendarID].ETag = &etag
+	m.calendars[calendarID].LastError = nil
+	delete(m.errors, calendarID)
+	return nil
+}
+func (m *mockExternalCalendarRepo) RecordExternalCalendarError(ctx context.Context, calendarID int, message string) error {
+	m.errors[calendarID] = message
+	m.calendars[calendarID].LastError = &message
+	return nil
+}
+
+// platformFeed is the feed served by the stand-in for an external platform.
+const platformFeed = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\nUID:stay-1\r\nDTSTART;VALUE=DATE:20300503\r\nDTEND;VALUE=DATE:20300506\r\nSUMMARY:Reserved\r\nEND:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\nUID:stay-2\r\nDTSTART:20300510T140000Z\r\nDTEND:20300512T100000Z\r\nSUMMARY:Reserved\r\nEND:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\nUID:stay-3\r\nDTSTART;VALUE=DATE:20300515\r\nDTEND;VALUE=DATE:20300516\r\nSTATUS:CANCELLED\r\nEND:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\nUID:stay-0\r\nDTSTART;VALUE=DATE:20300420\r\nDTEND;VALUE=DATE:20300422\r\nEND:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestSyncDue_ImportsBlocksFromFeeds(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/room-101.ics" {
+			http.Error(w, "gone", http.StatusGone)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"rev-1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"rev-1"`)
+		w.Write([]byte(platformFeed))
+	}))
+	defer server.Close()
+
+	repo := newMockExternalCalendarRepo(
+		models.ExternalCalendar{ID: 1, RoomID: 7, URL: server.URL + "/room-101.ics", Active: true},
+		models.ExternalCalendar{ID: 2, RoomID: 8, URL: server.URL + "/room-102.ics", Active: true},
+	)
+	svc := NewExternalCalendarService(repo, &calsync.Client{}, ExternalCalendarConfig{SyncInterval: 15 * time.Minute})
+	now := time.Date(2030, 5, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	synced, err := svc.SyncDue(context.Background())
+	if err != nil || synced != 1 {
+		t.Fatalf("expected one calendar to sync, got %d err=%v", synced, err)
+	}
+	if !repo.claimedBy.Equal(now.Add(-15 * time.Minute)) {
+		t.Fatalf("expected calendars not synced within the interval to be claimed, got %v", repo.claimedBy)
+	}
+	blocks := repo.blocks[1]
+	if len(blocks) != 2 {
+		t.Fatalf("expected the cancelled and past stays to be skipped, got %+v", blocks)
+	}
+	if blocks[0].UID != "stay-1" || !blocks[0].StartDate.Equal(time.Date(2030, 5, 3, 0, 0, 0, 0, time.UTC)) || !blocks[0].EndDate.Equal(time.Date(2030, 5, 6, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected all-day block: %+v", blocks[0])
+	}
+	if !blocks[1].StartDate.Equal(time.Date(2030, 5, 10, 0, 0, 0, 0, time.UTC)) || !blocks[1].EndDate.Equal(time.Date(2030, 5, 12, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected a timed stay to block the nights it covers, got %+v", blocks[1])
+	}
+	if msg := repo.errors[2]; msg == "" || repo.synced[2] != 0 {
+		t.Fatalf("expected the failed download to be recorded, got %q", msg)
+	}
+
+	// An unchanged feed keeps its blocks
+	if _, err := svc.SyncDue(context.Background()); err != nil || repo.synced[1] != 2 || len(repo.blocks[1]) != 2 {
+		t.Fatalf("expected the unchanged feed to be marked synced, got %d syncs err=%v", repo.synced[1], err)
+	}
+}
+
+func TestAddCalendar_ValidatesURLAndSyncs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(platformFeed))
+	}))
+	defer server.Close()
+	repo := newMockExternalCalendarRepo()
+	svc := NewExternalCalendarService(repo, &calsync.Client{}, ExternalCalendarConfig{SyncInterval: time.Hour})
+	svc.now = func() time.Time { return time.Date(2030, 5, 1, 0, 0, 0, 0, time.UTC) }
+
+	for _, url := range []string{"", "ftp://example.com/feed.ics", "/relative.ics"} {
+		if _, err := svc.AddCalendar(context.Background(), &models.ExternalCalendar{RoomID: 7, URL: url}); !errors.Is(err, ErrValidation) {
+			t.Fatalf("expected %q to be rejected, got %v", url, err)
+		}
+	}
+	calendar, err := svc.AddCalendar(context.Background(), &models.ExternalCalendar{RoomID: 7, URL: " " + server.URL + "/feed.ics "})
+	if err != nil {
+		t.Fatalf("AddCalendar failed: %v", err)
+	}
+	if calendar.Name != "127.0.0.1" || calendar.LastError != nil || len(repo.blocks[calendar.ID]) != 2 {
+		t.Fatalf("expected the new calendar to be synced right away, got %+v with %d blocks", calendar, len(repo.blocks[calendar.ID]))
+	}
+}
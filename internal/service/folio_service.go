@@ -0,0 +1,149 @@
+// Package service provides business logic layer implementations.
+// Services contain validation logic and business rules before delegating to repositories.
+package service
+
+import (
+	"context"
+	"fmt"
+	"industry-api/internal/models"
+	"industry-api/internal/repository"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// FolioService handles booking folios: the incidental charges and credits posted during a stay
+// and the statement that sets them and the room charge against the payments received.
+type FolioService struct {
+	repo repository.FolioRepo // Repository interface for data access (allows mocking in tests)
+}
+
+// NewFolioService creates and returns a new instance of FolioService.
+// It accepts a FolioRepo interface for data access operations.
+func NewFolioService(repo repository.FolioRepo) *FolioService {
+	return &FolioService{repo: repo}
+}
+
+// GetFolio returns the statement of a booking: the room charge, every posted entry and every paid
+// payment in posting order with the running balance. Voided entries are listed but do not count.
+func (s *FolioService) GetFolio(ctx context.Context, booking *models.Booking) (*models.Folio, error) {
+	entries, err := s.repo.ListFolioEntries(ctx, booking.ID)
+	if err != nil {
+		return nil, err
+	}
+	payments, err := s.repo.ListPaidPayments(ctx, booking.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	room := models.FolioLine{
+		Type:        models.FolioLineRoom,
+		Description: "Room charge",
+		Amount:      booking.TotalAmount,
+		PostedAt:    booking.CreatedAt,
+	}
+	if booking.PriceBreakdown != nil {
+		room.Description = fmt.Sprintf("Room charge (%d nights)", booking.PriceBreakdown.Nights)
+		room.TaxAmount = booking.PriceBreakdown.TaxTotal
+	}
+	lines := []models.FolioLine{room}
+	for _, entry := range entries {
+		line := models.FolioLine{
+			Type:        entry.Kind,
+			EntryID:     &entry.ID,
+			Category:    entry.Category,
+			Description: entry.Description,
+			Amount:      roundMoney(entry.Amount + entry.TaxAmount),
+			TaxAmount:   entry.TaxAmount,
+			PostedBy:    entry.PostedBy,
+			PostedAt:    entry.PostedAt,
+			Voided:      entry.VoidedAt != nil,
+			VoidReason:  entry.VoidReason,
+		}
+		if entry.Kind == models.FolioCredit {
+			line.Amount, line.TaxAmount = -line.Amount, -line.TaxAmount
+		}
+		lines = append(lines, line)
+	}
+	for _, payment := range payments {
+		line := models.FolioLine{
+			Type:        models.FolioLinePayment,
+			PaymentID:   &payment.ID,
+			Description: fmt.Sprintf("Payment (%s)", payment.PaymentMethod),
+			Amount:      -roundMoney(float64(payment.Amount) / 100),
+			PostedAt:    payment.CreatedAt,
+		}
+		if payment.ProcessedAt != nil {
+			line.PostedAt = *payment.ProcessedAt
+		}
+		lines = append(lines, line)
+	}
+	// The room charge stays first; everything else follows in the order it happened
+	sort.SliceStable(lines[1:], func(i, j int) bool { return lines[i+1].PostedAt.Before(lines[j+1].PostedAt) })
+
+	folio := &models.Folio{BookingID: booking.ID, RoomCharge: booking.TotalAmount}
+	for i := range lines {
+		line := &lines[i]
+		if !line.Voided {
+			switch line.Type {
+			case models.FolioCharge:
+				folio.Charges = roundMoney(folio.Charges + line.Amount)
+			case models.FolioCredit:
+				folio.Credits = roundMoney(folio.Credits - line.Amount)
+			case models.FolioLinePayment:
+				folio.Payments = roundMoney(folio.Payments - line.Amount)
+			}
+			folio.Balance = roundMoney(folio.Balance + line.Amount)
+		}
+		line.Balance = folio.Balance
+	}
+	folio.Lines = lines
+	return folio, nil
+}
+
+// PostEntry posts a charge or credit to the folio of a checked-in booking on behalf of postedBy.
+// Returns a validation error for unknown categories or amounts, or a conflict error if the
+// booking is not checked in.
+func (s *FolioService) PostEntry(ctx context.Context, booking *models.Booking, entry *models.FolioEntry, postedBy *int) (*models.FolioEntry, error) {
+	if entry.Kind != models.FolioCharge && entry.Kind != models.FolioCredit {
+		return nil, validationError("kind must be %q or %q", models.FolioCharge, models.FolioCredit)
+	}
+	entry.Category = strings.ToLower(strings.TrimSpace(entry.Category))
+	if !slices.Contains(models.FolioCategories, entry.Category) {
+		return nil, validationError("category must be one of %s", strings.Join(models.FolioCategories, ", "))
+	}
+	entry.Amount, entry.TaxAmount = roundMoney(entry.Amount), roundMoney(entry.TaxAmount)
+	if entry.Amount <= 0 {
+		return nil, validationError("amount must be positive")
+	}
+	if entry.TaxAmount < 0 {
+		return nil, validationError("tax amount cannot be negative")
+	}
+	if booking.Status != models.BookingStatusCheckedIn {
+		return nil, conflictError("charges can only be posted to checked-in bookings, booking is %s", booking.Status)
+	}
+
+	entry.BookingID = booking.ID
+	entry.Description = strings.TrimSpace(entry.Description)
+	entry.PostedBy = postedBy
+	if err := s.repo.PostFolioEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// VoidEntry voids an entry of a checked-in booking's folio on behalf of voidedBy. A reason is
+// required and kept with the entry.
+func (s *FolioService) VoidEntry(ctx context.Context, booking *models.Booking, entryID int, voidedBy *int, reason string) (*models.FolioEntry, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, validationError("a reason is required to void a folio entry")
+	}
+	if entryID <= 0 {
+		return nil, validationError("folio entry id is required")
+	}
+	if booking.Status != models.BookingStatusCheckedIn {
+		return nil, conflictError("entries can only be voided on checked-in bookings, booking is %s", booking.Status)
+	}
+	return s.repo.VoidFolioEntry(ctx, booking.ID, entryID, voidedBy, reason)
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"industry-api/internal/models"
+)
+
+type mockFolioRepo struct {
+	entries  []models.FolioEntry
+	payments []models.Payment
+	posted   *models.FolioEntry
+	voided   string
+}
+
+func (m *mockFolioRepo) PostFolioEntry(ctx context.Context, entry *models.FolioEntry) error {
+	entry.ID = len(m.entries) + 1
+	m.posted = entry
+	return nil
+}
+func (m *mockFolioRepo) VoidFolioEntry(ctx context.Context, bookingID, entryID int, voidedBy *int, reason string) (*models.FolioEntry, error) {
+	m.voided = reason
+	return &models.FolioEntry{ID: entryID, BookingID: bookingID, VoidReason: &reason}, nil
+}
+func (m *mockFolioRepo) ListFolioEntries(ctx context.Context, bookingID int) ([]models.FolioEntry, error) {
+	return m.entries, nil
+}
+func (m *mockFolioRepo) ListPaidPayments(ctx context.Context, bookingID int) ([]models.Payment, error) {
+	return m.payments, nil
+}
+
+func TestGetFolio_RunningBalance(t *testing.T) {
+	created := time.Date(2030, 5, 1, 12, 0, 0, 0, time.UTC)
+	voidedAt := created.Add(50 * time.Hour)
+	reason := "posted to the wrong room"
+	paidAt := created.Add(72 * time.Hour)
+	repo := &mockFolioRepo{
+		entries: []models.FolioEntry{
+			{ID: 1, Kind: models.FolioCharge, Category: "minibar", Amount: 10, TaxAmount: 2, PostedAt: created.Add(48 * time.Hour)},
+			{ID: 2, Kind: models.FolioCharge, Category: "parking", Amount: 15, PostedAt: created.Add(49 * time.Hour), VoidedAt: &voidedAt, VoidReason: &reason},
+			{ID: 3, Kind: models.FolioCredit, Category: "adjustment", Amount: 5, PostedAt: created.Add(80 * time.Hour)},
+		},
+		payments: []models.Payment{{ID: 9, Amount: 20000, PaymentMethod: "credit_card", CreatedAt: created, ProcessedAt: &paidAt}},
+	}
+	svc := NewFolioService(repo)
+
+	folio, err := svc.GetFolio(context.Background(), &models.Booking{ID: 1, TotalAmount: 250, CreatedAt: created, PriceBreakdown: &models.PriceBreakdown{Nights: 2}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 250 room + 12 minibar - 200 paid - 5 credit; the voided parking does not count
+	if folio.Balance != 57 || folio.Charges != 12 || folio.Credits != 5 || folio.Payments != 200 || len(folio.Lines) != 5 {
+		t.Fatalf("unexpected folio: %+v", folio)
+	}
+	wantTypes := []string{models.FolioLineRoom, models.FolioCharge, models.FolioCharge, models.FolioLinePayment, models.FolioCredit}
+	wantBalances := []float64{250, 262, 262, 62, 57}
+	for i, line := range folio.Lines {
+		if line.Type != wantTypes[i] || line.Balance != wantBalances[i] {
+			t.Fatalf("line %d: expected %s with balance %v, got %+v", i, wantTypes[i], wantBalances[i], line)
+		}
+	}
+	if !folio.Lines[2].Voided {
+		t.Fatalf("expected the parking charge to be marked voided, got %+v", folio.Lines[2])
+	}
+}
+
+func TestPostEntry_Validation(t *testing.T) {
+	repo := &mockFolioRepo{}
+	svc := NewFolioService(repo)
+	staff := 7
+	booking := &models.Booking{ID: 1, Status: models.BookingStatusCheckedIn}
+
+	entry, err := svc.PostEntry(context.Background(), booking, &models.FolioEntry{Kind: models.FolioCharge, Category: " Room_Service ", Amount: 18.504, TaxAmount: 1.8}, &staff)
+	if err != nil || entry.Category != "room_service" || entry.Amount != 18.5 || entry.BookingID != 1 || *entry.PostedBy != staff {
+		t.Fatalf("unexpected entry: %+v err=%v", entry, err)
+	}
+	if _, err := svc.PostEntry(context.Background(), booking, &models.FolioEntry{Kind: models.FolioCharge, Category: "casino", Amount: 5}, &staff); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error for an unknown category, got %v", err)
+	}
+	booking.Status = models.BookingStatusCheckedOut
+	if _, err := svc.PostEntry(context.Background(), booking, &models.FolioEntry{Kind: models.FolioCharge, Category: "minibar", Amount: 5}, &staff); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected conflict for a checked-out booking, got %v", err)
+	}
+}
+
+func TestVoidEntry_RequiresReason(t *testing.T) {
+	repo := &mockFolioRepo{}
+	svc := NewFolioService(repo)
+	booking := &models.Booking{ID: 1, Status: models.BookingStatusCheckedIn}
+
+	if _, err := svc.VoidEntry(context.Background(), booking, 3, nil, "  "); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error without a reason, got %v", err)
+	}
+	if _, err := svc.VoidEntry(context.Background(), booking, 3, nil, " duplicate "); err != nil || repo.voided != "duplicate" {
+		t.Fatalf("expected the entry to be voided with its reason, got %q err=%v", repo.voided, err)
+	}
+}
@@ -0,0 +1,229 @@
+// Package service provides business logic layer implementations.
+// Services contain validation logic and business rules before delegating to repositories.
+package service
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"industry-api/internal/models"
+	"industry-api/internal/repository"
+	"os"
+	"strings"
+	"time"
+)
+
+// adultAge is the age from which a guest counts as an adult on the arrival date; younger guests
+// are children, which are priced at the child rate.
+const adultAge = 18
+
+// GuestConfig holds the configurable rules of guest manifests.
+type GuestConfig struct {
+	DocumentKey []byte // AES-256 key encrypting identity document numbers
+}
+
+// GuestConfigFromEnv builds the guest manifest configuration from GUEST_DOCUMENT_KEY, a
+// base64-encoded 32-byte key. Without a key, manifests can still be read but identity documents
+// cannot be recorded. Returns an error if the key is set to an invalid value.
+func GuestConfigFromEnv() (GuestConfig, error) {
+	var config GuestConfig
+	v := os.Getenv("GUEST_DOCUMENT_KEY")
+	if v == "" {
+		return config, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(v)
+	if err != nil || len(key) != 32 {
+		return config, fmt.Errorf("invalid GUEST_DOCUMENT_KEY: must be 32 bytes encoded in base64")
+	}
+	config.DocumentKey = key
+	return config, nil
+}
+
+// GuestService handles the guest manifests of bookings: who stays under a booking, checked
+// against its adult and child counts, and the registration report for the authorities.
+type GuestService struct {
+	repo   repository.GuestRepo // Repository interface for data access (allows mocking in tests)
+	config GuestConfig          // Document encryption key
+}
+
+// NewGuestService creates and returns a new instance of GuestService.
+// It accepts a GuestRepo interface for data access operations and the configuration.
+func NewGuestService(repo repository.GuestRepo, config GuestConfig) *GuestService {
+	return &GuestService{repo: repo, config: config}
+}
+
+// ListGuests returns the guest manifest of a booking with masked document numbers.
+func (s *GuestService) ListGuests(ctx context.Context, bookingID int) ([]models.BookingGuest, error) {
+	guests, err := s.repo.ListBookingGuests(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range guests {
+		if err := s.revealDocument(&guests[i].DocumentNumber, guests[i].DocumentCipher); err != nil {
+			return nil, err
+		}
+		guests[i].DocumentNumber = maskDocument(guests[i].DocumentNumber)
+	}
+	return guests, nil
+}
+
+// ReplaceGuests replaces the guest manifest of a booking. Every guest is classed as an adult or
+// a child by their age on the arrival date, and the manifest must list exactly the booking's
+// number of adults and children. Adults must carry an identity document.
+// Returns the stored manifest with masked document numbers, a validation error, or a conflict
+// error once the booking is closed.
+func (s *GuestService) ReplaceGuests(ctx context.Context, booking *models.Booking, req []models.BookingGuestRequest) ([]models.BookingGuest, error) {
+	switch booking.Status {
+	case models.BookingStatusCheckedOut, models.BookingStatusCancelled, models.BookingStatusNoShow:
+		return nil, conflictError("guests of a %s booking cannot be changed", booking.Status)
+	}
+
+	guests := make([]models.BookingGuest, 0, len(req))
+	adults, children := 0, 0
+	for i, r := range req {
+		guest := models.BookingGuest{
+			FirstName:    strings.TrimSpace(r.FirstName),
+			LastName:     strings.TrimSpace(r.LastName),
+			Nationality:  strings.ToUpper(strings.TrimSpace(r.Nationality)),
+			DateOfBirth:  dateOnly(r.DateOfBirth),
+			DocumentType: strings.TrimSpace(r.DocumentType),
+		}
+		number := strings.TrimSpace(r.DocumentNumber)
+		if guest.FirstName == "" || guest.LastName == "" {
+			return nil, validationError("guest %d: first and last name are required", i+1)
+		}
+		if !isCountryCode(guest.Nationality) {
+			return nil, validationError("guest %d: nationality must be a two-letter country code", i+1)
+		}
+		if guest.DateOfBirth.After(dateOnly(booking.CheckInDate)) {
+			return nil, validationError("guest %d: date of birth is after the arrival date", i+1)
+		}
+		guest.IsChild = ageOn(guest.DateOfBirth, booking.CheckInDate) < adultAge
+		if guest.IsChild {
+			children++
+		} else {
+			adults++
+		}
+
+		if (guest.DocumentType == "") != (number == "") {
+			return nil, validationError("guest %d: document type and number go together", i+1)
+		}
+		if number == "" && !guest.IsChild {
+			return nil, validationError("guest %d: adults must provide an identity document", i+1)
+		}
+		if number != "" {
+			switch guest.DocumentType {
+			case models.DocumentPassport, models.DocumentNationalID, models.DocumentDrivingLicence:
+			default:
+				return nil, validationError("guest %d: unknown document type %q", i+1, guest.DocumentType)
+			}
+			sealed, err := s.sealDocument(number)
+			if err != nil {
+				return nil, err
+			}
+			guest.DocumentCipher = sealed
+			guest.DocumentNumber = maskDocument(number)
+		}
+		guests = append(guests, guest)
+	}
+	// The counts price the booking, so the manifest must agree with them
+	if adults != booking.Adults || children != booking.Children {
+		return nil, validationError("booking is for %d adult(s) and %d child(ren) but the guests are %d adult(s) and %d child(ren) on arrival",
+			booking.Adults, booking.Children, adults, children)
+	}
+
+	if err := s.repo.ReplaceBookingGuests(ctx, booking.ID, guests); err != nil {
+		return nil, err
+	}
+	return guests, nil
+}
+
+// RegistrationReport returns every recorded guest staying the night of date at the given
+// properties (nil for all) with their document numbers in clear, as handed to the authorities.
+func (s *GuestService) RegistrationReport(ctx context.Context, date time.Time, propertyIDs []int) ([]models.GuestRegistration, error) {
+	registrations, err := s.repo.ListGuestRegistrations(ctx, dateOnly(date), propertyIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range registrations {
+		if err := s.revealDocument(&registrations[i].DocumentNumber, registrations[i].DocumentCipher); err != nil {
+			return nil, err
+		}
+	}
+	return registrations, nil
+}
+
+// sealDocument encrypts a document number with AES-256-GCM; the random nonce is prepended to
+// the ciphertext.
+func (s *GuestService) sealDocument(number string) ([]byte, error) {
+	aead, err := s.documentAEAD()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, []byte(number), nil), nil
+}
+
+// revealDocument decrypts a document number sealed by sealDocument into dst.
+// Guests without a document leave dst empty.
+func (s *GuestService) revealDocument(dst *string, sealed []byte) error {
+	*dst = ""
+	if len(sealed) == 0 {
+		return nil
+	}
+	aead, err := s.documentAEAD()
+	if err != nil {
+		return err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return errors.New("failed to decrypt document number: ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt document number: %w", err)
+	}
+	*dst = string(plain)
+	return nil
+}
+
+// documentAEAD returns the AES-GCM cipher for document numbers.
+func (s *GuestService) documentAEAD() (cipher.AEAD, error) {
+	if len(s.config.DocumentKey) == 0 {
+		return nil, errors.New("identity documents cannot be encrypted: set GUEST_DOCUMENT_KEY")
+	}
+	block, err := aes.NewCipher(s.config.DocumentKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid document key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// maskDocument hides all but the last four characters of a document number.
+func maskDocument(number string) string {
+	if len(number) <= 4 {
+		return strings.Repeat("*", len(number))
+	}
+	return strings.Repeat("*", len(number)-4) + number[len(number)-4:]
+}
+
+// isCountryCode reports whether code looks like an ISO 3166-1 alpha-2 country code.
+func isCountryCode(code string) bool {
+	return len(code) == 2 && code[0] >= 'A' && code[0] <= 'Z' && code[1] >= 'A' && code[1] <= 'Z'
+}
+
+// ageOn returns the age in whole years of someone born on birth at the date of on.
+func ageOn(birth, on time.Time) int {
+	age := on.Year() - birth.Year()
+	if on.Month() < birth.Month() || (on.Month() == birth.Month() && on.Day() < birth.Day()) {
+		age--
+	}
+	return age
+}
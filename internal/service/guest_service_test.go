@@ -0,0 +1,93 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"industry-api/internal/models"
+)
+
+type mockGuestRepo struct {
+	stored        []models.BookingGuest
+	registrations []models.GuestRegistration
+}
+
+func (m *mockGuestRepo) ReplaceBookingGuests(ctx context.Context, bookingID int, guests []models.BookingGuest) error {
+	m.stored = guests
+	return nil
+}
+func (m *mockGuestRepo) ListBookingGuests(ctx context.Context, bookingID int) ([]models.BookingGuest, error) {
+	return append([]models.BookingGuest(nil), m.stored...), nil
+}
+func (m *mockGuestRepo) ListGuestRegistrations(ctx context.Context, date time.Time, propertyIDs []int) ([]models.GuestRegistration, error) {
+	return m.registrations, nil
+}
+
+var testGuestConfig = GuestConfig{DocumentKey: bytes.Repeat([]byte{7}, 32)}
+
+func TestReplaceGuests_CountsMustMatchAgesOnArrival(t *testing.T) {
+	repo := &mockGuestRepo{}
+	svc := NewGuestService(repo, testGuestConfig)
+	checkIn := time.Date(2030, 6, 10, 0, 0, 0, 0, time.UTC)
+	booking := &models.Booking{ID: 1, Status: models.BookingStatusConfirmed, CheckInDate: checkIn, Adults: 1, Children: 1}
+
+	adult := models.BookingGuestRequest{FirstName: "Asha", LastName: "Rao", Nationality: "in", DateOfBirth: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC), DocumentType: models.DocumentPassport, DocumentNumber: "P1234567"}
+	// turns 18 the day after arrival, so still a child
+	child := models.BookingGuestRequest{FirstName: "Ravi", LastName: "Rao", Nationality: "IN", DateOfBirth: time.Date(2012, 6, 11, 0, 0, 0, 0, time.UTC)}
+
+	guests, err := svc.ReplaceGuests(context.Background(), booking, []models.BookingGuestRequest{adult, child})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if guests[0].IsChild || !guests[1].IsChild || guests[0].Nationality != "IN" || guests[0].DocumentNumber != "****4567" {
+		t.Fatalf("unexpected guests: %+v", guests)
+	}
+	if bytes.Contains(repo.stored[0].DocumentCipher, []byte("P1234567")) {
+		t.Fatal("expected the document number to be stored encrypted")
+	}
+
+	// a guest turning 18 on the arrival date is an adult, which no longer matches the booking
+	child.DateOfBirth = time.Date(2012, 6, 10, 0, 0, 0, 0, time.UTC)
+	if _, err := svc.ReplaceGuests(context.Background(), booking, []models.BookingGuestRequest{adult, child}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error for mismatched counts, got %v", err)
+	}
+	// adults need an identity document
+	adult.DocumentType, adult.DocumentNumber = "", ""
+	if _, err := svc.ReplaceGuests(context.Background(), booking, []models.BookingGuestRequest{adult}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error for a missing document, got %v", err)
+	}
+	// closed bookings keep their manifest
+	booking.Status = models.BookingStatusCheckedOut
+	if _, err := svc.ReplaceGuests(context.Background(), booking, nil); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected conflict for a checked-out booking, got %v", err)
+	}
+}
+
+func TestRegistrationReport_DecryptsDocuments(t *testing.T) {
+	repo := &mockGuestRepo{}
+	svc := NewGuestService(repo, testGuestConfig)
+	sealed, err := svc.sealDocument("ID998877")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repo.stored = []models.BookingGuest{{ID: 1, DocumentType: models.DocumentNationalID, DocumentCipher: sealed}}
+	repo.registrations = []models.GuestRegistration{{BookingID: 1, DocumentType: models.DocumentNationalID, DocumentCipher: sealed}}
+
+	report, err := svc.RegistrationReport(context.Background(), time.Now(), nil)
+	if err != nil || report[0].DocumentNumber != "ID998877" {
+		t.Fatalf("expected the document number in clear, got %+v err=%v", report, err)
+	}
+	guests, err := svc.ListGuests(context.Background(), 1)
+	if err != nil || guests[0].DocumentNumber != "****8877" {
+		t.Fatalf("expected a masked document number, got %+v err=%v", guests, err)
+	}
+
+	// another key cannot read the documents
+	other := NewGuestService(repo, GuestConfig{DocumentKey: bytes.Repeat([]byte{8}, 32)})
+	if _, err := other.RegistrationReport(context.Background(), time.Now(), nil); err == nil {
+		t.Fatal("expected decryption with the wrong key to fail")
+	}
+}
@@ -0,0 +1,126 @@
+// Package service provides business logic layer implementations.
+// Services contain validation logic and business rules before delegating to repositories.
+package service
+
+import (
+	"context"
+	"industry-api/internal/models"
+	"industry-api/internal/repository"
+)
+
+// housekeepingHistoryLimit is the number of events returned by the room history endpoint.
+const housekeepingHistoryLimit = 50
+
+// housekeepingTransitions lists the statuses each housekeeping status may move to.
+// A room always goes back through dirty before it can be sold again, so a room that
+// was out of order or failed inspection is cleaned and re-inspected.
+var housekeepingTransitions = map[string][]string{
+	models.HousekeepingDirty:      {models.HousekeepingCleaning, models.HousekeepingOutOfOrder},
+	models.HousekeepingCleaning:   {models.HousekeepingClean, models.HousekeepingDirty, models.HousekeepingOutOfOrder},
+	models.HousekeepingClean:      {models.HousekeepingInspected, models.HousekeepingDirty, models.HousekeepingOutOfOrder},
+	models.HousekeepingInspected:  {models.HousekeepingDirty, models.HousekeepingOutOfOrder},
+	models.HousekeepingOutOfOrder: {models.HousekeepingDirty},
+}
+
+// HousekeepingService handles the housekeeping workflow of rooms.
+type HousekeepingService struct {
+	repo repository.HousekeepingRepo // Repository interface for data access (allows mocking in tests)
+}
+
+// NewHousekeepingService creates and returns a new instance of HousekeepingService.
+// It accepts a HousekeepingRepo interface for data access operations.
+func NewHousekeepingService(repo repository.HousekeepingRepo) *HousekeepingService {
+	return &HousekeepingService{repo: repo}
+}
+
+// canTransition reports whether a room may move from one housekeeping status to another.
+func canTransition(from, to string) bool {
+	for _, allowed := range housekeepingTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ChangeStatus moves a room to a new housekeeping status on behalf of a staff member.
+// The transition must be allowed from the room's current status and out of order
+// requires a note explaining why.
+// Returns the updated room or an error if validation fails or the transition is not allowed.
+func (s *HousekeepingService) ChangeStatus(ctx context.Context, roomID int, to string, changedBy *int, note string) (*models.HousekeepingRoom, error) {
+	// Validate the request
+	if roomID <= 0 {
+		return nil, validationError("room id is required")
+	}
+	if _, ok := housekeepingTransitions[to]; !ok {
+		return nil, validationError("unknown housekeeping status %q", to)
+	}
+	if changedBy == nil {
+		return nil, validationError("changed by is required")
+	}
+	if to == models.HousekeepingOutOfOrder && note == "" {
+		return nil, validationError("a note is required when marking a room out of order")
+	}
+
+	// Check the transition against the current status
+	room, err := s.repo.GetHousekeepingRoom(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if !canTransition(room.Status, to) {
+		return nil, conflictError("room cannot move from %s to %s", room.Status, to)
+	}
+
+	return s.repo.SetHousekeepingStatus(ctx, roomID, room.Status, to, changedBy, note)
+}
+
+// GetRoom returns a room's housekeeping entry, including the property it belongs to.
+// Returns an error wrapping ErrNotFound if the room does not exist.
+func (s *HousekeepingService) GetRoom(ctx context.Context, roomID int) (*models.HousekeepingRoom, error) {
+	if roomID <= 0 {
+		return nil, validationError("room id is required")
+	}
+	return s.repo.GetHousekeepingRoom(ctx, roomID)
+}
+
+// Board returns the housekeeping board grouped by property and floor, optionally restricted to one
+// status and to the given properties (nil means every property).
+// Each floor carries a count of rooms per status.
+func (s *HousekeepingService) Board(ctx context.Context, status string, propertyIDs []int) ([]models.HousekeepingFloor, error) {
+	if status != "" {
+		if _, ok := housekeepingTransitions[status]; !ok {
+			return nil, validationError("unknown housekeeping status %q", status)
+		}
+	}
+	rooms, err := s.repo.ListHousekeepingRooms(ctx, status, propertyIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rooms arrive ordered by property and floor, so a new group starts whenever either changes
+	floors := []models.HousekeepingFloor{}
+	for _, room := range rooms {
+		last := len(floors) - 1
+		if last < 0 || floors[last].PropertyID != room.PropertyID || floors[last].Floor != room.Floor {
+			floors = append(floors, models.HousekeepingFloor{PropertyID: room.PropertyID, Floor: room.Floor, Counts: map[string]int{}})
+			last++
+		}
+		floors[last].Rooms = append(floors[last].Rooms, room)
+		floors[last].Counts[room.Status]++
+	}
+	return floors, nil
+}
+
+// History returns the most recent housekeeping events of a room, newest first.
+func (s *HousekeepingService) History(ctx context.Context, roomID int) ([]models.HousekeepingEvent, error) {
+	if roomID <= 0 {
+		return nil, validationError("room id is required")
+	}
+	return s.repo.ListHousekeepingEvents(ctx, roomID, housekeepingHistoryLimit)
+}
+
+// readyForSameDayArrival reports whether a room in the given housekeeping status can be
+// assigned to a guest arriving today. Only inspected rooms qualify.
+func readyForSameDayArrival(status string) bool {
+	return status == models.HousekeepingInspected
+}
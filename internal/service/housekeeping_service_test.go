@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"industry-api/internal/models"
+)
+
+type mockHousekeepingRepo struct {
+	room  *models.HousekeepingRoom
+	rooms []models.HousekeepingRoom
+	set   int
+}
+
+func (m *mockHousekeepingRepo) GetHousekeepingRoom(ctx context.Context, roomID int) (*models.HousekeepingRoom, error) {
+	if m.room == nil {
+		return nil, ErrNotFound
+	}
+	return m.room, nil
+}
+func (m *mockHousekeepingRepo) SetHousekeepingStatus(ctx context.Context, roomID int, from, to string, changedBy *int, note string) (*models.HousekeepingRoom, error) {
+	m.set++
+	m.room.Status = to
+	m.room.UpdatedBy = changedBy
+	return m.room, nil
+}
+func (m *mockHousekeepingRepo) ListHousekeepingRooms(ctx context.Context, status string, propertyIDs []int) ([]models.HousekeepingRoom, error) {
+	return m.rooms, nil
+}
+func (m *mockHousekeepingRepo) ListHousekeepingEvents(ctx context.Context, roomID int, limit int) ([]models.HousekeepingEvent, error) {
+	return nil, errors.New("not-implemented")
+}
+
+func TestChangeStatus_EnforcesTransitions(t *testing.T) {
+	repo := &mockHousekeepingRepo{room: &models.HousekeepingRoom{RoomID: 1, Status: models.HousekeepingDirty}}
+	svc := NewHousekeepingService(repo)
+	ctx := context.Background()
+	staff := 9
+
+	if _, err := svc.ChangeStatus(ctx, 1, models.HousekeepingCleaning, nil, ""); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error without a staff member, got %v", err)
+	}
+	if _, err := svc.ChangeStatus(ctx, 1, "sparkling", &staff, ""); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error for unknown status, got %v", err)
+	}
+	if _, err := svc.ChangeStatus(ctx, 1, models.HousekeepingInspected, &staff, ""); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected conflict for dirty -> inspected, got %v", err)
+	}
+	if _, err := svc.ChangeStatus(ctx, 1, models.HousekeepingOutOfOrder, &staff, ""); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error for out of order without note, got %v", err)
+	}
+
+	for _, step := range []string{models.HousekeepingCleaning, models.HousekeepingClean, models.HousekeepingInspected} {
+		room, err := svc.ChangeStatus(ctx, 1, step, &staff, "")
+		if err != nil {
+			t.Fatalf("transition to %s failed: %v", step, err)
+		}
+		if room.Status != step || room.UpdatedBy == nil || *room.UpdatedBy != 9 {
+			t.Fatalf("unexpected room after %s: %+v", step, room)
+		}
+	}
+}
+
+func TestBoard_GroupsByFloor(t *testing.T) {
+	repo := &mockHousekeepingRepo{rooms: []models.HousekeepingRoom{
+		{RoomID: 1, Floor: 1, Status: models.HousekeepingDirty},
+		{RoomID: 2, Floor: 1, Status: models.HousekeepingInspected},
+		{RoomID: 3, Floor: 2, Status: models.HousekeepingDirty},
+	}}
+	svc := NewHousekeepingService(repo)
+
+	board, err := svc.Board(context.Background(), "", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(board) != 2 || len(board[0].Rooms) != 2 || board[0].Counts[models.HousekeepingDirty] != 1 || board[1].Floor != 2 {
+		t.Fatalf("unexpected board: %+v", board)
+	}
+}
+
+func TestBoard_SeparatesProperties(t *testing.T) {
+	repo := &mockHousekeepingRepo{rooms: []models.HousekeepingRoom{
+		{RoomID: 1, PropertyID: 1, Floor: 1, Status: models.HousekeepingDirty},
+		{RoomID: 2, PropertyID: 2, Floor: 1, Status: models.HousekeepingDirty},
+	}}
+	svc := NewHousekeepingService(repo)
+
+	board, err := svc.Board(context.Background(), "", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(board) != 2 || board[0].PropertyID != 1 || board[1].PropertyID != 2 {
+		t.Fatalf("expected one floor group per property, got %+v", board)
+	}
+}
@@ -0,0 +1,56 @@
+// Package service provides business logic layer implementations.
+// Services contain validation logic and business rules before delegating to repositories.
+package service
+
+import (
+	"context"
+	"industry-api/internal/models"
+	"industry-api/internal/repository"
+	"time"
+)
+
+const (
+	// idempotencyKeyTTL is how long a stored response is replayed for retries of its key.
+	idempotencyKeyTTL = 24 * time.Hour
+	// idempotencyInFlightLease is how long a key stays locked to a request that never completed
+	// nor released it. Requests finish far sooner, so only a key whose request died with its
+	// server is taken over by a retry; until then retries get 409.
+	idempotencyInFlightLease = time.Hour
+)
+
+// IdempotencyService stores the first response to each Idempotency-Key so retries can replay it.
+type IdempotencyService struct {
+	repo repository.IdempotencyRepo // Repository interface for data access (allows mocking in tests)
+	now  func() time.Time           // Clock used for key timestamps (replaced in tests)
+}
+
+// NewIdempotencyService creates and returns a new instance of IdempotencyService.
+// It accepts an IdempotencyRepo interface for data access operations.
+func NewIdempotencyService(repo repository.IdempotencyRepo) *IdempotencyService {
+	return &IdempotencyService{repo: repo, now: time.Now}
+}
+
+// Claim reserves key for caller before the request identified by requestHash runs.
+// Returns nil when the request may proceed, otherwise the record stored by an earlier request
+// with the same key, which may still be in flight.
+func (s *IdempotencyService) Claim(ctx context.Context, caller, key, requestHash string) (*models.IdempotencyRecord, error) {
+	now := s.now()
+	record := &models.IdempotencyRecord{Caller: caller, Key: key, RequestHash: requestHash, CreatedAt: now}
+	return s.repo.ClaimIdempotencyKey(ctx, record, now.Add(-idempotencyInFlightLease), now.Add(-idempotencyKeyTTL))
+}
+
+// Complete stores the response of a claimed request for later replay.
+func (s *IdempotencyService) Complete(ctx context.Context, caller, key string, statusCode int, body []byte) error {
+	return s.repo.CompleteIdempotencyKey(ctx, caller, key, statusCode, body)
+}
+
+// Release frees a claimed key without storing a response so the request can be retried.
+func (s *IdempotencyService) Release(ctx context.Context, caller, key string) error {
+	return s.repo.ReleaseIdempotencyKey(ctx, caller, key)
+}
+
+// PurgeExpired removes keys older than idempotencyKeyTTL and returns how many were removed.
+// It is run periodically as a background job.
+func (s *IdempotencyService) PurgeExpired(ctx context.Context) (int, error) {
+	return s.repo.DeleteIdempotencyKeys(ctx, s.now().Add(-idempotencyKeyTTL))
+}
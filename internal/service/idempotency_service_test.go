@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"industry-api/internal/models"
+)
+
+type mockIdempotencyRepo struct {
+	claimed       *models.IdempotencyRecord
+	staleBefore   time.Time
+	expiredBefore time.Time
+	deleteBefore  time.Time
+}
+
+func (m *mockIdempotencyRepo) ClaimIdempotencyKey(ctx context.Context, record *models.IdempotencyRecord, staleBefore, expiredBefore time.Time) (*models.IdempotencyRecord, error) {
+	m.claimed, m.staleBefore, m.expiredBefore = record, staleBefore, expiredBefore
+	return nil, nil
+}
+func (m *mockIdempotencyRepo) CompleteIdempotencyKey(ctx context.Context, caller, key string, statusCode int, body []byte) error {
+	return nil
+}
+func (m *mockIdempotencyRepo) ReleaseIdempotencyKey(ctx context.Context, caller, key string) error {
+	return nil
+}
+func (m *mockIdempotencyRepo) DeleteIdempotencyKeys(ctx context.Context, before time.Time) (int, error) {
+	m.deleteBefore = before
+	return 3, nil
+}
+
+func TestIdempotencyService_UsesClockForExpiry(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := &mockIdempotencyRepo{}
+	svc := NewIdempotencyService(repo)
+	svc.now = func() time.Time { return now }
+
+	if _, err := svc.Claim(context.Background(), "user:5", "k1", "hash"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !repo.claimed.CreatedAt.Equal(now) || !repo.staleBefore.Equal(now.Add(-time.Hour)) || !repo.expiredBefore.Equal(now.Add(-24*time.Hour)) {
+		t.Fatalf("unexpected claim windows: %+v stale=%v expired=%v", repo.claimed, repo.staleBefore, repo.expiredBefore)
+	}
+
+	purged, err := svc.PurgeExpired(context.Background())
+	if err != nil || purged != 3 || !repo.deleteBefore.Equal(now.Add(-24*time.Hour)) {
+		t.Fatalf("expected keys older than a day to be purged, got %d %v before %v", purged, err, repo.deleteBefore)
+	}
+}
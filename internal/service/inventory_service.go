@@ -0,0 +1,124 @@
+// Package service provides business logic layer implementations.
+// Services contain validation logic and business rules before delegating to repositories.
+package service
+
+import (
+	"context"
+	"errors"
+	"industry-api/internal/models"
+	"industry-api/internal/repository"
+	"sort"
+)
+
+// maxCalendarNights limits how many nights a single calendar request may span.
+const maxCalendarNights = 93
+
+// dateLayout is the date format used for calendar days in API requests and responses.
+const dateLayout = "2006-01-02"
+
+// ErrInvalidDateRange is returned when a requested date range is empty, reversed or too long.
+var ErrInvalidDateRange = errors.New("invalid date range")
+
+// InventoryService builds inventory views from rooms, bookings and maintenance records.
+type InventoryService struct {
+	repo repository.InventoryRepo // Repository interface for data access (allows mocking in tests)
+}
+
+// NewInventoryService creates and returns a new instance of InventoryService.
+// It accepts an InventoryRepo interface for data access operations.
+func NewInventoryService(repo repository.InventoryRepo) *InventoryService {
+	return &InventoryService{repo: repo}
+}
+
+// GetCalendar returns the tape-chart calendar for the requested range.
+// Rooms are returned as rows with one entry per night, together with per-day
+// counts for each room type. The counts are derived from the same cells so the
+// grid and the totals can never disagree.
+func (s *InventoryService) GetCalendar(ctx context.Context, filter models.InventoryFilter) (*models.InventoryCalendar, error) {
+	// Validate the requested range
+	if filter.From.IsZero() || filter.To.IsZero() || filter.To.Before(filter.From) {
+		return nil, ErrInvalidDateRange
+	}
+	if int(filter.To.Sub(filter.From).Hours()/24)+1 > maxCalendarNights {
+		return nil, ErrInvalidDateRange
+	}
+
+	// Fetch every room/night cell in one query
+	cells, err := s.repo.GetCalendarCells(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := buildCalendar(cells)
+	calendar.From = filter.From.Format(dateLayout)
+	calendar.To = filter.To.Format(dateLayout)
+	return calendar, nil
+}
+
+// buildCalendar groups cells into room rows and aggregates per-day counts by room type.
+// Cells are expected in room order followed by date order, as returned by the repository.
+func buildCalendar(cells []models.InventoryCell) *models.InventoryCalendar {
+	calendar := &models.InventoryCalendar{
+		Rooms:  []models.CalendarRoom{},
+		Counts: []models.RoomTypeDayCount{},
+	}
+
+	type countKey struct {
+		date     string
+		roomType string
+	}
+	counts := make(map[countKey]*models.RoomTypeDayCount)
+
+	for _, cell := range cells {
+		// Start a new row whenever the room changes
+		last := len(calendar.Rooms) - 1
+		if last < 0 || calendar.Rooms[last].RoomID != cell.RoomID {
+			calendar.Rooms = append(calendar.Rooms, models.CalendarRoom{
+				RoomID:     cell.RoomID,
+				PropertyID: cell.PropertyID,
+				RoomNumber: cell.RoomNumber,
+				RoomType:   cell.RoomType,
+				Floor:      cell.Floor,
+			})
+			last++
+		}
+
+		date := cell.Date.Format(dateLayout)
+		calendar.Rooms[last].Days = append(calendar.Rooms[last].Days, models.CalendarDay{
+			Date:      date,
+			Status:    cell.Status,
+			BookingID: cell.BookingID,
+		})
+
+		// Aggregate the cell into its room type/day counter
+		key := countKey{date: date, roomType: cell.RoomType}
+		count, ok := counts[key]
+		if !ok {
+			count = &models.RoomTypeDayCount{Date: date, RoomType: cell.RoomType}
+			counts[key] = count
+		}
+		count.Total++
+		switch cell.Status {
+		case models.CellStatusBooked:
+			count.Booked++
+		case models.CellStatusMaintenance:
+			count.Maintenance++
+		case models.CellStatusBlocked:
+			count.Blocked++
+		default:
+			count.Free++
+		}
+	}
+
+	for _, count := range counts {
+		calendar.Counts = append(calendar.Counts, *count)
+	}
+	// Order counts by date and then room type for stable output
+	sort.Slice(calendar.Counts, func(i, j int) bool {
+		if calendar.Counts[i].Date != calendar.Counts[j].Date {
+			return calendar.Counts[i].Date < calendar.Counts[j].Date
+		}
+		return calendar.Counts[i].RoomType < calendar.Counts[j].RoomType
+	})
+	return calendar
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"industry-api/internal/models"
+)
+
+type mockInventoryRepo struct {
+	cells func(ctx context.Context, filter models.InventoryFilter) ([]models.InventoryCell, error)
+}
+
+func (m *mockInventoryRepo) GetCalendarCells(ctx context.Context, filter models.InventoryFilter) ([]models.InventoryCell, error) {
+	return m.cells(ctx, filter)
+}
+
+func TestGetCalendar_InvalidRange(t *testing.T) {
+	svc := &InventoryService{repo: &mockInventoryRepo{cells: func(ctx context.Context, f models.InventoryFilter) ([]models.InventoryCell, error) {
+		return nil, errors.New("should not be called")
+	}}}
+	from := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	if _, err := svc.GetCalendar(context.Background(), models.InventoryFilter{From: from, To: from.AddDate(0, 0, -1)}); !errors.Is(err, ErrInvalidDateRange) {
+		t.Fatalf("expected ErrInvalidDateRange for reversed range, got %v", err)
+	}
+	if _, err := svc.GetCalendar(context.Background(), models.InventoryFilter{From: from, To: from.AddDate(0, 0, maxCalendarNights)}); !errors.Is(err, ErrInvalidDateRange) {
+		t.Fatalf("expected ErrInvalidDateRange for long range, got %v", err)
+	}
+}
+
+func TestGetCalendar_BuildsRowsAndCounts(t *testing.T) {
+	d1 := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	d2 := d1.AddDate(0, 0, 1)
+	bookingID := 42
+	cells := []models.InventoryCell{
+		{RoomID: 1, RoomNumber: "101", RoomType: "double", Floor: 1, Date: d1, Status: models.CellStatusBooked, BookingID: &bookingID},
+		{RoomID: 1, RoomNumber: "101", RoomType: "double", Floor: 1, Date: d2, Status: models.CellStatusFree},
+		{RoomID: 2, RoomNumber: "102", RoomType: "double", Floor: 1, Date: d1, Status: models.CellStatusMaintenance},
+		{RoomID: 2, RoomNumber: "102", RoomType: "double", Floor: 1, Date: d2, Status: models.CellStatusBlocked},
+	}
+	svc := &InventoryService{repo: &mockInventoryRepo{cells: func(ctx context.Context, f models.InventoryFilter) ([]models.InventoryCell, error) {
+		return cells, nil
+	}}}
+
+	cal, err := svc.GetCalendar(context.Background(), models.InventoryFilter{From: d1, To: d2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cal.Rooms) != 2 || len(cal.Rooms[0].Days) != 2 {
+		t.Fatalf("unexpected rows: %+v", cal.Rooms)
+	}
+	if cal.Rooms[0].Days[0].BookingID == nil || *cal.Rooms[0].Days[0].BookingID != bookingID {
+		t.Fatalf("expected booking id on first cell")
+	}
+	if len(cal.Counts) != 2 {
+		t.Fatalf("expected 2 count rows, got %d", len(cal.Counts))
+	}
+	first := cal.Counts[0]
+	if first.Date != "2025-01-10" || first.Total != 2 || first.Booked != 1 || first.Maintenance != 1 || first.Free != 0 {
+		t.Fatalf("unexpected counts for first day: %+v", first)
+	}
+	second := cal.Counts[1]
+	if second.Free != 1 || second.Blocked != 1 {
+		t.Fatalf("unexpected counts for second day: %+v", second)
+	}
+}
@@ -0,0 +1,178 @@
+// Package service provides business logic layer implementations.
+// Services contain validation logic and business rules before delegating to repositories.
+package service
+
+import (
+	"context"
+	"industry-api/internal/models"
+	"industry-api/internal/repository"
+	"slices"
+	"strings"
+)
+
+// invoiceableStatuses are the booking statuses an invoice can be issued for.
+var invoiceableStatuses = []string{models.BookingStatusConfirmed, models.BookingStatusCheckedIn, models.BookingStatusCheckedOut}
+
+// InvoiceService handles invoices and credit notes. Invoices are built from the booking's price
+// breakdown and folio at the time they are issued and never change afterwards.
+type InvoiceService struct {
+	repo   repository.InvoiceRepo // Repository interface for data access (allows mocking in tests)
+	folios *FolioService          // Provides the incidental charges and payments of a booking
+}
+
+// NewInvoiceService creates and returns a new instance of InvoiceService.
+// It accepts an InvoiceRepo interface for data access operations and the FolioService
+// whose folios are invoiced.
+func NewInvoiceService(repo repository.InvoiceRepo, folios *FolioService) *InvoiceService {
+	return &InvoiceService{repo: repo, folios: folios}
+}
+
+// IssueInvoice issues an invoice for a booking's stay, incidental charges and taxes, less the
+// payments received so far, on behalf of issuedBy.
+// Returns a conflict error if the booking cannot be invoiced or already has an invoice that has
+// not been credited.
+func (s *InvoiceService) IssueInvoice(ctx context.Context, booking *models.Booking, req *models.InvoiceRequest, issuedBy *int) (*models.Invoice, error) {
+	if !slices.Contains(invoiceableStatuses, booking.Status) {
+		return nil, conflictError("a %s booking cannot be invoiced", booking.Status)
+	}
+	folio, err := s.folios.GetFolio(ctx, booking)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice := &models.Invoice{
+		BookingID:     booking.ID,
+		Kind:          models.InvoiceKindInvoice,
+		BillToName:    strings.TrimSpace(req.BillToName),
+		BillToAddress: strings.TrimSpace(req.BillToAddress),
+		BillToTaxID:   strings.TrimSpace(req.BillToTaxID),
+		StayFrom:      dateOnly(booking.CheckInDate),
+		StayTo:        dateOnly(booking.CheckOutDate),
+		Lines:         []models.InvoiceLine{},
+		Taxes:         []models.InvoiceTax{},
+		AmountPaid:    folio.Payments,
+		IssuedBy:      issuedBy,
+	}
+	if breakdown := booking.PriceBreakdown; breakdown != nil {
+		for _, line := range breakdown.Lines {
+			invoice.Lines = append(invoice.Lines, models.InvoiceLine{Description: line.Description, Quantity: line.Quantity, UnitPrice: line.UnitPrice, Amount: line.Amount})
+		}
+		for _, tax := range breakdown.Taxes {
+			invoice.Taxes = append(invoice.Taxes, models.InvoiceTax{Code: tax.Code, Description: tax.Description, Inclusive: tax.Inclusive, Amount: tax.Amount})
+		}
+	} else {
+		invoice.Lines = append(invoice.Lines, models.InvoiceLine{Description: "Room charge", Quantity: 1, UnitPrice: booking.TotalAmount, Amount: booking.TotalAmount})
+	}
+
+	// Incidental charges and credits are invoiced net, with their tax summed on one line
+	incidentalTax := 0.0
+	for _, line := range folio.Lines {
+		if line.Voided || (line.Type != models.FolioCharge && line.Type != models.FolioCredit) {
+			continue
+		}
+		net := roundMoney(line.Amount - line.TaxAmount)
+		description := folioCategoryLabel(line.Category)
+		if line.Description != "" {
+			description += ": " + line.Description
+		}
+		invoice.Lines = append(invoice.Lines, models.InvoiceLine{Description: description, Quantity: 1, UnitPrice: net, Amount: net})
+		incidentalTax = roundMoney(incidentalTax + line.TaxAmount)
+	}
+	if incidentalTax != 0 {
+		invoice.Taxes = append(invoice.Taxes, models.InvoiceTax{Code: "incidentals", Description: "Tax on incidental charges", Amount: incidentalTax})
+	}
+
+	for _, line := range invoice.Lines {
+		invoice.Subtotal = roundMoney(invoice.Subtotal + line.Amount)
+	}
+	for _, tax := range invoice.Taxes {
+		if tax.Inclusive {
+			invoice.IncludedTax = roundMoney(invoice.IncludedTax + tax.Amount)
+		} else {
+			invoice.TaxTotal = roundMoney(invoice.TaxTotal + tax.Amount)
+		}
+	}
+	invoice.Total = roundMoney(invoice.Subtotal + invoice.TaxTotal)
+	invoice.BalanceDue = roundMoney(invoice.Total - invoice.AmountPaid)
+
+	if err := s.repo.IssueInvoice(ctx, invoice); err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// CreditInvoice issues a credit note reversing an invoice in full on behalf of issuedBy. A new
+// invoice can then be issued for the booking.
+// Returns a validation error without a reason or for a credit note, or a conflict error if the
+// invoice was already credited.
+func (s *InvoiceService) CreditInvoice(ctx context.Context, invoice *models.Invoice, reason string, issuedBy *int) (*models.Invoice, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, validationError("a reason is required to credit an invoice")
+	}
+	if invoice.Kind != models.InvoiceKindInvoice {
+		return nil, validationError("only invoices can be credited")
+	}
+	if invoice.CreditNoteID != nil {
+		return nil, conflictError("invoice %s was already credited", invoice.InvoiceNumber)
+	}
+
+	note := &models.Invoice{
+		BookingID:         invoice.BookingID,
+		Kind:              models.InvoiceKindCreditNote,
+		CorrectsInvoiceID: &invoice.ID,
+		Reason:            reason,
+		BillToName:        invoice.BillToName,
+		BillToAddress:     invoice.BillToAddress,
+		BillToTaxID:       invoice.BillToTaxID,
+		StayFrom:          invoice.StayFrom,
+		StayTo:            invoice.StayTo,
+		Lines:             make([]models.InvoiceLine, 0, len(invoice.Lines)),
+		Taxes:             make([]models.InvoiceTax, 0, len(invoice.Taxes)),
+		Subtotal:          negate(invoice.Subtotal),
+		TaxTotal:          negate(invoice.TaxTotal),
+		IncludedTax:       negate(invoice.IncludedTax),
+		Total:             negate(invoice.Total),
+		BalanceDue:        negate(invoice.Total),
+		IssuedBy:          issuedBy,
+	}
+	for _, line := range invoice.Lines {
+		line.UnitPrice, line.Amount = negate(line.UnitPrice), negate(line.Amount)
+		note.Lines = append(note.Lines, line)
+	}
+	for _, tax := range invoice.Taxes {
+		tax.Amount = negate(tax.Amount)
+		note.Taxes = append(note.Taxes, tax)
+	}
+	if err := s.repo.IssueInvoice(ctx, note); err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+// GetInvoice returns an invoice or credit note by its ID.
+func (s *InvoiceService) GetInvoice(ctx context.Context, id int) (*models.Invoice, error) {
+	if id <= 0 {
+		return nil, validationError("invoice id is required")
+	}
+	return s.repo.GetInvoice(ctx, id)
+}
+
+// ListBookingInvoices returns the invoices and credit notes of a booking.
+func (s *InvoiceService) ListBookingInvoices(ctx context.Context, bookingID int) ([]models.Invoice, error) {
+	return s.repo.ListBookingInvoices(ctx, bookingID)
+}
+
+// negate returns -v without turning zero amounts into negative zero.
+func negate(v float64) float64 {
+	return 0 - v
+}
+
+// folioCategoryLabel returns the human-readable name of a folio category (e.g., "Room service").
+func folioCategoryLabel(category string) string {
+	label := strings.ReplaceAll(category, "_", " ")
+	if label == "" {
+		return label
+	}
+	return strings.ToUpper(label[:1]) + label[1:]
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"industry-api/internal/models"
+)
+
+type mockInvoiceRepo struct {
+	issued []*models.Invoice
+}
+
+func (m *mockInvoiceRepo) IssueInvoice(ctx context.Context, invoice *models.Invoice) error {
+	m.issued = append(m.issued, invoice)
+	invoice.ID, invoice.Number = len(m.issued), len(m.issued)
+	return nil
+}
+func (m *mockInvoiceRepo) GetInvoice(ctx context.Context, id int) (*models.Invoice, error) {
+	return nil, nil
+}
+func (m *mockInvoiceRepo) ListBookingInvoices(ctx context.Context, bookingID int) ([]models.Invoice, error) {
+	return nil, nil
+}
+
+func TestIssueInvoice_FromBreakdownAndFolio(t *testing.T) {
+	created := time.Date(2030, 5, 1, 12, 0, 0, 0, time.UTC)
+	voidedAt := created.Add(2 * time.Hour)
+	reason := "duplicate"
+	folios := &mockFolioRepo{
+		entries: []models.FolioEntry{
+			{ID: 1, Kind: models.FolioCharge, Category: "room_service", Description: "Dinner", Amount: 30, TaxAmount: 3, PostedAt: created.Add(time.Hour)},
+			{ID: 2, Kind: models.FolioCharge, Category: "minibar", Amount: 8, PostedAt: created.Add(time.Hour), VoidedAt: &voidedAt, VoidReason: &reason},
+		},
+		payments: []models.Payment{{ID: 1, Amount: 15000, PaymentMethod: "credit_card", CreatedAt: created}},
+	}
+	repo := &mockInvoiceRepo{}
+	svc := NewInvoiceService(repo, NewFolioService(folios))
+	booking := &models.Booking{ID: 3, Status: models.BookingStatusCheckedOut, TotalAmount: 231, CreatedAt: created,
+		CheckInDate: created, CheckOutDate: created.AddDate(0, 0, 2),
+		PriceBreakdown: &models.PriceBreakdown{Nights: 2,
+			Lines: []models.PriceLine{{Code: "room", Description: "Room 101", Quantity: 2, UnitPrice: 110, Amount: 220}},
+			Taxes: []models.TaxLine{{Code: "vat", Description: "VAT 5%", Amount: 11}, {Code: "service", Description: "Service", Inclusive: true, Amount: 10}}}}
+	staff := 2
+
+	invoice, err := svc.IssueInvoice(context.Background(), booking, &models.InvoiceRequest{BillToName: " Acme Ltd ", BillToTaxID: "GB123"}, &staff)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 220 room + 30 dinner; 11 VAT and 3 tax on the dinner on top; 10 service already included
+	if invoice.Subtotal != 250 || invoice.TaxTotal != 14 || invoice.IncludedTax != 10 || invoice.Total != 264 || invoice.AmountPaid != 150 || invoice.BalanceDue != 114 {
+		t.Fatalf("unexpected totals: %+v", invoice)
+	}
+	if len(invoice.Lines) != 2 || invoice.Lines[1].Description != "Room service: Dinner" || invoice.BillToName != "Acme Ltd" || *invoice.IssuedBy != staff {
+		t.Fatalf("unexpected invoice: %+v", invoice)
+	}
+
+	note, err := svc.CreditInvoice(context.Background(), invoice, "wrong company", &staff)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if note.Kind != models.InvoiceKindCreditNote || *note.CorrectsInvoiceID != invoice.ID || note.Total != -264 || note.Lines[0].Amount != -220 || note.Taxes[0].Amount != -11 {
+		t.Fatalf("unexpected credit note: %+v", note)
+	}
+	if _, err := svc.CreditInvoice(context.Background(), note, "again", &staff); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error crediting a credit note, got %v", err)
+	}
+	invoice.CreditNoteID = &note.ID
+	if _, err := svc.CreditInvoice(context.Background(), invoice, "again", &staff); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected conflict crediting twice, got %v", err)
+	}
+
+	booking.Status = models.BookingStatusCancelled
+	if _, err := svc.IssueInvoice(context.Background(), booking, &models.InvoiceRequest{}, &staff); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected conflict invoicing a cancelled booking, got %v", err)
+	}
+}
@@ -0,0 +1,367 @@
+// Package service provides business logic layer implementations.
+// Services contain validation logic and business rules before delegating to repositories.
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png" // Register the PNG decoder for image.Decode
+	"industry-api/internal/models"
+	"industry-api/internal/repository"
+	"industry-api/internal/storage"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Upload limits for room photos.
+const (
+	MaxMediaBytes    = 5 << 20          // Largest accepted upload (5 MB)
+	maxMediaPixels   = 40 * 1000 * 1000 // Largest accepted image area, guards against decompression bombs
+	thumbnailMaxEdge = 320              // Longest edge of generated thumbnails in pixels
+	thumbnailQuality = 80               // JPEG quality used for thumbnails
+	maxCaptionLength = 500              // Longest accepted caption
+)
+
+// allowedMediaTypes maps the accepted content types to the file extension used in storage.
+// The content type is sniffed from the uploaded bytes, never taken from the client.
+var allowedMediaTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+}
+
+// unsafeKeyChars matches characters that are not allowed in storage key segments.
+var unsafeKeyChars = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// MediaService handles room and room type photos: validation, thumbnails, storage and ordering.
+type MediaService struct {
+	repo  repository.MediaRepo // Repository interface for photo metadata
+	store storage.Storage      // Storage backend for image files
+}
+
+// NewMediaService creates and returns a new instance of MediaService.
+// It accepts a MediaRepo for metadata and a Storage backend for the image files.
+func NewMediaService(repo repository.MediaRepo, store storage.Storage) *MediaService {
+	return &MediaService{repo: repo, store: store}
+}
+
+// Upload validates an uploaded image, generates its thumbnail, stores both files and records the photo.
+// The photo is appended to the end of its owner's display order.
+// Returns the created photo with public URLs or an error if validation or storage fails.
+func (s *MediaService) Upload(ctx context.Context, owner models.MediaOwner, data []byte, caption string) (*models.RoomMedia, error) {
+	// Validate the owner
+	if err := validateMediaOwner(owner); err != nil {
+		return nil, err
+	}
+	// Validate size and caption
+	if len(data) == 0 {
+		return nil, validationError("file is required")
+	}
+	if len(data) > MaxMediaBytes {
+		return nil, validationError("file exceeds the %d byte limit", MaxMediaBytes)
+	}
+	if len(caption) > maxCaptionLength {
+		return nil, validationError("caption must be at most %d characters", maxCaptionLength)
+	}
+	// Validate the content type from the file contents
+	contentType := http.DetectContentType(data)
+	ext, ok := allowedMediaTypes[contentType]
+	if !ok {
+		return nil, validationError("unsupported content type %q; only JPEG and PNG images are accepted", contentType)
+	}
+
+	// Generate the thumbnail (this also proves the file is a decodable image)
+	thumbnail, err := makeThumbnail(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Store the original and the thumbnail under a random name
+	name, err := randomName()
+	if err != nil {
+		return nil, err
+	}
+	prefix := mediaKeyPrefix(owner)
+	media := &models.RoomMedia{
+		StorageKey:   prefix + name + ext,
+		ThumbnailKey: prefix + name + "_thumb.jpg",
+		ContentType:  contentType,
+		SizeBytes:    int64(len(data)),
+		Caption:      caption,
+	}
+	if owner.RoomID != nil {
+		media.RoomID = owner.RoomID
+	} else {
+		media.RoomType = &owner.RoomType
+	}
+
+	if err := s.store.Put(ctx, media.StorageKey, bytes.NewReader(data), contentType); err != nil {
+		return nil, err
+	}
+	if err := s.store.Put(ctx, media.ThumbnailKey, bytes.NewReader(thumbnail), "image/jpeg"); err != nil {
+		s.removeFiles(ctx, media)
+		return nil, err
+	}
+
+	// Record the photo, removing the stored files if the insert fails
+	if err := s.repo.AddMedia(ctx, media); err != nil {
+		s.removeFiles(ctx, media)
+		return nil, err
+	}
+
+	invalidateAvailableRooms(ctx)
+	s.fillURLs(media)
+	return media, nil
+}
+
+// List retrieves the photos of a room or room type in display order.
+func (s *MediaService) List(ctx context.Context, owner models.MediaOwner) ([]models.RoomMedia, error) {
+	if err := validateMediaOwner(owner); err != nil {
+		return nil, err
+	}
+	media, err := s.repo.ListMedia(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	for i := range media {
+		s.fillURLs(&media[i])
+	}
+	return media, nil
+}
+
+// Get returns a single photo with its public URLs.
+// Returns an error wrapping ErrNotFound if the photo does not exist.
+func (s *MediaService) Get(ctx context.Context, id int) (*models.RoomMedia, error) {
+	media, err := s.repo.GetMediaByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.fillURLs(media)
+	return media, nil
+}
+
+// OwnerPropertyID returns the property whose staff manage the photos of owner: the property of
+// a room, or nil for a room type, whose photos are shared by every property.
+// Returns an error wrapping ErrNotFound if the room does not exist.
+func (s *MediaService) OwnerPropertyID(ctx context.Context, owner models.MediaOwner) (*int, error) {
+	if owner.RoomID == nil {
+		return nil, nil
+	}
+	propertyID, err := s.repo.GetRoomPropertyID(ctx, *owner.RoomID)
+	if err != nil {
+		return nil, err
+	}
+	return &propertyID, nil
+}
+
+// UpdateCaption changes the caption of a photo.
+// Returns the updated photo or an error if validation fails or the photo does not exist.
+func (s *MediaService) UpdateCaption(ctx context.Context, id int, caption string) (*models.RoomMedia, error) {
+	if len(caption) > maxCaptionLength {
+		return nil, validationError("caption must be at most %d characters", maxCaptionLength)
+	}
+	media, err := s.repo.UpdateMediaCaption(ctx, id, strings.TrimSpace(caption))
+	if err != nil {
+		return nil, err
+	}
+	invalidateAvailableRooms(ctx)
+	s.fillURLs(media)
+	return media, nil
+}
+
+// Reorder sets the display order of an owner's photos.
+// ids must list every photo of the owner exactly once, in the desired order.
+// Returns the photos in their new order or an error if validation fails.
+func (s *MediaService) Reorder(ctx context.Context, owner models.MediaOwner, ids []int) ([]models.RoomMedia, error) {
+	current, err := s.List(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate that ids is a permutation of the owner's photos
+	if len(ids) != len(current) {
+		return nil, validationError("media_ids must list all %d photos exactly once", len(current))
+	}
+	known := make(map[int]bool, len(current))
+	for _, m := range current {
+		known[m.ID] = true
+	}
+	for _, id := range ids {
+		if !known[id] {
+			return nil, validationError("media %d is not a photo of this owner or is listed twice", id)
+		}
+		delete(known, id)
+	}
+
+	if err := s.repo.ReorderMedia(ctx, owner, ids); err != nil {
+		return nil, err
+	}
+	invalidateAvailableRooms(ctx)
+	return s.List(ctx, owner)
+}
+
+// Delete removes a photo record and its stored files.
+// File removal is best effort: a failure is logged but does not fail the request.
+func (s *MediaService) Delete(ctx context.Context, id int) error {
+	media, err := s.repo.GetMediaByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteMedia(ctx, id); err != nil {
+		return err
+	}
+	s.removeFiles(ctx, media)
+	invalidateAvailableRooms(ctx)
+	return nil
+}
+
+// AttachToRooms fills the Media field of each room with its ordered photos.
+// Photos of the room itself come first, followed by photos shared by its room type.
+// All photos are loaded with a single query.
+func (s *MediaService) AttachToRooms(ctx context.Context, rooms []*models.Room) error {
+	if len(rooms) == 0 {
+		return nil
+	}
+	roomIDs := make([]int, 0, len(rooms))
+	typeSeen := make(map[string]bool)
+	var roomTypes []string
+	for _, room := range rooms {
+		roomIDs = append(roomIDs, room.ID)
+		if !typeSeen[room.RoomType] {
+			typeSeen[room.RoomType] = true
+			roomTypes = append(roomTypes, room.RoomType)
+		}
+	}
+
+	media, err := s.repo.ListMediaForRooms(ctx, roomIDs, roomTypes)
+	if err != nil {
+		return err
+	}
+
+	// Group links by owner, keeping the repository's display order
+	byRoom := make(map[int][]models.MediaLink)
+	byType := make(map[string][]models.MediaLink)
+	for i := range media {
+		s.fillURLs(&media[i])
+		link := models.MediaLink{ID: media[i].ID, URL: media[i].URL, ThumbnailURL: media[i].ThumbnailURL, Caption: media[i].Caption}
+		if media[i].RoomID != nil {
+			byRoom[*media[i].RoomID] = append(byRoom[*media[i].RoomID], link)
+		} else if media[i].RoomType != nil {
+			byType[*media[i].RoomType] = append(byType[*media[i].RoomType], link)
+		}
+	}
+
+	for _, room := range rooms {
+		links := make([]models.MediaLink, 0, len(byRoom[room.ID])+len(byType[room.RoomType]))
+		links = append(links, byRoom[room.ID]...)
+		links = append(links, byType[room.RoomType]...)
+		room.Media = links
+	}
+	return nil
+}
+
+// fillURLs sets the public URLs of a photo from its storage keys.
+func (s *MediaService) fillURLs(media *models.RoomMedia) {
+	media.URL = s.store.URL(media.StorageKey)
+	media.ThumbnailURL = s.store.URL(media.ThumbnailKey)
+}
+
+// removeFiles deletes the stored original and thumbnail of a photo, logging any failure.
+func (s *MediaService) removeFiles(ctx context.Context, media *models.RoomMedia) {
+	for _, key := range []string{media.StorageKey, media.ThumbnailKey} {
+		if err := s.store.Delete(ctx, key); err != nil {
+			fmt.Printf("⚠️ Failed to delete stored media %s: %v\n", key, err)
+		}
+	}
+}
+
+// validateMediaOwner checks that exactly one owner (room or room type) is given.
+func validateMediaOwner(owner models.MediaOwner) error {
+	if owner.RoomID != nil {
+		if *owner.RoomID <= 0 {
+			return validationError("room id must be greater than 0")
+		}
+		return nil
+	}
+	if strings.TrimSpace(owner.RoomType) == "" {
+		return validationError("room id or room type is required")
+	}
+	return nil
+}
+
+// mediaKeyPrefix returns the storage directory for an owner's photos.
+func mediaKeyPrefix(owner models.MediaOwner) string {
+	if owner.RoomID != nil {
+		return fmt.Sprintf("rooms/%d/", *owner.RoomID)
+	}
+	slug := unsafeKeyChars.ReplaceAllString(strings.ToLower(owner.RoomType), "-")
+	return "room-types/" + slug + "/"
+}
+
+// randomName returns a random hex string used as the base name of stored files.
+func randomName() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate file name: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// makeThumbnail decodes an image and returns a JPEG thumbnail whose longest edge is at most
+// thumbnailMaxEdge pixels. Smaller images are re-encoded without scaling.
+// Each thumbnail pixel is the average of the source pixels it covers, which avoids the
+// aliasing of nearest-neighbour sampling without pulling in an imaging dependency.
+func makeThumbnail(data []byte) ([]byte, error) {
+	// Check dimensions before decoding the full image
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, validationError("file is not a valid image")
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxMediaPixels {
+		return nil, validationError("image dimensions %dx%d are not supported", cfg.Width, cfg.Height)
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, validationError("file is not a valid image")
+	}
+
+	// Compute the thumbnail size, preserving the aspect ratio
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	tw, th := w, h
+	if w > thumbnailMaxEdge || h > thumbnailMaxEdge {
+		if w >= h {
+			tw, th = thumbnailMaxEdge, max(1, h*thumbnailMaxEdge/w)
+		} else {
+			tw, th = max(1, w*thumbnailMaxEdge/h), thumbnailMaxEdge
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		y0, y1 := bounds.Min.Y+y*h/th, bounds.Min.Y+max((y+1)*h/th, y*h/th+1)
+		for x := 0; x < tw; x++ {
+			x0, x1 := bounds.Min.X+x*w/tw, bounds.Min.X+max((x+1)*w/tw, x*w/tw+1)
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, b, a, n = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca), n+1
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{R: uint8(r / n >> 8), G: uint8(g / n >> 8), B: uint8(b / n >> 8), A: uint8(a / n >> 8)})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"industry-api/internal/models"
+	"industry-api/internal/storage"
+)
+
+type mockMediaRepo struct {
+	media []models.RoomMedia
+	order []int
+}
+
+func (m *mockMediaRepo) AddMedia(ctx context.Context, media *models.RoomMedia) error {
+	media.ID = len(m.media) + 1
+	media.Position = len(m.media)
+	m.media = append(m.media, *media)
+	return nil
+}
+func (m *mockMediaRepo) GetMediaByID(ctx context.Context, id int) (*models.RoomMedia, error) {
+	for i := range m.media {
+		if m.media[i].ID == id {
+			return &m.media[i], nil
+		}
+	}
+	return nil, ErrNotFound
+}
+func (m *mockMediaRepo) ListMedia(ctx context.Context, owner models.MediaOwner) ([]models.RoomMedia, error) {
+	return m.media, nil
+}
+func (m *mockMediaRepo) ListMediaForRooms(ctx context.Context, roomIDs []int, roomTypes []string) ([]models.RoomMedia, error) {
+	return m.media, nil
+}
+func (m *mockMediaRepo) UpdateMediaCaption(ctx context.Context, id int, caption string) (*models.RoomMedia, error) {
+	return nil, errors.New("not-implemented")
+}
+func (m *mockMediaRepo) ReorderMedia(ctx context.Context, owner models.MediaOwner, ids []int) error {
+	m.order = ids
+	return nil
+}
+func (m *mockMediaRepo) DeleteMedia(ctx context.Context, id int) error { return nil }
+func (m *mockMediaRepo) GetRoomPropertyID(ctx context.Context, roomID int) (int, error) {
+	return 1, nil
+}
+
+// testPNG returns an encoded PNG of the given size.
+func testPNG(t *testing.T, w, h int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 100, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("png encode: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestMediaUpload_ValidatesAndStores(t *testing.T) {
+	store, err := storage.NewLocalStorage(t.TempDir(), "/media")
+	if err != nil {
+		t.Fatalf("storage: %v", err)
+	}
+	repo := &mockMediaRepo{}
+	svc := NewMediaService(repo, store)
+	roomID := 7
+
+	if _, err := svc.Upload(context.Background(), models.MediaOwner{RoomID: &roomID}, []byte("plain text"), ""); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error for non-image upload, got %v", err)
+	}
+	if _, err := svc.Upload(context.Background(), models.MediaOwner{}, testPNG(t, 10, 10), ""); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error for missing owner, got %v", err)
+	}
+
+	media, err := svc.Upload(context.Background(), models.MediaOwner{RoomID: &roomID}, testPNG(t, 800, 400), "Sea view")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if media.ContentType != "image/png" || media.URL == "" || media.ThumbnailURL == "" {
+		t.Fatalf("unexpected media: %+v", media)
+	}
+}
+
+func TestMakeThumbnail_FitsWithinMaxEdge(t *testing.T) {
+	thumb, err := makeThumbnail(testPNG(t, 800, 400))
+	if err != nil {
+		t.Fatalf("makeThumbnail: %v", err)
+	}
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(thumb))
+	if err != nil {
+		t.Fatalf("decode thumbnail: %v", err)
+	}
+	if format != "jpeg" || cfg.Width != thumbnailMaxEdge || cfg.Height != thumbnailMaxEdge/2 {
+		t.Fatalf("unexpected thumbnail %s %dx%d", format, cfg.Width, cfg.Height)
+	}
+}
+
+func TestMediaReorder_RequiresEveryPhotoOnce(t *testing.T) {
+	repo := &mockMediaRepo{media: []models.RoomMedia{{ID: 1}, {ID: 2}, {ID: 3}}}
+	store, _ := storage.NewLocalStorage(t.TempDir(), "/media")
+	svc := NewMediaService(repo, store)
+	owner := models.MediaOwner{RoomType: "suite"}
+
+	if _, err := svc.Reorder(context.Background(), owner, []int{1, 2}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error for missing photo, got %v", err)
+	}
+	if _, err := svc.Reorder(context.Background(), owner, []int{1, 1, 2}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error for duplicate photo, got %v", err)
+	}
+	if _, err := svc.Reorder(context.Background(), owner, []int{3, 1, 2}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.order) != 3 || repo.order[0] != 3 {
+		t.Fatalf("expected new order to reach repository, got %v", repo.order)
+	}
+}
+
+func TestAttachToRooms_RoomPhotosBeforeTypePhotos(t *testing.T) {
+	roomID := 5
+	suite := "suite"
+	repo := &mockMediaRepo{media: []models.RoomMedia{
+		{ID: 10, RoomID: &roomID, StorageKey: "rooms/5/a.jpg"},
+		{ID: 20, RoomType: &suite, StorageKey: "room-types/suite/b.jpg"},
+	}}
+	store, _ := storage.NewLocalStorage(t.TempDir(), "/media")
+	svc := NewMediaService(repo, store)
+
+	rooms := []*models.Room{{ID: 5, RoomType: "suite"}, {ID: 6, RoomType: "suite"}}
+	if err := svc.AttachToRooms(context.Background(), rooms); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rooms[0].Media) != 2 || rooms[0].Media[0].ID != 10 || rooms[0].Media[1].ID != 20 {
+		t.Fatalf("unexpected media for room 5: %+v", rooms[0].Media)
+	}
+	if len(rooms[1].Media) != 1 || rooms[1].Media[0].URL != "/media/room-types/suite/b.jpg" {
+		t.Fatalf("unexpected media for room 6: %+v", rooms[1].Media)
+	}
+}
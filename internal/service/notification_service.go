@@ -0,0 +1,213 @@
+// Package service provides business logic layer implementations.
+// Services contain validation logic and business rules before delegating to repositories.
+package service
+
+import (
+	"context"
+	"fmt"
+	"industry-api/internal/models"
+	"industry-api/internal/notify"
+	"industry-api/internal/render"
+	"industry-api/internal/repository"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dispatchNotificationsBatchSize is the number of outbox events claimed per repository call.
+const dispatchNotificationsBatchSize = 50
+
+// notificationLease is how long a claimed event is left to its dispatcher before another one
+// may pick it up again.
+const notificationLease = 5 * time.Minute
+
+// notificationSendTimeout bounds a single delivery attempt on one channel.
+const notificationSendTimeout = 30 * time.Second
+
+// Outbox listing defaults.
+const (
+	defaultOutboxListLimit = 50
+	maxOutboxListLimit     = 500
+)
+
+// NotificationConfig holds the retry settings of notification delivery.
+type NotificationConfig struct {
+	MaxAttempts int           // Failed attempts after which an event becomes a dead letter
+	RetryBase   time.Duration // Delay before the first retry; doubled for every further one
+	RetryMax    time.Duration // Longest delay between retries
+}
+
+// DefaultNotificationConfig is used when nothing is configured: 8 attempts, retried after
+// 1 minute and then twice as long each time, up to 6 hours.
+var DefaultNotificationConfig = NotificationConfig{
+	MaxAttempts: 8,
+	RetryBase:   time.Minute,
+	RetryMax:    6 * time.Hour,
+}
+
+// NotificationConfigFromEnv builds the notification configuration from NOTIFY_MAX_ATTEMPTS and
+// NOTIFY_RETRY_BASE (a Go duration), falling back to DefaultNotificationConfig.
+// Returns an error if a variable is set to an invalid value.
+func NotificationConfigFromEnv() (NotificationConfig, error) {
+	config := DefaultNotificationConfig
+	if v := os.Getenv("NOTIFY_MAX_ATTEMPTS"); v != "" {
+		attempts, err := strconv.Atoi(v)
+		if err != nil || attempts <= 0 {
+			return config, fmt.Errorf("invalid NOTIFY_MAX_ATTEMPTS %q: must be a positive integer", v)
+		}
+		config.MaxAttempts = attempts
+	}
+	if v := os.Getenv("NOTIFY_RETRY_BASE"); v != "" {
+		base, err := time.ParseDuration(v)
+		if err != nil || base <= 0 {
+			return config, fmt.Errorf("invalid NOTIFY_RETRY_BASE %q: must be a positive duration", v)
+		}
+		config.RetryBase = base
+	}
+	return config, nil
+}
+
+// NotificationService delivers the booking events written to the outbox to guests, through one
+// sender per channel. Delivery is at least once: an event is retried with backoff until every
+// channel delivered it, and channels that already delivered it are not sent it again.
+type NotificationService struct {
+	repo    repository.OutboxRepo // Repository interface for data access (allows mocking in tests)
+	senders []notify.Sender       // One sender per channel
+	config  NotificationConfig    // Retry settings
+	now     func() time.Time      // Clock, replaceable in tests
+}
+
+// NewNotificationService creates and returns a new instance of NotificationService.
+// It accepts an OutboxRepo interface for data access operations, the senders of the channels to
+// deliver on and the configuration.
+func NewNotificationService(repo repository.OutboxRepo, senders []notify.Sender, config NotificationConfig) *NotificationService {
+	return &NotificationService{repo: repo, senders: senders, config: config, now: time.Now}
+}
+
+// Dispatch delivers the due outbox events and returns how many were fully delivered. It is run
+// periodically by a background job and is safe to run from several instances at once. Failed
+// deliveries are recorded on their event and retried later; only failures to claim or record
+// events are returned as errors.
+func (s *NotificationService) Dispatch(ctx context.Context) (int, error) {
+	sent := 0
+	for {
+		now := s.clock()
+		events, err := s.repo.ClaimOutboxEvents(ctx, now, now.Add(notificationLease), dispatchNotificationsBatchSize)
+		if err != nil {
+			return sent, err
+		}
+		for i := range events {
+			ok, err := s.deliver(ctx, &events[i])
+			if err != nil {
+				return sent, err
+			}
+			if ok {
+				sent++
+			}
+		}
+		if len(events) < dispatchNotificationsBatchSize {
+			return sent, nil
+		}
+	}
+}
+
+// deliver sends an event on every channel that has not delivered it yet and records the outcome.
+// A channel the guest has no address for is skipped. It reports whether the event is now fully
+// delivered; only failures to record the outcome are returned as errors.
+func (s *NotificationService) deliver(ctx context.Context, event *models.OutboxEvent) (bool, error) {
+	delivered := slices.Clone(event.DeliveredChannels)
+	var failures []string
+	for _, sender := range s.senders {
+		channel := sender.Channel()
+		to := notificationRecipient(channel, event.Guest)
+		if to == "" || slices.Contains(delivered, channel) {
+			continue
+		}
+		if err := s.send(ctx, sender, to, event); err != nil {
+			failures = append(failures, channel+": "+err.Error())
+			continue
+		}
+		delivered = append(delivered, channel)
+	}
+	if len(failures) == 0 {
+		return true, s.repo.MarkOutboxEventSent(ctx, event.ID, delivered)
+	}
+
+	attempts := event.Attempts + 1
+	dead := attempts >= s.config.MaxAttempts
+	message := strings.Join(failures, "; ")
+	if dead {
+		log.Printf("outbox event %d (%s for booking %d) is dead after %d attempts: %s", event.ID, event.EventType, event.BookingID, attempts, message)
+	}
+	return false, s.repo.RecordOutboxEventFailure(ctx, event.ID, delivered, message, s.clock().Add(s.retryDelay(attempts)), dead)
+}
+
+// send renders an event for the sender's channel and delivers it to to.
+func (s *NotificationService) send(ctx context.Context, sender notify.Sender, to string, event *models.OutboxEvent) error {
+	subject, body, err := render.Notification(sender.Channel(), *event)
+	if err != nil {
+		return err
+	}
+	sendCtx, cancel := context.WithTimeout(ctx, notificationSendTimeout)
+	defer cancel()
+	return sender.Send(sendCtx, notify.Envelope{To: to, Subject: subject, Body: body})
+}
+
+// retryDelay returns how long to wait after the given number of failed attempts: RetryBase after
+// the first, doubling with each further attempt up to RetryMax.
+func (s *NotificationService) retryDelay(attempts int) time.Duration {
+	delay := s.config.RetryBase
+	for i := 1; i < attempts && delay < s.config.RetryMax; i++ {
+		delay *= 2
+	}
+	if s.config.RetryMax > 0 && delay > s.config.RetryMax {
+		delay = s.config.RetryMax
+	}
+	return delay
+}
+
+// notificationRecipient returns the guest's address on a channel, or "" if they have none.
+func notificationRecipient(channel string, guest models.NotificationGuest) string {
+	switch channel {
+	case notify.ChannelEmail:
+		return strings.TrimSpace(guest.Email)
+	case notify.ChannelSMS:
+		return strings.TrimSpace(guest.Phone)
+	}
+	return ""
+}
+
+// ListEvents returns the most recent outbox events, optionally only those with status, so dead
+// letters can be inspected. limit defaults to 50 and is capped at 500.
+// Returns a validation error for an unknown status.
+func (s *NotificationService) ListEvents(ctx context.Context, status string, limit int) ([]models.OutboxEvent, error) {
+	switch status {
+	case "", models.OutboxStatusPending, models.OutboxStatusSent, models.OutboxStatusDead:
+	default:
+		return nil, validationError("unknown outbox status %q", status)
+	}
+	if limit <= 0 {
+		limit = defaultOutboxListLimit
+	}
+	if limit > maxOutboxListLimit {
+		limit = maxOutboxListLimit
+	}
+	return s.repo.ListOutboxEvents(ctx, status, limit)
+}
+
+// RetryEvent queues a dead outbox event for delivery again with a fresh set of attempts.
+// Returns an error wrapping ErrNotFound if the event does not exist, or ErrConflict if it is not dead.
+func (s *NotificationService) RetryEvent(ctx context.Context, id int64) (*models.OutboxEvent, error) {
+	return s.repo.RetryOutboxEvent(ctx, id)
+}
+
+// clock returns the current time from the configured clock.
+func (s *NotificationService) clock() time.Time {
+	if s.now == nil {
+		return time.Now()
+	}
+	return s.now()
+}
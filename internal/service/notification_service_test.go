@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"industry-api/internal/models"
+	"industry-api/internal/notify"
+)
+
+// mockOutboxRepo hands out the queued events once and records the outcome of each delivery.
+type mockOutboxRepo struct {
+	queue    []models.OutboxEvent
+	sent     map[int64][]string
+	failures map[int64]outboxFailure
+}
+
+type outboxFailure struct {
+	delivered []string
+	message   string
+	retryAt   time.Time
+	dead      bool
+}
+
+func (m *mockOutboxRepo) ClaimOutboxEvents(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.OutboxEvent, error) {
+	n := min(limit, len(m.queue))
+	events := m.queue[:n]
+	m.queue = m.queue[n:]
+	return events, nil
+}
+
+func (m *mockOutboxRepo) MarkOutboxEventSent(ctx context.Context, id int64, delivered []string) error {
+	if m.sent == nil {
+		m.sent = map[int64][]string{}
+	}
+	m.sent[id] = delivered
+	return nil
+}
+
+func (m *mockOutboxRepo) RecordOutboxEventFailure(ctx context.Context, id int64, delivered []string, message string, retryAt time.Time, dead bool) error {
+	if m.failures == nil {
+		m.failures = map[int64]outboxFailure{}
+	}
+	m.failures[id] = outboxFailure{delivered: delivered, message: message, retryAt: retryAt, dead: dead}
+	return nil
+}
+
+func (m *mockOutboxRepo) ListOutboxEvents(ctx context.Context, status string, limit int) ([]models.OutboxEvent, error) {
+	return []models.OutboxEvent{}, nil
+}
+
+func (m *mockOutboxRepo) RetryOutboxEvent(ctx context.Context, id int64) (*models.OutboxEvent, error) {
+	return &models.OutboxEvent{ID: id, Status: models.OutboxStatusPending}, nil
+}
+
+// recordingSender records the envelopes it is given and fails while err is set.
+type recordingSender struct {
+	channel string
+	err     error
+	sent    []notify.Envelope
+}
+
+func (s *recordingSender) Channel() string { return s.channel }
+
+func (s *recordingSender) Send(ctx context.Context, env notify.Envelope) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.sent = append(s.sent, env)
+	return nil
+}
+
+func testOutboxEvent(id int64, attempts int, delivered ...string) models.OutboxEvent {
+	checkIn := time.Date(2030, 5, 10, 0, 0, 0, 0, time.UTC)
+	return models.OutboxEvent{
+		ID: id, EventType: models.EventBookingCreated, BookingID: 7, Status: models.OutboxStatusPending,
+		Attempts: attempts, DeliveredChannels: delivered,
+		Guest: models.NotificationGuest{Name: "Ada", Email: "ada@example.com", Phone: "5550100100", PropertyName: "Main", RoomNumber: "101",
+			CheckInDate: checkIn, CheckOutDate: checkIn.AddDate(0, 0, 2), TotalAmount: 200},
+	}
+}
+
+func TestNotificationDispatch_DeliversOnEveryChannel(t *testing.T) {
+	email, sms := &recordingSender{channel: notify.ChannelEmail}, &recordingSender{channel: notify.ChannelSMS}
+	noPhone := testOutboxEvent(2, 0)
+	noPhone.Guest.Phone = ""
+	repo := &mockOutboxRepo{queue: []models.OutboxEvent{testOutboxEvent(1, 0), noPhone}}
+	svc := NewNotificationService(repo, []notify.Sender{email, sms}, DefaultNotificationConfig)
+
+	sent, err := svc.Dispatch(context.Background())
+	if err != nil || sent != 2 {
+		t.Fatalf("expected 2 delivered events, got %d err=%v", sent, err)
+	}
+	if len(email.sent) != 2 || email.sent[0].To != "ada@example.com" || email.sent[0].Subject != "Booking #7 received" {
+		t.Fatalf("unexpected emails: %+v", email.sent)
+	}
+	if len(sms.sent) != 1 || sms.sent[0].To != "5550100100" || sms.sent[0].Subject != "" {
+		t.Fatalf("expected one SMS to the guest with a phone, got %+v", sms.sent)
+	}
+	if got := repo.sent[2]; len(got) != 1 || got[0] != notify.ChannelEmail {
+		t.Fatalf("expected only email delivered for the guest without phone, got %v", got)
+	}
+}
+
+func TestNotificationDispatch_RetriesOnlyFailedChannels(t *testing.T) {
+	email := &recordingSender{channel: notify.ChannelEmail}
+	sms := &recordingSender{channel: notify.ChannelSMS, err: errors.New("gateway unavailable")}
+	repo := &mockOutboxRepo{queue: []models.OutboxEvent{testOutboxEvent(1, 2)}}
+	now := time.Date(2030, 5, 1, 12, 0, 0, 0, time.UTC)
+	svc := NewNotificationService(repo, []notify.Sender{email, sms}, NotificationConfig{MaxAttempts: 5, RetryBase: time.Minute, RetryMax: time.Hour})
+	svc.now = func() time.Time { return now }
+
+	sent, err := svc.Dispatch(context.Background())
+	if err != nil || sent != 0 {
+		t.Fatalf("expected no fully delivered event, got %d err=%v", sent, err)
+	}
+	failure, ok := repo.failures[1]
+	if !ok || failure.dead || !strings.Contains(failure.message, "sms: gateway unavailable") {
+		t.Fatalf("expected a retryable sms failure, got %+v", failure)
+	}
+	if len(failure.delivered) != 1 || failure.delivered[0] != notify.ChannelEmail {
+		t.Fatalf("expected email to be recorded as delivered, got %v", failure.delivered)
+	}
+	if !failure.retryAt.Equal(now.Add(4 * time.Minute)) {
+		t.Fatalf("expected the third attempt to back off 4 minutes, got %v", failure.retryAt.Sub(now))
+	}
+
+	// The retry skips the channel that already delivered the event
+	sms.err = nil
+	repo.queue = []models.OutboxEvent{testOutboxEvent(1, 3, failure.delivered...)}
+	if sent, err := svc.Dispatch(context.Background()); err != nil || sent != 1 {
+		t.Fatalf("expected the retry to deliver the event, got %d err=%v", sent, err)
+	}
+	if len(email.sent) != 1 || len(sms.sent) != 1 {
+		t.Fatalf("expected one email and one SMS in total, got %d and %d", len(email.sent), len(sms.sent))
+	}
+}
+
+func TestNotificationDispatch_DeadAfterMaxAttempts(t *testing.T) {
+	email := &recordingSender{channel: notify.ChannelEmail, err: errors.New("mailbox unavailable")}
+	unknown := testOutboxEvent(2, 0)
+	unknown.EventType = "booking.archived"
+	repo := &mockOutboxRepo{queue: []models.OutboxEvent{testOutboxEvent(1, 2), unknown}}
+	svc := NewNotificationService(repo, []notify.Sender{email}, NotificationConfig{MaxAttempts: 3, RetryBase: time.Minute, RetryMax: time.Hour})
+
+	if _, err := svc.Dispatch(context.Background()); err != nil {
+		t.Fatalf("Dispatch failed: %v", err)
+	}
+	if !repo.failures[1].dead {
+		t.Fatalf("expected the event to be dead after its third attempt, got %+v", repo.failures[1])
+	}
+	if failure := repo.failures[2]; failure.dead || !strings.Contains(failure.message, "no notification template") {
+		t.Fatalf("expected an unrenderable event to be retried, got %+v", failure)
+	}
+}
+
+func TestNotificationRetryDelay_DoublesUpToMax(t *testing.T) {
+	svc := &NotificationService{config: NotificationConfig{RetryBase: time.Minute, RetryMax: 10 * time.Minute}}
+	for attempts, want := range map[int]time.Duration{1: time.Minute, 2: 2 * time.Minute, 4: 8 * time.Minute, 5: 10 * time.Minute, 40: 10 * time.Minute} {
+		if got := svc.retryDelay(attempts); got != want {
+			t.Fatalf("attempt %d: expected %v, got %v", attempts, want, got)
+		}
+	}
+}
+
+func TestNotificationListEvents_ValidatesStatus(t *testing.T) {
+	svc := NewNotificationService(&mockOutboxRepo{}, nil, DefaultNotificationConfig)
+	if _, err := svc.ListEvents(context.Background(), "archived", 0); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if _, err := svc.ListEvents(context.Background(), models.OutboxStatusDead, 0); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+}
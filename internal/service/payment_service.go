@@ -7,22 +7,35 @@ import (
 	"errors"
 	"industry-api/internal/models"
 	"industry-api/internal/repository"
+	"time"
 )
 
 // PaymentService handles all payment-related business logic operations.
 // It acts as an intermediary between handlers and repository layers.
 type PaymentService struct {
-	repo repository.PaymentRepo // Repository interface for easier testing
+	repo    repository.PaymentRepo // Repository interface for easier testing
+	holdTTL time.Duration          // How far initiating a payment extends the booking's hold
+	now     func() time.Time       // clock used for holds (time.Now when nil)
 }
 
 // NewPaymentService creates and returns a new instance of PaymentService.
-// It accepts a PaymentRepo interface for data access operations.
-func NewPaymentService(repo repository.PaymentRepo) *PaymentService {
-	return &PaymentService{repo: repo}
+// It accepts a PaymentRepo interface for data access operations and the booking hold duration
+// granted to a guest who starts paying.
+func NewPaymentService(repo repository.PaymentRepo, holdTTL time.Duration) *PaymentService {
+	return &PaymentService{repo: repo, holdTTL: holdTTL}
+}
+
+// clock returns the current time of the service's clock.
+func (s *PaymentService) clock() time.Time {
+	if s.now == nil {
+		return time.Now()
+	}
+	return s.now()
 }
 
 // InitiatePaymet initiates a new payment transaction after validation.
-// It validates all required payment fields before delegating to the repository.
+// It validates all required payment fields and delegates to the repository, which extends the
+// booking's hold in the same transaction.
 // Returns the created payment or an error if validation fails.
 func (s *PaymentService) InitiatePaymet(ctx context.Context, payment *models.Payment) (*models.Payment, error) {
 	// Validate payment amount is provided and greater than zero
@@ -38,8 +51,15 @@ func (s *PaymentService) InitiatePaymet(ctx context.Context, payment *models.Pay
 		return nil, errors.New("payment method is required")
 	}
 
+	// Keep the room held while the guest pays; a booking whose hold already expired cannot be paid
+	var holdUntil *time.Time
+	if s.holdTTL > 0 {
+		until := s.clock().Add(s.holdTTL)
+		holdUntil = &until
+	}
+
 	// Delegate to repository to persist the payment
-	pmt, err := s.repo.InitiatePayment(ctx, payment)
+	pmt, err := s.repo.InitiatePayment(ctx, payment, holdUntil)
 	if err != nil {
 		return nil, err
 	}
@@ -11,9 +11,12 @@ import (
 type mockPaymentRepo struct {
 	init   func(ctx context.Context, p *models.Payment) (*models.Payment, error)
 	update func(ctx context.Context, p *models.Payment, id int) (*models.Payment, error)
+	// heldUntil records the hold extension requested for the booking
+	heldUntil *time.Time
 }
 
-func (m *mockPaymentRepo) InitiatePayment(ctx context.Context, p *models.Payment) (*models.Payment, error) {
+func (m *mockPaymentRepo) InitiatePayment(ctx context.Context, p *models.Payment, holdUntil *time.Time) (*models.Payment, error) {
+	m.heldUntil = holdUntil
 	return m.init(ctx, p)
 }
 func (m *mockPaymentRepo) UpdatePayment(ctx context.Context, p *models.Payment, id int) (*models.Payment, error) {
@@ -61,3 +64,22 @@ func TestUpdatePayment_Validation(t *testing.T) {
 		t.Fatalf("expected updated id 2")
 	}
 }
+
+func TestInitiatePayment_ExtendsBookingHold(t *testing.T) {
+	repo := &mockPaymentRepo{init: func(ctx context.Context, p *models.Payment) (*models.Payment, error) { p.ID = 1; return p, nil }}
+	now := time.Date(2030, 5, 10, 12, 0, 0, 0, time.UTC)
+	svc := NewPaymentService(repo, 20*time.Minute)
+	svc.now = func() time.Time { return now }
+
+	if _, err := svc.InitiatePaymet(context.Background(), &models.Payment{BookingID: 1, Amount: 100, PaymentMethod: "card"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.heldUntil == nil || !repo.heldUntil.Equal(now.Add(20*time.Minute)) {
+		t.Fatalf("expected the hold to be extended by 20 minutes, got %v", repo.heldUntil)
+	}
+
+	svc = NewPaymentService(repo, 0)
+	if _, err := svc.InitiatePaymet(context.Background(), &models.Payment{BookingID: 1, Amount: 100, PaymentMethod: "card"}); err != nil || repo.heldUntil != nil {
+		t.Fatalf("expected no hold extension without a hold TTL, got %v (%v)", repo.heldUntil, err)
+	}
+}
@@ -0,0 +1,74 @@
+// Package service provides business logic layer implementations.
+// Services contain validation logic and business rules before delegating to repositories.
+package service
+
+import (
+	"fmt"
+	"industry-api/internal/models"
+	"math"
+	"time"
+)
+
+const priceMismatchTolerance = 0.005
+
+// GuestPricing sets how many guests a room's nightly rate covers and what every further guest
+// is charged per night.
+type GuestPricing struct {
+	IncludedGuests int     // Guests covered by the nightly rate, adults first
+	ExtraAdultRate float64 // Per-night charge for every further adult
+	ExtraChildRate float64 // Per-night charge for every further child
+}
+
+// DefaultGuestPricing is used when nothing is configured: the rate covers two guests, and
+// further adults pay 25 and children 10 per night.
+var DefaultGuestPricing = GuestPricing{IncludedGuests: 2, ExtraAdultRate: 25, ExtraChildRate: 10}
+
+// nightsBetween returns the number of nights between the check-in and check-out dates.
+// Times of day are ignored.
+func nightsBetween(checkIn, checkOut time.Time) int {
+	return int(math.Round(dateOnly(checkOut).Sub(dateOnly(checkIn)).Hours() / 24))
+}
+
+// roundMoney rounds an amount to whole cents.
+func roundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// calculatePrice computes the itemised price of a stay in a room: the nightly rate times the
+// number of nights plus a per-night charge for every guest beyond those included in the rate.
+// Returns a validation error if the stay has no nights or exceeds the room's capacity.
+func calculatePrice(room *models.Room, pricing GuestPricing, checkIn, checkOut time.Time, adults, children int) (*models.PriceBreakdown, error) {
+	nights := nightsBetween(checkIn, checkOut)
+	if nights <= 0 {
+		return nil, validationError("check out date must be after check in date")
+	}
+	if adults+children > room.Capacity {
+		return nil, validationError("room %s holds at most %d guests", room.RoomNumber, room.Capacity)
+	}
+
+	breakdown := &models.PriceBreakdown{Nights: nights, Taxes: []models.TaxLine{}}
+	addLine := func(code, description string, quantity int, unitPrice float64) {
+		if quantity <= 0 {
+			return
+		}
+		amount := roundMoney(float64(quantity) * unitPrice)
+		breakdown.Lines = append(breakdown.Lines, models.PriceLine{
+			Code:        code,
+			Description: description,
+			Quantity:    quantity,
+			UnitPrice:   unitPrice,
+			Amount:      amount,
+		})
+		breakdown.Total = roundMoney(breakdown.Total + amount)
+	}
+
+	// Included guest slots go to adults first
+	extraAdults := max(adults-pricing.IncludedGuests, 0)
+	extraChildren := max(children-max(pricing.IncludedGuests-adults, 0), 0)
+
+	addLine("room", fmt.Sprintf("Room %s (%s), %d night(s)", room.RoomNumber, room.RoomType, nights), nights, room.Price)
+	addLine("extra_adult", "Extra adult per night", extraAdults*nights, pricing.ExtraAdultRate)
+	addLine("extra_child", "Extra child per night", extraChildren*nights, pricing.ExtraChildRate)
+	breakdown.FirstNight = roundMoney(room.Price + float64(extraAdults)*pricing.ExtraAdultRate + float64(extraChildren)*pricing.ExtraChildRate)
+	return breakdown, nil
+}
@@ -0,0 +1,147 @@
+// Package service provides business logic layer implementations.
+// Services contain validation logic and business rules before delegating to repositories.
+package service
+
+import (
+	"context"
+	"fmt"
+	"industry-api/internal/models"
+	"industry-api/internal/repository"
+	"slices"
+	"strings"
+	"time"
+)
+
+// maxPromoCodesPerBooking is the number of promo codes a single booking may redeem.
+const maxPromoCodesPerBooking = 5
+
+// PromoService handles the management of promo codes. Codes are redeemed through BookingService,
+// which applies their discounts when a booking is priced.
+type PromoService struct {
+	repo repository.PromoRepo // Repository interface for data access (allows mocking in tests)
+}
+
+// NewPromoService creates and returns a new instance of PromoService.
+// It accepts a PromoRepo interface for data access operations.
+func NewPromoService(repo repository.PromoRepo) *PromoService {
+	return &PromoService{repo: repo}
+}
+
+// CreatePromoCode validates and stores a new promo code. The code is upper-cased and the
+// minimum stay defaults to one night.
+// Returns the created code, a validation error, or a conflict error if the code is taken.
+func (s *PromoService) CreatePromoCode(ctx context.Context, promo *models.PromoCode) (*models.PromoCode, error) {
+	promo.Code = strings.ToUpper(strings.TrimSpace(promo.Code))
+	if promo.Code == "" || strings.ContainsAny(promo.Code, " \t") {
+		return nil, validationError("code is required and cannot contain spaces")
+	}
+	switch promo.DiscountType {
+	case models.DiscountPercentage:
+		if promo.DiscountValue <= 0 || promo.DiscountValue > 100 {
+			return nil, validationError("a percentage discount must be between 0 and 100")
+		}
+	case models.DiscountFixed:
+		if promo.DiscountValue <= 0 {
+			return nil, validationError("a fixed discount must be positive")
+		}
+	default:
+		return nil, validationError("discount type must be %q or %q", models.DiscountPercentage, models.DiscountFixed)
+	}
+	promo.DiscountValue = roundMoney(promo.DiscountValue)
+	if promo.StayFrom != nil && promo.StayTo != nil && promo.StayTo.Before(*promo.StayFrom) {
+		return nil, validationError("stay window ends before it starts")
+	}
+	if promo.BookFrom != nil && promo.BookTo != nil && promo.BookTo.Before(*promo.BookFrom) {
+		return nil, validationError("booking window ends before it starts")
+	}
+	if promo.MinNights == 0 {
+		promo.MinNights = 1
+	}
+	if promo.MinNights < 1 {
+		return nil, validationError("minimum nights must be at least 1")
+	}
+	if promo.RoomTypes == nil {
+		promo.RoomTypes = []string{}
+	}
+	if err := s.repo.CreatePromoCode(ctx, promo); err != nil {
+		return nil, err
+	}
+	return promo, nil
+}
+
+// ListPromoCodes returns every promo code, newest first.
+func (s *PromoService) ListPromoCodes(ctx context.Context) ([]models.PromoCode, error) {
+	return s.repo.ListPromoCodes(ctx)
+}
+
+// DeactivatePromoCode stops a promo code from being redeemed.
+func (s *PromoService) DeactivatePromoCode(ctx context.Context, id int) (*models.PromoCode, error) {
+	if id <= 0 {
+		return nil, validationError("promo code id is required")
+	}
+	return s.repo.DeactivatePromoCode(ctx, id)
+}
+
+// checkPromoCode checks that a promo code may be redeemed at now for the stay of a booking in room.
+func checkPromoCode(promo *models.PromoCode, booking *models.Booking, room *models.Room, now time.Time) error {
+	if !promo.IsActive {
+		return validationError("promo code %s is no longer active", promo.Code)
+	}
+	if (promo.BookFrom != nil && now.Before(*promo.BookFrom)) || (promo.BookTo != nil && now.After(*promo.BookTo)) {
+		return validationError("promo code %s cannot be used for bookings made today", promo.Code)
+	}
+	if err := checkPromoStay(promo, booking, room); err != nil {
+		return err
+	}
+	if promo.MaxUses != nil && promo.TimesUsed >= *promo.MaxUses {
+		return conflictError("promo code %s has been used up", promo.Code)
+	}
+	return nil
+}
+
+// checkPromoStay checks that the stay of a booking in room meets the conditions of a promo code:
+// its stay window, minimum nights and room types.
+func checkPromoStay(promo *models.PromoCode, booking *models.Booking, room *models.Room) error {
+	// Every night of the stay must fall inside the stay window
+	firstNight := dateOnly(booking.CheckInDate)
+	lastNight := dateOnly(booking.CheckOutDate).AddDate(0, 0, -1)
+	if (promo.StayFrom != nil && firstNight.Before(dateOnly(*promo.StayFrom))) || (promo.StayTo != nil && lastNight.After(dateOnly(*promo.StayTo))) {
+		return validationError("promo code %s is not valid for the selected dates", promo.Code)
+	}
+	if nights := nightsBetween(booking.CheckInDate, booking.CheckOutDate); nights < promo.MinNights {
+		return validationError("promo code %s requires a stay of at least %d nights", promo.Code, promo.MinNights)
+	}
+	if len(promo.RoomTypes) > 0 && !slices.ContainsFunc(promo.RoomTypes, func(t string) bool { return strings.EqualFold(t, room.RoomType) }) {
+		return validationError("promo code %s is not valid for %s rooms", promo.Code, room.RoomType)
+	}
+	return nil
+}
+
+// addDiscounts adds a discount line per promo code to a price breakdown and returns the
+// redemptions. Percentages apply to the price before any discount, so stacked codes add up
+// rather than compound, and the total never drops below zero.
+func addDiscounts(breakdown *models.PriceBreakdown, promos []models.PromoCode) []models.PromoRedemption {
+	base, firstNight := breakdown.Total, breakdown.FirstNight
+	redemptions := make([]models.PromoRedemption, 0, len(promos))
+	for _, promo := range promos {
+		amount := promo.DiscountValue
+		description := fmt.Sprintf("Promo code %s (%.2f off)", promo.Code, promo.DiscountValue)
+		if promo.DiscountType == models.DiscountPercentage {
+			amount = roundMoney(base * promo.DiscountValue / 100)
+			description = fmt.Sprintf("Promo code %s (%g%% off)", promo.Code, promo.DiscountValue)
+			// Fixed amounts come off the stay; percentages come off every night
+			breakdown.FirstNight = roundMoney(max(0, breakdown.FirstNight-firstNight*promo.DiscountValue/100))
+		}
+		amount = min(amount, breakdown.Total)
+		breakdown.Lines = append(breakdown.Lines, models.PriceLine{
+			Code:        "promo",
+			Description: description,
+			Quantity:    1,
+			UnitPrice:   -amount,
+			Amount:      -amount,
+		})
+		breakdown.Total = roundMoney(breakdown.Total - amount)
+		redemptions = append(redemptions, models.PromoRedemption{PromoCodeID: promo.ID, Code: promo.Code, DiscountAmount: amount})
+	}
+	return redemptions
+}
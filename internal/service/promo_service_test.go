@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"industry-api/internal/models"
+)
+
+type mockPromoRepo struct {
+	created *models.PromoCode
+}
+
+func (m *mockPromoRepo) CreatePromoCode(ctx context.Context, promo *models.PromoCode) error {
+	promo.ID, promo.IsActive = 1, true
+	m.created = promo
+	return nil
+}
+func (m *mockPromoRepo) ListPromoCodes(ctx context.Context) ([]models.PromoCode, error) {
+	return nil, nil
+}
+func (m *mockPromoRepo) DeactivatePromoCode(ctx context.Context, id int) (*models.PromoCode, error) {
+	return nil, nil
+}
+
+func TestCreatePromoCode_Validation(t *testing.T) {
+	svc := NewPromoService(&mockPromoRepo{})
+
+	promo, err := svc.CreatePromoCode(context.Background(), &models.PromoCode{Code: " summer10 ", DiscountType: models.DiscountPercentage, DiscountValue: 10})
+	if err != nil || promo.Code != "SUMMER10" || promo.MinNights != 1 || promo.RoomTypes == nil {
+		t.Fatalf("unexpected promo code: %+v err=%v", promo, err)
+	}
+	if _, err := svc.CreatePromoCode(context.Background(), &models.PromoCode{Code: "HALF", DiscountType: models.DiscountPercentage, DiscountValue: 150}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error for a percentage above 100, got %v", err)
+	}
+	from := time.Now()
+	to := from.AddDate(0, 0, -1)
+	if _, err := svc.CreatePromoCode(context.Background(), &models.PromoCode{Code: "BACK", DiscountType: models.DiscountFixed, DiscountValue: 5, StayFrom: &from, StayTo: &to}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error for an inverted stay window, got %v", err)
+	}
+}
+
+func TestAddBooking_AppliesPromoCodes(t *testing.T) {
+	checkIn := time.Now().AddDate(0, 1, 0)
+	stayFrom, stayTo := checkIn.AddDate(0, 0, -1), checkIn.AddDate(0, 0, 5)
+	one := 1
+	repo := &mockBookingRepo{
+		add: func(ctx context.Context, b *models.Booking) (*models.Booking, error) { b.ID = 1; return b, nil },
+		room: func(ctx context.Context, roomID int) (*models.Room, error) {
+			return &models.Room{ID: roomID, RoomNumber: "101", RoomType: "Deluxe", Price: 100, Capacity: 4, HousekeepingStatus: models.HousekeepingInspected}, nil
+		},
+		promos: []models.PromoCode{
+			{ID: 1, Code: "TEN", DiscountType: models.DiscountPercentage, DiscountValue: 10, MinNights: 2, StayFrom: &stayFrom, StayTo: &stayTo, Stackable: true, IsActive: true},
+			{ID: 2, Code: "FIVE", DiscountType: models.DiscountFixed, DiscountValue: 5, MinNights: 1, RoomTypes: []string{"deluxe"}, Stackable: true, IsActive: true},
+			{ID: 3, Code: "SOLO", DiscountType: models.DiscountFixed, DiscountValue: 50, MinNights: 1, MaxUses: &one, IsActive: true},
+		},
+	}
+	svc := NewBookingService(repo, DefaultBookingConfig)
+	booking := func(nights int, codes ...string) *models.Booking {
+		return &models.Booking{UserID: 5, RoomID: 1, CheckInDate: checkIn, CheckOutDate: checkIn.AddDate(0, 0, nights), Adults: 2, Children: 1, PromoCodes: codes}
+	}
+
+	// 2 nights at 100 plus a child at 10 a night is 220; 10% is 22 and 5 more is 27 off
+	created, err := svc.AddBooking(context.Background(), booking(2, "ten", "FIVE"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.TotalAmount != 193 || created.PriceBreakdown.Total != 193 || len(created.Redemptions) != 2 || created.Redemptions[0].DiscountAmount != 22 {
+		t.Fatalf("unexpected discounted booking: total=%v breakdown=%+v redemptions=%+v", created.TotalAmount, created.PriceBreakdown, created.Redemptions)
+	}
+
+	for name, tc := range map[string]struct {
+		booking *models.Booking
+		want    error
+	}{
+		"below minimum nights":       {booking(1, "TEN"), ErrValidation},
+		"outside stay window":        {booking(7, "TEN"), ErrValidation},
+		"unknown code":               {booking(2, "NOPE"), ErrValidation},
+		"non-stackable with another": {booking(2, "SOLO", "FIVE"), ErrValidation},
+	} {
+		if _, err := svc.AddBooking(context.Background(), tc.booking); !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", name, tc.want, err)
+		}
+	}
+	repo.promos[2].TimesUsed = 1
+	if _, err := svc.AddBooking(context.Background(), booking(2, "SOLO")); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected conflict for a used-up code, got %v", err)
+	}
+}
+
+func TestModifyBooking_KeepsRedeemedDiscount(t *testing.T) {
+	checkIn := time.Now().AddDate(0, 1, 0)
+	repo := &mockBookingRepo{
+		get: func(ctx context.Context, id int) (*models.Booking, error) {
+			return &models.Booking{ID: id, RoomID: 1, Status: models.BookingStatusConfirmed, CheckInDate: checkIn, CheckOutDate: checkIn.AddDate(0, 0, 1), Adults: 1, TotalAmount: 90, Version: 1}, nil
+		},
+		modify: func(ctx context.Context, b *models.Booking, change *models.BookingChange) (*models.Booking, error) {
+			return b, nil
+		},
+		redeemed: []models.PromoCode{{ID: 1, Code: "TEN", DiscountType: models.DiscountPercentage, DiscountValue: 10}},
+	}
+	svc := NewBookingService(repo, DefaultBookingConfig)
+
+	checkOut := checkIn.AddDate(0, 0, 2)
+	result, err := svc.ModifyBooking(context.Background(), 1, &models.BookingUpdateRequest{CheckOutDate: &checkOut}, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Booking.TotalAmount != 180 || result.Change.PriceDifference != 90 {
+		t.Fatalf("expected the 10%% discount to carry over, got total=%v change=%+v", result.Booking.TotalAmount, result.Change)
+	}
+	if len(result.Booking.Redemptions) != 1 || result.Booking.Redemptions[0].DiscountAmount != 20 {
+		t.Fatalf("expected the redemption to be repriced to 20, got %+v", result.Booking.Redemptions)
+	}
+
+	// A stay the code no longer applies to is rejected
+	repo.redeemed[0].MinNights = 3
+	if _, err := svc.ModifyBooking(context.Background(), 1, &models.BookingUpdateRequest{CheckOutDate: &checkOut}, nil, nil); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected a validation error for a stay too short for the code, got %v", err)
+	}
+}
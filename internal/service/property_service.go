@@ -0,0 +1,101 @@
+// Package service provides business logic layer implementations.
+// Services contain validation logic and business rules before delegating to repositories.
+package service
+
+import (
+	"context"
+	"fmt"
+	"industry-api/internal/cache"
+	"industry-api/internal/models"
+	"industry-api/internal/repository"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PropertyService handles hotel properties and the assignment of staff to them.
+type PropertyService struct {
+	repo repository.PropertyRepo // Repository interface for data access (allows mocking in tests)
+}
+
+// NewPropertyService creates and returns a new instance of PropertyService.
+// It accepts a PropertyRepository dependency for data access operations.
+func NewPropertyService(repo repository.PropertyRepo) *PropertyService {
+	return &PropertyService{repo: repo}
+}
+
+// CreateProperty validates and stores a new property.
+// The code is upper-cased and the time zone defaults to UTC; it must be a valid IANA zone name.
+// Returns the created property or an error if validation fails or the code is already taken.
+func (s *PropertyService) CreateProperty(ctx context.Context, property *models.Property) (*models.Property, error) {
+	property.Code = strings.ToUpper(strings.TrimSpace(property.Code))
+	property.Name = strings.TrimSpace(property.Name)
+	if property.Code == "" || property.Name == "" {
+		return nil, validationError("code and name are required")
+	}
+	if property.Timezone == "" {
+		property.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(property.Timezone); err != nil {
+		return nil, validationError("unknown timezone %q", property.Timezone)
+	}
+
+	if err := s.repo.CreateProperty(ctx, property); err != nil {
+		return nil, err
+	}
+	return property, nil
+}
+
+// ListProperties returns the properties within the given scope (nil means all properties).
+func (s *PropertyService) ListProperties(ctx context.Context, propertyIDs []int) ([]models.Property, error) {
+	return s.repo.ListProperties(ctx, propertyIDs)
+}
+
+// UserPropertyIDs returns the IDs of the properties a user is assigned to.
+func (s *PropertyService) UserPropertyIDs(ctx context.Context, userID int) ([]int, error) {
+	return s.repo.GetUserPropertyIDs(ctx, userID)
+}
+
+// AssignUser replaces the set of properties a user is assigned to.
+// Duplicate IDs are ignored; an empty list removes every assignment.
+// Returns the new assignment or an error if the user or a property does not exist.
+func (s *PropertyService) AssignUser(ctx context.Context, userID int, propertyIDs []int) ([]int, error) {
+	ids := slices.Clone(propertyIDs)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+	for _, id := range ids {
+		if id <= 0 {
+			return nil, validationError("property ids must be positive")
+		}
+	}
+
+	if err := s.repo.SetUserProperties(ctx, userID, ids); err != nil {
+		return nil, err
+	}
+	// Cached user details include the assignment
+	if err := cache.DeletePattern(ctx, propertyCachePattern(fmt.Sprintf("user:%d", userID))); err != nil {
+		fmt.Printf("⚠️ Failed to invalidate user cache: %v\n", err)
+	}
+	return ids, nil
+}
+
+// propertyCacheKey namespaces a cache key by the property scope it was computed for,
+// e.g. "property:1,3:available_rooms" or "property:all:user:7".
+func propertyCacheKey(propertyIDs []int, key string) string {
+	if propertyIDs == nil {
+		return "property:all:" + key
+	}
+	ids := slices.Clone(propertyIDs)
+	slices.Sort(ids)
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	return "property:" + strings.Join(parts, ",") + ":" + key
+}
+
+// propertyCachePattern returns a glob pattern matching a key built by propertyCacheKey under every scope.
+func propertyCachePattern(key string) string {
+	return "property:*:" + key
+}
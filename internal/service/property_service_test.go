@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"industry-api/internal/models"
+)
+
+type mockPropertyRepo struct {
+	assigned []int
+}
+
+func (m *mockPropertyRepo) CreateProperty(ctx context.Context, property *models.Property) error {
+	property.ID = 1
+	return nil
+}
+func (m *mockPropertyRepo) ListProperties(ctx context.Context, propertyIDs []int) ([]models.Property, error) {
+	return nil, errors.New("not-implemented")
+}
+func (m *mockPropertyRepo) GetUserPropertyIDs(ctx context.Context, userID int) ([]int, error) {
+	return m.assigned, nil
+}
+func (m *mockPropertyRepo) SetUserProperties(ctx context.Context, userID int, propertyIDs []int) error {
+	m.assigned = propertyIDs
+	return nil
+}
+
+func TestCreateProperty_Validation(t *testing.T) {
+	svc := NewPropertyService(&mockPropertyRepo{})
+
+	if _, err := svc.CreateProperty(context.Background(), &models.Property{Code: " ", Name: "Hotel"}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error for blank code, got %v", err)
+	}
+	if _, err := svc.CreateProperty(context.Background(), &models.Property{Code: "lon1", Name: "London", Timezone: "Mars/Base"}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error for unknown timezone, got %v", err)
+	}
+
+	p, err := svc.CreateProperty(context.Background(), &models.Property{Code: "lon1", Name: "London"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Code != "LON1" || p.Timezone != "UTC" {
+		t.Fatalf("expected normalized code and default timezone, got %+v", p)
+	}
+}
+
+func TestAssignUser_DeduplicatesIDs(t *testing.T) {
+	repo := &mockPropertyRepo{}
+	svc := NewPropertyService(repo)
+
+	ids, err := svc.AssignUser(context.Background(), 5, []int{3, 1, 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ids) != 2 || ids[0] != 1 || ids[1] != 3 || len(repo.assigned) != 2 {
+		t.Fatalf("expected sorted unique ids, got %v", ids)
+	}
+	if _, err := svc.AssignUser(context.Background(), 5, []int{0}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error for non-positive id, got %v", err)
+	}
+}
+
+func TestPropertyCacheKey(t *testing.T) {
+	if got := propertyCacheKey(nil, "available_rooms"); got != "property:all:available_rooms" {
+		t.Fatalf("unexpected unscoped key %q", got)
+	}
+	if got := propertyCacheKey([]int{3, 1}, "user:7"); got != "property:1,3:user:7" {
+		t.Fatalf("unexpected scoped key %q", got)
+	}
+	if propertyCacheKey([]int{}, "k") == propertyCacheKey(nil, "k") {
+		t.Fatalf("an empty scope must not share the unscoped key")
+	}
+}
@@ -0,0 +1,243 @@
+// Package service provides business logic layer implementations.
+// Services contain validation logic and business rules before delegating to repositories.
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"industry-api/internal/models"
+	"industry-api/internal/repository"
+	"math"
+	"math/big"
+	"time"
+)
+
+const (
+	// maxReservationRooms is the largest number of rooms booked by one group reservation.
+	maxReservationRooms = 20
+	// confirmationCodeAlphabet leaves out characters that are easily confused (0/O, 1/I).
+	confirmationCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	// confirmationCodeLength is the number of characters in a confirmation code.
+	confirmationCodeLength = 8
+)
+
+// ReservationService handles group reservations made of several room bookings.
+// Each room is validated and priced like a single booking by the BookingService.
+type ReservationService struct {
+	repo     repository.ReservationRepo // Repository interface for data access (allows mocking in tests)
+	bookings *BookingService            // Prepares and prices the individual bookings
+}
+
+// NewReservationService creates and returns a new instance of ReservationService.
+// It accepts a ReservationRepo interface for data access operations and the BookingService
+// whose rules apply to each room.
+func NewReservationService(repo repository.ReservationRepo, bookings *BookingService) *ReservationService {
+	return &ReservationService{repo: repo, bookings: bookings}
+}
+
+// CreateReservation books every requested room for the lead guest under one confirmation code.
+// All rooms must belong to the same property. Booking is all-or-nothing: if any room is taken
+// for its dates nothing is booked and a *BookingConflictError names the taken room.
+// Returns the created reservation with its bookings and totals.
+func (s *ReservationService) CreateReservation(ctx context.Context, req *models.ReservationRequest) (*models.Reservation, error) {
+	if req.UserID <= 0 {
+		return nil, validationError("user id is required")
+	}
+	if len(req.Rooms) == 0 || len(req.Rooms) > maxReservationRooms {
+		return nil, validationError("a reservation must book between 1 and %d rooms", maxReservationRooms)
+	}
+
+	reservation := &models.Reservation{LeadUserID: req.UserID, SpecialRequests: req.SpecialRequests}
+	var total float64
+	for i, room := range req.Rooms {
+		if room.RoomID <= 0 {
+			return nil, validationError("room %d: room id is required", i+1)
+		}
+		if !dateOnly(room.CheckOutDate).After(dateOnly(room.CheckInDate)) {
+			return nil, validationError("room %d: check out date must be after check in date", i+1)
+		}
+		if room.Adults < 1 {
+			return nil, validationError("room %d: at least one adult is required", i+1)
+		}
+		if room.Children < 0 {
+			return nil, validationError("room %d: children cannot be negative", i+1)
+		}
+
+		booking := models.Booking{
+			UserID:          req.UserID,
+			RoomID:          room.RoomID,
+			CheckInDate:     room.CheckInDate,
+			CheckOutDate:    room.CheckOutDate,
+			Adults:          room.Adults,
+			Children:        room.Children,
+			SpecialRequests: room.SpecialRequests,
+		}
+		if err := s.bookings.prepareBooking(ctx, &booking); err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			reservation.PropertyID = booking.PropertyID
+		} else if booking.PropertyID != reservation.PropertyID {
+			return nil, validationError("all rooms of a reservation must belong to the same property")
+		}
+		total += booking.TotalAmount
+		reservation.Bookings = append(reservation.Bookings, booking)
+	}
+	if req.TotalAmount != 0 && math.Abs(req.TotalAmount-total) > priceMismatchTolerance {
+		return nil, validationError("total amount %.2f does not match the computed price %.2f", req.TotalAmount, roundMoney(total))
+	}
+
+	code, err := newConfirmationCode()
+	if err != nil {
+		return nil, err
+	}
+	reservation.ConfirmationCode = code
+	if err := s.repo.CreateReservation(ctx, reservation); err != nil {
+		return nil, err
+	}
+	summarizeReservation(reservation, nil)
+	return reservation, nil
+}
+
+// GetReservation returns a reservation with its bookings and combined totals.
+func (s *ReservationService) GetReservation(ctx context.Context, id int) (*models.Reservation, error) {
+	if id <= 0 {
+		return nil, validationError("reservation id is required")
+	}
+	reservation, err := s.repo.GetReservation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	paid, err := s.repo.GetReservationPaidAmounts(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	summarizeReservation(reservation, paid)
+	return reservation, nil
+}
+
+// PayReservation starts one payment for a whole reservation. The amount is split into one
+// pending payment per booking, settling the outstanding balance of each active booking in
+// order of arrival, and every paid booking's hold is extended like a single booking payment.
+// Payments still pending count towards the balance, so a retried payment cannot pay twice.
+// Returns a validation error if the amount exceeds what the active bookings still owe, or an
+// error wrapping ErrConflict if a booking is paid for concurrently.
+func (s *ReservationService) PayReservation(ctx context.Context, id int, req *models.ReservationPaymentRequest) (*models.ReservationPayment, error) {
+	if req.Amount <= 0 {
+		return nil, validationError("amount must be positive")
+	}
+	if req.PaymentMethod == "" || req.TransactionID == "" {
+		return nil, validationError("payment method and transaction id are required")
+	}
+	reservation, err := s.repo.GetReservation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	paid, err := s.repo.GetReservationPaidAmounts(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := s.repo.GetReservationPendingAmounts(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	committed := make(map[int]int64, len(reservation.Bookings))
+	for _, booking := range reservation.Bookings {
+		committed[booking.ID] = paid[booking.ID] + pending[booking.ID]
+	}
+
+	// Fill each active booking's balance in turn until the amount is used up
+	remaining := req.Amount
+	var payments []*models.Payment
+	for _, booking := range reservation.Bookings {
+		if remaining == 0 {
+			break
+		}
+		if !reservationBookingPayable(booking.Status) {
+			continue
+		}
+		due := toCents(bookingAmountDue(booking)) - committed[booking.ID]
+		if due <= 0 {
+			continue
+		}
+		amount := min(due, remaining)
+		remaining -= amount
+		payments = append(payments, &models.Payment{
+			BookingID:     booking.ID,
+			ReservationID: &reservation.ID,
+			Amount:        amount,
+			PaymentMethod: req.PaymentMethod,
+			TransactionID: &req.TransactionID,
+			Status:        models.PaymentStatusPending,
+		})
+	}
+	if remaining > 0 {
+		return nil, validationError("amount exceeds the reservation's outstanding balance by %.2f", float64(remaining)/100)
+	}
+
+	// Keep the rooms held while the guest pays
+	var holdUntil *time.Time
+	if ttl := s.bookings.config.HoldTTL; ttl > 0 {
+		until := s.bookings.clock().Add(ttl)
+		holdUntil = &until
+	}
+	if err := s.repo.AddReservationPayments(ctx, payments, committed, holdUntil); err != nil {
+		return nil, err
+	}
+
+	result := &models.ReservationPayment{ReservationID: reservation.ID, Amount: req.Amount}
+	for _, payment := range payments {
+		result.Payments = append(result.Payments, *payment)
+	}
+	return result, nil
+}
+
+// summarizeReservation fills in a reservation's combined totals from its bookings and the
+// paid amount in cents of each booking.
+func summarizeReservation(reservation *models.Reservation, paid map[int]int64) {
+	var total float64
+	var paidCents int64
+	for _, booking := range reservation.Bookings {
+		total += bookingAmountDue(booking)
+		paidCents += paid[booking.ID]
+	}
+	reservation.TotalAmount = roundMoney(total)
+	reservation.AmountPaid = roundMoney(float64(paidCents) / 100)
+	reservation.BalanceDue = roundMoney(math.Max(0, reservation.TotalAmount-reservation.AmountPaid))
+}
+
+// bookingAmountDue is what a booking costs the guest: its price, or only the cancellation
+// penalty once it has been cancelled.
+func bookingAmountDue(booking models.Booking) float64 {
+	if booking.Status == models.BookingStatusCancelled {
+		if booking.Cancellation == nil {
+			return 0
+		}
+		return booking.Cancellation.Penalty
+	}
+	return booking.TotalAmount
+}
+
+// reservationBookingPayable reports whether a booking of the given status still accepts payments.
+func reservationBookingPayable(status string) bool {
+	return status == models.BookingStatusPending || status == models.BookingStatusConfirmed || status == models.BookingStatusCheckedIn
+}
+
+// toCents converts an amount of money to cents.
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// newConfirmationCode returns a random confirmation code.
+func newConfirmationCode() (string, error) {
+	code := make([]byte, confirmationCodeLength)
+	limit := big.NewInt(int64(len(confirmationCodeAlphabet)))
+	for i := range code {
+		n, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			return "", err
+		}
+		code[i] = confirmationCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
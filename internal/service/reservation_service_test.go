@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"industry-api/internal/models"
+)
+
+type mockReservationRepo struct {
+	created   *models.Reservation
+	stored    *models.Reservation
+	paid      map[int]int64
+	pending   map[int]int64
+	payments  []*models.Payment
+	holdUntil *time.Time
+}
+
+func (m *mockReservationRepo) CreateReservation(ctx context.Context, reservation *models.Reservation) error {
+	reservation.ID = 1
+	for i := range reservation.Bookings {
+		reservation.Bookings[i].ID = i + 1
+	}
+	m.created = reservation
+	return nil
+}
+func (m *mockReservationRepo) GetReservation(ctx context.Context, id int) (*models.Reservation, error) {
+	return m.stored, nil
+}
+func (m *mockReservationRepo) GetReservationPaidAmounts(ctx context.Context, id int) (map[int]int64, error) {
+	return m.paid, nil
+}
+func (m *mockReservationRepo) GetReservationPendingAmounts(ctx context.Context, id int) (map[int]int64, error) {
+	return m.pending, nil
+}
+func (m *mockReservationRepo) AddReservationPayments(ctx context.Context, payments []*models.Payment, committed map[int]int64, holdUntil *time.Time) error {
+	m.payments, m.holdUntil = payments, holdUntil
+	return nil
+}
+
+func TestCreateReservation_PricesEveryRoomInOneProperty(t *testing.T) {
+	bookingRepo := &mockBookingRepo{room: func(ctx context.Context, roomID int) (*models.Room, error) {
+		return &models.Room{ID: roomID, PropertyID: roomID / 100, RoomNumber: "r", Price: 100, Capacity: 4, HousekeepingStatus: models.HousekeepingInspected}, nil
+	}}
+	repo := &mockReservationRepo{}
+	svc := NewReservationService(repo, NewBookingService(bookingRepo, DefaultBookingConfig))
+
+	checkIn := time.Now().AddDate(0, 1, 0)
+	room := func(id, nights int) models.ReservationRoomRequest {
+		return models.ReservationRoomRequest{RoomID: id, CheckInDate: checkIn, CheckOutDate: checkIn.AddDate(0, 0, nights), Adults: 1}
+	}
+
+	reservation, err := svc.CreateReservation(context.Background(), &models.ReservationRequest{UserID: 5, Rooms: []models.ReservationRoomRequest{room(101, 2), room(102, 1)}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reservation.ConfirmationCode) != confirmationCodeLength || reservation.PropertyID != 1 || reservation.TotalAmount != 300 || reservation.BalanceDue != 300 {
+		t.Fatalf("unexpected reservation: %+v", reservation)
+	}
+	for _, b := range repo.created.Bookings {
+		if b.UserID != 5 || b.Status != models.BookingStatusPending || b.HoldExpiresAt == nil {
+			t.Fatalf("expected pending held bookings for the lead guest, got %+v", b)
+		}
+	}
+
+	// rooms of different properties cannot be grouped
+	_, err = svc.CreateReservation(context.Background(), &models.ReservationRequest{UserID: 5, Rooms: []models.ReservationRoomRequest{room(101, 1), room(201, 1)}})
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error for mixed properties, got %v", err)
+	}
+	// a mismatched expected total is rejected
+	_, err = svc.CreateReservation(context.Background(), &models.ReservationRequest{UserID: 5, TotalAmount: 250, Rooms: []models.ReservationRoomRequest{room(101, 2), room(102, 1)}})
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error for a wrong total, got %v", err)
+	}
+}
+
+func TestPayReservation_SplitsAcrossOutstandingBookings(t *testing.T) {
+	repo := &mockReservationRepo{
+		stored: &models.Reservation{ID: 1, Bookings: []models.Booking{
+			{ID: 1, Status: models.BookingStatusConfirmed, TotalAmount: 200},
+			{ID: 2, Status: models.BookingStatusCancelled, TotalAmount: 100, Cancellation: &models.CancellationQuote{Penalty: 50}},
+			{ID: 3, Status: models.BookingStatusPending, TotalAmount: 150},
+		}},
+		paid: map[int]int64{1: 5000},
+	}
+	svc := NewReservationService(repo, NewBookingService(&mockBookingRepo{}, DefaultBookingConfig))
+
+	// booking 1 still owes 150.00 and booking 3 owes 150.00; the cancelled booking is skipped
+	payment, err := svc.PayReservation(context.Background(), 1, &models.ReservationPaymentRequest{Amount: 20000, PaymentMethod: "card", TransactionID: "tx-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(payment.Payments) != 2 || payment.Payments[0].BookingID != 1 || payment.Payments[0].Amount != 15000 || payment.Payments[1].BookingID != 3 || payment.Payments[1].Amount != 5000 {
+		t.Fatalf("unexpected split: %+v", payment.Payments)
+	}
+	if repo.holdUntil == nil || *repo.payments[0].ReservationID != 1 {
+		t.Fatalf("expected held bookings and reservation-linked payments, got %+v", repo.payments[0])
+	}
+
+	if _, err := svc.PayReservation(context.Background(), 1, &models.ReservationPaymentRequest{Amount: 40000, PaymentMethod: "card", TransactionID: "tx-2"}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error for an overpayment, got %v", err)
+	}
+
+	// pending payments count towards the balance, so they cannot be paid twice
+	repo.pending = map[int]int64{1: 15000, 3: 15000}
+	if _, err := svc.PayReservation(context.Background(), 1, &models.ReservationPaymentRequest{Amount: 100, PaymentMethod: "card", TransactionID: "tx-3"}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error while the balance is pending, got %v", err)
+	}
+}
+
+func TestGetReservation_TotalsOnlyPenaltiesOfCancelledRooms(t *testing.T) {
+	repo := &mockReservationRepo{
+		stored: &models.Reservation{ID: 1, Bookings: []models.Booking{
+			{ID: 1, Status: models.BookingStatusConfirmed, TotalAmount: 200},
+			{ID: 2, Status: models.BookingStatusCancelled, TotalAmount: 100, Cancellation: &models.CancellationQuote{Penalty: 50}},
+		}},
+		paid: map[int]int64{1: 20000, 2: 1000},
+	}
+	svc := NewReservationService(repo, NewBookingService(&mockBookingRepo{}, DefaultBookingConfig))
+
+	reservation, err := svc.GetReservation(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reservation.TotalAmount != 250 || reservation.AmountPaid != 210 || reservation.BalanceDue != 40 {
+		t.Fatalf("unexpected totals: %+v", reservation)
+	}
+}
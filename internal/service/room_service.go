@@ -10,19 +10,32 @@ import (
 	"industry-api/internal/cache"
 	"industry-api/internal/models"
 	"industry-api/internal/repository"
+	"strings"
 	"time"
 )
 
+// Default and maximum page sizes for the faceted room search.
+const (
+	defaultRoomSearchLimit = 20
+	maxRoomSearchLimit     = 100
+)
+
+// availableRoomsCacheKey is the Redis key under which the available rooms list is cached.
+// It is namespaced by property scope with propertyCacheKey.
+const availableRoomsCacheKey = "available_rooms"
+
 // RoomService handles all room-related business logic operations.
 // It includes validation, caching logic, and delegates data access to the repository.
 type RoomService struct {
-	repo repository.RoomRepo // Repository interface for data access (allows mocking in tests)
+	repo  repository.RoomRepo // Repository interface for data access (allows mocking in tests)
+	media *MediaService       // Attaches photos to room responses (optional)
 }
 
 // NewRoomService creates and returns a new instance of RoomService.
-// It accepts a RoomRepository dependency for data access operations.
-func NewRoomService(repo repository.RoomRepo) *RoomService {
-	return &RoomService{repo: repo}
+// It accepts a RoomRepository dependency for data access operations and an optional
+// MediaService used to include room photos in responses.
+func NewRoomService(repo repository.RoomRepo, media *MediaService) *RoomService {
+	return &RoomService{repo: repo, media: media}
 }
 
 // AddRoom creates a new room after comprehensive validation.
@@ -31,6 +44,10 @@ func NewRoomService(repo repository.RoomRepo) *RoomService {
 func (s *RoomService) AddRoom(ctx context.Context, room *models.Room) (*models.Room, error) {
 	fmt.Printf("Service: Adding room - %+v\n", room)
 
+	// Validate the room is assigned to a property
+	if room.PropertyID <= 0 {
+		return nil, errors.New("property id is required")
+	}
 	// Validate room number is provided
 	if room.RoomNumber == "" {
 		return nil, errors.New("room number is required")
@@ -67,28 +84,34 @@ func (s *RoomService) AddRoom(ctx context.Context, room *models.Room) (*models.R
 		fmt.Printf("Service: Repository error - %v\n", err)
 		return nil, fmt.Errorf("failed to add room: %w", err)
 	}
+	// A new room changes the available rooms list of its property
+	invalidateAvailableRooms(ctx)
 
 	fmt.Printf("Service: Room added successfully - ID: %d\n", room.ID)
 	return room, nil
 }
 
-// GetRoomsList retrieves all rooms in the system without filtering or caching.
-// Returns a list of all rooms or an error if the database operation fails.
-func (s *RoomService) GetRoomsList(ctx context.Context) ([]*models.Room, error) {
+// GetRoomsList retrieves all rooms of the given properties (nil means every property) without caching.
+// Returns a list of rooms or an error if the database operation fails.
+func (s *RoomService) GetRoomsList(ctx context.Context, propertyIDs []int) ([]*models.Room, error) {
 	// Delegate to repository to fetch all rooms
-	rooms, err := s.repo.GetRoomsList(ctx)
+	rooms, err := s.repo.GetRoomsList(ctx, propertyIDs)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.attachMedia(ctx, rooms); err != nil {
+		return nil, err
+	}
 	return rooms, nil
 }
 
-// GetAvailableRooms retrieves all available rooms with Redis caching.
+// GetAvailableRooms retrieves the available rooms of the given properties with Redis caching.
 // It first tries to fetch from cache, and if not found (cache miss),
 // fetches from the database and caches the result for 10 minutes.
-func (s *RoomService) GetAvailableRooms(ctx context.Context) ([]*models.Room, error) {
-	// Cache key for storing available rooms
-	cacheKey := "available_rooms"
+// Each property scope is cached under its own key.
+func (s *RoomService) GetAvailableRooms(ctx context.Context, propertyIDs []int) ([]*models.Room, error) {
+	// Cache key for storing available rooms of this property scope
+	cacheKey := propertyCacheKey(propertyIDs, availableRoomsCacheKey)
 
 	// Check if Redis client is initialized
 	if cache.Client == nil {
@@ -110,10 +133,14 @@ func (s *RoomService) GetAvailableRooms(ctx context.Context) ([]*models.Room, er
 	fmt.Println("\u274c Cache miss - fetching available rooms from database")
 
 	// Fetch available rooms from database
-	rooms, err := s.repo.GetAvailableRooms(ctx)
+	rooms, err := s.repo.GetAvailableRooms(ctx, propertyIDs)
 	if err != nil {
 		return nil, err
 	}
+	// Include photos before caching so cached responses carry them too
+	if err := s.attachMedia(ctx, rooms); err != nil {
+		return nil, err
+	}
 
 	// Cache the result for future requests (10-minute TTL)
 	roomsJSON, err := json.Marshal(rooms)
@@ -128,3 +155,71 @@ func (s *RoomService) GetAvailableRooms(ctx context.Context) ([]*models.Room, er
 
 	return rooms, nil
 }
+
+// SearchRooms runs the faceted room search after validating and normalizing the filter.
+// Pagination defaults to page 1 with 20 rooms per page; unknown sort fields are rejected.
+// Returns the matching page of rooms with facet counts or an error if validation fails.
+func (s *RoomService) SearchRooms(ctx context.Context, filter models.RoomSearchFilter) (*models.RoomSearchResponse, error) {
+	// Validate the price range
+	if filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+		return nil, validationError("min_price must not exceed max_price")
+	}
+	// Validate the sort field and direction
+	if filter.Sort != "" {
+		if _, ok := roomSortFields[filter.Sort]; !ok {
+			return nil, validationError("unsupported sort field %q", filter.Sort)
+		}
+	}
+	if filter.Order != "" && !strings.EqualFold(filter.Order, "asc") && !strings.EqualFold(filter.Order, "desc") {
+		return nil, validationError("order must be asc or desc")
+	}
+
+	// Apply pagination defaults
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultRoomSearchLimit
+	}
+	if filter.Limit > maxRoomSearchLimit {
+		filter.Limit = maxRoomSearchLimit
+	}
+
+	result, err := s.repo.SearchRooms(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	rooms := make([]*models.Room, len(result.Rooms))
+	for i := range result.Rooms {
+		rooms[i] = &result.Rooms[i]
+	}
+	if err := s.attachMedia(ctx, rooms); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// attachMedia includes ordered photos in the given rooms when a MediaService is configured.
+func (s *RoomService) attachMedia(ctx context.Context, rooms []*models.Room) error {
+	if s.media == nil {
+		return nil
+	}
+	return s.media.AttachToRooms(ctx, rooms)
+}
+
+// invalidateAvailableRooms removes the cached available rooms lists of every property scope
+// so room and photo changes show up immediately.
+func invalidateAvailableRooms(ctx context.Context) {
+	if err := cache.DeletePattern(ctx, propertyCachePattern(availableRoomsCacheKey)); err != nil {
+		fmt.Printf("⚠️ Failed to invalidate available rooms cache: %v\n", err)
+	}
+}
+
+// roomSortFields lists the sort fields accepted by SearchRooms.
+var roomSortFields = map[string]struct{}{
+	"price":       {},
+	"capacity":    {},
+	"floor":       {},
+	"room_number": {},
+	"created_at":  {},
+}
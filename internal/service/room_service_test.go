@@ -11,14 +11,20 @@ import (
 // mockRoomRepo implements only what RoomService needs
 type mockRoomRepo struct {
 	add       func(ctx context.Context, room *models.Room) error
-	list      func(ctx context.Context) ([]*models.Room, error)
-	available func(ctx context.Context) ([]*models.Room, error)
+	list      func(ctx context.Context, propertyIDs []int) ([]*models.Room, error)
+	available func(ctx context.Context, propertyIDs []int) ([]*models.Room, error)
+	search    func(ctx context.Context, filter models.RoomSearchFilter) (*models.RoomSearchResponse, error)
 }
 
-func (m *mockRoomRepo) AddRoom(ctx context.Context, room *models.Room) error     { return m.add(ctx, room) }
-func (m *mockRoomRepo) GetRoomsList(ctx context.Context) ([]*models.Room, error) { return m.list(ctx) }
-func (m *mockRoomRepo) GetAvailableRooms(ctx context.Context) ([]*models.Room, error) {
-	return m.available(ctx)
+func (m *mockRoomRepo) AddRoom(ctx context.Context, room *models.Room) error { return m.add(ctx, room) }
+func (m *mockRoomRepo) GetRoomsList(ctx context.Context, propertyIDs []int) ([]*models.Room, error) {
+	return m.list(ctx, propertyIDs)
+}
+func (m *mockRoomRepo) GetAvailableRooms(ctx context.Context, propertyIDs []int) ([]*models.Room, error) {
+	return m.available(ctx, propertyIDs)
+}
+func (m *mockRoomRepo) SearchRooms(ctx context.Context, filter models.RoomSearchFilter) (*models.RoomSearchResponse, error) {
+	return m.search(ctx, filter)
 }
 
 func TestAddRoom_Validation(t *testing.T) {
@@ -28,7 +34,8 @@ func TestAddRoom_Validation(t *testing.T) {
 		name string
 		room *models.Room
 	}{
-		{"missing number", &models.Room{RoomNumber: "", RoomType: "Deluxe", Description: "d", Price: 100, Capacity: 2, Floor: 1, Amenities: []string{"a"}}},
+		{"missing property", &models.Room{RoomNumber: "101", RoomType: "Deluxe", Description: "d", Price: 100, Capacity: 2, Floor: 1, Amenities: []string{"a"}}},
+		{"missing number", &models.Room{PropertyID: 1, RoomNumber: "", RoomType: "Deluxe", Description: "d", Price: 100, Capacity: 2, Floor: 1, Amenities: []string{"a"}}},
 		{"missing type", &models.Room{RoomNumber: "101", RoomType: "", Description: "d", Price: 100, Capacity: 2, Floor: 1, Amenities: []string{"a"}}},
 		{"bad price", &models.Room{RoomNumber: "101", RoomType: "A", Description: "d", Price: 0, Capacity: 2, Floor: 1, Amenities: []string{"a"}}},
 		{"no amenities", &models.Room{RoomNumber: "101", RoomType: "A", Description: "d", Price: 100, Capacity: 2, Floor: 1, Amenities: []string{}}},
@@ -47,11 +54,15 @@ func TestAddRoom_Success(t *testing.T) {
 			room.ID = 777
 			return nil
 		},
-		list:      func(ctx context.Context) ([]*models.Room, error) { return nil, errors.New("not-implemented") },
-		available: func(ctx context.Context) ([]*models.Room, error) { return nil, errors.New("not-implemented") },
+		list: func(ctx context.Context, propertyIDs []int) ([]*models.Room, error) {
+			return nil, errors.New("not-implemented")
+		},
+		available: func(ctx context.Context, propertyIDs []int) ([]*models.Room, error) {
+			return nil, errors.New("not-implemented")
+		},
 	}
 	svc := &RoomService{repo: repo}
-	room := &models.Room{RoomNumber: "R1", RoomType: "T", Description: "desc", Price: 50, Capacity: 2, Floor: 1, Amenities: []string{"wifi"}}
+	room := &models.Room{PropertyID: 1, RoomNumber: "R1", RoomType: "T", Description: "desc", Price: 50, Capacity: 2, Floor: 1, Amenities: []string{"wifi"}}
 	created, err := svc.AddRoom(context.Background(), room)
 	if err != nil {
 		t.Fatalf("AddRoom returned error: %v", err)
@@ -63,9 +74,9 @@ func TestAddRoom_Success(t *testing.T) {
 
 func TestGetRoomsList_DelegatesToRepo(t *testing.T) {
 	sample := []*models.Room{{ID: 1, RoomNumber: "1"}}
-	repo := &mockRoomRepo{list: func(ctx context.Context) ([]*models.Room, error) { return sample, nil }}
+	repo := &mockRoomRepo{list: func(ctx context.Context, propertyIDs []int) ([]*models.Room, error) { return sample, nil }}
 	svc := &RoomService{repo: repo}
-	got, err := svc.GetRoomsList(context.Background())
+	got, err := svc.GetRoomsList(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("GetRoomsList error: %v", err)
 	}
@@ -73,3 +84,27 @@ func TestGetRoomsList_DelegatesToRepo(t *testing.T) {
 		t.Fatalf("unexpected result from GetRoomsList")
 	}
 }
+
+func TestSearchRooms_ValidationAndDefaults(t *testing.T) {
+	var got models.RoomSearchFilter
+	repo := &mockRoomRepo{search: func(ctx context.Context, filter models.RoomSearchFilter) (*models.RoomSearchResponse, error) {
+		got = filter
+		return &models.RoomSearchResponse{Page: filter.Page, Limit: filter.Limit}, nil
+	}}
+	svc := &RoomService{repo: repo}
+
+	min, max := 200.0, 100.0
+	if _, err := svc.SearchRooms(context.Background(), models.RoomSearchFilter{MinPrice: &min, MaxPrice: &max}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error for reversed price range, got %v", err)
+	}
+	if _, err := svc.SearchRooms(context.Background(), models.RoomSearchFilter{Sort: "password"}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error for unknown sort, got %v", err)
+	}
+
+	if _, err := svc.SearchRooms(context.Background(), models.RoomSearchFilter{Limit: 5000}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Page != 1 || got.Limit != maxRoomSearchLimit {
+		t.Fatalf("expected page 1 and capped limit, got page=%d limit=%d", got.Page, got.Limit)
+	}
+}
@@ -0,0 +1,160 @@
+// Package service provides business logic layer implementations.
+// Services contain validation logic and business rules before delegating to repositories.
+package service
+
+import (
+	"context"
+	"fmt"
+	"industry-api/internal/models"
+	"industry-api/internal/repository"
+	"strings"
+	"time"
+)
+
+// TaxService handles the configuration of taxes and fees and the tax report. Taxes are charged
+// through BookingService, which applies the rates in force when a booking is priced.
+type TaxService struct {
+	repo repository.TaxRepo // Repository interface for data access (allows mocking in tests)
+}
+
+// NewTaxService creates and returns a new instance of TaxService.
+// It accepts a TaxRepo interface for data access operations.
+func NewTaxService(repo repository.TaxRepo) *TaxService {
+	return &TaxService{repo: repo}
+}
+
+// CreateTaxRate validates and stores a tax rate. The code is lower-cased and fixed amounts are
+// charged per night unless another period is given.
+// Returns the created rate or a validation error.
+func (s *TaxService) CreateTaxRate(ctx context.Context, rate *models.TaxRate) (*models.TaxRate, error) {
+	rate.Code = strings.ToLower(strings.TrimSpace(rate.Code))
+	rate.Name = strings.TrimSpace(rate.Name)
+	if rate.Code == "" || rate.Name == "" {
+		return nil, validationError("code and name are required")
+	}
+	switch rate.Basis {
+	case models.TaxPercentage:
+		if rate.Rate <= 0 || rate.Rate > 100 {
+			return nil, validationError("a percentage rate must be between 0 and 100")
+		}
+	case models.TaxPerPerson, models.TaxPerRoom:
+		if rate.Rate <= 0 {
+			return nil, validationError("a fixed rate must be positive")
+		}
+	default:
+		return nil, validationError("unknown tax basis %q", rate.Basis)
+	}
+	if rate.Period == "" {
+		rate.Period = models.TaxPerNight
+	}
+	if rate.Period != models.TaxPerNight && rate.Period != models.TaxPerStay {
+		return nil, validationError("unknown tax period %q", rate.Period)
+	}
+	if rate.ValidFrom != nil && rate.ValidTo != nil && rate.ValidTo.Before(*rate.ValidFrom) {
+		return nil, validationError("validity ends before it starts")
+	}
+	if err := s.repo.CreateTaxRate(ctx, rate); err != nil {
+		return nil, err
+	}
+	return rate, nil
+}
+
+// ListTaxRates returns the tax rates of the given properties (nil for all), including the rates
+// that apply to every property.
+func (s *TaxService) ListTaxRates(ctx context.Context, propertyIDs []int) ([]models.TaxRate, error) {
+	return s.repo.ListTaxRates(ctx, propertyIDs)
+}
+
+// DeleteTaxRate removes a tax rate. Bookings priced with it keep their tax lines.
+func (s *TaxService) DeleteTaxRate(ctx context.Context, id int) error {
+	if id <= 0 {
+		return validationError("tax rate id is required")
+	}
+	return s.repo.DeleteTaxRate(ctx, id)
+}
+
+// TaxReport returns the tax charged per property and code on the stays arriving between from
+// and to (inclusive) that were not cancelled or marked as a no-show.
+func (s *TaxService) TaxReport(ctx context.Context, from, to time.Time, propertyIDs []int) ([]models.TaxReportLine, error) {
+	if to.Before(from) {
+		return nil, validationError("the report period ends before it starts")
+	}
+	return s.repo.TaxReport(ctx, from, to, propertyIDs)
+}
+
+// applyTaxRates adds a tax line per applicable rate to a price breakdown. Percentages apply to the
+// charges after discounts for the nights within the rate's validity; inclusive percentages are the
+// share of the charges that is tax. Fixed amounts are charged per person or room, for every night
+// within the validity or once per stay if the arrival date is within it. Exclusive taxes are
+// added to the total, and to the first night's price unless they are charged per stay.
+func applyTaxRates(breakdown *models.PriceBreakdown, rates []models.TaxRate, checkIn, checkOut time.Time, adults, children int) {
+	charges, firstNight := breakdown.Total, breakdown.FirstNight
+	for _, rate := range rates {
+		nights := nightsWithin(rate, checkIn, checkOut)
+		if nights == 0 || (rate.Period == models.TaxPerStay && !withinValidity(rate, dateOnly(checkIn))) {
+			continue
+		}
+
+		line := models.TaxLine{TaxRateID: rate.ID, Code: rate.Code, Rate: rate.Rate, Inclusive: rate.Inclusive}
+		firstNightTax := 0.0
+		switch rate.Basis {
+		case models.TaxPercentage:
+			base := charges * float64(nights) / float64(breakdown.Nights)
+			line.Quantity = 1
+			line.Description = fmt.Sprintf("%s %g%%", rate.Name, rate.Rate)
+			if rate.Inclusive {
+				line.Amount = roundMoney(base * rate.Rate / (100 + rate.Rate))
+			} else {
+				line.Amount = roundMoney(base * rate.Rate / 100)
+				firstNightTax = roundMoney(firstNight * rate.Rate / 100)
+			}
+		default:
+			units, unit := 1, "room"
+			if rate.Basis == models.TaxPerPerson {
+				units, unit = adults, "person"
+				if !rate.ChildrenExempt {
+					units += children
+				}
+			}
+			line.Quantity = units
+			if rate.Period == models.TaxPerNight {
+				line.Quantity *= nights
+				unit += " per night"
+			}
+			line.Description = fmt.Sprintf("%s per %s", rate.Name, unit)
+			line.Amount = roundMoney(float64(line.Quantity) * rate.Rate)
+			if rate.Period == models.TaxPerNight {
+				firstNightTax = roundMoney(float64(units) * rate.Rate)
+			}
+		}
+		if line.Amount <= 0 {
+			continue
+		}
+
+		breakdown.Taxes = append(breakdown.Taxes, line)
+		breakdown.TaxTotal = roundMoney(breakdown.TaxTotal + line.Amount)
+		if !rate.Inclusive {
+			breakdown.Total = roundMoney(breakdown.Total + line.Amount)
+			if withinValidity(rate, dateOnly(checkIn)) {
+				breakdown.FirstNight = roundMoney(breakdown.FirstNight + firstNightTax)
+			}
+		}
+	}
+}
+
+// nightsWithin returns the number of nights of the stay inside a rate's validity dates.
+func nightsWithin(rate models.TaxRate, checkIn, checkOut time.Time) int {
+	nights := 0
+	for night := dateOnly(checkIn); night.Before(dateOnly(checkOut)); night = night.AddDate(0, 0, 1) {
+		if withinValidity(rate, night) {
+			nights++
+		}
+	}
+	return nights
+}
+
+// withinValidity reports whether a calendar date falls inside a rate's validity dates.
+func withinValidity(rate models.TaxRate, date time.Time) bool {
+	return (rate.ValidFrom == nil || !date.Before(dateOnly(*rate.ValidFrom))) &&
+		(rate.ValidTo == nil || !date.After(dateOnly(*rate.ValidTo)))
+}
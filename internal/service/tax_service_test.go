@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"industry-api/internal/models"
+)
+
+type mockTaxRepo struct {
+	created *models.TaxRate
+}
+
+func (m *mockTaxRepo) CreateTaxRate(ctx context.Context, rate *models.TaxRate) error {
+	rate.ID = 1
+	m.created = rate
+	return nil
+}
+func (m *mockTaxRepo) ListTaxRates(ctx context.Context, propertyIDs []int) ([]models.TaxRate, error) {
+	return nil, nil
+}
+func (m *mockTaxRepo) DeleteTaxRate(ctx context.Context, id int) error {
+	return nil
+}
+func (m *mockTaxRepo) TaxReport(ctx context.Context, from, to time.Time, propertyIDs []int) ([]models.TaxReportLine, error) {
+	return nil, nil
+}
+
+func TestCreateTaxRate_Validation(t *testing.T) {
+	svc := NewTaxService(&mockTaxRepo{})
+
+	rate, err := svc.CreateTaxRate(context.Background(), &models.TaxRate{Code: " City_Tax ", Name: "City tax", Basis: models.TaxPerPerson, Rate: 2})
+	if err != nil || rate.Code != "city_tax" || rate.Period != models.TaxPerNight {
+		t.Fatalf("unexpected tax rate: %+v err=%v", rate, err)
+	}
+	if _, err := svc.CreateTaxRate(context.Background(), &models.TaxRate{Code: "vat", Name: "VAT", Basis: models.TaxPercentage, Rate: 120}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error for a percentage above 100, got %v", err)
+	}
+	from := time.Now()
+	to := from.AddDate(0, 0, -1)
+	if _, err := svc.CreateTaxRate(context.Background(), &models.TaxRate{Code: "fee", Name: "Fee", Basis: models.TaxPerRoom, Rate: 5, ValidFrom: &from, ValidTo: &to}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error for inverted validity dates, got %v", err)
+	}
+	if _, err := svc.TaxReport(context.Background(), from, to, nil); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error for an inverted report period, got %v", err)
+	}
+}
+
+func TestAddBooking_AppliesTaxes(t *testing.T) {
+	checkIn := time.Date(2030, 6, 10, 0, 0, 0, 0, time.UTC)
+	firstNight, secondNight := checkIn, checkIn.AddDate(0, 0, 1)
+	repo := &mockBookingRepo{
+		add: func(ctx context.Context, b *models.Booking) (*models.Booking, error) { b.ID = 1; return b, nil },
+		room: func(ctx context.Context, roomID int) (*models.Room, error) {
+			return &models.Room{ID: roomID, RoomNumber: "101", RoomType: "Deluxe", Price: 100, Capacity: 4, HousekeepingStatus: models.HousekeepingInspected}, nil
+		},
+		taxRates: []models.TaxRate{
+			{ID: 1, Code: "vat", Name: "VAT", Basis: models.TaxPercentage, Period: models.TaxPerNight, Rate: 10},
+			{ID: 2, Code: "city_tax", Name: "City tax", Basis: models.TaxPerPerson, Period: models.TaxPerNight, Rate: 2, ChildrenExempt: true},
+			{ID: 3, Code: "service", Name: "Service", Basis: models.TaxPercentage, Period: models.TaxPerNight, Rate: 10, Inclusive: true, ValidTo: &firstNight},
+			{ID: 4, Code: "cleaning", Name: "Cleaning fee", Basis: models.TaxPerRoom, Period: models.TaxPerStay, Rate: 15, ValidFrom: &secondNight},
+		},
+	}
+	svc := NewBookingService(repo, DefaultBookingConfig)
+
+	// 2 nights at 100 plus a child at 10 a night is 220: 22 VAT, 2 adults x 2 nights x 2 city tax,
+	// 10 service included in the first night's 110, and no cleaning fee since arrival is before it applies
+	created, err := svc.AddBooking(context.Background(), &models.Booking{UserID: 5, RoomID: 1, CheckInDate: checkIn, CheckOutDate: checkIn.AddDate(0, 0, 2), Adults: 2, Children: 1, TotalAmount: 250})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	breakdown := created.PriceBreakdown
+	if created.TotalAmount != 250 || breakdown.TaxTotal != 40 || len(breakdown.Taxes) != 3 {
+		t.Fatalf("unexpected taxed booking: total=%v breakdown=%+v", created.TotalAmount, breakdown)
+	}
+	if breakdown.Taxes[1].Quantity != 4 || breakdown.Taxes[1].Amount != 8 || !breakdown.Taxes[2].Inclusive || breakdown.Taxes[2].Amount != 10 {
+		t.Fatalf("unexpected tax lines: %+v", breakdown.Taxes)
+	}
+
+	// Taxes apply to the charges after discounts
+	repo.promos = []models.PromoCode{{ID: 1, Code: "FIFTY", DiscountType: models.DiscountFixed, DiscountValue: 20, MinNights: 1, IsActive: true}}
+	repo.taxRates = repo.taxRates[:1]
+	created, err = svc.AddBooking(context.Background(), &models.Booking{UserID: 5, RoomID: 1, CheckInDate: checkIn, CheckOutDate: checkIn.AddDate(0, 0, 2), Adults: 2, Children: 1, PromoCodes: []string{"FIFTY"}})
+	if err != nil || created.TotalAmount != 220 || created.PriceBreakdown.TaxTotal != 20 {
+		t.Fatalf("expected 10%% tax on the discounted 200, got total=%v breakdown=%+v err=%v", created.TotalAmount, created.PriceBreakdown, err)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"industry-api/internal/cache"
 	"industry-api/internal/models"
 	"industry-api/internal/repository"
+	"slices"
 	"strings"
 	"time"
 
@@ -62,25 +63,30 @@ func (s *UserService) CreateUser(ctx context.Context, user *models.User) (*model
 }
 
 // GetUserList retrieves a paginated list of users with optional filtering.
+// If propertyIDs is not nil, only users assigned to one of those properties are listed.
 // It delegates to the repository for database query execution.
-func (s *UserService) GetUserList(ctx context.Context, role string, isActive *bool, search string, page, limit int) (*models.UserListResponse, error) {
-	return s.repo.GetUserList(ctx, role, isActive, search, page, limit)
+func (s *UserService) GetUserList(ctx context.Context, role string, isActive *bool, search string, propertyIDs []int, page, limit int) (*models.UserListResponse, error) {
+	return s.repo.GetUserList(ctx, role, isActive, search, propertyIDs, page, limit)
 }
 
 // GetUserByID retrieves a user by ID with Redis caching.
 // It first tries to fetch from cache, and if not found (cache miss),
 // fetches from the database, caches the result, and returns it.
-func (s *UserService) GetUserByID(ctx context.Context, id int) (*models.User, error) {
-	// Create a unique cache key for this user
-	cacheKey := fmt.Sprintf("user:%d", id)
+// If propertyIDs is not nil, users not assigned to any of those properties are reported as not found;
+// the cache key is namespaced by the property scope so scopes never share entries.
+func (s *UserService) GetUserByID(ctx context.Context, id int, propertyIDs []int) (*models.User, error) {
+	// Create a unique cache key for this user and property scope
+	cacheKey := propertyCacheKey(propertyIDs, fmt.Sprintf("user:%d", id))
 	// Try to get user from cache
-	cachedUser, err := cache.Client.Get(ctx, cacheKey).Result()
-	if err == nil {
-		var user models.User
-		// Deserialize cached JSON into user model
-		if err := json.Unmarshal([]byte(cachedUser), &user); err == nil {
-			fmt.Println("cache hit- user details")
-			return &user, nil
+	if cache.Client != nil {
+		cachedUser, err := cache.Client.Get(ctx, cacheKey).Result()
+		if err == nil {
+			var user models.User
+			// Deserialize cached JSON into user model
+			if err := json.Unmarshal([]byte(cachedUser), &user); err == nil {
+				fmt.Println("cache hit- user details")
+				return &user, nil
+			}
 		}
 	}
 
@@ -90,6 +96,13 @@ func (s *UserService) GetUserByID(ctx context.Context, id int) (*models.User, er
 	if err != nil {
 		return nil, err
 	}
+	// Hide users outside the caller's properties
+	if propertyIDs != nil && !sharesProperty(user.PropertyIDs, propertyIDs) {
+		return nil, errors.New("user not found")
+	}
+	if cache.Client == nil {
+		return user, nil
+	}
 	// Create a copy of the user for caching (without password)
 	userToCache := *user
 	userToCache.Password = "" // Remove password before caching
@@ -106,11 +119,11 @@ func (s *UserService) GetUserByID(ctx context.Context, id int) (*models.User, er
 }
 
 // UpdateUserStatus updates a user's active status.
-// It validates the user exists before updating and invalidates the cache.
+// It validates the user exists within the property scope before updating and invalidates the cache.
 // Returns the updated user or an error if the user is not found.
-func (s *UserService) UpdateUserStatus(ctx context.Context, id int, isActive bool) (*models.User, error) {
+func (s *UserService) UpdateUserStatus(ctx context.Context, id int, isActive bool, propertyIDs []int) (*models.User, error) {
 	// Verify user exists before attempting update
-	_, err := s.GetUserByID(ctx, id)
+	_, err := s.GetUserByID(ctx, id, propertyIDs)
 	if err != nil {
 		return nil, err // This will already be "user not found" if applicable
 	}
@@ -128,11 +141,25 @@ func (s *UserService) UpdateUserStatus(ctx context.Context, id int, isActive boo
 // invalidateUserCache removes cached data for a specific user and user lists.
 // This is called whenever user data is modified to ensure fresh data on next fetch.
 func (s *UserService) invalidateUserCache(ctx context.Context, userID int) {
-	// Invalidate the specific user's cache
-	cacheKey := fmt.Sprintf("user:%d", userID)
-	cache.Client.Del(ctx, cacheKey)
+	if cache.Client == nil {
+		return
+	}
+	// Invalidate the specific user's cache under every property scope
+	if err := cache.DeletePattern(ctx, propertyCachePattern(fmt.Sprintf("user:%d", userID))); err != nil {
+		fmt.Printf("failed to invalidate user cache: %v\n", err)
+	}
 
 	// Also invalidate user lists if you have them cached
 	userListCacheKey := "users:list"
 	cache.Client.Del(ctx, userListCacheKey)
 }
+
+// sharesProperty reports whether the two property ID lists have at least one ID in common.
+func sharesProperty(a, b []int) bool {
+	for _, id := range a {
+		if slices.Contains(b, id) {
+			return true
+		}
+	}
+	return false
+}
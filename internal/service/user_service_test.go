@@ -29,7 +29,7 @@ func (m *mockUserRepo) GetUserByID(ctx context.Context, id int) (*models.User, e
 func (m *mockUserRepo) UpdateUserStatus(ctx context.Context, id int, isActive bool) (*models.User, error) {
 	return nil, errors.New("not-implemented")
 }
-func (m *mockUserRepo) GetUserList(ctx context.Context, role string, isActive *bool, search string, page, limit int) (*models.UserListResponse, error) {
+func (m *mockUserRepo) GetUserList(ctx context.Context, role string, isActive *bool, search string, propertyIDs []int, page, limit int) (*models.UserListResponse, error) {
 	return nil, errors.New("not-implemented")
 }
 
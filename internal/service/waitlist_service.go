@@ -0,0 +1,235 @@
+// Package service provides business logic layer implementations.
+// Services contain validation logic and business rules before delegating to repositories.
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"industry-api/internal/models"
+	"industry-api/internal/notify"
+	"industry-api/internal/repository"
+	"log"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// offerWaitlistBatchSize is the number of waiting entries matched per transaction.
+const offerWaitlistBatchSize = 100
+
+// WaitlistConfig holds the configurable rules of the waitlist.
+type WaitlistConfig struct {
+	OfferTTL time.Duration // How long a guest has to claim an offered room
+	Secret   []byte        // Key signing the claim links
+	ClaimURL string        // Link target; the signed token is appended as the token query parameter
+}
+
+// WaitlistConfigFromEnv builds the waitlist configuration from WAITLIST_OFFER_TTL (a Go duration,
+// 2 hours by default), WAITLIST_SECRET (JWT_SECRET when unset) and WAITLIST_CLAIM_URL (the claim
+// endpoint by default). Returns an error if a variable is set to an invalid value.
+func WaitlistConfigFromEnv() (WaitlistConfig, error) {
+	config := WaitlistConfig{OfferTTL: 2 * time.Hour, Secret: []byte(os.Getenv("WAITLIST_SECRET")), ClaimURL: os.Getenv("WAITLIST_CLAIM_URL")}
+	if len(config.Secret) == 0 {
+		config.Secret = []byte(os.Getenv("JWT_SECRET"))
+	}
+	if config.ClaimURL == "" {
+		config.ClaimURL = "/api/v1/waitlist/claim"
+	}
+	if v := os.Getenv("WAITLIST_OFFER_TTL"); v != "" {
+		ttl, err := time.ParseDuration(v)
+		if err != nil || ttl <= 0 {
+			return config, fmt.Errorf("invalid WAITLIST_OFFER_TTL %q: must be a positive duration", v)
+		}
+		config.OfferTTL = ttl
+	}
+	return config, nil
+}
+
+// WaitlistService handles the waitlist for sold-out dates: guests register interest in a room
+// type, freed rooms are offered to them in the order they joined, and a signed link turns an
+// offer into a booking.
+type WaitlistService struct {
+	repo     repository.WaitlistRepo // Repository interface for data access (allows mocking in tests)
+	bookings *BookingService         // Prepares and prices bookings created from offers
+	notifier notify.Notifier         // Tells guests about their offers
+	config   WaitlistConfig          // Offer duration and link signing
+}
+
+// NewWaitlistService creates and returns a new instance of WaitlistService.
+// It accepts a WaitlistRepo interface for data access operations, the BookingService whose
+// rules apply to claimed offers, the notifier used to deliver offers and the configuration.
+func NewWaitlistService(repo repository.WaitlistRepo, bookings *BookingService, notifier notify.Notifier, config WaitlistConfig) *WaitlistService {
+	return &WaitlistService{repo: repo, bookings: bookings, notifier: notifier, config: config}
+}
+
+// Join puts a guest on the waitlist for a room type and date range.
+// Returns the waiting entry or a validation error.
+func (s *WaitlistService) Join(ctx context.Context, entry *models.WaitlistEntry) (*models.WaitlistEntry, error) {
+	entry.RoomType = strings.TrimSpace(entry.RoomType)
+	if entry.UserID <= 0 || entry.PropertyID <= 0 || entry.RoomType == "" {
+		return nil, validationError("user, property and room type are required")
+	}
+	if !dateOnly(entry.CheckOutDate).After(dateOnly(entry.CheckInDate)) {
+		return nil, validationError("check out date must be after check in date")
+	}
+	if dateOnly(entry.CheckInDate).Before(dateOnly(s.bookings.clock())) {
+		return nil, validationError("check in date is in the past")
+	}
+	if entry.Adults < 1 {
+		return nil, validationError("at least one adult is required")
+	}
+	if entry.Children < 0 {
+		return nil, validationError("children cannot be negative")
+	}
+	if err := s.repo.AddWaitlistEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// GetEntry returns a waitlist entry by its ID.
+func (s *WaitlistService) GetEntry(ctx context.Context, id int) (*models.WaitlistEntry, error) {
+	if id <= 0 {
+		return nil, validationError("waitlist entry id is required")
+	}
+	return s.repo.GetWaitlistEntry(ctx, id)
+}
+
+// ListEntries returns a guest's waitlist entries, newest first.
+func (s *WaitlistService) ListEntries(ctx context.Context, userID int) ([]models.WaitlistEntry, error) {
+	return s.repo.ListWaitlistEntries(ctx, userID)
+}
+
+// Leave takes a waiting or offered entry off the waitlist.
+func (s *WaitlistService) Leave(ctx context.Context, id int) (*models.WaitlistEntry, error) {
+	if id <= 0 {
+		return nil, validationError("waitlist entry id is required")
+	}
+	return s.repo.CancelWaitlistEntry(ctx, id)
+}
+
+// ProcessWaitlist expires lapsed offers and then offers rooms freed since the last pass, for
+// example by cancellations, expired holds or shortened maintenance windows, to waiting entries
+// in the order they joined. It runs periodically as a background job, so every source of freed
+// inventory is picked up without each one having to notify the waitlist.
+// Returns the number of offers expired and made.
+func (s *WaitlistService) ProcessWaitlist(ctx context.Context) (int, error) {
+	if len(s.config.Secret) == 0 {
+		return 0, errors.New("waitlist offers cannot be signed: set WAITLIST_SECRET or JWT_SECRET")
+	}
+	now := s.bookings.clock()
+	processed, err := s.repo.ExpireWaitlistOffers(ctx, now)
+	if err != nil {
+		return processed, err
+	}
+
+	for {
+		offers, err := s.repo.OfferWaitlistRooms(ctx, now.Add(s.config.OfferTTL), offerWaitlistBatchSize)
+		if err != nil {
+			return processed, err
+		}
+		processed += len(offers)
+		for _, offer := range offers {
+			// The offer stands even if the message is lost; it lapses like an unclaimed one
+			if err := s.notifier.Notify(ctx, s.offerMessage(offer)); err != nil {
+				log.Printf("failed to notify user %d of waitlist offer %d: %v", offer.UserID, offer.ID, err)
+			}
+		}
+		// Entries without a free room stay waiting, so one batch per pass keeps the order fair
+		if len(offers) == 0 || len(offers) < offerWaitlistBatchSize {
+			return processed, nil
+		}
+	}
+}
+
+// Claim turns the offer identified by a signed token into a booking for the waiting guest.
+// Returns the booking, a validation error for a forged or malformed token, a conflict error
+// once the offer has lapsed, or a *BookingConflictError if the room was taken in the meantime,
+// in which case the guest keeps their place in line.
+func (s *WaitlistService) Claim(ctx context.Context, token string) (*models.Booking, error) {
+	id, expiresAt, err := s.verifyOfferToken(token)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := s.repo.GetWaitlistEntry(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// A token only claims the offer it was issued for
+	if entry.Status != models.WaitlistOffered || entry.OfferedRoomID == nil || entry.OfferExpiresAt == nil || entry.OfferExpiresAt.Unix() != expiresAt {
+		return nil, conflictError("waitlist offer is no longer open")
+	}
+	now := s.bookings.clock()
+	if now.After(*entry.OfferExpiresAt) {
+		return nil, conflictError("waitlist offer expired at %s", entry.OfferExpiresAt.Format(time.RFC3339))
+	}
+
+	booking := &models.Booking{
+		UserID:       entry.UserID,
+		RoomID:       *entry.OfferedRoomID,
+		CheckInDate:  entry.CheckInDate,
+		CheckOutDate: entry.CheckOutDate,
+		Adults:       entry.Adults,
+		Children:     entry.Children,
+	}
+	if err := s.bookings.prepareBooking(ctx, booking); err != nil {
+		return nil, err
+	}
+	if err := s.repo.ClaimWaitlistOffer(ctx, entry.ID, booking, now); err != nil {
+		return nil, err
+	}
+	return booking, nil
+}
+
+// offerMessage builds the notification carrying an offer's claim link.
+func (s *WaitlistService) offerMessage(offer models.WaitlistEntry) notify.Message {
+	link := s.config.ClaimURL
+	if strings.Contains(link, "?") {
+		link += "&"
+	} else {
+		link += "?"
+	}
+	link += "token=" + url.QueryEscape(s.signOffer(offer.ID, offer.OfferExpiresAt.Unix()))
+	return notify.Message{
+		UserID:  offer.UserID,
+		Subject: "A room is available for your dates",
+		Body: fmt.Sprintf("A %s room is free from %s to %s. It is held for you until %s.",
+			offer.RoomType, offer.CheckInDate.Format("2006-01-02"), offer.CheckOutDate.Format("2006-01-02"), offer.OfferExpiresAt.Format(time.RFC3339)),
+		Link: link,
+	}
+}
+
+// signOffer returns the claim token of an offer: the entry ID and offer expiry, followed by an
+// HMAC-SHA256 of both.
+func (s *WaitlistService) signOffer(id int, expiresAt int64) string {
+	payload := strconv.Itoa(id) + "." + strconv.FormatInt(expiresAt, 10)
+	mac := hmac.New(sha256.New, s.config.Secret)
+	mac.Write([]byte(payload))
+	return payload + "." + hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifyOfferToken checks a claim token's signature and returns the entry ID and offer expiry it names.
+func (s *WaitlistService) verifyOfferToken(token string) (int, int64, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || len(s.config.Secret) == 0 {
+		return 0, 0, validationError("invalid offer token")
+	}
+	id, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, validationError("invalid offer token")
+	}
+	expiresAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, validationError("invalid offer token")
+	}
+	if !hmac.Equal([]byte(s.signOffer(id, expiresAt)), []byte(token)) {
+		return 0, 0, validationError("invalid offer token")
+	}
+	return id, expiresAt, nil
+}
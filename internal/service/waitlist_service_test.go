@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"industry-api/internal/models"
+	"industry-api/internal/notify"
+)
+
+type mockWaitlistRepo struct {
+	entry    *models.WaitlistEntry
+	offers   [][]models.WaitlistEntry // batches returned by successive OfferWaitlistRooms calls
+	made     []models.WaitlistEntry   // offers returned so far
+	expired  int
+	claimed  *models.Booking
+	claimErr error
+}
+
+func (m *mockWaitlistRepo) AddWaitlistEntry(ctx context.Context, entry *models.WaitlistEntry) error {
+	entry.ID, entry.Status = 1, models.WaitlistWaiting
+	return nil
+}
+func (m *mockWaitlistRepo) GetWaitlistEntry(ctx context.Context, id int) (*models.WaitlistEntry, error) {
+	if m.entry == nil || m.entry.ID != id {
+		return nil, ErrNotFound
+	}
+	copied := *m.entry
+	return &copied, nil
+}
+func (m *mockWaitlistRepo) ListWaitlistEntries(ctx context.Context, userID int) ([]models.WaitlistEntry, error) {
+	return nil, nil
+}
+func (m *mockWaitlistRepo) CancelWaitlistEntry(ctx context.Context, id int) (*models.WaitlistEntry, error) {
+	return m.entry, nil
+}
+func (m *mockWaitlistRepo) ExpireWaitlistOffers(ctx context.Context, now time.Time) (int, error) {
+	return m.expired, nil
+}
+func (m *mockWaitlistRepo) OfferWaitlistRooms(ctx context.Context, expiresAt time.Time, limit int) ([]models.WaitlistEntry, error) {
+	if len(m.offers) == 0 {
+		return nil, nil
+	}
+	batch := m.offers[0]
+	m.offers = m.offers[1:]
+	for i := range batch {
+		batch[i].OfferExpiresAt = &expiresAt
+	}
+	m.made = append(m.made, batch...)
+	return batch, nil
+}
+func (m *mockWaitlistRepo) ClaimWaitlistOffer(ctx context.Context, id int, booking *models.Booking, now time.Time) error {
+	if m.claimErr != nil {
+		return m.claimErr
+	}
+	booking.ID = 42
+	m.claimed = booking
+	return nil
+}
+
+type recordingNotifier struct {
+	messages []notify.Message
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, msg notify.Message) error {
+	n.messages = append(n.messages, msg)
+	return nil
+}
+
+func newTestWaitlistService(repo *mockWaitlistRepo, notifier notify.Notifier) *WaitlistService {
+	bookings := NewBookingService(&mockBookingRepo{}, DefaultBookingConfig)
+	config := WaitlistConfig{OfferTTL: time.Hour, Secret: []byte("waitlist-test-secret"), ClaimURL: "https://hotel.example/claim"}
+	return NewWaitlistService(repo, bookings, notifier, config)
+}
+
+func TestJoinWaitlist_Validation(t *testing.T) {
+	svc := newTestWaitlistService(&mockWaitlistRepo{}, notify.LogNotifier{})
+	in := time.Now().AddDate(0, 1, 0)
+
+	entry, err := svc.Join(context.Background(), &models.WaitlistEntry{UserID: 5, PropertyID: 1, RoomType: " Deluxe ", CheckInDate: in, CheckOutDate: in.AddDate(0, 0, 2), Adults: 2})
+	if err != nil || entry.Status != models.WaitlistWaiting || entry.RoomType != "Deluxe" {
+		t.Fatalf("expected a waiting entry, got %+v err=%v", entry, err)
+	}
+	_, err = svc.Join(context.Background(), &models.WaitlistEntry{UserID: 5, PropertyID: 1, RoomType: "Deluxe", CheckInDate: in, CheckOutDate: in, Adults: 2})
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error for an empty stay, got %v", err)
+	}
+	past := time.Now().AddDate(0, 0, -2)
+	_, err = svc.Join(context.Background(), &models.WaitlistEntry{UserID: 5, PropertyID: 1, RoomType: "Deluxe", CheckInDate: past, CheckOutDate: past.AddDate(0, 0, 1), Adults: 2})
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error for past dates, got %v", err)
+	}
+}
+
+func TestProcessWaitlist_NotifiesOffersWithClaimableLink(t *testing.T) {
+	in := time.Now().AddDate(0, 1, 0)
+	roomID := 7
+	repo := &mockWaitlistRepo{expired: 1, offers: [][]models.WaitlistEntry{{
+		{ID: 3, UserID: 5, PropertyID: 1, RoomType: "Deluxe", Status: models.WaitlistOffered, OfferedRoomID: &roomID, CheckInDate: in, CheckOutDate: in.AddDate(0, 0, 2), Adults: 2},
+	}}}
+	notifier := &recordingNotifier{}
+	svc := newTestWaitlistService(repo, notifier)
+
+	processed, err := svc.ProcessWaitlist(context.Background())
+	if err != nil || processed != 2 {
+		t.Fatalf("expected one expiry and one offer, got %d err=%v", processed, err)
+	}
+	if len(notifier.messages) != 1 || notifier.messages[0].UserID != 5 || !strings.HasPrefix(notifier.messages[0].Link, "https://hotel.example/claim?token=") {
+		t.Fatalf("unexpected notifications: %+v", notifier.messages)
+	}
+
+	link, _ := url.Parse(notifier.messages[0].Link)
+	token := link.Query().Get("token")
+	repo.entry = &repo.made[0]
+
+	// a tampered token is rejected before the offer is looked up
+	if _, err := svc.Claim(context.Background(), token+"0"); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error for a forged token, got %v", err)
+	}
+	booking, err := svc.Claim(context.Background(), token)
+	if err != nil {
+		t.Fatalf("unexpected claim error: %v", err)
+	}
+	if booking.ID != 42 || booking.RoomID != roomID || booking.UserID != 5 || booking.TotalAmount != 200 || booking.Status != models.BookingStatusPending {
+		t.Fatalf("unexpected booking: %+v", booking)
+	}
+}
+
+func TestClaimWaitlistOffer_LapsedOffer(t *testing.T) {
+	roomID := 7
+	in := time.Now().AddDate(0, 1, 0)
+	expired := time.Now().Add(-time.Minute).Truncate(time.Second)
+	repo := &mockWaitlistRepo{entry: &models.WaitlistEntry{ID: 3, UserID: 5, Status: models.WaitlistOffered, OfferedRoomID: &roomID, OfferExpiresAt: &expired, CheckInDate: in, CheckOutDate: in.AddDate(0, 0, 1), Adults: 1}}
+	svc := newTestWaitlistService(repo, notify.LogNotifier{})
+
+	if _, err := svc.Claim(context.Background(), svc.signOffer(3, expired.Unix())); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected conflict for a lapsed offer, got %v", err)
+	}
+	// a token of an earlier offer cannot claim the current one
+	later := time.Now().Add(time.Hour).Truncate(time.Second)
+	repo.entry.OfferExpiresAt = &later
+	if _, err := svc.Claim(context.Background(), svc.signOffer(3, expired.Unix())); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected conflict for a stale token, got %v", err)
+	}
+	if repo.claimed != nil {
+		t.Fatalf("expected no booking, got %+v", repo.claimed)
+	}
+}
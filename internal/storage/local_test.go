@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStorage_PutDeleteURL(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewLocalStorage(dir, "/media/")
+	if err != nil {
+		t.Fatalf("NewLocalStorage failed: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := s.Put(ctx, "rooms/1/photo.jpg", strings.NewReader("data"), "image/jpeg"); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, "rooms", "1", "photo.jpg"))
+	if err != nil || string(got) != "data" {
+		t.Fatalf("expected stored file, got %q err=%v", got, err)
+	}
+	if url := s.URL("rooms/1/photo.jpg"); url != "/media/rooms/1/photo.jpg" {
+		t.Fatalf("unexpected URL %q", url)
+	}
+
+	if err := s.Delete(ctx, "rooms/1/photo.jpg"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := s.Delete(ctx, "rooms/1/photo.jpg"); err != nil {
+		t.Fatalf("deleting a missing file should succeed, got %v", err)
+	}
+}
+
+func TestLocalStorage_RejectsEscapingKeys(t *testing.T) {
+	s, err := NewLocalStorage(t.TempDir(), "/media")
+	if err != nil {
+		t.Fatalf("NewLocalStorage failed: %v", err)
+	}
+	for _, key := range []string{"", "/etc/passwd", "../outside", "a/../../outside"} {
+		if err := s.Put(context.Background(), key, strings.NewReader("x"), "text/plain"); !errors.Is(err, ErrInvalidKey) {
+			t.Fatalf("key %q: expected ErrInvalidKey, got %v", key, err)
+		}
+	}
+}
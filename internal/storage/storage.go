@@ -0,0 +1,23 @@
+// Package storage provides pluggable object storage for uploaded files such as room photos.
+// Services depend on the Storage interface so the backend (local disk today, S3-compatible
+// object storage later) can be swapped without touching business logic.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrInvalidKey is returned when a storage key is empty or tries to escape the storage root.
+var ErrInvalidKey = errors.New("invalid storage key")
+
+// Storage defines the operations required to persist and serve uploaded objects.
+type Storage interface {
+	// Put stores the content under key, replacing any existing object.
+	Put(ctx context.Context, key string, content io.Reader, contentType string) error
+	// Delete removes the object stored under key. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+	// URL returns the public URL clients use to fetch the object stored under key.
+	URL(key string) string
+}
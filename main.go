@@ -3,14 +3,23 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"industry-api/db"
 	"industry-api/internal/cache"
+	"industry-api/internal/calsync"
 	"industry-api/internal/handler"
+	"industry-api/internal/jobs"
+	"industry-api/internal/middleware"
+	"industry-api/internal/models"
+	"industry-api/internal/notify"
 	"industry-api/internal/repository"
 	"industry-api/internal/service"
+	"industry-api/internal/storage"
 	"log"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -57,9 +66,43 @@ func main() {
 	userService := service.NewUserService(userRepo)
 	userHandler := handler.NewUserHandler(userService)
 
+	// ========== Property Setup ==========
+	propertyRepo := repository.NewPropertyRepository(db.DB)
+	propertyService := service.NewPropertyService(propertyRepo)
+	propertyHandler := handler.NewPropertyHandler(propertyService)
+	// Resolves which properties a request may see from the caller's role and the property_id filter
+	propertyScope := middleware.PropertyScope(propertyService)
+	// The public catalog of properties and rooms is also open to anonymous callers
+	publicScope := middleware.PublicPropertyScope(propertyService)
+	adminOnly := middleware.RequireRoles(models.RoleAdmin)
+	staffOnly := middleware.RequireRoles(models.RoleAdmin, models.RoleStaff)
+	authenticated := middleware.RequireRoles(models.RoleAdmin, models.RoleStaff, models.RoleGuest)
+
+	// ========== Room Media Setup ==========
+	// Photos are stored on the local filesystem under MEDIA_DIR and served under MEDIA_BASE_URL
+	mediaDir := os.Getenv("MEDIA_DIR")
+	if mediaDir == "" {
+		mediaDir = "uploads"
+	}
+	mediaBaseURL := os.Getenv("MEDIA_BASE_URL")
+	if mediaBaseURL == "" {
+		mediaBaseURL = "/media"
+	}
+	mediaStorage, err := storage.NewLocalStorage(mediaDir, mediaBaseURL)
+	if err != nil {
+		log.Fatalf("Failed to initialize media storage: %v", err)
+	}
+	// Serve stored files directly unless the base URL points at another host (e.g. a CDN)
+	if strings.HasPrefix(mediaBaseURL, "/") {
+		router.Static(mediaBaseURL, mediaDir)
+	}
+	mediaRepo := repository.NewMediaRepository(db.DB)
+	mediaService := service.NewMediaService(mediaRepo, mediaStorage)
+	mediaHandler := handler.NewMediaHandler(mediaService)
+
 	// ========== Room Management Setup ==========
 	roomRepo := repository.NewRoomRepository(db.DB)
-	roomService := service.NewRoomService(roomRepo)
+	roomService := service.NewRoomService(roomRepo, mediaService)
 	roomHandler := handler.NewRoomHandler(roomService)
 
 	// ========== Room Maintenance Setup ==========
@@ -69,44 +112,259 @@ func main() {
 
 	// ========== Booking Management Setup ==========
 	bookingRepo := repository.NewBookingRepository(db.DB)
-	bookingService := service.NewBookingService(bookingRepo)
+	// Cancellation penalties and hold durations come from CANCELLATION_* and BOOKING_HOLD_TTL
+	bookingConfig, err := service.BookingConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid booking configuration: %v", err)
+	}
+	bookingService := service.NewBookingService(bookingRepo, bookingConfig)
 	bookingHandler := handler.NewBookingHandler(bookingService)
 
+	// ========== Promo Code Setup ==========
+	// Codes are managed here and redeemed through the booking service
+	promoRepo := repository.NewPromoRepository(db.DB)
+	promoService := service.NewPromoService(promoRepo)
+	promoHandler := handler.NewPromoHandler(promoService)
+
+	// ========== Tax Setup ==========
+	// Rates are managed here and charged through the booking service
+	taxRepo := repository.NewTaxRepository(db.DB)
+	taxService := service.NewTaxService(taxRepo)
+	taxHandler := handler.NewTaxHandler(taxService)
+
+	// ========== Guest Manifest Setup ==========
+	// Identity document numbers are encrypted with GUEST_DOCUMENT_KEY
+	guestConfig, err := service.GuestConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid guest configuration: %v", err)
+	}
+	guestRepo := repository.NewGuestRepository(db.DB)
+	guestService := service.NewGuestService(guestRepo, guestConfig)
+	guestHandler := handler.NewGuestHandler(guestService, bookingService)
+
+	// ========== Folio Setup ==========
+	// Incidental charges posted during a stay count towards the check-out balance
+	folioRepo := repository.NewFolioRepository(db.DB)
+	folioService := service.NewFolioService(folioRepo)
+	folioHandler := handler.NewFolioHandler(folioService, bookingService)
+
+	// ========== Invoice Setup ==========
+	// Invoices are issued from a booking's price breakdown and folio
+	invoiceRepo := repository.NewInvoiceRepository(db.DB)
+	invoiceService := service.NewInvoiceService(invoiceRepo, folioService)
+	invoiceHandler := handler.NewInvoiceHandler(invoiceService, bookingService)
+
+	// ========== Reservation Setup ==========
+	// Group reservations book several rooms at once with the booking rules above
+	reservationRepo := repository.NewReservationRepository(db.DB)
+	reservationService := service.NewReservationService(reservationRepo, bookingService)
+	reservationHandler := handler.NewReservationHandler(reservationService)
+
+	// ========== Waitlist Setup ==========
+	// Freed rooms are offered to waiting guests through the notifier; offers last WAITLIST_OFFER_TTL
+	waitlistConfig, err := service.WaitlistConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid waitlist configuration: %v", err)
+	}
+	waitlistRepo := repository.NewWaitlistRepository(db.DB)
+	waitlistService := service.NewWaitlistService(waitlistRepo, bookingService, notify.LogNotifier{}, waitlistConfig)
+	waitlistHandler := handler.NewWaitlistHandler(waitlistService)
+
 	// ========== Payment Management Setup ==========
 	paymentRepo := repository.NewPaymentRepository(db.DB)
-	paymentService := service.NewPaymentService(paymentRepo)
+	paymentService := service.NewPaymentService(paymentRepo, bookingConfig.HoldTTL)
 	paymentHandler := handler.NewPaymentHandler(paymentService)
 
+	// ========== Inventory Setup ==========
+	inventoryRepo := repository.NewInventoryRepository(db.DB)
+	inventoryService := service.NewInventoryService(inventoryRepo)
+	inventoryHandler := handler.NewInventoryHandler(inventoryService)
+
+	// ========== Housekeeping Setup ==========
+	housekeepingRepo := repository.NewHousekeepingRepository(db.DB)
+	housekeepingService := service.NewHousekeepingService(housekeepingRepo)
+	housekeepingHandler := handler.NewHousekeepingHandler(housekeepingService)
+
+	// ========== Calendar Feed Setup ==========
+	// Feed links are signed with CALENDAR_SECRET (JWT_SECRET when unset)
+	calendarRepo := repository.NewCalendarRepository(db.DB)
+	calendarService := service.NewCalendarService(calendarRepo, service.CalendarConfigFromEnv())
+	calendarHandler := handler.NewCalendarHandler(calendarService)
+
+	// ========== External Calendar Setup ==========
+	// Reservations from other booking platforms' iCal feeds block rooms; feeds are downloaded
+	// every EXTERNAL_CALENDAR_SYNC_INTERVAL
+	externalCalendarConfig, err := service.ExternalCalendarConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid external calendar configuration: %v", err)
+	}
+	externalCalendarRepo := repository.NewExternalCalendarRepository(db.DB)
+	externalCalendarService := service.NewExternalCalendarService(externalCalendarRepo, &calsync.Client{}, externalCalendarConfig)
+	externalCalendarHandler := handler.NewExternalCalendarHandler(externalCalendarService)
+
+	// ========== Notification Setup ==========
+	// Booking events are written to an outbox with the change and delivered to guests by email
+	// and SMS through the senders chosen by NOTIFY_EMAIL_SENDER and NOTIFY_SMS_SENDER
+	notificationConfig, err := service.NotificationConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid notification configuration: %v", err)
+	}
+	senders, err := notify.SendersFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid notification configuration: %v", err)
+	}
+	outboxRepo := repository.NewOutboxRepository(db.DB)
+	notificationService := service.NewNotificationService(outboxRepo, senders, notificationConfig)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+
+	// ========== Idempotency Setup ==========
+	// Retries of creation requests that carry an Idempotency-Key header replay the first response
+	idempotencyRepo := repository.NewIdempotencyRepository(db.DB)
+	idempotencyService := service.NewIdempotencyService(idempotencyRepo)
+	idempotent := middleware.Idempotency(idempotencyService)
+
+	// ========== Background Jobs Setup ==========
+	// Every instance runs the jobs; they claim rows with SKIP LOCKED so they never collide
+	go jobs.Every(context.Background(), time.Minute, "expire booking holds", bookingService.ExpireHolds)
+	go jobs.Every(context.Background(), time.Hour, "purge idempotency keys", idempotencyService.PurgeExpired)
+	go jobs.Every(context.Background(), time.Minute, "process waitlist", waitlistService.ProcessWaitlist)
+	go jobs.Every(context.Background(), time.Minute, "sync external calendars", externalCalendarService.SyncDue)
+	go jobs.Every(context.Background(), 5*time.Minute, "process no-shows", bookingService.ProcessNoShows)
+	go jobs.Every(context.Background(), 10*time.Second, "dispatch notifications", notificationService.Dispatch)
+
 	// ========== Route Configuration ==========
 	// All API routes are prefixed with /api/v1 for versioning
+	// Bearer tokens are optional; when present they identify the caller for property scoping
 	v1 := router.Group("/api/v1")
+	v1.Use(middleware.Authenticate())
 	{
 		// Authentication and user management routes
 		users := v1.Group("/auth")
 		users.POST("/register", userHandler.Register)
 		users.POST("/login", userHandler.LoginUser)
-		users.GET("/fetch-users", userHandler.GetUserList)
-		users.GET("/fetch-user-by-id/:id", userHandler.GetUserByID)
-		users.PUT("/update-user-status/:id", userHandler.UpdateUserStatus)
+		users.GET("/fetch-users", staffOnly, propertyScope, userHandler.GetUserList)
+		users.GET("/fetch-user-by-id/:id", staffOnly, propertyScope, userHandler.GetUserByID)
+		users.PUT("/update-user-status/:id", staffOnly, propertyScope, userHandler.UpdateUserStatus)
+		users.PUT("/users/:id/properties", adminOnly, propertyHandler.AssignUser)
+
+		// Property routes
+		properties := v1.Group("/properties")
+		properties.GET("", publicScope, propertyHandler.ListProperties)
+		properties.POST("", adminOnly, propertyHandler.CreateProperty)
 
 		// Room management routes
 		rooms := v1.Group("/rooms")
-		rooms.POST("/add", roomHandler.AddRoom)
-		rooms.GET("/allRoomsList", roomHandler.GetRoomsList)
-		rooms.GET("/availableRoomsList", roomHandler.GetAvailableRooms)
+		rooms.GET("", publicScope, roomHandler.SearchRooms)
+		rooms.POST("/add", authenticated, staffOnly, propertyScope, roomHandler.AddRoom)
+		rooms.GET("/allRoomsList", publicScope, roomHandler.GetRoomsList)
+		rooms.GET("/availableRoomsList", publicScope, roomHandler.GetAvailableRooms)
+
+		// Room and room type photo routes
+		rooms.GET("/:id/media", mediaHandler.ListRoomMedia)
+		rooms.POST("/:id/media", authenticated, staffOnly, propertyScope, mediaHandler.UploadRoomMedia)
+		rooms.PUT("/:id/media/order", authenticated, staffOnly, propertyScope, mediaHandler.ReorderRoomMedia)
+		roomTypes := v1.Group("/room-types")
+		roomTypes.GET("/:type/media", mediaHandler.ListRoomTypeMedia)
+		roomTypes.POST("/:type/media", authenticated, staffOnly, propertyScope, mediaHandler.UploadRoomTypeMedia)
+		roomTypes.PUT("/:type/media/order", authenticated, staffOnly, propertyScope, mediaHandler.ReorderRoomTypeMedia)
+		media := v1.Group("/media")
+		media.PUT("/:id", authenticated, staffOnly, propertyScope, mediaHandler.UpdateCaption)
+		media.DELETE("/:id", authenticated, staffOnly, propertyScope, mediaHandler.DeleteMedia)
 
 		// Room maintenance routes
 		roomMaintenance := v1.Group("/roomMaintenance")
-		roomMaintenance.POST("/add", roomMaintenanceHandler.AddRoomMaintenance)
+		roomMaintenance.POST("/add", authenticated, staffOnly, roomMaintenanceHandler.AddRoomMaintenance)
 
 		// Booking management routes
 		booking := v1.Group("/bookings")
-		booking.POST("/add", bookingHandler.AddBooking)
+		booking.POST("/add", authenticated, idempotent, bookingHandler.AddBooking)
+		booking.GET("", authenticated, propertyScope, bookingHandler.ListBookings)
+		booking.GET("/arrivals", staffOnly, propertyScope, bookingHandler.GetArrivals)
+		booking.GET("/departures", staffOnly, propertyScope, bookingHandler.GetDepartures)
+		booking.GET("/:id", authenticated, propertyScope, bookingHandler.GetBooking)
+		booking.POST("/:id/confirm", staffOnly, propertyScope, bookingHandler.ConfirmBooking)
+		booking.POST("/:id/check-in", staffOnly, propertyScope, bookingHandler.CheckInBooking)
+		booking.POST("/:id/check-out", staffOnly, propertyScope, bookingHandler.CheckOutBooking)
+		booking.POST("/:id/no-show", staffOnly, propertyScope, bookingHandler.MarkNoShow)
+		booking.POST("/:id/cancel", authenticated, propertyScope, bookingHandler.CancelBooking)
+		booking.GET("/:id/history", authenticated, propertyScope, bookingHandler.GetBookingHistory)
+		booking.PATCH("/:id", authenticated, propertyScope, bookingHandler.ModifyBooking)
+		booking.GET("/:id/changes", authenticated, propertyScope, bookingHandler.GetBookingChanges)
+		booking.GET("/:id/guests", authenticated, propertyScope, guestHandler.ListGuests)
+		booking.PUT("/:id/guests", authenticated, propertyScope, guestHandler.ReplaceGuests)
+		booking.GET("/:id/folio", authenticated, propertyScope, folioHandler.GetFolio)
+		booking.POST("/:id/folio/entries", staffOnly, propertyScope, folioHandler.PostEntry)
+		booking.POST("/:id/folio/entries/:entryId/void", staffOnly, propertyScope, folioHandler.VoidEntry)
+		booking.GET("/:id/invoices", authenticated, propertyScope, invoiceHandler.ListInvoices)
+		booking.POST("/:id/invoices", staffOnly, propertyScope, invoiceHandler.IssueInvoice)
+
+		// Invoice routes; issued invoices never change and are corrected with credit notes
+		invoices := v1.Group("/invoices")
+		invoices.GET("/:id", authenticated, propertyScope, invoiceHandler.GetInvoice)
+		invoices.POST("/:id/credit-note", staffOnly, propertyScope, invoiceHandler.CreditInvoice)
+
+		// Guest registration report for the authorities
+		guests := v1.Group("/guests")
+		guests.GET("/registrations", staffOnly, propertyScope, guestHandler.GetRegistrationReport)
+
+		// Group reservation routes; individual rooms are cancelled or modified through the booking routes
+		reservations := v1.Group("/reservations")
+		reservations.POST("", authenticated, idempotent, reservationHandler.CreateReservation)
+		reservations.GET("/:id", authenticated, propertyScope, reservationHandler.GetReservation)
+		reservations.POST("/:id/payments", authenticated, propertyScope, idempotent, reservationHandler.PayReservation)
+
+		// Promo code routes; guests redeem codes with promo_codes when adding a booking
+		promoCodes := v1.Group("/promo-codes")
+		promoCodes.GET("", staffOnly, promoHandler.ListPromoCodes)
+		promoCodes.POST("", adminOnly, promoHandler.CreatePromoCode)
+		promoCodes.DELETE("/:id", adminOnly, promoHandler.DeactivatePromoCode)
+
+		// Tax routes; rates are charged on bookings priced after they are created
+		taxes := v1.Group("/taxes")
+		taxes.GET("/rates", staffOnly, propertyScope, taxHandler.ListTaxRates)
+		taxes.POST("/rates", adminOnly, propertyScope, taxHandler.CreateTaxRate)
+		taxes.DELETE("/rates/:id", adminOnly, taxHandler.DeleteTaxRate)
+		taxes.GET("/report", staffOnly, propertyScope, taxHandler.GetTaxReport)
+
+		// Waitlist routes; offers are claimed with the signed token from the offer link
+		waitlist := v1.Group("/waitlist")
+		waitlist.POST("", authenticated, waitlistHandler.JoinWaitlist)
+		waitlist.GET("", authenticated, waitlistHandler.ListWaitlist)
+		waitlist.DELETE("/:id", authenticated, propertyScope, waitlistHandler.LeaveWaitlist)
+		waitlist.POST("/claim", waitlistHandler.ClaimOffer)
 
 		// Payment processing routes
 		payment := v1.Group("/payments")
-		payment.POST("/initiate", paymentHandler.InitiatePayment)
-		payment.PUT("/update-payment", paymentHandler.UpdatePayment)
+		payment.POST("/initiate", authenticated, idempotent, paymentHandler.InitiatePayment)
+		payment.PUT("/update-payment", staffOnly, paymentHandler.UpdatePayment)
+
+		// Inventory routes
+		inventory := v1.Group("/inventory")
+		inventory.GET("/calendar", staffOnly, propertyScope, inventoryHandler.GetCalendar)
+
+		// Calendar feed routes; feeds are public and authorised by the signed token in their link
+		calendar := v1.Group("/calendar")
+		calendar.GET("/links", staffOnly, propertyScope, calendarHandler.GetFeedLink)
+		calendar.GET("/feeds/:token", calendarHandler.GetFeed)
+
+		// External calendar routes; reservations imported from other platforms block their rooms
+		externalCalendars := v1.Group("/external-calendars")
+		externalCalendars.GET("", adminOnly, propertyScope, externalCalendarHandler.ListCalendars)
+		externalCalendars.POST("", adminOnly, externalCalendarHandler.AddCalendar)
+		externalCalendars.GET("/:id/blocks", adminOnly, propertyScope, externalCalendarHandler.ListBlocks)
+		externalCalendars.POST("/:id/sync", adminOnly, propertyScope, externalCalendarHandler.SyncCalendar)
+		externalCalendars.DELETE("/:id", adminOnly, propertyScope, externalCalendarHandler.DeleteCalendar)
+
+		// Notification outbox routes (admin only), to inspect and retry undelivered events
+		notifications := v1.Group("/notifications")
+		notifications.GET("/outbox", adminOnly, notificationHandler.ListEvents)
+		notifications.POST("/outbox/:id/retry", adminOnly, notificationHandler.RetryEvent)
+
+		// Housekeeping routes
+		housekeeping := v1.Group("/housekeeping")
+		housekeeping.GET("/board", authenticated, staffOnly, propertyScope, housekeepingHandler.GetBoard)
+		housekeeping.PUT("/rooms/:id/status", authenticated, staffOnly, propertyScope, housekeepingHandler.ChangeStatus)
+		housekeeping.GET("/rooms/:id/history", authenticated, staffOnly, propertyScope, housekeepingHandler.GetHistory)
 
 	}
 